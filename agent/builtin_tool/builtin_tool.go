@@ -19,7 +19,10 @@ import (
 	"encoding/json"
 
 	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/casibase/casibase/agent/builtin_tool/code"
+	"github.com/casibase/casibase/agent/builtin_tool/knowledge"
 	"github.com/casibase/casibase/agent/builtin_tool/time"
+	"github.com/casibase/casibase/agent/builtin_tool/web"
 )
 
 type BuiltinTool interface {
@@ -44,6 +47,11 @@ func NewToolRegistry() *ToolRegistry {
 	registry.RegisterTool(&timetools.TimezoneConversionTool{})   // timezone conversion
 	registry.RegisterTool(&timetools.WeekdayTool{})              // weekday calculator
 
+	registry.RegisterTool(&knowledgetools.KnowledgeSearchTool{}) // knowledge search, bound to the chat's stores
+	registry.RegisterTool(&codetools.ExecuteCodeTool{})          // code execution in a sandbox, bound to the chat's files
+	registry.RegisterTool(&webtools.WebFetchTool{})              // web page fetch, bound to the store's web policy
+	registry.RegisterTool(&webtools.HttpRequestTool{})           // JSON HTTP request, bound to the store's web policy
+
 	return registry
 }
 
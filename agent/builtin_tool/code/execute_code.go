@@ -0,0 +1,155 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codetools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+)
+
+// FileLoader returns the files attached to the chat, they are copied into the scratch directory.
+type FileLoader func(ctx context.Context) ([]*File, error)
+
+// FileSaver saves a file generated by the code and returns its URL.
+type FileSaver func(ctx context.Context, file *File) (string, error)
+
+type OutputFile struct {
+	Name  string `json:"name"`
+	Url   string `json:"url,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+type ExecuteCodeResult struct {
+	Stdout    string        `json:"stdout"`
+	Stderr    string        `json:"stderr"`
+	ExitCode  int           `json:"exitCode"`
+	IsTimeout bool          `json:"isTimeout,omitempty"`
+	Files     []*OutputFile `json:"files,omitempty"`
+}
+
+// ExecuteCodeTool runs Python or JavaScript code in the sandbox. The registry holds it without
+// files, the chat binds its attached files and the storage of the generated files with WithFiles.
+type ExecuteCodeTool struct {
+	Sandbox   *Sandbox
+	LoadFiles FileLoader
+	SaveFile  FileSaver
+}
+
+func (t *ExecuteCodeTool) WithFiles(loadFiles FileLoader, saveFile FileSaver) *ExecuteCodeTool {
+	return &ExecuteCodeTool{
+		Sandbox:   t.Sandbox,
+		LoadFiles: loadFiles,
+		SaveFile:  saveFile,
+	}
+}
+
+func (t *ExecuteCodeTool) GetName() string {
+	return "execute_code"
+}
+
+func (t *ExecuteCodeTool) GetDescription() string {
+	return fmt.Sprintf("Execute Python or JavaScript code in a sandbox without network access, for calculations and data transforms. The files attached to the chat are in the working directory, e.g. open('data.csv') in Python. Print the results to stdout, files written to the working directory are returned as download links. The code is killed after %d seconds.", int(t.Sandbox.getTimeout().Seconds()))
+}
+
+func (t *ExecuteCodeTool) GetInputSchema() interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"language": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{"python", "javascript"},
+				"description": "The language of the code. Defaults to python.",
+			},
+			"code": map[string]interface{}{
+				"type":        "string",
+				"description": "The complete program to run.",
+			},
+		},
+		"required": []string{"code"},
+	}
+}
+
+func getErrorResult(text string) *protocol.CallToolResult {
+	return &protocol.CallToolResult{
+		IsError: true,
+		Content: []protocol.Content{
+			&protocol.TextContent{
+				Type: "text",
+				Text: text,
+			},
+		},
+	}
+}
+
+func (t *ExecuteCodeTool) Execute(ctx context.Context, arguments map[string]interface{}) (*protocol.CallToolResult, error) {
+	code, ok := arguments["code"].(string)
+	if !ok || code == "" {
+		return getErrorResult("Missing required parameter: code"), nil
+	}
+
+	language := "python"
+	if l, ok := arguments["language"].(string); ok && l != "" {
+		language = l
+	}
+
+	files := []*File{}
+	if t.LoadFiles != nil {
+		var err error
+		files, err = t.LoadFiles(ctx)
+		if err != nil {
+			return getErrorResult(fmt.Sprintf("Failed to load the chat files: %s", err.Error())), nil
+		}
+	}
+
+	sandboxResult, err := t.Sandbox.Run(ctx, language, code, files)
+	if err != nil {
+		return getErrorResult(fmt.Sprintf("Failed to execute the code: %s", err.Error())), nil
+	}
+
+	res := &ExecuteCodeResult{
+		Stdout:    sandboxResult.Stdout,
+		Stderr:    sandboxResult.Stderr,
+		ExitCode:  sandboxResult.ExitCode,
+		IsTimeout: sandboxResult.IsTimeout,
+	}
+	for _, file := range sandboxResult.Files {
+		outputFile := &OutputFile{Name: file.Name}
+		if t.SaveFile != nil {
+			outputFile.Url, err = t.SaveFile(ctx, file)
+			if err != nil {
+				outputFile.Error = err.Error()
+			}
+		}
+		res.Files = append(res.Files, outputFile)
+	}
+
+	data, err := json.Marshal(res)
+	if err != nil {
+		return nil, err
+	}
+
+	return &protocol.CallToolResult{
+		IsError: sandboxResult.ExitCode != 0,
+		Content: []protocol.Content{
+			&protocol.TextContent{
+				Type: "text",
+				Text: string(data),
+			},
+		},
+	}, nil
+}
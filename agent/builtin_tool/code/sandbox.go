@@ -0,0 +1,242 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codetools
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	defaultTimeoutSeconds = 30
+	defaultMemoryMb       = 512
+	defaultMaxOutputBytes = 64 * 1024
+	defaultMaxFileBytes   = 10 * 1024 * 1024
+	defaultMaxFileCount   = 10
+)
+
+// File is a file copied into the scratch directory of the sandbox or generated by the code.
+type File struct {
+	Name string
+	Data []byte
+}
+
+type SandboxResult struct {
+	Stdout    string
+	Stderr    string
+	ExitCode  int
+	IsTimeout bool
+	Files     []*File
+}
+
+type language struct {
+	binary   string
+	fileName string
+	args     []string
+}
+
+func getLanguage(name string, memoryMb int) (*language, bool) {
+	switch name {
+	case "python":
+		return &language{binary: "python3", fileName: "main.py", args: []string{"-I", "main.py"}}, true
+	case "javascript":
+		// V8 reserves far more address space than it uses, its heap is limited by the flag instead
+		return &language{binary: "node", fileName: "main.js", args: []string{fmt.Sprintf("--max-old-space-size=%d", memoryMb), "main.js"}}, true
+	default:
+		return nil, false
+	}
+}
+
+// Sandbox runs code with an interpreter in a scratch directory, without network access and
+// with CPU, memory, output and file size limits, see runCommand for the isolation.
+type Sandbox struct {
+	TimeoutSeconds int
+	MemoryMb       int
+	MaxOutputBytes int
+	MaxFileBytes   int
+}
+
+func (s *Sandbox) getTimeout() time.Duration {
+	if s == nil || s.TimeoutSeconds <= 0 {
+		return defaultTimeoutSeconds * time.Second
+	}
+	return time.Duration(s.TimeoutSeconds) * time.Second
+}
+
+func (s *Sandbox) getMemoryMb() int {
+	if s == nil || s.MemoryMb <= 0 {
+		return defaultMemoryMb
+	}
+	return s.MemoryMb
+}
+
+func (s *Sandbox) getMaxOutputBytes() int {
+	if s == nil || s.MaxOutputBytes <= 0 {
+		return defaultMaxOutputBytes
+	}
+	return s.MaxOutputBytes
+}
+
+func (s *Sandbox) getMaxFileBytes() int {
+	if s == nil || s.MaxFileBytes <= 0 {
+		return defaultMaxFileBytes
+	}
+	return s.MaxFileBytes
+}
+
+// limitedBuffer keeps the first bytes written to it and drops the rest.
+type limitedBuffer struct {
+	buf         bytes.Buffer
+	limit       int
+	isTruncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	left := b.limit - b.buf.Len()
+	if left < len(p) {
+		b.isTruncated = true
+		if left > 0 {
+			b.buf.Write(p[:left])
+		}
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *limitedBuffer) String() string {
+	if b.isTruncated {
+		return b.buf.String() + "\n[output truncated]"
+	}
+	return b.buf.String()
+}
+
+func getSafeFileName(name string) string {
+	name = filepath.Base(filepath.Clean("/" + name))
+	if name == "/" || name == "." {
+		return ""
+	}
+	return name
+}
+
+// Run writes the files and the code into a new scratch directory, runs the code and returns
+// its output with the files it created or changed.
+func (s *Sandbox) Run(ctx context.Context, languageName string, code string, files []*File) (*SandboxResult, error) {
+	lang, ok := getLanguage(languageName, s.getMemoryMb())
+	if !ok {
+		return nil, fmt.Errorf("the language: %s is not supported, use python or javascript", languageName)
+	}
+
+	binary, err := findInterpreter(lang.binary)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "casibase-sandbox-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	inputs := map[string][]byte{}
+	for _, file := range files {
+		name := getSafeFileName(file.Name)
+		if name == "" || name == lang.fileName {
+			continue
+		}
+		err = os.WriteFile(filepath.Join(dir, name), file.Data, 0o666)
+		if err != nil {
+			return nil, err
+		}
+		inputs[name] = file.Data
+	}
+
+	err = os.WriteFile(filepath.Join(dir, lang.fileName), []byte(code), 0o666)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.getTimeout())
+	defer cancel()
+
+	stdout := &limitedBuffer{limit: s.getMaxOutputBytes()}
+	stderr := &limitedBuffer{limit: s.getMaxOutputBytes()}
+	err = runCommand(ctx, dir, binary, lang.args, s.getTimeout(), s.getMemoryMb(), s.getMaxFileBytes(), lang.binary == "node", stdout, stderr)
+
+	res := &SandboxResult{}
+	var exitError *exec.ExitError
+	if ctx.Err() == context.DeadlineExceeded {
+		res.IsTimeout = true
+		res.ExitCode = -1
+	} else if errors.As(err, &exitError) {
+		res.ExitCode = exitError.ExitCode()
+	} else if err != nil {
+		return nil, err
+	}
+	res.Stdout = stdout.String()
+	res.Stderr = stderr.String()
+
+	res.Files, err = getOutputFiles(dir, lang.fileName, inputs, s.getMaxFileBytes())
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func getOutputFiles(dir string, codeFileName string, inputs map[string][]byte, maxFileBytes int) ([]*File, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+
+	res := []*File{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.Type().IsRegular() || name == codeFileName {
+			continue
+		}
+		if len(res) >= defaultMaxFileCount {
+			break
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		if info.Size() > int64(maxFileBytes) {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		if input, ok := inputs[name]; ok && bytes.Equal(input, data) {
+			continue
+		}
+
+		res = append(res, &File{Name: name, Data: data})
+	}
+	return res, nil
+}
@@ -0,0 +1,477 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package codetools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+const nobodyId = 65534
+
+// sandboxInitName is the name the server binary is run with to set up the sandbox, see init.
+const sandboxInitName = "casibase-sandbox-init"
+
+const (
+	sandboxWorkDir = "/work"
+	sandboxOldRoot = "/.oldroot"
+)
+
+// sandboxPaths are bound read-only into the root of the sandbox when they exist: the system
+// directories holding the interpreters and their libraries, and the few files of /etc they need.
+var sandboxPaths = []string{
+	"/bin", "/sbin", "/lib", "/lib32", "/lib64", "/libx32", "/usr",
+	"/etc/alternatives", "/etc/ld.so.cache", "/etc/ld.so.conf", "/etc/ld.so.conf.d", "/etc/localtime", "/etc/ssl", "/etc/fonts", "/etc/mime.types",
+}
+
+var sandboxDevices = []string{"/dev/null", "/dev/zero", "/dev/random", "/dev/urandom"}
+
+// sandboxBinDirs are searched for the interpreters, which must resolve into the sandboxPaths:
+// the ones installed in a home directory (pyenv, nvm...) are neither bound nor readable by nobody.
+var sandboxBinDirs = []string{"/usr/local/bin", "/usr/bin", "/bin"}
+
+// sandboxArgs are the arguments of sandboxInitName, see runCommand.
+type sandboxArgs struct {
+	rootDir      string
+	workDir      string
+	cpuSeconds   int
+	maxFileBytes int
+	memoryBytes  int
+	binary       string
+	args         []string
+}
+
+func init() {
+	if len(os.Args) > 0 && os.Args[0] == sandboxInitName {
+		initSandbox()
+	}
+}
+
+// runCommand runs the interpreter in new user, network, mount, PID, IPC and UTS namespaces as
+// nobody: the code has no network interface but loopback, which is down, can't see or signal the
+// server processes, and only sees a root made of read-only system directories and its scratch
+// directory. The server binary sets up the namespaces as sandboxInitName before running the
+// interpreter, see initSandbox. Mapping the code to nobody needs the server to run as root, the
+// code is refused otherwise.
+func runCommand(ctx context.Context, dir string, binary string, args []string, timeout time.Duration, memoryMb int, maxFileBytes int, isNode bool, stdout io.Writer, stderr io.Writer) error {
+	err := checkSandboxUser()
+	if err != nil {
+		return err
+	}
+
+	// the scratch directory and the input files belong to the code
+	err = filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		return os.Lchown(path, nobodyId, nobodyId)
+	})
+	if err != nil {
+		return err
+	}
+	err = os.Chmod(dir, 0o700)
+	if err != nil {
+		return err
+	}
+
+	rootDir, err := os.MkdirTemp("", "casibase-sandbox-root-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(rootDir)
+
+	memoryBytes := 0
+	if !isNode {
+		memoryBytes = memoryMb * 1024 * 1024
+	}
+	cpuSeconds := int(timeout.Seconds()) + 1
+
+	errorReader, errorWriter, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer errorReader.Close()
+
+	cmd := exec.CommandContext(ctx, "/proc/self/exe")
+	cmd.Args = append([]string{sandboxInitName, rootDir, dir, strconv.Itoa(cpuSeconds), strconv.Itoa(maxFileBytes), strconv.Itoa(memoryBytes), binary}, args...)
+	cmd.Dir = dir
+	cmd.Env = []string{
+		"PATH=/usr/local/bin:/usr/bin:/bin",
+		"HOME=" + sandboxWorkDir,
+		"TMPDIR=" + sandboxWorkDir,
+		"LANG=C.UTF-8",
+		"PYTHONDONTWRITEBYTECODE=1",
+		"MPLBACKEND=Agg",
+	}
+	cmd.Stdin = nil
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.ExtraFiles = []*os.File{errorWriter}
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Cloneflags: syscall.CLONE_NEWUSER | syscall.CLONE_NEWNET | syscall.CLONE_NEWNS | syscall.CLONE_NEWPID | syscall.CLONE_NEWIPC | syscall.CLONE_NEWUTS,
+		UidMappings: []syscall.SysProcIDMap{
+			{ContainerID: 0, HostID: nobodyId, Size: 1},
+		},
+		GidMappings: []syscall.SysProcIDMap{
+			{ContainerID: 0, HostID: nobodyId, Size: 1},
+		},
+		GidMappingsEnableSetgroups: false,
+		// root of the user namespace, which keeps the capabilities to set up the sandbox across the exec
+		Credential: &syscall.Credential{Uid: 0, Gid: 0, NoSetGroups: true},
+		Pdeathsig:  syscall.SIGKILL,
+	}
+
+	err = cmd.Start()
+	errorWriter.Close()
+	if err != nil {
+		return err
+	}
+
+	// the pipe is closed on the exec of the interpreter, anything written before is a setup error
+	setupError, _ := io.ReadAll(errorReader)
+	err = cmd.Wait()
+	if len(setupError) > 0 {
+		return fmt.Errorf("failed to set up the code sandbox: %s", setupError)
+	}
+	return err
+}
+
+// initSandbox runs in the namespaces created by runCommand, as root of the user namespace which is
+// nobody outside: it builds the root of the sandbox, applies the resource limits, drops all the
+// capabilities and replaces itself with the interpreter. It never returns.
+func initSandbox() {
+	// the capabilities are per thread, they must be dropped by the thread running the interpreter
+	runtime.LockOSThread()
+
+	errorFile := os.NewFile(3, "sandbox-error")
+	syscall.CloseOnExec(3)
+
+	err := setupSandbox(os.Args[1:])
+	if err != nil {
+		_, _ = errorFile.WriteString(err.Error())
+		os.Exit(1)
+	}
+}
+
+// checkSandboxUser refuses to run the code unless the server runs as root, see runCommand.
+func checkSandboxUser() error {
+	if os.Geteuid() != 0 {
+		return fmt.Errorf("the code sandbox requires the server to run as root to run the code as nobody, the server runs as the user: %d", os.Geteuid())
+	}
+	return nil
+}
+
+// CheckSandbox tells at startup why the execute_code tool can't run any code on this server.
+func CheckSandbox() error {
+	err := checkSandboxUser()
+	if err != nil {
+		return err
+	}
+
+	_, err = findInterpreter("python3")
+	return err
+}
+
+// findInterpreter returns the path of the interpreter in the sandboxBinDirs.
+func findInterpreter(name string) (string, error) {
+	return findSandboxBinary(name, sandboxBinDirs, sandboxPaths)
+}
+
+func findSandboxBinary(name string, dirs []string, paths []string) (string, error) {
+	for _, dir := range dirs {
+		path := filepath.Join(dir, name)
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() || info.Mode()&0o111 == 0 {
+			continue
+		}
+
+		if isSandboxPath(resolvePath(path), paths) {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("the interpreter: %s is not installed in %s of the server, the interpreters installed in a home directory can't run in the code sandbox", name, strings.Join(dirs, ", "))
+}
+
+func parseSandboxArgs(args []string) (*sandboxArgs, error) {
+	if len(args) < 6 {
+		return nil, fmt.Errorf("expected at least 6 arguments, got %d", len(args))
+	}
+
+	limits := []int{}
+	for _, arg := range args[2:5] {
+		limit, err := strconv.Atoi(arg)
+		if err != nil {
+			return nil, err
+		}
+		limits = append(limits, limit)
+	}
+
+	res := &sandboxArgs{
+		rootDir:      args[0],
+		workDir:      args[1],
+		cpuSeconds:   limits[0],
+		maxFileBytes: limits[1],
+		memoryBytes:  limits[2],
+		binary:       args[5],
+		args:         args[6:],
+	}
+	return res, nil
+}
+
+func setupSandbox(args []string) error {
+	sandbox, err := parseSandboxArgs(args)
+	if err != nil {
+		return err
+	}
+
+	err = setupSandboxRoot(sandbox.rootDir, sandbox.workDir)
+	if err != nil {
+		return err
+	}
+
+	err = setSandboxLimits(sandbox.cpuSeconds, sandbox.maxFileBytes, sandbox.memoryBytes)
+	if err != nil {
+		return err
+	}
+
+	err = dropCapabilities()
+	if err != nil {
+		return err
+	}
+
+	err = syscall.Exec(sandbox.binary, append([]string{sandbox.binary}, sandbox.args...), os.Environ())
+	return fmt.Errorf("failed to run the interpreter: %s, %s", sandbox.binary, err.Error())
+}
+
+// setupSandboxRoot mounts a tmpfs as the new root with the sandboxPaths bound read-only, the
+// interpreter being one of them, see findInterpreter, the scratch directory bound to sandboxWorkDir
+// and a few devices, then pivots into it and detaches the old root so that nothing else of the
+// host is reachable.
+func setupSandboxRoot(rootDir string, workDir string) error {
+	err := unix.Mount("", "/", "", unix.MS_REC|unix.MS_PRIVATE, "")
+	if err != nil {
+		return fmt.Errorf("failed to make the mounts private: %s", err.Error())
+	}
+
+	err = unix.Mount("tmpfs", rootDir, "tmpfs", unix.MS_NOSUID|unix.MS_NODEV, "size=16m,mode=0755")
+	if err != nil {
+		return fmt.Errorf("failed to mount the root: %s", err.Error())
+	}
+
+	for _, path := range sandboxPaths {
+		err = bindSandboxPath(rootDir, path, path, true)
+		if err != nil {
+			return err
+		}
+	}
+	for _, device := range sandboxDevices {
+		err = bindSandboxPath(rootDir, device, device, false)
+		if err != nil {
+			return err
+		}
+	}
+	err = bindSandboxPath(rootDir, workDir, sandboxWorkDir, false)
+	if err != nil {
+		return err
+	}
+
+	// the interpreters work without /proc, it may not be mountable in a nested container
+	procDir := filepath.Join(rootDir, "proc")
+	err = os.Mkdir(procDir, 0o555)
+	if err != nil {
+		return err
+	}
+	_ = unix.Mount("proc", procDir, "proc", unix.MS_NOSUID|unix.MS_NODEV|unix.MS_NOEXEC, "")
+
+	err = os.Mkdir(filepath.Join(rootDir, sandboxOldRoot), 0o700)
+	if err != nil {
+		return err
+	}
+	err = unix.PivotRoot(rootDir, filepath.Join(rootDir, sandboxOldRoot))
+	if err != nil {
+		return fmt.Errorf("failed to pivot the root: %s", err.Error())
+	}
+	err = unix.Chdir("/")
+	if err != nil {
+		return err
+	}
+	err = unix.Unmount(sandboxOldRoot, unix.MNT_DETACH)
+	if err != nil {
+		return fmt.Errorf("failed to detach the old root: %s", err.Error())
+	}
+	err = os.Remove(sandboxOldRoot)
+	if err != nil {
+		return err
+	}
+
+	err = unix.Mount("", "/", "", unix.MS_REMOUNT|unix.MS_BIND|unix.MS_RDONLY|unix.MS_NOSUID|unix.MS_NODEV, "")
+	if err != nil {
+		return fmt.Errorf("failed to make the root read-only: %s", err.Error())
+	}
+	return unix.Chdir(sandboxWorkDir)
+}
+
+func resolvePath(path string) string {
+	res, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return path
+	}
+	return res
+}
+
+func isSandboxPath(path string, paths []string) bool {
+	for _, p := range paths {
+		if path == p || strings.HasPrefix(path, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// bindSandboxPath binds source to target under the root, keeping the symbolic links of merged
+// /usr systems (/bin -> usr/bin) as links. The missing sources are skipped.
+func bindSandboxPath(rootDir string, source string, target string, isReadOnly bool) error {
+	info, err := os.Lstat(source)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	targetPath := filepath.Join(rootDir, target)
+	err = os.MkdirAll(filepath.Dir(targetPath), 0o755)
+	if err != nil {
+		return err
+	}
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		link, err := os.Readlink(source)
+		if err != nil {
+			return err
+		}
+		return os.Symlink(link, targetPath)
+	}
+
+	if info.IsDir() {
+		err = os.Mkdir(targetPath, 0o755)
+	} else {
+		err = os.WriteFile(targetPath, nil, 0o644)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = unix.Mount(source, targetPath, "", unix.MS_BIND|unix.MS_REC, "")
+	if err != nil {
+		return fmt.Errorf("failed to bind %s: %s", source, err.Error())
+	}
+
+	// the flags of the source mount are locked in the user namespace and must be kept
+	var stat unix.Statfs_t
+	err = unix.Statfs(targetPath, &stat)
+	if err != nil {
+		return err
+	}
+
+	flags := uintptr(unix.MS_REMOUNT | unix.MS_BIND | unix.MS_NOSUID)
+	for statFlag, mountFlag := range map[int64]uintptr{
+		unix.ST_RDONLY:     unix.MS_RDONLY,
+		unix.ST_NODEV:      unix.MS_NODEV,
+		unix.ST_NOEXEC:     unix.MS_NOEXEC,
+		unix.ST_NOATIME:    unix.MS_NOATIME,
+		unix.ST_NODIRATIME: unix.MS_NODIRATIME,
+		unix.ST_RELATIME:   unix.MS_RELATIME,
+	} {
+		if stat.Flags&statFlag != 0 {
+			flags |= mountFlag
+		}
+	}
+	if isReadOnly {
+		flags |= unix.MS_RDONLY
+	}
+	if !strings.HasPrefix(source, "/dev/") {
+		flags |= unix.MS_NODEV
+	}
+	err = unix.Mount("", targetPath, "", flags, "")
+	if err != nil {
+		return fmt.Errorf("failed to restrict %s: %s", source, err.Error())
+	}
+	return nil
+}
+
+// getSandboxLimits returns the resource limits of the code, the address space is not limited
+// when memoryBytes is 0, see runCommand.
+func getSandboxLimits(cpuSeconds int, maxFileBytes int, memoryBytes int) map[int]uint64 {
+	res := map[int]uint64{
+		unix.RLIMIT_CPU:   uint64(cpuSeconds),
+		unix.RLIMIT_FSIZE: uint64(maxFileBytes),
+		unix.RLIMIT_NPROC: 64,
+	}
+	if memoryBytes > 0 {
+		res[unix.RLIMIT_AS] = uint64(memoryBytes)
+	}
+	return res
+}
+
+func setSandboxLimits(cpuSeconds int, maxFileBytes int, memoryBytes int) error {
+	for resource, limit := range getSandboxLimits(cpuSeconds, maxFileBytes, memoryBytes) {
+		err := unix.Setrlimit(resource, &unix.Rlimit{Cur: limit, Max: limit})
+		if err != nil {
+			return fmt.Errorf("failed to set the resource limit: %d, %s", resource, err.Error())
+		}
+	}
+	return nil
+}
+
+// dropCapabilities empties the bounding, ambient and inheritable capability sets, so that the
+// interpreter gets no capability in the user namespace although it runs as its root.
+func dropCapabilities() error {
+	err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	for capability := 0; capability <= unix.CAP_LAST_CAP; capability++ {
+		err = unix.Prctl(unix.PR_CAPBSET_DROP, uintptr(capability), 0, 0, 0)
+		if err != nil && !errors.Is(err, unix.EINVAL) {
+			return fmt.Errorf("failed to drop the capability: %d, %s", capability, err.Error())
+		}
+	}
+
+	err = unix.Prctl(unix.PR_CAP_AMBIENT, unix.PR_CAP_AMBIENT_CLEAR_ALL, 0, 0, 0)
+	if err != nil && !errors.Is(err, unix.EINVAL) {
+		return err
+	}
+
+	header := unix.CapUserHeader{Version: unix.LINUX_CAPABILITY_VERSION_3}
+	data := [2]unix.CapUserData{}
+	return unix.Capset(&header, &data[0])
+}
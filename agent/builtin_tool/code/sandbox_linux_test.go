@@ -0,0 +1,131 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux && skipCi
+// +build linux,skipCi
+
+package codetools
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+func writeExecutable(t *testing.T, path string) {
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(path, []byte("#!/bin/sh\n"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFindSandboxBinary(t *testing.T) {
+	dir := t.TempDir()
+	homeBinDir := filepath.Join(dir, "root/.pyenv/shims")
+	systemDir := filepath.Join(dir, "usr")
+	systemBinDir := filepath.Join(systemDir, "bin")
+	localBinDir := filepath.Join(systemDir, "local/bin")
+
+	// a pyenv shim first in the directories, then a link out of the system directories
+	writeExecutable(t, filepath.Join(homeBinDir, "python3"))
+	writeExecutable(t, filepath.Join(dir, "root/.pyenv/versions/3.12/bin/python3.12"))
+	err := os.MkdirAll(localBinDir, 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Symlink(filepath.Join(dir, "root/.pyenv/versions/3.12/bin/python3.12"), filepath.Join(localBinDir, "python3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeExecutable(t, filepath.Join(systemBinDir, "python3.11"))
+	err = os.Symlink("python3.11", filepath.Join(systemBinDir, "python3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dirs := []string{homeBinDir, localBinDir, systemBinDir}
+	paths := []string{systemDir}
+	binary, err := findSandboxBinary("python3", dirs, paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if binary != filepath.Join(systemBinDir, "python3") {
+		t.Errorf("findSandboxBinary() = %s, want the system python3", binary)
+	}
+
+	_, err = findSandboxBinary("node", dirs, paths)
+	if err == nil {
+		t.Errorf("expected an error for a missing interpreter")
+	}
+}
+
+func TestIsSandboxPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/usr", true},
+		{"/usr/bin/python3", true},
+		{"/usr2/bin/python3", false},
+		{"/root/.pyenv/shims/python3", false},
+	}
+
+	for _, test := range tests {
+		if got := isSandboxPath(test.path, sandboxPaths); got != test.want {
+			t.Errorf("isSandboxPath(%s) = %v, want %v", test.path, got, test.want)
+		}
+	}
+}
+
+func TestParseSandboxArgs(t *testing.T) {
+	sandbox, err := parseSandboxArgs([]string{"/tmp/root", "/tmp/work", "31", "1024", "0", "/usr/bin/python3", "-I", "main.py"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &sandboxArgs{rootDir: "/tmp/root", workDir: "/tmp/work", cpuSeconds: 31, maxFileBytes: 1024, memoryBytes: 0, binary: "/usr/bin/python3", args: []string{"-I", "main.py"}}
+	if !reflect.DeepEqual(sandbox, want) {
+		t.Errorf("parseSandboxArgs() = %+v, want %+v", sandbox, want)
+	}
+
+	_, err = parseSandboxArgs([]string{"/tmp/root", "/tmp/work", "31"})
+	if err == nil {
+		t.Errorf("expected an error for missing arguments")
+	}
+	_, err = parseSandboxArgs([]string{"/tmp/root", "/tmp/work", "31", "big", "0", "/usr/bin/python3"})
+	if err == nil {
+		t.Errorf("expected an error for a non-numeric limit")
+	}
+}
+
+func TestGetSandboxLimits(t *testing.T) {
+	limits := getSandboxLimits(31, 1024, 512*1024*1024)
+	want := map[int]uint64{unix.RLIMIT_CPU: 31, unix.RLIMIT_FSIZE: 1024, unix.RLIMIT_NPROC: 64, unix.RLIMIT_AS: 512 * 1024 * 1024}
+	if !reflect.DeepEqual(limits, want) {
+		t.Errorf("getSandboxLimits() = %v, want %v", limits, want)
+	}
+
+	// node reserves far more address space than it uses, it is not limited
+	limits = getSandboxLimits(31, 1024, 0)
+	if _, ok := limits[unix.RLIMIT_AS]; ok {
+		t.Errorf("expected no address space limit, got %v", limits)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !linux
+// +build !linux
+
+package codetools
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+// CheckSandbox tells at startup why the execute_code tool can't run any code on this server.
+func CheckSandbox() error {
+	return fmt.Errorf("the code sandbox is not supported on %s", runtime.GOOS)
+}
+
+func findInterpreter(name string) (string, error) {
+	return exec.LookPath(name)
+}
+
+// runCommand refuses to run the code, the sandbox relies on Linux namespaces.
+func runCommand(ctx context.Context, dir string, binary string, args []string, timeout time.Duration, memoryMb int, maxFileBytes int, isNode bool, stdout io.Writer, stderr io.Writer) error {
+	return fmt.Errorf("the code sandbox is not supported on %s", runtime.GOOS)
+}
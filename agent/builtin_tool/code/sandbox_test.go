@@ -0,0 +1,101 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build skipCi
+// +build skipCi
+
+package codetools
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+// requireSandbox skips the test when the sandbox can't run here: it needs root, python3 readable
+// by nobody and user namespaces allowed to mount.
+func requireSandbox(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("the code sandbox requires root")
+	}
+	if _, err := findInterpreter("python3"); err != nil {
+		t.Skip(err.Error())
+	}
+
+	res, err := (&Sandbox{TimeoutSeconds: 10}).Run(context.Background(), "python", "print(1)", nil)
+	if err != nil {
+		t.Skipf("the code sandbox is not available: %s", err.Error())
+	}
+	if res.ExitCode != 0 {
+		t.Skipf("python3 can't run in the code sandbox: %s", res.Stderr)
+	}
+}
+
+func TestSandboxRun(t *testing.T) {
+	requireSandbox(t)
+
+	sandbox := &Sandbox{TimeoutSeconds: 10}
+	files := []*File{{Name: "data.csv", Data: []byte("a,b\n1,2\n3,4\n")}}
+	code := `
+import csv
+rows = list(csv.DictReader(open("data.csv")))
+total = sum(int(row["a"]) + int(row["b"]) for row in rows)
+print(total)
+open("total.txt", "w").write(str(total))
+`
+
+	res, err := sandbox.Run(context.Background(), "python", code, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.ExitCode != 0 || strings.TrimSpace(res.Stdout) != "10" {
+		t.Fatalf("expected the output 10, got exit code %d, stdout %q, stderr %q", res.ExitCode, res.Stdout, res.Stderr)
+	}
+	if len(res.Files) != 1 || res.Files[0].Name != "total.txt" || string(res.Files[0].Data) != "10" {
+		t.Errorf("expected only the generated file total.txt, got %v", res.Files)
+	}
+}
+
+func TestSandboxNoNetwork(t *testing.T) {
+	requireSandbox(t)
+
+	sandbox := &Sandbox{TimeoutSeconds: 10}
+	code := `
+import socket
+socket.create_connection(("1.1.1.1", 80), 2)
+`
+
+	res, err := sandbox.Run(context.Background(), "python", code, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.ExitCode == 0 || !strings.Contains(res.Stderr, "unreachable") {
+		t.Errorf("expected the connection to fail, got exit code %d, stderr %q", res.ExitCode, res.Stderr)
+	}
+}
+
+func TestSandboxTimeout(t *testing.T) {
+	requireSandbox(t)
+
+	sandbox := &Sandbox{TimeoutSeconds: 1}
+
+	res, err := sandbox.Run(context.Background(), "python", "while True: pass", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.IsTimeout {
+		t.Errorf("expected a timeout, got exit code %d", res.ExitCode)
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knowledgetools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+)
+
+const (
+	defaultKnowledgeCount = 5
+	maxKnowledgeCount     = 20
+)
+
+// KnowledgeChunk is a piece of knowledge found in a store, with the file it comes from.
+type KnowledgeChunk struct {
+	Store string  `json:"store"`
+	File  string  `json:"file"`
+	Text  string  `json:"text"`
+	Score float32 `json:"score"`
+}
+
+// KnowledgeSearcher searches the knowledge of a store for the query.
+type KnowledgeSearcher func(ctx context.Context, store string, query string, knowledgeCount int) ([]*KnowledgeChunk, error)
+
+// KnowledgeSearchTool lets the model search the knowledge bases of the store on demand. The
+// registry holds it without a searcher, the chat binds the stores it can search with WithSearcher.
+type KnowledgeSearchTool struct {
+	Stores []string
+	Search KnowledgeSearcher
+}
+
+// WithSearcher returns the tool bound to the stores it may search, the first one being the default.
+func (t *KnowledgeSearchTool) WithSearcher(stores []string, search KnowledgeSearcher) *KnowledgeSearchTool {
+	return &KnowledgeSearchTool{
+		Stores: stores,
+		Search: search,
+	}
+}
+
+func (t *KnowledgeSearchTool) GetName() string {
+	return "knowledge_search"
+}
+
+func (t *KnowledgeSearchTool) GetDescription() string {
+	return "Search the knowledge bases for text chunks relevant to a query. Use it when the answer needs documents that are not in the conversation yet, possibly several times with refined queries. Returns the matching chunks with their store, source file and similarity score."
+}
+
+func (t *KnowledgeSearchTool) GetInputSchema() interface{} {
+	storeSchema := map[string]interface{}{
+		"type":        "string",
+		"description": "Optional. The store to search. Defaults to the current store.",
+	}
+	if len(t.Stores) > 0 {
+		storeSchema["enum"] = t.Stores
+	}
+
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"query": map[string]interface{}{
+				"type":        "string",
+				"description": "The search query, phrased like the text to find.",
+			},
+			"store": storeSchema,
+			"count": map[string]interface{}{
+				"type":        "integer",
+				"description": fmt.Sprintf("Optional. The number of chunks to return, from 1 to %d. Defaults to %d.", maxKnowledgeCount, defaultKnowledgeCount),
+			},
+		},
+		"required": []string{"query"},
+	}
+}
+
+func getErrorResult(text string) *protocol.CallToolResult {
+	return &protocol.CallToolResult{
+		IsError: true,
+		Content: []protocol.Content{
+			&protocol.TextContent{
+				Type: "text",
+				Text: text,
+			},
+		},
+	}
+}
+
+func (t *KnowledgeSearchTool) Execute(ctx context.Context, arguments map[string]interface{}) (*protocol.CallToolResult, error) {
+	if t.Search == nil || len(t.Stores) == 0 {
+		return getErrorResult("Knowledge search is not available in this chat"), nil
+	}
+
+	query, ok := arguments["query"].(string)
+	if !ok || query == "" {
+		return getErrorResult("Missing required parameter: query"), nil
+	}
+
+	store := t.Stores[0]
+	if s, ok := arguments["store"].(string); ok && s != "" {
+		store = s
+	}
+
+	isAllowed := false
+	for _, s := range t.Stores {
+		if s == store {
+			isAllowed = true
+			break
+		}
+	}
+	if !isAllowed {
+		return getErrorResult(fmt.Sprintf("Invalid store: %s, the available stores are: %v", store, t.Stores)), nil
+	}
+
+	count := defaultKnowledgeCount
+	if c, ok := arguments["count"].(float64); ok && c > 0 {
+		count = int(c)
+	}
+	if count > maxKnowledgeCount {
+		count = maxKnowledgeCount
+	}
+
+	chunks, err := t.Search(ctx, store, query, count)
+	if err != nil {
+		return getErrorResult(fmt.Sprintf("Failed to search the store: %s, %s", store, err.Error())), nil
+	}
+
+	data, err := json.Marshal(chunks)
+	if err != nil {
+		return nil, err
+	}
+
+	return &protocol.CallToolResult{
+		IsError: false,
+		Content: []protocol.Content{
+			&protocol.TextContent{
+				Type: "text",
+				Text: string(data),
+			},
+		},
+	}, nil
+}
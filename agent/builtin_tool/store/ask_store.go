@@ -0,0 +1,117 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storetools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+)
+
+const maxToolNameLength = 64
+
+var invalidToolNameRegex = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// StoreAsker answers the question with the store, using its own knowledge, prompt, model and tools.
+type StoreAsker func(ctx context.Context, store string, question string) (string, error)
+
+// AskStoreTool delegates the questions of the model to another store. Unlike the other builtin
+// tools it is not in the registry, a router store registers one for each of its child stores.
+type AskStoreTool struct {
+	Name        string
+	Store       string
+	Description string
+	Ask         StoreAsker
+}
+
+// GetToolName returns the name of the tool asking the store. The tool names only allow letters,
+// digits, "_" and "-", and "__" separates the MCP server names, so it can't appear in them.
+func GetToolName(store string) string {
+	name := invalidToolNameRegex.ReplaceAllString(store, "_")
+	for strings.Contains(name, "__") {
+		name = strings.ReplaceAll(name, "__", "_")
+	}
+	name = "ask_" + strings.Trim(name, "_")
+	if len(name) > maxToolNameLength {
+		name = name[:maxToolNameLength]
+	}
+	return name
+}
+
+func (t *AskStoreTool) GetName() string {
+	return t.Name
+}
+
+func (t *AskStoreTool) GetDescription() string {
+	description := fmt.Sprintf("Delegate a question to the specialized assistant: %s, which answers it with its own knowledge base and tools. Ask it self-contained questions within its field, one sub-question at a time, and compose its answers.", t.Store)
+	if t.Description != "" {
+		description = fmt.Sprintf("%s About this assistant: %s", description, t.Description)
+	}
+	return description
+}
+
+func (t *AskStoreTool) GetInputSchema() interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"question": map[string]interface{}{
+				"type":        "string",
+				"description": "The question to answer, including the context the assistant needs as it does not see the conversation.",
+			},
+		},
+		"required": []string{"question"},
+	}
+}
+
+func getErrorResult(text string) *protocol.CallToolResult {
+	return &protocol.CallToolResult{
+		IsError: true,
+		Content: []protocol.Content{
+			&protocol.TextContent{
+				Type: "text",
+				Text: text,
+			},
+		},
+	}
+}
+
+func (t *AskStoreTool) Execute(ctx context.Context, arguments map[string]interface{}) (*protocol.CallToolResult, error) {
+	if t.Ask == nil {
+		return getErrorResult("Delegation is not available in this chat"), nil
+	}
+
+	question, ok := arguments["question"].(string)
+	if !ok || question == "" {
+		return getErrorResult("Missing required parameter: question"), nil
+	}
+
+	answer, err := t.Ask(ctx, t.Store, question)
+	if err != nil {
+		return getErrorResult(fmt.Sprintf("Failed to ask the store: %s, %s", t.Store, err.Error())), nil
+	}
+
+	return &protocol.CallToolResult{
+		IsError: false,
+		Content: []protocol.Content{
+			&protocol.TextContent{
+				Type: "text",
+				Text: answer,
+			},
+		},
+	}, nil
+}
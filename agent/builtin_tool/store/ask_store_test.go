@@ -0,0 +1,63 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build skipCi
+// +build skipCi
+
+package storetools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+)
+
+func TestGetToolName(t *testing.T) {
+	tests := map[string]string{
+		"store-built-in":         "ask_store-built-in",
+		"法律 Store":               "ask_Store",
+		"a__b":                   "ask_a_b",
+		strings.Repeat("x", 100): "ask_" + strings.Repeat("x", 60),
+	}
+	for store, expected := range tests {
+		if name := GetToolName(store); name != expected {
+			t.Errorf("GetToolName(%q) = %q, expected %q", store, name, expected)
+		}
+	}
+}
+
+func TestAskStoreTool(t *testing.T) {
+	tool := &AskStoreTool{
+		Name:  GetToolName("law"),
+		Store: "law",
+		Ask: func(ctx context.Context, store string, question string) (string, error) {
+			return store + ": " + question, nil
+		},
+	}
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"question": "hi"})
+	if err != nil || result.IsError {
+		t.Fatalf("expected the question to be answered, got %v", err)
+	}
+	if text := result.Content[0].(*protocol.TextContent).Text; text != "law: hi" {
+		t.Errorf("unexpected answer: %s", text)
+	}
+
+	result, _ = tool.Execute(context.Background(), map[string]interface{}{})
+	if !result.IsError {
+		t.Errorf("expected an error without question")
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webtools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+)
+
+var allowedHttpMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// the headers the model can't set, they would leak or override the request's own ones
+var deniedHttpHeaders = map[string]bool{
+	"host": true, "cookie": true, "content-length": true, "transfer-encoding": true, "connection": true,
+	"proxy-authorization": true, "x-forwarded-for": true, "x-real-ip": true,
+}
+
+type HttpResponse struct {
+	Status      int         `json:"status"`
+	ContentType string      `json:"contentType"`
+	Body        interface{} `json:"body"`
+	IsTruncated bool        `json:"isTruncated,omitempty"`
+}
+
+// HttpRequestTool sends JSON HTTP requests to APIs.
+type HttpRequestTool struct {
+	Policy *WebPolicy
+}
+
+func (t *HttpRequestTool) WithPolicy(policy *WebPolicy) *HttpRequestTool {
+	return &HttpRequestTool{
+		Policy: policy,
+	}
+}
+
+func (t *HttpRequestTool) GetName() string {
+	return "http_request"
+}
+
+func (t *HttpRequestTool) GetDescription() string {
+	return "Send an HTTP request to a JSON API and return the status and the response body, parsed when it is JSON."
+}
+
+func (t *HttpRequestTool) GetInputSchema() interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"method": map[string]interface{}{
+				"type":        "string",
+				"enum":        allowedHttpMethods,
+				"description": "The HTTP method. Defaults to GET.",
+			},
+			"url": map[string]interface{}{
+				"type":        "string",
+				"description": "The http or https URL, including the query string.",
+			},
+			"headers": map[string]interface{}{
+				"type":                 "object",
+				"description":          "Optional. The request headers.",
+				"additionalProperties": map[string]interface{}{"type": "string"},
+			},
+			"body": map[string]interface{}{
+				"description": "Optional. The request body, sent as JSON.",
+			},
+			"maxLength": map[string]interface{}{
+				"type":        "integer",
+				"description": fmt.Sprintf("Optional. The maximum number of characters of the body to return. Defaults to %d.", defaultMaxResultLength),
+			},
+		},
+		"required": []string{"url"},
+	}
+}
+
+func (t *HttpRequestTool) Execute(ctx context.Context, arguments map[string]interface{}) (*protocol.CallToolResult, error) {
+	rawUrl, ok := arguments["url"].(string)
+	if !ok || rawUrl == "" {
+		return getTextResult("Missing required parameter: url", true), nil
+	}
+
+	u, err := t.Policy.parseUrl(rawUrl)
+	if err != nil {
+		return getTextResult(fmt.Sprintf("Invalid URL: %s", err.Error()), true), nil
+	}
+
+	method := http.MethodGet
+	if m, ok := arguments["method"].(string); ok && m != "" {
+		method = strings.ToUpper(m)
+	}
+	isAllowed := false
+	for _, allowedMethod := range allowedHttpMethods {
+		if method == allowedMethod {
+			isAllowed = true
+			break
+		}
+	}
+	if !isAllowed {
+		return getTextResult(fmt.Sprintf("The method: %s is not supported", method), true), nil
+	}
+
+	var body io.Reader
+	if b, ok := arguments["body"]; ok && b != nil {
+		var data []byte
+		if s, ok := b.(string); ok {
+			data = []byte(s)
+		} else {
+			data, err = json.Marshal(b)
+			if err != nil {
+				return getTextResult(fmt.Sprintf("Invalid body: %s", err.Error()), true), nil
+			}
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, u.String(), body)
+	if err != nil {
+		return getTextResult(err.Error(), true), nil
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if headers, ok := arguments["headers"].(map[string]interface{}); ok {
+		for key, value := range headers {
+			if deniedHttpHeaders[strings.ToLower(key)] {
+				continue
+			}
+			req.Header.Set(key, fmt.Sprintf("%v", value))
+		}
+	}
+
+	resp, data, isTruncated, err := t.Policy.do(ctx, req)
+	if err != nil {
+		return getTextResult(fmt.Sprintf("Failed to send the request: %s", err.Error()), true), nil
+	}
+
+	res := &HttpResponse{
+		Status:      resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+		IsTruncated: isTruncated,
+	}
+
+	var jsonBody interface{}
+	if !isTruncated && len(data) <= getMaxLength(arguments) && json.Unmarshal(data, &jsonBody) == nil {
+		res.Body = jsonBody
+	} else {
+		text, isCut := truncateText(string(data), getMaxLength(arguments))
+		res.Body = text
+		res.IsTruncated = isTruncated || isCut
+	}
+
+	result, err := json.Marshal(res)
+	if err != nil {
+		return nil, err
+	}
+	return getTextResult(string(result), resp.StatusCode >= 400), nil
+}
@@ -0,0 +1,172 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webtools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/casibase/casibase/proxy"
+)
+
+const (
+	defaultMaxResponseBytes = 2 * 1024 * 1024
+	defaultMaxResultLength  = 20000
+	requestTimeout          = 30 * time.Second
+	maxRedirects            = 5
+)
+
+// WebPolicy restricts the URLs the web tools can reach. A domain matches itself and its
+// subdomains, when AllowedDomains is not empty only the matching domains can be reached.
+type WebPolicy struct {
+	AllowedDomains   []string
+	DeniedDomains    []string
+	MaxResponseBytes int
+}
+
+func (p *WebPolicy) getMaxResponseBytes() int {
+	if p == nil || p.MaxResponseBytes <= 0 {
+		return defaultMaxResponseBytes
+	}
+	return p.MaxResponseBytes
+}
+
+func isDomainMatched(host string, domains []string) bool {
+	for _, domain := range domains {
+		domain = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(domain), "*."))
+		if domain == "" {
+			continue
+		}
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckUrl returns an error when the URL is not an HTTP(S) URL allowed by the policy.
+func (p *WebPolicy) CheckUrl(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("the URL scheme: %s is not supported, use http or https", u.Scheme)
+	}
+	if u.User != nil {
+		return fmt.Errorf("the URL must not contain credentials")
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "" {
+		return fmt.Errorf("the URL: %s has no host", u.String())
+	}
+	if p == nil {
+		return nil
+	}
+
+	if isDomainMatched(host, p.DeniedDomains) {
+		return fmt.Errorf("the domain: %s is denied", host)
+	}
+	if len(p.AllowedDomains) > 0 && !isDomainMatched(host, p.AllowedDomains) {
+		return fmt.Errorf("the domain: %s is not in the allowed domains: %s", host, strings.Join(p.AllowedDomains, ", "))
+	}
+	return nil
+}
+
+// getHttpClient returns the client of proxy.GetSafeHttpClient, checking the policy on redirects too.
+func (p *WebPolicy) getHttpClient(rawUrl string) *http.Client {
+	client := proxy.GetSafeHttpClient(rawUrl, requestTimeout)
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		return p.CheckUrl(req.URL)
+	}
+	return client
+}
+
+func (p *WebPolicy) parseUrl(rawUrl string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(rawUrl))
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.CheckUrl(u)
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// do sends the request and reads at most MaxResponseBytes of the response body.
+func (p *WebPolicy) do(ctx context.Context, req *http.Request) (*http.Response, []byte, bool, error) {
+	req = req.WithContext(ctx)
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", "Casibase")
+	}
+
+	resp, err := p.getHttpClient(req.URL.String()).Do(req)
+	if err != nil {
+		var urlError *url.Error
+		if errors.As(err, &urlError) {
+			err = urlError.Err
+		}
+		return nil, nil, false, err
+	}
+	defer resp.Body.Close()
+
+	limit := p.getMaxResponseBytes()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, int64(limit)+1))
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	isTruncated := len(data) > limit
+	if isTruncated {
+		data = data[:limit]
+	}
+	return resp, data, isTruncated, nil
+}
+
+func getTextResult(text string, isError bool) *protocol.CallToolResult {
+	return &protocol.CallToolResult{
+		IsError: isError,
+		Content: []protocol.Content{
+			&protocol.TextContent{
+				Type: "text",
+				Text: text,
+			},
+		},
+	}
+}
+
+func truncateText(text string, maxLength int) (string, bool) {
+	runes := []rune(text)
+	if len(runes) <= maxLength {
+		return text, false
+	}
+	return string(runes[:maxLength]), true
+}
+
+func getMaxLength(arguments map[string]interface{}) int {
+	if length, ok := arguments["maxLength"].(float64); ok && length > 0 && int(length) < defaultMaxResultLength {
+		return int(length)
+	}
+	return defaultMaxResultLength
+}
@@ -0,0 +1,160 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webtools
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/casibase/casibase/txt"
+)
+
+// WebFetchTool fetches a web page or document and returns it as Markdown.
+type WebFetchTool struct {
+	Policy *WebPolicy
+	Lang   string
+}
+
+func (t *WebFetchTool) WithPolicy(policy *WebPolicy, lang string) *WebFetchTool {
+	return &WebFetchTool{
+		Policy: policy,
+		Lang:   lang,
+	}
+}
+
+func (t *WebFetchTool) GetName() string {
+	return "web_fetch"
+}
+
+func (t *WebFetchTool) GetDescription() string {
+	return "Fetch a web page or an online document (HTML, PDF, DOCX, XLSX, PPTX, CSV or text) by its URL and return its content as Markdown. Use it to read the URLs given by the user."
+}
+
+func (t *WebFetchTool) GetInputSchema() interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"url": map[string]interface{}{
+				"type":        "string",
+				"description": "The http or https URL to fetch.",
+			},
+			"maxLength": map[string]interface{}{
+				"type":        "integer",
+				"description": fmt.Sprintf("Optional. The maximum number of characters to return. Defaults to %d.", defaultMaxResultLength),
+			},
+		},
+		"required": []string{"url"},
+	}
+}
+
+// getFileExtension returns the extension of the txt parser for the response.
+func getFileExtension(contentType string, urlPath string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "text/html", "application/xhtml+xml":
+		return ".html"
+	case "application/pdf":
+		return ".pdf"
+	case "application/vnd.openxmlformats-officedocument.wordprocessingml.document":
+		return ".docx"
+	case "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":
+		return ".xlsx"
+	case "application/vnd.openxmlformats-officedocument.presentationml.presentation":
+		return ".pptx"
+	case "text/csv":
+		return ".csv"
+	}
+
+	ext := strings.ToLower(path.Ext(urlPath))
+	for _, supportedExt := range append(txt.GetSupportedFileTypes(), ".html", ".htm") {
+		if ext == supportedExt {
+			return ext
+		}
+	}
+
+	if strings.HasPrefix(mediaType, "text/") || mediaType == "application/json" || mediaType == "application/xml" {
+		return ".txt"
+	}
+	return ""
+}
+
+func (t *WebFetchTool) Execute(ctx context.Context, arguments map[string]interface{}) (*protocol.CallToolResult, error) {
+	rawUrl, ok := arguments["url"].(string)
+	if !ok || rawUrl == "" {
+		return getTextResult("Missing required parameter: url", true), nil
+	}
+
+	u, err := t.Policy.parseUrl(rawUrl)
+	if err != nil {
+		return getTextResult(fmt.Sprintf("Invalid URL: %s", err.Error()), true), nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return getTextResult(err.Error(), true), nil
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,text/plain,application/pdf,*/*;q=0.8")
+
+	resp, data, isTruncated, err := t.Policy.do(ctx, req)
+	if err != nil {
+		return getTextResult(fmt.Sprintf("Failed to fetch the URL: %s", err.Error()), true), nil
+	}
+	if resp.StatusCode >= 400 {
+		return getTextResult(fmt.Sprintf("Failed to fetch the URL, status: %s", resp.Status), true), nil
+	}
+
+	ext := getFileExtension(resp.Header.Get("Content-Type"), resp.Request.URL.Path)
+	if ext == "" {
+		return getTextResult(fmt.Sprintf("The content type: %s is not supported", resp.Header.Get("Content-Type")), true), nil
+	}
+	if isTruncated && ext != ".html" && ext != ".txt" && ext != ".md" && ext != ".csv" {
+		return getTextResult(fmt.Sprintf("The document is larger than %d bytes", t.Policy.getMaxResponseBytes()), true), nil
+	}
+
+	file, err := os.CreateTemp("", "casibase-web-*"+ext)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	lang := t.Lang
+	if lang == "" {
+		lang = "en"
+	}
+	text, err := txt.GetParsedTextFromUrl(file.Name(), ext, lang)
+	if err != nil {
+		return getTextResult(fmt.Sprintf("Failed to parse the content: %s", err.Error()), true), nil
+	}
+
+	text, isCut := truncateText(text, getMaxLength(arguments))
+	if isTruncated || isCut {
+		text += "\n\n[content truncated]"
+	}
+	return getTextResult(fmt.Sprintf("URL: %s\n\n%s", resp.Request.URL.String(), text), false), nil
+}
@@ -23,14 +23,16 @@ import (
 type McpAgentProvider struct {
 	Typ        string
 	SubType    string
+	ProviderId string
 	McpServers string
 	McpTools   []*McpTools
 }
 
-func NewMcpAgentProvider(typ string, subType string, mcpServers string, mcpTools []*McpTools) (*McpAgentProvider, error) {
+func NewMcpAgentProvider(typ string, subType string, providerId string, mcpServers string, mcpTools []*McpTools) (*McpAgentProvider, error) {
 	p := &McpAgentProvider{
 		Typ:        typ,
 		SubType:    subType,
+		ProviderId: providerId,
 		McpServers: mcpServers,
 		McpTools:   mcpTools,
 	}
@@ -42,27 +44,41 @@ func (p *McpAgentProvider) GetAgentClients() (*AgentClients, error) {
 	for _, tool := range p.McpTools {
 		toolsMap[tool.ServerName] = tool.IsEnabled
 	}
-	clients, err := GetMCPClientMap(p.McpServers, toolsMap)
+	clients, err := getPooledMCPClientMap(p.ProviderId, p.McpServers, toolsMap)
 	if err != nil {
 		return nil, err
 	}
 	var tools []*protocol.Tool
+	toolPolicies := map[string]string{}
 	for _, mcpTool := range p.McpTools {
 		if !mcpTool.IsEnabled {
 			continue
 		}
+		// the tools of the servers that could not be connected are not offered to the model
+		if _, ok := clients[mcpTool.ServerName]; !ok {
+			continue
+		}
 		toolsStr := mcpTool.Tools
 		var toolsList []*protocol.Tool
 		if err := json.Unmarshal([]byte(toolsStr), &toolsList); err != nil {
 			return nil, err
 		}
 		for _, tool := range toolsList {
+			policy := mcpTool.ToolPolicies[tool.Name]
 			tool.Name = GetIdFromServerNameAndToolName(mcpTool.ServerName, tool.Name)
+			if policy != "" {
+				toolPolicies[tool.Name] = policy
+			}
+
+			// denied tools are never offered to the model, and refused if it calls them anyway
+			if policy != ToolPolicyDeny {
+				tools = append(tools, tool)
+			}
 		}
-		tools = append(tools, toolsList...)
 	}
 	return &AgentClients{
-		Clients: clients,
-		Tools:   tools,
+		Clients:      clients,
+		Tools:        tools,
+		ToolPolicies: toolPolicies,
 	}, nil
 }
@@ -0,0 +1,326 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// OAuthConfig authorizes the requests to an MCP server with OAuth 2.1 access tokens, obtained
+// with the refresh token when there is one, or with the client credentials grant. When TokenUrl
+// is empty, the token endpoint is discovered from the server's authorization server metadata.
+// The authorization code grant needs the interactive login of a user, its refresh token is set
+// here once obtained, the rotated refresh tokens are then saved by SaveRotatedTokens.
+type OAuthConfig struct {
+	TokenUrl     string   `json:"tokenUrl,omitempty"`
+	ClientId     string   `json:"clientId"`
+	ClientSecret string   `json:"clientSecret,omitempty"`
+	Scopes       []string `json:"scopes,omitempty"`
+	RefreshToken string   `json:"refreshToken,omitempty"`
+}
+
+// SaveRotatedTokens persists the refresh tokens rotated by the authorization servers of the MCP
+// servers of a provider, see GetConfigWithRotatedTokens, the previous ones being revoked.
+var SaveRotatedTokens func(providerId string) error
+
+// tokenSourceEntry is the token source of a server, shared by its clients so the access tokens
+// are reused until they expire. The entry is replaced when the OAuth config of the server changes,
+// the refresh tokens it rotated being the same config.
+type tokenSourceEntry struct {
+	configHash    string
+	refreshTokens map[string]bool
+	lastToken     string
+	tokenSource   oauth2.TokenSource
+}
+
+// the token sources by provider and server name
+var (
+	tokenSources     = map[string]*tokenSourceEntry{}
+	tokenSourcesLock sync.Mutex
+)
+
+type mcpRoundTripper struct {
+	base        http.RoundTripper
+	headers     map[string]string
+	bearerToken string
+	tokenSource oauth2.TokenSource
+}
+
+func (t *mcpRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+
+	if t.tokenSource != nil {
+		token, err := t.tokenSource.Token()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the OAuth access token: %w", err)
+		}
+		token.SetAuthHeader(req)
+	} else if t.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+t.bearerToken)
+	}
+
+	return t.base.RoundTrip(req)
+}
+
+// getMcpHttpClient returns the HTTP client sending the headers and the credentials of the server.
+func getMcpHttpClient(srv ServerConfig, headers map[string]string) *http.Client {
+	if len(headers) == 0 && srv.BearerToken == "" && srv.OAuth == nil {
+		return http.DefaultClient
+	}
+
+	var tokenSource oauth2.TokenSource
+	if srv.OAuth != nil {
+		tokenSource = getTokenSource(srv)
+	}
+
+	return &http.Client{
+		Transport: &mcpRoundTripper{
+			base:        http.DefaultTransport,
+			headers:     headers,
+			bearerToken: srv.BearerToken,
+			tokenSource: tokenSource,
+		},
+	}
+}
+
+func getTokenSourceKey(providerId string, serverName string, serverUrl string) string {
+	if providerId == "" {
+		return serverUrl
+	}
+	return fmt.Sprintf("%s/%s", providerId, serverName)
+}
+
+// getOAuthConfigHash hashes the OAuth config of the server but its refresh token, which is rotated.
+func getOAuthConfigHash(serverUrl string, config *OAuthConfig) string {
+	data := strings.Join([]string{serverUrl, config.TokenUrl, config.ClientId, config.ClientSecret, strings.Join(config.Scopes, " ")}, "\n")
+	hash := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(hash[:])
+}
+
+func getTokenSource(srv ServerConfig) oauth2.TokenSource {
+	key := getTokenSourceKey(srv.providerId, srv.serverName, srv.URL)
+	configHash := getOAuthConfigHash(srv.URL, srv.OAuth)
+
+	tokenSourcesLock.Lock()
+	defer tokenSourcesLock.Unlock()
+
+	entry, ok := tokenSources[key]
+	if ok && entry.configHash == configHash && (srv.OAuth.RefreshToken == "" || entry.refreshTokens[srv.OAuth.RefreshToken]) {
+		return entry.tokenSource
+	}
+
+	entry = &tokenSourceEntry{
+		configHash:    configHash,
+		refreshTokens: map[string]bool{},
+		lastToken:     srv.OAuth.RefreshToken,
+	}
+	if srv.OAuth.RefreshToken != "" {
+		entry.refreshTokens[srv.OAuth.RefreshToken] = true
+	}
+	entry.tokenSource = oauth2.ReuseTokenSource(nil, &lazyTokenSource{
+		key:        key,
+		providerId: srv.providerId,
+		serverUrl:  srv.URL,
+		config:     srv.OAuth,
+		entry:      entry,
+	})
+	tokenSources[key] = entry
+	return entry.tokenSource
+}
+
+// GetConfigWithRotatedTokens replaces the refresh tokens of the provider's MCP config that were
+// rotated since, so that saving a config read before the rotation doesn't restore revoked tokens.
+func GetConfigWithRotatedTokens(providerId string, config string) (string, error) {
+	var outer map[string]interface{}
+	err := json.Unmarshal([]byte(config), &outer)
+	if err != nil {
+		return "", err
+	}
+	servers, ok := outer["mcpServers"].(map[string]interface{})
+	if !ok {
+		return config, nil
+	}
+
+	isChanged := false
+	tokenSourcesLock.Lock()
+	for name, server := range servers {
+		serverMap, ok := server.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		oauthMap, ok := serverMap["oauth"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		refreshToken, ok := oauthMap["refreshToken"].(string)
+		if !ok || refreshToken == "" {
+			continue
+		}
+
+		entry, ok := tokenSources[getTokenSourceKey(providerId, name, "")]
+		if ok && entry.refreshTokens[refreshToken] && entry.lastToken != refreshToken {
+			oauthMap["refreshToken"] = entry.lastToken
+			isChanged = true
+		}
+	}
+	tokenSourcesLock.Unlock()
+
+	if !isChanged {
+		return config, nil
+	}
+
+	data, err := json.MarshalIndent(outer, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// lazyTokenSource discovers the token endpoint on the first token request, so the discovery
+// failures are reported, and retried, like the token failures.
+type lazyTokenSource struct {
+	key        string
+	providerId string
+	serverUrl  string
+	config     *OAuthConfig
+	entry      *tokenSourceEntry
+
+	lock        sync.Mutex
+	tokenSource oauth2.TokenSource
+}
+
+func (s *lazyTokenSource) Token() (*oauth2.Token, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.tokenSource == nil {
+		tokenUrl := s.config.TokenUrl
+		if tokenUrl == "" {
+			var err error
+			tokenUrl, err = discoverTokenUrl(s.serverUrl)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		ctx := context.Background()
+		if s.config.RefreshToken != "" {
+			config := &oauth2.Config{
+				ClientID:     s.config.ClientId,
+				ClientSecret: s.config.ClientSecret,
+				Endpoint:     oauth2.Endpoint{TokenURL: tokenUrl},
+				Scopes:       s.config.Scopes,
+			}
+			s.tokenSource = config.TokenSource(ctx, &oauth2.Token{RefreshToken: s.config.RefreshToken})
+		} else {
+			config := &clientcredentials.Config{
+				ClientID:     s.config.ClientId,
+				ClientSecret: s.config.ClientSecret,
+				TokenURL:     tokenUrl,
+				Scopes:       s.config.Scopes,
+			}
+			s.tokenSource = config.TokenSource(ctx)
+		}
+	}
+
+	token, err := s.tokenSource.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	if token.RefreshToken != "" {
+		s.saveRefreshToken(token.RefreshToken)
+	}
+	return token, nil
+}
+
+// saveRefreshToken records the refresh token rotated by the authorization server and saves it,
+// the server revoking the previous one.
+func (s *lazyTokenSource) saveRefreshToken(refreshToken string) {
+	tokenSourcesLock.Lock()
+	if s.entry.lastToken == refreshToken {
+		tokenSourcesLock.Unlock()
+		return
+	}
+	s.entry.refreshTokens[refreshToken] = true
+	s.entry.lastToken = refreshToken
+	tokenSourcesLock.Unlock()
+
+	if SaveRotatedTokens == nil || s.providerId == "" {
+		return
+	}
+
+	err := SaveRotatedTokens(s.providerId)
+	if err != nil {
+		fmt.Printf("saveRefreshToken() error, failed to save the rotated refresh token of the MCP server: %s, %s\n", s.key, err.Error())
+	}
+}
+
+// discoverTokenUrl returns the token endpoint of the authorization server metadata (RFC 8414)
+// at the server's origin, or the default "/token" endpoint when the server has no metadata.
+func discoverTokenUrl(serverUrl string) (string, error) {
+	u, err := url.Parse(serverUrl)
+	if err != nil {
+		return "", err
+	}
+	origin := fmt.Sprintf("%s://%s", u.Scheme, u.Host)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	req, err := http.NewRequest(http.MethodGet, origin+"/.well-known/oauth-authorization-server", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("MCP-Protocol-Version", "2025-03-26")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to discover the OAuth metadata of the MCP server: %s, %w", origin, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return origin + "/token", nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to discover the OAuth metadata of the MCP server: %s, status: %s", origin, resp.Status)
+	}
+
+	var metadata struct {
+		TokenEndpoint string `json:"token_endpoint"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&metadata)
+	if err != nil {
+		return "", err
+	}
+	if metadata.TokenEndpoint == "" {
+		return origin + "/token", nil
+	}
+	return metadata.TokenEndpoint, nil
+}
@@ -0,0 +1,140 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build skipCi
+// +build skipCi
+
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMcpHttpClientOAuth(t *testing.T) {
+	tokenCount := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/oauth-authorization-server", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"token_endpoint":"http://` + r.Host + `/oauth/token"}`))
+	})
+	mux.HandleFunc("/oauth/token", func(w http.ResponseWriter, r *http.Request) {
+		tokenCount++
+		if r.FormValue("grant_type") != "client_credentials" {
+			t.Errorf("unexpected grant type: %s", r.FormValue("grant_type"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"token123","token_type":"Bearer","expires_in":3600}`))
+	})
+	mux.HandleFunc("/mcp", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token123" || r.Header.Get("X-Tenant") != "casibase" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	srv := ServerConfig{
+		URL:   server.URL + "/mcp",
+		OAuth: &OAuthConfig{ClientId: "id", ClientSecret: "secret"},
+	}
+	client := getMcpHttpClient(srv, map[string]string{"X-Tenant": "casibase"})
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected the request to be authorized, got status: %s", resp.Status)
+		}
+	}
+
+	if tokenCount != 1 {
+		t.Errorf("expected the access token to be reused, got %d token requests", tokenCount)
+	}
+}
+
+func TestGetMCPClientMapSkipsUnreachableServers(t *testing.T) {
+	config := `{"mcpServers": {"broken": {"type": "streamablehttp", "url": "http://127.0.0.1:1/mcp"}}}`
+	_, err := GetMCPClientMap("", config, nil)
+	if err == nil {
+		t.Errorf("expected an error when no server is reachable")
+	}
+}
+
+func TestMcpHttpClientRotatedRefreshToken(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("grant_type") != "refresh_token" || r.FormValue("refresh_token") != "refresh1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"token123","token_type":"Bearer","expires_in":3600,"refresh_token":"refresh2"}`))
+	})
+	mux.HandleFunc("/mcp", func(w http.ResponseWriter, r *http.Request) {})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	savedProviderIds := []string{}
+	SaveRotatedTokens = func(providerId string) error {
+		savedProviderIds = append(savedProviderIds, providerId)
+		return nil
+	}
+	defer func() {
+		SaveRotatedTokens = nil
+	}()
+
+	srv := ServerConfig{
+		URL:        server.URL + "/mcp",
+		OAuth:      &OAuthConfig{TokenUrl: server.URL + "/oauth/token", ClientId: "id", RefreshToken: "refresh1"},
+		providerId: "admin/provider_mcp",
+		serverName: "server",
+	}
+	resp, err := getMcpHttpClient(srv, nil).Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if len(savedProviderIds) != 1 || savedProviderIds[0] != "admin/provider_mcp" {
+		t.Fatalf("expected the rotated refresh token to be saved once, got %v", savedProviderIds)
+	}
+
+	config := `{"mcpServers": {"server": {"url": "` + srv.URL + `", "oauth": {"clientId": "id", "refreshToken": "refresh1"}}}}`
+	config, err = GetConfigWithRotatedTokens(srv.providerId, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverConfigs, err := GetServerConfigs(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refreshToken := serverConfigs["server"].OAuth.RefreshToken; refreshToken != "refresh2" {
+		t.Errorf("expected the rotated refresh token in the config, got %s", refreshToken)
+	}
+
+	// the config saved with the rotated token keeps the token source, a new client replaces it
+	tokenSource := getTokenSource(srv)
+	srv.OAuth = &OAuthConfig{TokenUrl: srv.OAuth.TokenUrl, ClientId: "id", RefreshToken: "refresh2"}
+	if getTokenSource(srv) != tokenSource {
+		t.Errorf("expected the token source to be kept for the rotated refresh token")
+	}
+	srv.OAuth = &OAuthConfig{TokenUrl: srv.OAuth.TokenUrl, ClientId: "id2", RefreshToken: "refresh2"}
+	if getTokenSource(srv) == tokenSource || len(tokenSources) == 0 || tokenSources["admin/provider_mcp/server"].tokenSource == tokenSource {
+		t.Errorf("expected the token source to be replaced for the new client")
+	}
+}
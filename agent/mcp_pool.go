@@ -0,0 +1,436 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ThinkInAIXYZ/go-mcp/client"
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/casibase/casibase/conf"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	mcpHealthCheckInterval      = 30 * time.Second
+	mcpPingTimeout              = 5 * time.Second
+	defaultMcpIdleMinutes       = 10
+	defaultMcpMaxStdioProcesses = 10
+)
+
+var (
+	McpToolCallCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "casibase_mcp_tool_calls",
+		Help: "The MCP tool calls by status: success, error (the tool returned an error) or failure (the call failed)",
+	}, []string{"server", "tool", "status"})
+
+	McpToolCallLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "casibase_mcp_tool_call_latency",
+		Help: "MCP tool call latency in milliseconds",
+	}, []string{"server", "tool"})
+
+	McpPoolClients = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "casibase_mcp_pool_clients",
+		Help: "The connected MCP clients of the pool by transport type",
+	}, []string{"type"})
+)
+
+type mcpPoolEntry struct {
+	providerId    string
+	serverName    string
+	config        ServerConfig
+	transportType string
+	client        *client.Client
+	lastUsedTime  time.Time
+	isChecking    bool
+}
+
+// McpClientPool keeps the connections to the MCP servers of the agent providers across the
+// answers. The clients are pinged periodically and reconnected when they fail, which restarts
+// the crashed stdio servers, and closed after being idle for mcpIdleMinutes. At most
+// mcpMaxStdioProcesses stdio servers run at the same time, the unused clients being evicted
+// to start new ones, see evictStdioEntry.
+//
+// The answers acquire the clients with GetClient and release them with ReleaseClient. A client
+// replaced or evicted while answers still use it is retired, and closed on its last release.
+type McpClientPool struct {
+	lock              sync.Mutex
+	entries           map[string]*mcpPoolEntry
+	useCounts         map[*client.Client]int
+	retiredClients    map[*client.Client]string
+	stdioCount        int
+	maxStdioProcesses int
+	idleTimeout       time.Duration
+	startOnce         sync.Once
+}
+
+var mcpClientPool = newMcpClientPool()
+
+func newMcpClientPool() *McpClientPool {
+	maxStdioProcesses := conf.GetConfigInt("mcpMaxStdioProcesses")
+	if maxStdioProcesses <= 0 {
+		maxStdioProcesses = defaultMcpMaxStdioProcesses
+	}
+
+	idleMinutes := conf.GetConfigInt("mcpIdleMinutes")
+	if idleMinutes <= 0 {
+		idleMinutes = defaultMcpIdleMinutes
+	}
+
+	return &McpClientPool{
+		entries:           map[string]*mcpPoolEntry{},
+		useCounts:         map[*client.Client]int{},
+		retiredClients:    map[*client.Client]string{},
+		maxStdioProcesses: maxStdioProcesses,
+		idleTimeout:       time.Duration(idleMinutes) * time.Minute,
+	}
+}
+
+// getMcpPoolKey returns the key of a server of a provider, the key changes with the server's config
+// so the edited servers get new clients and the old ones are evicted when idle.
+func getMcpPoolKey(providerId string, serverName string, srv ServerConfig) string {
+	data, err := json.Marshal(srv)
+	if err != nil {
+		data = []byte(srv.URL + srv.Command)
+	}
+	hash := md5.Sum(data)
+	return fmt.Sprintf("%s/%s/%s", providerId, serverName, hex.EncodeToString(hash[:]))
+}
+
+func getTransportType(srv ServerConfig) string {
+	if srv.Type != "" {
+		return srv.Type
+	}
+	if srv.URL != "" {
+		return "sse"
+	}
+	return "stdio"
+}
+
+func (pool *McpClientPool) updateClientCount() {
+	counts := map[string]int{"sse": 0, "stdio": 0, "streamablehttp": 0}
+	for _, entry := range pool.entries {
+		counts[entry.transportType]++
+	}
+	for transportType, count := range counts {
+		McpPoolClients.WithLabelValues(transportType).Set(float64(count))
+	}
+}
+
+// GetClient returns the pooled client of the server of the provider, connecting to it if needed.
+// The caller releases the client with ReleaseClient when it is done with it.
+func (pool *McpClientPool) GetClient(providerId string, serverName string, srv ServerConfig) (*client.Client, error) {
+	pool.startOnce.Do(func() {
+		go pool.runHealthChecks()
+	})
+
+	key := getMcpPoolKey(providerId, serverName, srv)
+
+	pool.lock.Lock()
+	if entry, ok := pool.entries[key]; ok {
+		entry.lastUsedTime = time.Now()
+		pool.useCounts[entry.client]++
+		pool.lock.Unlock()
+		return entry.client, nil
+	}
+
+	transportType := getTransportType(srv)
+	if transportType == "stdio" {
+		if pool.stdioCount >= pool.maxStdioProcesses && !pool.evictStdioEntry(key, providerId, serverName) {
+			pool.lock.Unlock()
+			return nil, fmt.Errorf("the MCP server: %s can't be started, the %d stdio MCP servers allowed are all running", serverName, pool.maxStdioProcesses)
+		}
+		// reserve the process while connecting
+		pool.stdioCount++
+	}
+	pool.lock.Unlock()
+
+	cli, err := createMCPClientWithRetry(srv)
+
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	if err != nil {
+		if transportType == "stdio" {
+			pool.stdioCount--
+		}
+		return nil, err
+	}
+
+	// another answer connected to the server meanwhile
+	if entry, ok := pool.entries[key]; ok {
+		cli.Close()
+		if transportType == "stdio" {
+			pool.stdioCount--
+		}
+		entry.lastUsedTime = time.Now()
+		pool.useCounts[entry.client]++
+		return entry.client, nil
+	}
+
+	pool.useCounts[cli]++
+	pool.entries[key] = &mcpPoolEntry{
+		providerId:    providerId,
+		serverName:    serverName,
+		config:        srv,
+		transportType: transportType,
+		client:        cli,
+		lastUsedTime:  time.Now(),
+	}
+	pool.updateClientCount()
+	return cli, nil
+}
+
+// ReleaseClient releases a client returned by GetClient, closing it if it was retired meanwhile.
+func (pool *McpClientPool) ReleaseClient(cli *client.Client) {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	if pool.useCounts[cli] <= 0 {
+		return
+	}
+
+	pool.useCounts[cli]--
+	if pool.useCounts[cli] > 0 {
+		return
+	}
+	delete(pool.useCounts, cli)
+
+	if transportType, ok := pool.retiredClients[cli]; ok {
+		delete(pool.retiredClients, cli)
+		pool.closeClient(cli, transportType)
+		return
+	}
+
+	// the client is idle from its last release, not from its last acquisition
+	for _, entry := range pool.entries {
+		if entry.client == cli {
+			entry.lastUsedTime = time.Now()
+		}
+	}
+}
+
+// closeClient closes the client and frees its stdio process, the caller holds the lock.
+func (pool *McpClientPool) closeClient(cli *client.Client, transportType string) {
+	if transportType == "stdio" {
+		pool.stdioCount--
+	}
+	cli.Close()
+}
+
+// retireClient closes the client if no answer uses it, or else lets its last release close it.
+// The caller holds the lock.
+func (pool *McpClientPool) retireClient(cli *client.Client, transportType string) {
+	if pool.useCounts[cli] > 0 {
+		pool.retiredClients[cli] = transportType
+		return
+	}
+	pool.closeClient(cli, transportType)
+}
+
+// removeEntry removes the entry and retires its client, the caller holds the lock.
+func (pool *McpClientPool) removeEntry(key string, entry *mcpPoolEntry) {
+	if pool.entries[key] != entry {
+		return
+	}
+
+	delete(pool.entries, key)
+	pool.updateClientCount()
+	pool.retireClient(entry.client, entry.transportType)
+}
+
+// evictStdioEntry frees a stdio process for the server of the key by evicting an unused client,
+// the ones of the previous configs of the server first, then the least recently used one.
+// It returns false when all the stdio clients are in use. The caller holds the lock.
+func (pool *McpClientPool) evictStdioEntry(key string, providerId string, serverName string) bool {
+	evictedKey := ""
+	var evictedEntry *mcpPoolEntry
+	for k, entry := range pool.entries {
+		if k == key || entry.transportType != "stdio" || entry.isChecking || pool.useCounts[entry.client] > 0 {
+			continue
+		}
+
+		if entry.providerId == providerId && entry.serverName == serverName {
+			evictedKey, evictedEntry = k, entry
+			break
+		}
+		if evictedEntry == nil || entry.lastUsedTime.Before(evictedEntry.lastUsedTime) {
+			evictedKey, evictedEntry = k, entry
+		}
+	}
+
+	if evictedEntry == nil {
+		return false
+	}
+
+	pool.removeEntry(evictedKey, evictedEntry)
+	return true
+}
+
+func (pool *McpClientPool) runHealthChecks() {
+	ticker := time.NewTicker(mcpHealthCheckInterval)
+	for range ticker.C {
+		pool.checkClients()
+	}
+}
+
+// checkClients evicts the idle clients and checks the others, the clients in use are never idle.
+func (pool *McpClientPool) checkClients() {
+	pool.lock.Lock()
+	keys := []string{}
+	for key, entry := range pool.entries {
+		if pool.useCounts[entry.client] == 0 && time.Since(entry.lastUsedTime) > pool.idleTimeout {
+			pool.removeEntry(key, entry)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	pool.lock.Unlock()
+
+	for _, key := range keys {
+		pool.checkClient(key)
+	}
+}
+
+// checkClient pings the client and reconnects it when the ping fails. A client failing
+// to reconnect is removed, the next answer using it will connect again with backoff.
+func (pool *McpClientPool) checkClient(key string) {
+	pool.lock.Lock()
+	entry, ok := pool.entries[key]
+	if !ok || entry.isChecking {
+		pool.lock.Unlock()
+		return
+	}
+	entry.isChecking = true
+	oldClient := entry.client
+	pool.lock.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), mcpPingTimeout)
+	_, err := oldClient.Ping(ctx, protocol.NewPingRequest())
+	cancel()
+
+	var cli *client.Client
+	if err != nil {
+		fmt.Printf("McpClientPool.checkClient() error, the MCP server: %s is unhealthy and will be reconnected: %s\n", entry.serverName, err.Error())
+
+		// reserve the process of the new client while connecting, the old one runs until it is retired
+		if entry.transportType == "stdio" {
+			pool.lock.Lock()
+			if pool.stdioCount >= pool.maxStdioProcesses && !pool.evictStdioEntry(key, entry.providerId, entry.serverName) {
+				entry.isChecking = false
+				fmt.Printf("McpClientPool.checkClient() error, the MCP server: %s can't be restarted, the %d stdio MCP servers allowed are all running\n", entry.serverName, pool.maxStdioProcesses)
+				pool.removeEntry(key, entry)
+				pool.lock.Unlock()
+				return
+			}
+			pool.stdioCount++
+			pool.lock.Unlock()
+		}
+
+		cli, err = createMCPClient(entry.config)
+	}
+
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	entry.isChecking = false
+	if err != nil {
+		fmt.Printf("McpClientPool.checkClient() error, failed to reconnect to the MCP server: %s, %s\n", entry.serverName, err.Error())
+		if entry.transportType == "stdio" {
+			pool.stdioCount--
+		}
+		pool.removeEntry(key, entry)
+		return
+	}
+
+	if cli != nil {
+		if pool.entries[key] != entry {
+			pool.closeClient(cli, entry.transportType)
+			return
+		}
+
+		// swap in the new client, the answers still using the old one keep it until they release it
+		entry.client = cli
+		pool.retireClient(oldClient, entry.transportType)
+	}
+}
+
+// reportFailure checks the client in the background after one of its calls failed.
+func (pool *McpClientPool) reportFailure(cli *client.Client) {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	for key, entry := range pool.entries {
+		if entry.client == cli {
+			go pool.checkClient(key)
+			return
+		}
+	}
+}
+
+// getPooledMCPClientMap returns the pooled clients of the enabled servers of the provider's config,
+// they are released by AgentClients.Release.
+func getPooledMCPClientMap(providerId string, config string, toolsMap map[string]bool) (map[string]*client.Client, error) {
+	return getMCPClientMap(providerId, config, toolsMap, func(name string, srv ServerConfig) (*client.Client, error) {
+		return mcpClientPool.GetClient(providerId, name, srv)
+	})
+}
+
+// Release releases the pooled MCP clients, it is called once the answer is done with its tools.
+func (agentClients *AgentClients) Release() {
+	if agentClients == nil || agentClients.isReleased {
+		return
+	}
+
+	agentClients.isReleased = true
+	for _, cli := range agentClients.Clients {
+		mcpClientPool.ReleaseClient(cli)
+	}
+}
+
+// CallTool calls the tool of the MCP server, recording the latency and the result of the call.
+func (agentClients *AgentClients) CallTool(ctx context.Context, serverName string, toolName string, arguments map[string]interface{}) (*protocol.CallToolResult, error) {
+	cli, ok := agentClients.Clients[serverName]
+	if !ok {
+		return nil, fmt.Errorf("the MCP server: %s is not enabled or not reachable", serverName)
+	}
+
+	startTime := time.Now()
+	result, err := cli.CallTool(ctx, &protocol.CallToolRequest{
+		Name:      toolName,
+		Arguments: arguments,
+	})
+	McpToolCallLatency.WithLabelValues(serverName, toolName).Observe(float64(time.Since(startTime).Milliseconds()))
+
+	status := "success"
+	if err != nil {
+		status = "failure"
+		if ctx.Err() == nil {
+			mcpClientPool.reportFailure(cli)
+		}
+	} else if result.IsError {
+		status = "error"
+	}
+	McpToolCallCount.WithLabelValues(serverName, toolName, status).Inc()
+
+	return result, err
+}
@@ -0,0 +1,172 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build skipCi
+// +build skipCi
+
+package agent
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ThinkInAIXYZ/go-mcp/client"
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/ThinkInAIXYZ/go-mcp/server"
+	"github.com/ThinkInAIXYZ/go-mcp/transport"
+)
+
+func newTestMcpServer(t *testing.T) *httptest.Server {
+	serverTransport, handler, err := transport.NewStreamableHTTPServerTransportAndHandler()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv, err := server.NewServer(serverTransport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.RegisterTool(&protocol.Tool{Name: "echo", InputSchema: protocol.InputSchema{Type: protocol.Object}}, func(ctx context.Context, request *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+		return &protocol.CallToolResult{Content: []protocol.Content{&protocol.TextContent{Type: "text", Text: "ok"}}}, nil
+	})
+	go srv.Run()
+
+	return httptest.NewServer(handler.HandleMCP())
+}
+
+func TestMcpClientPool(t *testing.T) {
+	httpServer := newTestMcpServer(t)
+	defer httpServer.Close()
+
+	pool := newMcpClientPool()
+	pool.maxStdioProcesses = 0
+	pool.startOnce.Do(func() {})
+
+	srv := ServerConfig{Type: "streamablehttp", URL: httpServer.URL}
+	cli, err := pool.GetClient("admin/provider", "test", srv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli2, err := pool.GetClient("admin/provider", "test", srv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cli != cli2 {
+		t.Errorf("expected the client to be reused")
+	}
+
+	agentClients := &AgentClients{Clients: map[string]*client.Client{"test": cli}}
+	result, err := agentClients.CallTool(context.Background(), "test", "echo", nil)
+	if err != nil || result.IsError {
+		t.Fatalf("expected the tool call to succeed, got %v", err)
+	}
+
+	_, err = pool.GetClient("admin/provider", "stdio", ServerConfig{Command: "true"})
+	if err == nil {
+		t.Errorf("expected the stdio servers to be limited")
+	}
+
+	pool.idleTimeout = 0
+	pool.checkClients()
+	if len(pool.entries) != 1 {
+		t.Errorf("expected the client in use not to be evicted")
+	}
+
+	pool.ReleaseClient(cli)
+	pool.ReleaseClient(cli2)
+	pool.checkClients()
+	if len(pool.entries) != 0 {
+		t.Errorf("expected the idle client to be evicted")
+	}
+}
+
+func TestMcpClientPoolRetiresClientsInUse(t *testing.T) {
+	httpServer := newTestMcpServer(t)
+	defer httpServer.Close()
+
+	pool := newMcpClientPool()
+	pool.startOnce.Do(func() {})
+
+	srv := ServerConfig{Type: "streamablehttp", URL: httpServer.URL}
+	cli, err := pool.GetClient("admin/provider", "test", srv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := getMcpPoolKey("admin/provider", "test", srv)
+	pool.lock.Lock()
+	pool.removeEntry(key, pool.entries[key])
+	pool.lock.Unlock()
+
+	agentClients := &AgentClients{Clients: map[string]*client.Client{"test": cli}}
+	result, err := agentClients.CallTool(context.Background(), "test", "echo", nil)
+	if err != nil || result.IsError {
+		t.Fatalf("expected the retired client to work until it is released, got %v", err)
+	}
+
+	if _, ok := pool.retiredClients[cli]; !ok {
+		t.Errorf("expected the client in use to be retired")
+	}
+	pool.ReleaseClient(cli)
+	if len(pool.retiredClients) != 0 || len(pool.useCounts) != 0 {
+		t.Errorf("expected the retired client to be closed on its last release")
+	}
+}
+
+func TestMcpClientPoolEvictsStdioEntries(t *testing.T) {
+	httpServer := newTestMcpServer(t)
+	defer httpServer.Close()
+
+	pool := newMcpClientPool()
+	pool.startOnce.Do(func() {})
+
+	// the HTTP clients stand for stdio servers, only their bookkeeping matters here
+	now := time.Now()
+	addEntry := func(key string, serverName string, lastUsedTime time.Time) *client.Client {
+		cli, err := createMCPClient(ServerConfig{Type: "streamablehttp", URL: httpServer.URL})
+		if err != nil {
+			t.Fatal(err)
+		}
+		pool.entries[key] = &mcpPoolEntry{providerId: "admin/provider", serverName: serverName, transportType: "stdio", client: cli, lastUsedTime: lastUsedTime}
+		pool.stdioCount++
+		return cli
+	}
+	inUseClient := addEntry("admin/provider/old/1", "old", now.Add(-time.Hour))
+	pool.useCounts[inUseClient]++
+	addEntry("admin/provider/lru/1", "lru", now.Add(-time.Minute))
+	addEntry("admin/provider/recent/1", "recent", now)
+	addEntry("admin/provider/edited/1", "edited", now)
+
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	// the previous config of the server goes first, then the least recently used unused client
+	for _, evictedKey := range []string{"admin/provider/edited/1", "admin/provider/lru/1", "admin/provider/recent/1"} {
+		if !pool.evictStdioEntry("admin/provider/edited/2", "admin/provider", "edited") {
+			t.Fatalf("expected the entry: %s to be evicted", evictedKey)
+		}
+		if _, ok := pool.entries[evictedKey]; ok {
+			t.Errorf("expected the entry: %s to be evicted, got %d entries", evictedKey, len(pool.entries))
+		}
+	}
+
+	if pool.evictStdioEntry("admin/provider/edited/2", "admin/provider", "edited") {
+		t.Errorf("expected the client in use not to be evicted")
+	}
+	if pool.stdioCount != 1 {
+		t.Errorf("expected 1 stdio process left, got %d", pool.stdioCount)
+	}
+}
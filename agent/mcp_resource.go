@@ -0,0 +1,80 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ThinkInAIXYZ/go-mcp/client"
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+)
+
+// getMcpClientFromId returns the client and the resource URI or prompt name of an ID like
+// "server__name", see GetIdFromServerNameAndToolName. Unlike tool names, URIs may contain "__".
+func (agentClients *AgentClients) getMcpClientFromId(id string) (*client.Client, string, error) {
+	tokens := strings.SplitN(id, "__", 2)
+	if len(tokens) != 2 {
+		return nil, "", fmt.Errorf("invalid MCP ID: %s", id)
+	}
+
+	if agentClients == nil || agentClients.Clients[tokens[0]] == nil {
+		return nil, "", fmt.Errorf("the MCP server: %s is not enabled or not reachable", tokens[0])
+	}
+	return agentClients.Clients[tokens[0]], tokens[1], nil
+}
+
+// ReadResource returns the text contents of the MCP resource, the binary contents are skipped.
+func (agentClients *AgentClients) ReadResource(ctx context.Context, id string) (string, error) {
+	cli, uri, err := agentClients.getMcpClientFromId(id)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := cli.ReadResource(ctx, protocol.NewReadResourceRequest(uri))
+	if err != nil {
+		return "", err
+	}
+
+	texts := []string{}
+	for _, content := range result.Contents {
+		if textContent, ok := content.(*protocol.TextResourceContents); ok && textContent.Text != "" {
+			texts = append(texts, textContent.Text)
+		}
+	}
+	return strings.Join(texts, "\n\n"), nil
+}
+
+// GetPrompt returns the text of the messages of the MCP prompt, requested without arguments.
+func (agentClients *AgentClients) GetPrompt(ctx context.Context, id string) (string, error) {
+	cli, name, err := agentClients.getMcpClientFromId(id)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := cli.GetPrompt(ctx, protocol.NewGetPromptRequest(name, nil))
+	if err != nil {
+		return "", err
+	}
+
+	texts := []string{}
+	for _, message := range result.Messages {
+		if textContent, ok := message.Content.(*protocol.TextContent); ok && textContent.Text != "" {
+			texts = append(texts, textContent.Text)
+		}
+	}
+	return strings.Join(texts, "\n\n"), nil
+}
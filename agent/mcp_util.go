@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/ThinkInAIXYZ/go-mcp/client"
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
 	"github.com/ThinkInAIXYZ/go-mcp/transport"
 )
 
@@ -38,16 +39,42 @@ type ServerConfig struct {
 	// - URL not empty -> SSE
 	// - URL empty -> Stdio
 	Type string `json:"type,omitempty"`
+
+	// HTTP config for the SSE and StreamableHTTP transports, OAuth takes precedence over BearerToken
+	Headers     map[string]string `json:"headers,omitempty"`
+	BearerToken string            `json:"bearerToken,omitempty"`
+	OAuth       *OAuthConfig      `json:"oauth,omitempty"`
+
+	// the provider and the name of the server, set on connection for its OAuth token source
+	providerId string
+	serverName string
 }
 
+const (
+	ToolPolicyAuto     = "Auto"
+	ToolPolicyApproval = "Approval"
+	ToolPolicyDeny     = "Deny"
+)
+
+const (
+	mcpConnectAttempts = 3
+	mcpConnectBackoff  = 500 * time.Millisecond
+)
+
+// McpTools are the tools, resources and prompts of an MCP server, each one a JSON array of the
+// protocol objects. ToolPolicies maps a tool name to its policy, the tools without a policy are
+// called automatically.
 type McpTools struct {
-	ServerName string `json:"serverName"`
-	Tools      string `json:"tools"`
-	IsEnabled  bool   `json:"isEnabled"`
+	ServerName   string            `json:"serverName"`
+	Tools        string            `json:"tools"`
+	Resources    string            `json:"resources"`
+	Prompts      string            `json:"prompts"`
+	IsEnabled    bool              `json:"isEnabled"`
+	ToolPolicies map[string]string `json:"toolPolicies"`
 }
 
-func GetToolsList(config string) ([]*McpTools, error) {
-	clients, err := GetMCPClientMap(config, nil)
+func GetToolsList(providerId string, config string) ([]*McpTools, error) {
+	clients, err := GetMCPClientMap(providerId, config, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -66,9 +93,38 @@ func GetToolsList(config string) ([]*McpTools, error) {
 		if err != nil {
 			return nil, err
 		}
+
+		resources := []*protocol.Resource{}
+		if cli.GetServerCapabilities().Resources != nil {
+			resourceList, err := cli.ListResources(ctx)
+			if err != nil {
+				return nil, err
+			}
+			resources = resourceList.Resources
+		}
+		resourcesJson, err := json.Marshal(resources)
+		if err != nil {
+			return nil, err
+		}
+
+		prompts := []*protocol.Prompt{}
+		if cli.GetServerCapabilities().Prompts != nil {
+			promptList, err := cli.ListPrompts(ctx)
+			if err != nil {
+				return nil, err
+			}
+			prompts = promptList.Prompts
+		}
+		promptsJson, err := json.Marshal(prompts)
+		if err != nil {
+			return nil, err
+		}
+
 		totalTools = append(totalTools, &McpTools{
 			ServerName: name,
 			Tools:      string(toolsJson),
+			Resources:  string(resourcesJson),
+			Prompts:    string(promptsJson),
 			IsEnabled:  true,
 		})
 	}
@@ -80,16 +136,8 @@ func createMCPClient(srv ServerConfig) (*client.Client, error) {
 	var transportClient transport.ClientTransport
 	var err error
 
-	// Determine transport type
-	transportType := srv.Type
-	if transportType == "" {
-		// Auto-detect based on URL field for backward compatibility
-		if srv.URL != "" {
-			transportType = "sse"
-		} else {
-			transportType = "stdio"
-		}
-	}
+	// Determine transport type, auto-detected based on URL field for backward compatibility
+	transportType := getTransportType(srv)
 
 	// Create appropriate transport
 	switch transportType {
@@ -97,17 +145,20 @@ func createMCPClient(srv ServerConfig) (*client.Client, error) {
 		if srv.URL == "" {
 			return nil, fmt.Errorf("URL is required for SSE transport")
 		}
-		transportClient, err = transport.NewSSEClientTransport(srv.URL)
+		transportClient, err = transport.NewSSEClientTransport(srv.URL, transport.WithSSEClientOptionHTTPClient(getMcpHttpClient(srv, srv.Headers)))
 	case "streamablehttp":
 		if srv.URL == "" {
 			return nil, fmt.Errorf("URL is required for StreamableHTTP transport")
 		}
-		if len(srv.Env) > 0 {
-			transportClient, err = transport.NewStreamableHTTPClientTransport(srv.URL, transport.WithStreamableHTTPClientOptionHeader(srv.Env))
-		} else {
-			// Initialize StreamableHTTP transport without headers when Env is empty or nil
-			transportClient, err = transport.NewStreamableHTTPClientTransport(srv.URL)
+		// Env is sent as headers too for the StreamableHTTP servers configured before Headers existed
+		headers := map[string]string{}
+		for k, v := range srv.Env {
+			headers[k] = v
+		}
+		for k, v := range srv.Headers {
+			headers[k] = v
 		}
+		transportClient, err = transport.NewStreamableHTTPClientTransport(srv.URL, transport.WithStreamableHTTPClientOptionHTTPClient(getMcpHttpClient(srv, headers)))
 	case "stdio":
 		envs := make([]string, 0, len(srv.Env))
 		for k, v := range srv.Env {
@@ -133,31 +184,73 @@ func createMCPClient(srv ServerConfig) (*client.Client, error) {
 	return cli, nil
 }
 
-func GetMCPClientMap(config string, toolsMap map[string]bool) (map[string]*client.Client, error) {
+// createMCPClientWithRetry connects to the server, retrying with an exponential backoff.
+func createMCPClientWithRetry(srv ServerConfig) (*client.Client, error) {
+	backoff := mcpConnectBackoff
+	var err error
+	for i := 0; i < mcpConnectAttempts; i++ {
+		if i > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+
+		var cli *client.Client
+		cli, err = createMCPClient(srv)
+		if err == nil {
+			return cli, nil
+		}
+	}
+	return nil, err
+}
+
+// GetServerConfigs returns the servers of the MCP config by their names.
+func GetServerConfigs(config string) (map[string]ServerConfig, error) {
 	var outer struct {
 		MCPServers map[string]ServerConfig `json:"mcpServers"`
 	}
 	if err := json.Unmarshal([]byte(config), &outer); err != nil {
 		return nil, err
 	}
+	return outer.MCPServers, nil
+}
+
+// GetMCPClientMap connects to the enabled servers of the config. The servers still unreachable
+// after the retries are skipped, an error is only returned when none of them is reachable.
+// The clients are not pooled, the caller closes them.
+func GetMCPClientMap(providerId string, config string, toolsMap map[string]bool) (map[string]*client.Client, error) {
+	return getMCPClientMap(providerId, config, toolsMap, func(name string, srv ServerConfig) (*client.Client, error) {
+		return createMCPClientWithRetry(srv)
+	})
+}
+
+func getMCPClientMap(providerId string, config string, toolsMap map[string]bool, connect func(name string, srv ServerConfig) (*client.Client, error)) (map[string]*client.Client, error) {
+	serverConfigs, err := GetServerConfigs(config)
+	if err != nil {
+		return nil, err
+	}
 
 	clients := make(map[string]*client.Client)
-	for name, srv := range outer.MCPServers {
+	var lastErr error
+	for name, srv := range serverConfigs {
 		if toolsMap != nil {
 			if enabled, exists := toolsMap[name]; !exists || !enabled {
 				continue
 			}
 		}
 
-		cli, err := createMCPClient(srv)
+		srv.providerId = providerId
+		srv.serverName = name
+		cli, err := connect(name, srv)
 		if err != nil {
-			for _, c := range clients {
-				c.Close()
-			}
-			return nil, err
+			fmt.Printf("GetMCPClientMap() error, failed to connect to the MCP server: %s, %s\n", name, err.Error())
+			lastErr = fmt.Errorf("failed to connect to the MCP server: %s, %w", name, err)
+			continue
 		}
 		clients[name] = cli
 	}
 
+	if len(clients) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
 	return clients, nil
 }
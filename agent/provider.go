@@ -30,15 +30,26 @@ type AgentProvider interface {
 type AgentClients struct {
 	Clients          map[string]*client.Client
 	Tools            []*protocol.Tool
+	ToolPolicies     map[string]string
 	BuiltinToolReg   *builtin_tool.ToolRegistry
 	WebSearchEnabled bool
+
+	isReleased bool
+}
+
+// GetToolPolicy returns the policy of a tool by its ID, see GetIdFromServerNameAndToolName.
+func (agentClients *AgentClients) GetToolPolicy(toolId string) string {
+	if policy, ok := agentClients.ToolPolicies[toolId]; ok {
+		return policy
+	}
+	return ToolPolicyAuto
 }
 
-func GetAgentProvider(typ string, subType string, text string, mcpTools []*McpTools, lang string) (AgentProvider, error) {
+func GetAgentProvider(typ string, subType string, providerId string, text string, mcpTools []*McpTools, lang string) (AgentProvider, error) {
 	var p AgentProvider
 	var err error
 	if typ == "MCP" {
-		p, err = NewMcpAgentProvider(typ, subType, text, mcpTools)
+		p, err = NewMcpAgentProvider(typ, subType, providerId, text, mcpTools)
 	} else {
 		return nil, fmt.Errorf(i18n.Translate(lang, "agent:the agent provider type: %s is not supported"), typ)
 	}
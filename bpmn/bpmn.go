@@ -21,36 +21,14 @@ import (
 	"strconv"
 	"strings"
 	"time"
-
-	"github.com/casibase/casibase/i18n"
 )
 
-type Definitions struct {
-	XMLName   xml.Name  `xml:"definitions"`
-	Processes []Process `xml:"process"`
-}
-
-type Process struct {
-	XMLName      xml.Name      `xml:"process"`
-	ID           string        `xml:"id,attr"`
-	Name         string        `xml:"name,attr,omitempty"`
-	FlowElements []FlowElement `xml:",any"`
-}
-
 type Task struct {
 	XMLName xml.Name `xml:"task"`
 	ID      string   `xml:"id,attr"`
 	Name    string   `xml:"name,attr"`
 }
 
-type FlowElement struct {
-	XMLName   xml.Name
-	ID        string `xml:"id,attr"`
-	Name      string `xml:"name,attr,omitempty"`
-	SourceRef string `xml:"sourceRef,attr,omitempty"`
-	TargetRef string `xml:"targetRef,attr,omitempty"`
-}
-
 type SequenceFlow struct {
 	XMLName             xml.Name `xml:"sequenceFlow"`
 	ID                  string   `xml:"id,attr"`
@@ -110,12 +88,13 @@ func PathToString(node *PathNode, indent string) string {
 	return sb.String()
 }
 
+// ParseBPMN parses the main process of the BPMN diagram into the tasks, the outgoing sequence flows
+// of each node, the branching and the parallel gateways, the timer delays in days and the start
+// events, which is the form the paths are built from.
 func ParseBPMN(bpmnText string, lang string) (map[string]Task, map[string][]SequenceFlow, map[string]bool, map[string]bool, map[string]int, []string, error) {
-	bytes := []byte(bpmnText)
-	var definitions Definitions
-	err := xml.Unmarshal(bytes, &definitions)
+	model, err := ParseModel(bpmnText, lang)
 	if err != nil {
-		return nil, nil, nil, nil, nil, nil, fmt.Errorf(i18n.Translate(lang, "bpmn:Error parsing BPMN file: %v"), err)
+		return nil, nil, nil, nil, nil, nil, err
 	}
 
 	tasks := map[string]Task{}
@@ -123,44 +102,46 @@ func ParseBPMN(bpmnText string, lang string) (map[string]Task, map[string][]Sequ
 	exclusiveGateways := map[string]bool{}
 	parallelGateways := map[string]bool{}
 	timerEvents := map[string]int{}
-	startEvents := []string{}
-
-	for _, process := range definitions.Processes {
-		for _, flowElement := range process.FlowElements {
-			switch flowElement.XMLName.Local {
-			case "task":
-				tasks[flowElement.ID] = Task{ID: flowElement.ID, Name: flowElement.Name}
-			case "startEvent", "endEvent", "intermediateCatchEvent", "intermediateThrowEvent":
-				defaultName := "Event"
-				if flowElement.Name != "" {
-					defaultName = flowElement.Name
-				}
-				tasks[flowElement.ID] = Task{ID: flowElement.ID, Name: defaultName}
-				if flowElement.XMLName.Local == "startEvent" {
-					startEvents = append(startEvents, flowElement.ID)
-				}
-			case "sequenceFlow":
-				sourceRef := flowElement.SourceRef
-				targetRef := flowElement.TargetRef
-				seqFlow := SequenceFlow{
-					ID:                  flowElement.ID,
-					SourceRef:           sourceRef,
-					TargetRef:           targetRef,
-					ConditionExpression: flowElement.Name,
-				}
-				sequenceFlows[sourceRef] = append(sequenceFlows[sourceRef], seqFlow)
-			case "exclusiveGateway", "parallelGateway":
-				tasks[flowElement.ID] = Task{ID: flowElement.ID, Name: flowElement.XMLName.Local}
-				if flowElement.XMLName.Local == "exclusiveGateway" {
-					exclusiveGateways[flowElement.ID] = true
-				} else if flowElement.XMLName.Local == "parallelGateway" {
-					parallelGateways[flowElement.ID] = true
-				}
-			case "timerEventDefinition":
-				var days int
-				fmt.Sscanf(flowElement.Name, "P%dD", &days)
-				timerEvents[flowElement.ID] = days
+	startEvents := model.StartNodes
+
+	for _, id := range model.NodeIds {
+		node := model.Nodes[id]
+		if node.ProcessId != model.Id {
+			continue
+		}
+
+		switch node.Type {
+		case NodeTypeStartEvent, NodeTypeEndEvent, NodeTypeIntermediateCatchEvent, NodeTypeIntermediateThrowEvent, NodeTypeBoundaryEvent, NodeTypeImplicitThrowEvent:
+			defaultName := "Event"
+			if node.Name != "" {
+				defaultName = node.Name
 			}
+			tasks[id] = Task{ID: id, Name: defaultName}
+		case NodeTypeExclusiveGateway, NodeTypeInclusiveGateway, NodeTypeEventBasedGateway, NodeTypeComplexGateway:
+			tasks[id] = Task{ID: id, Name: node.Type}
+			exclusiveGateways[id] = true
+		case NodeTypeParallelGateway:
+			tasks[id] = Task{ID: id, Name: node.Type}
+			parallelGateways[id] = true
+		default:
+			tasks[id] = Task{ID: id, Name: node.Name}
+		}
+
+		if node.Timer != nil && node.Timer.Type == TimerTypeDuration {
+			duration, err := ParseDuration(node.Timer.Value, lang)
+			if err == nil {
+				timerEvents[id] = int(duration / (24 * time.Hour))
+			}
+		}
+
+		for _, flowId := range node.Outgoing {
+			flow := model.Flows[flowId]
+			sequenceFlows[id] = append(sequenceFlows[id], SequenceFlow{
+				ID:                  flow.Id,
+				SourceRef:           flow.SourceRef,
+				TargetRef:           flow.TargetRef,
+				ConditionExpression: flow.Condition,
+			})
 		}
 	}
 
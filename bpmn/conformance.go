@@ -0,0 +1,457 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpmn
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/casibase/casibase/i18n"
+)
+
+const (
+	DeviationTypeUnknownActivity    = "UnknownActivity"
+	DeviationTypeUnexpectedActivity = "UnexpectedActivity"
+	DeviationTypeMissingActivity    = "MissingActivity"
+)
+
+// the places before the start events and after the end events
+const (
+	placeSource = "\x00source"
+	placeSink   = "\x00sink"
+)
+
+// the number of markings searched for a sequence of silent transitions enabling an activity
+const maxSilentMarkings = 1000
+
+// Deviation is where a case departs from the model: an activity the model does not have, an
+// activity done when the model did not allow it, like out of order or after skipping the activities
+// before it, or an activity the model expected but the case never did.
+type Deviation struct {
+	Type     string `json:"type"`
+	Activity string `json:"activity"`
+	Position int    `json:"position"`
+}
+
+// CaseConformance is the token replay of a case: the tokens produced, consumed, missing to do an
+// activity and remaining at the end, and its fitness from 0 to 1.
+type CaseConformance struct {
+	CaseId     string       `json:"caseId"`
+	Activities []string     `json:"activities"`
+	Fitness    float64      `json:"fitness"`
+	IsFitting  bool         `json:"isFitting"`
+	Produced   int          `json:"produced"`
+	Consumed   int          `json:"consumed"`
+	Missing    int          `json:"missing"`
+	Remaining  int          `json:"remaining"`
+	Deviations []*Deviation `json:"deviations"`
+}
+
+// ActivityDeviation counts the deviations of an activity over all cases.
+type ActivityDeviation struct {
+	Activity        string `json:"activity"`
+	UnknownCount    int    `json:"unknownCount"`
+	UnexpectedCount int    `json:"unexpectedCount"`
+	MissingCount    int    `json:"missingCount"`
+}
+
+type ConformanceReport struct {
+	Fitness            float64              `json:"fitness"`
+	CaseCount          int                  `json:"caseCount"`
+	FittingCaseCount   int                  `json:"fittingCaseCount"`
+	EventCount         int                  `json:"eventCount"`
+	ActivityDeviations []*ActivityDeviation `json:"activityDeviations"`
+	Cases              []*CaseConformance   `json:"cases"`
+}
+
+// transition is a transition of the Petri net the model is replayed on, the silent ones have no label.
+type transition struct {
+	label   string
+	inputs  []string
+	outputs []string
+}
+
+type petriNet struct {
+	transitions []*transition
+	labels      map[string]string
+}
+
+type marking map[string]int
+
+func (m marking) copy() marking {
+	res := marking{}
+	for place, count := range m {
+		if count != 0 {
+			res[place] = count
+		}
+	}
+	return res
+}
+
+func (m marking) key() string {
+	places := []string{}
+	for place, count := range m {
+		if count != 0 {
+			places = append(places, fmt.Sprintf("%s=%d", place, count))
+		}
+	}
+	sort.Strings(places)
+	return strings.Join(places, ",")
+}
+
+func (m marking) isEnabled(t *transition) bool {
+	needed := map[string]int{}
+	for _, place := range t.inputs {
+		needed[place]++
+	}
+	for place, count := range needed {
+		if m[place] < count {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeActivity(activity string) string {
+	return strings.ToLower(strings.TrimSpace(activity))
+}
+
+// newPetriNet translates the main process of the model into a Petri net whose places are the
+// sequence flows: an activity with several incoming flows is entered by any of them and leaves by
+// all of its outgoing flows, an exclusive gateway moves a token from any incoming flow to any
+// outgoing one, a parallel gateway waits for all incoming flows and fires all outgoing ones, and
+// an inclusive gateway does either. The events in between are silent, and so are the gateways.
+func newPetriNet(model *Model) *petriNet {
+	net := &petriNet{transitions: []*transition{}, labels: map[string]string{}}
+	for _, id := range model.NodeIds {
+		node := model.Nodes[id]
+		if node.ProcessId != model.Id || node.ParentId != "" {
+			continue
+		}
+
+		switch node.Type {
+		case NodeTypeStartEvent:
+			net.transitions = append(net.transitions, &transition{inputs: []string{placeSource}, outputs: node.Outgoing})
+		case NodeTypeEndEvent:
+			for _, flowId := range node.Incoming {
+				net.transitions = append(net.transitions, &transition{inputs: []string{flowId}, outputs: []string{placeSink}})
+			}
+		case NodeTypeParallelGateway:
+			if len(node.Incoming) != 0 {
+				net.transitions = append(net.transitions, &transition{inputs: node.Incoming, outputs: node.Outgoing})
+			}
+		case NodeTypeExclusiveGateway, NodeTypeEventBasedGateway, NodeTypeInclusiveGateway, NodeTypeComplexGateway:
+			for _, in := range node.Incoming {
+				for _, out := range node.Outgoing {
+					net.transitions = append(net.transitions, &transition{inputs: []string{in}, outputs: []string{out}})
+				}
+			}
+			if (node.Type == NodeTypeInclusiveGateway || node.Type == NodeTypeComplexGateway) && len(node.Incoming)+len(node.Outgoing) > 2 {
+				net.transitions = append(net.transitions, &transition{inputs: node.Incoming, outputs: node.Outgoing})
+			}
+		default:
+			label := ""
+			if !strings.HasSuffix(node.Type, "Event") {
+				label = node.Name
+				if label == "" {
+					label = node.Id
+				}
+				net.labels[normalizeActivity(label)] = label
+			}
+			for _, flowId := range node.Incoming {
+				net.transitions = append(net.transitions, &transition{label: normalizeActivity(label), inputs: []string{flowId}, outputs: node.Outgoing})
+			}
+		}
+	}
+	return net
+}
+
+// findSilentPath searches the shortest sequence of silent transitions leading from the marking to
+// one where the goal holds.
+func (net *petriNet) findSilentPath(start marking, isGoal func(marking) bool) []*transition {
+	type state struct {
+		marking marking
+		path    []*transition
+	}
+
+	visited := map[string]bool{start.key(): true}
+	queue := []*state{{marking: start, path: []*transition{}}}
+	for len(queue) != 0 && len(visited) < maxSilentMarkings {
+		current := queue[0]
+		queue = queue[1:]
+		if isGoal(current.marking) {
+			return current.path
+		}
+
+		for _, t := range net.transitions {
+			if t.label != "" || !current.marking.isEnabled(t) {
+				continue
+			}
+
+			next := current.marking.copy()
+			for _, place := range t.inputs {
+				next[place]--
+			}
+			for _, place := range t.outputs {
+				next[place]++
+			}
+			if key := next.key(); !visited[key] {
+				visited[key] = true
+				path := append(append([]*transition{}, current.path...), t)
+				queue = append(queue, &state{marking: next, path: path})
+			}
+		}
+	}
+	return nil
+}
+
+// getNextActivities returns the activities a token on the place leads to through silent transitions.
+func (net *petriNet) getNextActivities(place string) []string {
+	res := []string{}
+	visited := map[string]bool{place: true}
+	queue := []string{place}
+	for len(queue) != 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, t := range net.transitions {
+			if !containsString(t.inputs, current) {
+				continue
+			}
+			if t.label != "" {
+				if !containsString(res, net.labels[t.label]) {
+					res = append(res, net.labels[t.label])
+				}
+				continue
+			}
+			for _, output := range t.outputs {
+				if !visited[output] {
+					visited[output] = true
+					queue = append(queue, output)
+				}
+			}
+		}
+	}
+	return res
+}
+
+type tokenReplayer struct {
+	net     *petriNet
+	marking marking
+	result  *CaseConformance
+}
+
+func (r *tokenReplayer) fire(t *transition) {
+	for _, place := range t.inputs {
+		if r.marking[place] == 0 {
+			r.marking[place]++
+			r.result.Missing++
+		}
+		r.marking[place]--
+		r.result.Consumed++
+	}
+	for _, place := range t.outputs {
+		r.marking[place]++
+		r.result.Produced++
+	}
+}
+
+func (r *tokenReplayer) fireSilentPath(isGoal func(marking) bool) bool {
+	path := r.net.findSilentPath(r.marking, isGoal)
+	if path == nil {
+		return false
+	}
+	for _, t := range path {
+		r.fire(t)
+	}
+	return true
+}
+
+func (r *tokenReplayer) replayEvent(activity string, position int) {
+	candidates := []*transition{}
+	for _, t := range r.net.transitions {
+		if t.label != "" && t.label == normalizeActivity(activity) {
+			candidates = append(candidates, t)
+		}
+	}
+	if len(candidates) == 0 {
+		r.result.Deviations = append(r.result.Deviations, &Deviation{Type: DeviationTypeUnknownActivity, Activity: activity, Position: position})
+		return
+	}
+
+	isEnabled := func(m marking) bool {
+		for _, t := range candidates {
+			if m.isEnabled(t) {
+				return true
+			}
+		}
+		return false
+	}
+	if isEnabled(r.marking) || r.fireSilentPath(isEnabled) {
+		for _, t := range candidates {
+			if r.marking.isEnabled(t) {
+				r.fire(t)
+				return
+			}
+		}
+	}
+
+	// the activity is not allowed here, it is done anyway with the tokens it misses
+	best := candidates[0]
+	bestMissing := -1
+	for _, t := range candidates {
+		missing := 0
+		for _, place := range t.inputs {
+			if r.marking[place] == 0 {
+				missing++
+			}
+		}
+		if bestMissing == -1 || missing < bestMissing {
+			best, bestMissing = t, missing
+		}
+	}
+	r.fire(best)
+	r.result.Deviations = append(r.result.Deviations, &Deviation{Type: DeviationTypeUnexpectedActivity, Activity: activity, Position: position})
+}
+
+func getFitness(produced int, consumed int, missing int, remaining int) float64 {
+	fitness := 1.0
+	if consumed != 0 {
+		fitness -= 0.5 * float64(missing) / float64(consumed)
+	}
+	if produced != 0 {
+		fitness -= 0.5 * float64(remaining) / float64(produced)
+	}
+	return fitness
+}
+
+func (net *petriNet) replayTrace(trace *Trace) *CaseConformance {
+	r := &tokenReplayer{
+		net:     net,
+		marking: marking{placeSource: 1},
+		result: &CaseConformance{
+			CaseId:     trace.CaseId,
+			Activities: []string{},
+			Produced:   1,
+			Deviations: []*Deviation{},
+		},
+	}
+
+	for i, event := range trace.Events {
+		r.result.Activities = append(r.result.Activities, event.Activity)
+		r.replayEvent(event.Activity, i)
+	}
+
+	// the case ends when the remaining tokens reach the end events through silent transitions
+	isEnded := r.fireSilentPath(func(m marking) bool {
+		if m[placeSink] == 0 {
+			return false
+		}
+		for place, count := range m {
+			if place != placeSink && count != 0 {
+				return false
+			}
+		}
+		return true
+	})
+	if !isEnded {
+		r.fireSilentPath(func(m marking) bool {
+			return m[placeSink] != 0
+		})
+	}
+	if r.marking[placeSink] == 0 {
+		r.result.Missing++
+	} else {
+		r.marking[placeSink]--
+	}
+	r.result.Consumed++
+
+	places := []string{}
+	for place, count := range r.marking {
+		if count > 0 {
+			r.result.Remaining += count
+			places = append(places, place)
+		}
+	}
+	sort.Strings(places)
+	for _, place := range places {
+		for _, activity := range net.getNextActivities(place) {
+			r.result.Deviations = append(r.result.Deviations, &Deviation{Type: DeviationTypeMissingActivity, Activity: activity, Position: len(trace.Events)})
+		}
+	}
+
+	r.result.Fitness = getFitness(r.result.Produced, r.result.Consumed, r.result.Missing, r.result.Remaining)
+	r.result.IsFitting = r.result.Missing == 0 && r.result.Remaining == 0 && len(r.result.Deviations) == 0
+	return r.result
+}
+
+// CheckConformance replays the cases of the event log on the model by token replay. The fitness of
+// a case is 1 - missing / consumed / 2 - remaining / produced / 2 over its tokens, the one of the
+// log the same over the tokens of all cases. The activities the model does not have leave the
+// tokens alone, they only make their case a non-fitting one.
+func CheckConformance(model *Model, log *EventLog, lang string) (*ConformanceReport, error) {
+	if len(model.StartNodes) == 0 {
+		return nil, fmt.Errorf(i18n.Translate(lang, "bpmn:The BPMN file has no start event"))
+	}
+	if log.GetEventCount() == 0 {
+		return nil, fmt.Errorf(i18n.Translate(lang, "bpmn:The event log is empty"))
+	}
+
+	net := newPetriNet(model)
+	report := &ConformanceReport{
+		CaseCount:          len(log.Traces),
+		EventCount:         log.GetEventCount(),
+		ActivityDeviations: []*ActivityDeviation{},
+		Cases:              []*CaseConformance{},
+	}
+
+	produced, consumed, missing, remaining := 0, 0, 0, 0
+	activityDeviations := map[string]*ActivityDeviation{}
+	for _, trace := range log.Traces {
+		result := net.replayTrace(trace)
+		report.Cases = append(report.Cases, result)
+		if result.IsFitting {
+			report.FittingCaseCount++
+		}
+		produced += result.Produced
+		consumed += result.Consumed
+		missing += result.Missing
+		remaining += result.Remaining
+
+		for _, deviation := range result.Deviations {
+			activityDeviation, ok := activityDeviations[deviation.Activity]
+			if !ok {
+				activityDeviation = &ActivityDeviation{Activity: deviation.Activity}
+				activityDeviations[deviation.Activity] = activityDeviation
+				report.ActivityDeviations = append(report.ActivityDeviations, activityDeviation)
+			}
+			switch deviation.Type {
+			case DeviationTypeUnknownActivity:
+				activityDeviation.UnknownCount++
+			case DeviationTypeUnexpectedActivity:
+				activityDeviation.UnexpectedCount++
+			case DeviationTypeMissingActivity:
+				activityDeviation.MissingCount++
+			}
+		}
+	}
+
+	report.Fitness = getFitness(produced, consumed, missing, remaining)
+	sort.SliceStable(report.ActivityDeviations, func(i, j int) bool {
+		a, b := report.ActivityDeviations[i], report.ActivityDeviations[j]
+		return a.UnknownCount+a.UnexpectedCount+a.MissingCount > b.UnknownCount+b.UnexpectedCount+b.MissingCount
+	})
+	return report, nil
+}
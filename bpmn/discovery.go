@@ -0,0 +1,382 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpmn
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/casibase/casibase/i18n"
+)
+
+// the artificial activities before the first and after the last activity of every trace
+const (
+	discoveryStart = "\x00start"
+	discoveryEnd   = "\x00end"
+)
+
+// DiscoveryOptions are the thresholds of the heuristic miner: the dependency measure a relation
+// needs to become a flow, the number of times it must be observed, and the parallelism measure
+// two branches need to become parallel rather than exclusive.
+type DiscoveryOptions struct {
+	DependencyThreshold float64 `json:"dependencyThreshold"`
+	MinFrequency        int     `json:"minFrequency"`
+	AndThreshold        float64 `json:"andThreshold"`
+}
+
+func NewDiscoveryOptions() *DiscoveryOptions {
+	return &DiscoveryOptions{
+		DependencyThreshold: 0.5,
+		MinFrequency:        1,
+		AndThreshold:        0.1,
+	}
+}
+
+type heuristicMiner struct {
+	options    *DiscoveryOptions
+	activities []string
+	follows    map[string]map[string]int
+	outputs    map[string][]string
+	inputs     map[string][]string
+}
+
+func (m *heuristicMiner) getFollows(a string, b string) int {
+	return m.follows[a][b]
+}
+
+// getDependency is how much more often a is directly followed by b than b by a, from -1 to 1.
+func (m *heuristicMiner) getDependency(a string, b string) float64 {
+	ab := float64(m.getFollows(a, b))
+	if a == b {
+		return ab / (ab + 1)
+	}
+	ba := float64(m.getFollows(b, a))
+	return (ab - ba) / (ab + ba + 1)
+}
+
+func (m *heuristicMiner) hasEdge(a string, b string) bool {
+	for _, output := range m.outputs[a] {
+		if output == b {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *heuristicMiner) addEdge(a string, b string) {
+	if !m.hasEdge(a, b) {
+		m.outputs[a] = append(m.outputs[a], b)
+		m.inputs[b] = append(m.inputs[b], a)
+	}
+}
+
+func (m *heuristicMiner) countFollows(log *EventLog) {
+	counts := map[string]int{}
+	for _, trace := range log.Traces {
+		previous := discoveryStart
+		for _, event := range trace.Events {
+			if _, ok := m.follows[event.Activity]; !ok {
+				m.follows[event.Activity] = map[string]int{}
+			}
+			m.follows[previous][event.Activity]++
+			counts[event.Activity]++
+			previous = event.Activity
+		}
+		if previous != discoveryStart {
+			m.follows[previous][discoveryEnd]++
+		}
+	}
+
+	// the most frequent activities first, for a stable layout
+	for activity := range counts {
+		m.activities = append(m.activities, activity)
+	}
+	sort.Slice(m.activities, func(i, j int) bool {
+		if counts[m.activities[i]] != counts[m.activities[j]] {
+			return counts[m.activities[i]] > counts[m.activities[j]]
+		}
+		return m.activities[i] < m.activities[j]
+	})
+}
+
+// mine builds the dependency graph, then connects every activity left without an input or an
+// output to its best candidate so that the graph has no dangling activity.
+func (m *heuristicMiner) mine() {
+	nodes := append(append([]string{discoveryStart}, m.activities...), discoveryEnd)
+	for _, a := range nodes {
+		for _, b := range nodes {
+			if m.getFollows(a, b) < m.options.MinFrequency {
+				continue
+			}
+			if a == discoveryStart || b == discoveryEnd || m.getDependency(a, b) >= m.options.DependencyThreshold {
+				m.addEdge(a, b)
+			}
+		}
+	}
+
+	for _, a := range m.activities {
+		if len(m.inputs[a]) == 0 {
+			best := ""
+			for _, b := range nodes {
+				if b != a && b != discoveryEnd && m.getFollows(b, a) > 0 && (best == "" || m.getDependency(b, a) > m.getDependency(best, a)) {
+					best = b
+				}
+			}
+			if best == "" {
+				best = discoveryStart
+			}
+			m.addEdge(best, a)
+		}
+		if len(m.outputs[a]) == 0 {
+			best := ""
+			for _, b := range nodes {
+				if b != a && b != discoveryStart && m.getFollows(a, b) > 0 && (best == "" || m.getDependency(a, b) > m.getDependency(a, best)) {
+					best = b
+				}
+			}
+			if best == "" {
+				best = discoveryEnd
+			}
+			m.addEdge(a, best)
+		}
+	}
+}
+
+// isParallel tells whether the branches b and c, between a and d, happen in any order: they follow
+// each other both ways often enough and neither depends on the other.
+func (m *heuristicMiner) isParallel(b string, c string, total int) bool {
+	if b == c || b == discoveryStart || b == discoveryEnd || c == discoveryStart || c == discoveryEnd {
+		return false
+	}
+	if m.hasEdge(b, c) || m.hasEdge(c, b) || m.getFollows(b, c) == 0 || m.getFollows(c, b) == 0 {
+		return false
+	}
+	measure := float64(m.getFollows(b, c)+m.getFollows(c, b)) / float64(total+1)
+	return measure >= m.options.AndThreshold
+}
+
+// getGatewayType returns whether the branches of a split, or the ones of a join, are parallel or
+// exclusive: parallel when all pairs of them are.
+func (m *heuristicMiner) getGatewayType(node string, branches []string, isSplit bool) string {
+	for i := range branches {
+		for j := i + 1; j < len(branches); j++ {
+			total := m.getFollows(node, branches[i]) + m.getFollows(node, branches[j])
+			if !isSplit {
+				total = m.getFollows(branches[i], node) + m.getFollows(branches[j], node)
+			}
+			if branches[i] == node || branches[j] == node || !m.isParallel(branches[i], branches[j], total) {
+				return NodeTypeExclusiveGateway
+			}
+		}
+	}
+	return NodeTypeParallelGateway
+}
+
+type discoveredNode struct {
+	id   string
+	name string
+	typ  string
+}
+
+type discoveredFlow struct {
+	id     string
+	source string
+	target string
+}
+
+type discoveredProcess struct {
+	nodes []*discoveredNode
+	flows []*discoveredFlow
+}
+
+func (p *discoveredProcess) addNode(id string, name string, typ string) {
+	p.nodes = append(p.nodes, &discoveredNode{id: id, name: name, typ: typ})
+}
+
+func (p *discoveredProcess) addFlow(source string, target string) {
+	p.flows = append(p.flows, &discoveredFlow{id: fmt.Sprintf("Flow_%d", len(p.flows)+1), source: source, target: target})
+}
+
+// build turns the dependency graph into a process, with a split gateway after each activity with
+// several outputs and a join gateway before each one with several inputs.
+func (m *heuristicMiner) build() *discoveredProcess {
+	p := &discoveredProcess{}
+	ids := map[string]string{discoveryStart: "StartEvent_1", discoveryEnd: "EndEvent_1"}
+	p.addNode(ids[discoveryStart], "", NodeTypeStartEvent)
+	for i, activity := range m.activities {
+		ids[activity] = fmt.Sprintf("Activity_%d", i+1)
+		p.addNode(ids[activity], activity, NodeTypeTask)
+	}
+	p.addNode(ids[discoveryEnd], "", NodeTypeEndEvent)
+
+	nodes := append(append([]string{discoveryStart}, m.activities...), discoveryEnd)
+	splits := map[string]string{}
+	joins := map[string]string{}
+	for _, node := range nodes {
+		if len(m.outputs[node]) > 1 {
+			splits[node] = fmt.Sprintf("Gateway_Split_%s", ids[node])
+			p.addNode(splits[node], "", m.getGatewayType(node, m.outputs[node], true))
+			p.addFlow(ids[node], splits[node])
+		}
+		if len(m.inputs[node]) > 1 {
+			joins[node] = fmt.Sprintf("Gateway_Join_%s", ids[node])
+			p.addNode(joins[node], "", m.getGatewayType(node, m.inputs[node], false))
+			p.addFlow(joins[node], ids[node])
+		}
+	}
+
+	for _, a := range nodes {
+		for _, b := range m.outputs[a] {
+			source := ids[a]
+			if split, ok := splits[a]; ok {
+				source = split
+			}
+			target := ids[b]
+			if join, ok := joins[b]; ok {
+				target = join
+			}
+			p.addFlow(source, target)
+		}
+	}
+	return p
+}
+
+type discoveredShape struct {
+	x      int
+	y      int
+	width  int
+	height int
+}
+
+// layout places the nodes in columns by their distance from the start event, and the nodes of a
+// column one under the other.
+func (p *discoveredProcess) layout() map[string]*discoveredShape {
+	outputs := map[string][]string{}
+	for _, flow := range p.flows {
+		outputs[flow.source] = append(outputs[flow.source], flow.target)
+	}
+
+	columns := map[string]int{p.nodes[0].id: 0}
+	queue := []string{p.nodes[0].id}
+	maxColumn := 0
+	for len(queue) != 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, target := range outputs[id] {
+			if _, ok := columns[target]; !ok {
+				columns[target] = columns[id] + 1
+				if columns[target] > maxColumn {
+					maxColumn = columns[target]
+				}
+				queue = append(queue, target)
+			}
+		}
+	}
+
+	shapes := map[string]*discoveredShape{}
+	rows := map[int]int{}
+	for _, node := range p.nodes {
+		column, ok := columns[node.id]
+		if !ok {
+			column = maxColumn + 1
+		}
+		width, height := 100, 80
+		switch node.typ {
+		case NodeTypeStartEvent, NodeTypeEndEvent:
+			width, height = 36, 36
+		case NodeTypeExclusiveGateway, NodeTypeParallelGateway:
+			width, height = 50, 50
+		}
+
+		centerX := 150 + column*160
+		centerY := 120 + rows[column]*130
+		rows[column]++
+		shapes[node.id] = &discoveredShape{x: centerX - width/2, y: centerY - height/2, width: width, height: height}
+	}
+	return shapes
+}
+
+func escapeXml(text string) string {
+	var sb strings.Builder
+	_ = xml.EscapeText(&sb, []byte(text))
+	return sb.String()
+}
+
+// toXml writes the process as a BPMN diagram with the shapes and the edges of the layout, so that
+// the modeler can show it.
+func (p *discoveredProcess) toXml(processName string) string {
+	shapes := p.layout()
+
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	sb.WriteString(`<bpmn:definitions xmlns:bpmn="http://www.omg.org/spec/BPMN/20100524/MODEL" xmlns:bpmndi="http://www.omg.org/spec/BPMN/20100524/DI" xmlns:dc="http://www.omg.org/spec/DD/20100524/DC" xmlns:di="http://www.omg.org/spec/DD/20100524/DI" id="Definitions_1" targetNamespace="http://bpmn.io/schema/bpmn">` + "\n")
+	sb.WriteString(fmt.Sprintf(`  <bpmn:process id="Process_1" name="%s" isExecutable="true">`+"\n", escapeXml(processName)))
+	for _, node := range p.nodes {
+		sb.WriteString(fmt.Sprintf(`    <bpmn:%s id="%s"`, node.typ, node.id))
+		if node.name != "" {
+			sb.WriteString(fmt.Sprintf(` name="%s"`, escapeXml(node.name)))
+		}
+		sb.WriteString(" />\n")
+	}
+	for _, flow := range p.flows {
+		sb.WriteString(fmt.Sprintf(`    <bpmn:sequenceFlow id="%s" sourceRef="%s" targetRef="%s" />`+"\n", flow.id, flow.source, flow.target))
+	}
+	sb.WriteString("  </bpmn:process>\n")
+
+	sb.WriteString(`  <bpmndi:BPMNDiagram id="BPMNDiagram_1">` + "\n")
+	sb.WriteString(`    <bpmndi:BPMNPlane id="BPMNPlane_1" bpmnElement="Process_1">` + "\n")
+	for _, node := range p.nodes {
+		shape := shapes[node.id]
+		sb.WriteString(fmt.Sprintf(`      <bpmndi:BPMNShape id="%s_di" bpmnElement="%s">`+"\n", node.id, node.id))
+		sb.WriteString(fmt.Sprintf(`        <dc:Bounds x="%d" y="%d" width="%d" height="%d" />`+"\n", shape.x, shape.y, shape.width, shape.height))
+		sb.WriteString("      </bpmndi:BPMNShape>\n")
+	}
+	for _, flow := range p.flows {
+		source, target := shapes[flow.source], shapes[flow.target]
+		sb.WriteString(fmt.Sprintf(`      <bpmndi:BPMNEdge id="%s_di" bpmnElement="%s">`+"\n", flow.id, flow.id))
+		sb.WriteString(fmt.Sprintf(`        <di:waypoint x="%d" y="%d" />`+"\n", source.x+source.width, source.y+source.height/2))
+		sb.WriteString(fmt.Sprintf(`        <di:waypoint x="%d" y="%d" />`+"\n", target.x, target.y+target.height/2))
+		sb.WriteString("      </bpmndi:BPMNEdge>\n")
+	}
+	sb.WriteString("    </bpmndi:BPMNPlane>\n")
+	sb.WriteString("  </bpmndi:BPMNDiagram>\n")
+	sb.WriteString("</bpmn:definitions>\n")
+	return sb.String()
+}
+
+// DiscoverBpmn discovers the process of the event log with the heuristic miner and returns it as a
+// BPMN diagram: the tasks are the activities, the flows the dependencies between them, and the
+// gateways are parallel where the branches happen in any order and exclusive otherwise.
+func DiscoverBpmn(log *EventLog, processName string, options *DiscoveryOptions, lang string) (string, error) {
+	if log.GetEventCount() == 0 {
+		return "", fmt.Errorf(i18n.Translate(lang, "bpmn:The event log is empty"))
+	}
+	if options == nil {
+		options = NewDiscoveryOptions()
+	}
+
+	miner := &heuristicMiner{
+		options:    options,
+		activities: []string{},
+		follows:    map[string]map[string]int{discoveryStart: {}},
+		outputs:    map[string][]string{},
+		inputs:     map[string][]string{},
+	}
+	miner.countFollows(log)
+	miner.mine()
+	return miner.build().toXml(processName), nil
+}
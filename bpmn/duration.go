@@ -0,0 +1,124 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpmn
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/casibase/casibase/i18n"
+)
+
+var durationRegex = regexp.MustCompile(`^P(?:(\d+(?:[.,]\d+)?)Y)?(?:(\d+(?:[.,]\d+)?)M)?(?:(\d+(?:[.,]\d+)?)W)?(?:(\d+(?:[.,]\d+)?)D)?(?:T(?:(\d+(?:[.,]\d+)?)H)?(?:(\d+(?:[.,]\d+)?)M)?(?:(\d+(?:[.,]\d+)?)S)?)?$`)
+
+// the units of the components of a duration, a year counts 365 days and a month 30 days as the
+// durations are not anchored to a date
+var durationUnits = []time.Duration{
+	365 * 24 * time.Hour,
+	30 * 24 * time.Hour,
+	7 * 24 * time.Hour,
+	24 * time.Hour,
+	time.Hour,
+	time.Minute,
+	time.Second,
+}
+
+// ParseDuration parses an ISO-8601 duration like P3D, PT1H30M or P1Y2M10DT2H30M, the smallest
+// component may have a fraction like PT0.5S.
+func ParseDuration(duration string, lang string) (time.Duration, error) {
+	duration = strings.ToUpper(strings.TrimSpace(duration))
+	matches := durationRegex.FindStringSubmatch(duration)
+	if matches == nil || duration == "P" || strings.HasSuffix(duration, "T") {
+		return 0, fmt.Errorf(i18n.Translate(lang, "bpmn:The timer duration: %s is invalid"), duration)
+	}
+
+	var res time.Duration
+	for i, match := range matches[1:] {
+		if match == "" {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(strings.ReplaceAll(match, ",", "."), 64)
+		if err != nil {
+			return 0, fmt.Errorf(i18n.Translate(lang, "bpmn:The timer duration: %s is invalid"), duration)
+		}
+		res += time.Duration(value * float64(durationUnits[i]))
+	}
+	return res, nil
+}
+
+func parseTimerDate(date string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, date)
+	if err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02T15:04:05", date, time.Local)
+}
+
+// GetDueTime returns when the timer fires when it starts at the time. A cycle like R3/PT10M or
+// R/2025-01-01T09:00:00Z/P1D fires first at its start date if it is still to come, after an
+// interval otherwise.
+func (timer *Timer) GetDueTime(now time.Time, lang string) (time.Time, error) {
+	value := strings.TrimSpace(timer.Value)
+	switch timer.Type {
+	case TimerTypeDate:
+		dueTime, err := parseTimerDate(value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf(i18n.Translate(lang, "bpmn:The timer date: %s is invalid"), value)
+		}
+		return dueTime, nil
+	case TimerTypeCycle:
+		var start *time.Time
+		var interval time.Duration
+		for _, part := range strings.Split(value, "/") {
+			switch {
+			case strings.HasPrefix(part, "R"):
+				if part != "R" {
+					if _, err := strconv.Atoi(part[1:]); err != nil {
+						return time.Time{}, fmt.Errorf(i18n.Translate(lang, "bpmn:The timer cycle: %s is invalid"), value)
+					}
+				}
+			case strings.HasPrefix(part, "P"):
+				duration, err := ParseDuration(part, lang)
+				if err != nil {
+					return time.Time{}, fmt.Errorf(i18n.Translate(lang, "bpmn:The timer cycle: %s is invalid"), value)
+				}
+				interval = duration
+			default:
+				date, err := parseTimerDate(part)
+				if err != nil {
+					return time.Time{}, fmt.Errorf(i18n.Translate(lang, "bpmn:The timer cycle: %s is invalid"), value)
+				}
+				start = &date
+			}
+		}
+		if interval == 0 {
+			return time.Time{}, fmt.Errorf(i18n.Translate(lang, "bpmn:The timer cycle: %s is invalid"), value)
+		}
+		if start != nil && start.After(now) {
+			return *start, nil
+		}
+		return now.Add(interval), nil
+	default:
+		duration, err := ParseDuration(value, lang)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return now.Add(duration), nil
+	}
+}
@@ -0,0 +1,428 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpmn
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/casibase/casibase/i18n"
+)
+
+const (
+	TokenStateActive  = "Active"
+	TokenStateWaiting = "Waiting"
+)
+
+const (
+	HistoryTypeInstanceStarted   = "InstanceStarted"
+	HistoryTypeInstanceCompleted = "InstanceCompleted"
+	HistoryTypeNodeEntered       = "NodeEntered"
+	HistoryTypeNodeCompleted     = "NodeCompleted"
+	HistoryTypeFlowTaken         = "FlowTaken"
+	HistoryTypeTaskCompleted     = "TaskCompleted"
+	HistoryTypeTimerFired        = "TimerFired"
+)
+
+// the maximum number of the nodes executed in a row, to stop the loops without any wait state
+const maxEngineSteps = 10000
+
+// Token is the position of a path of execution in the process. The tokens waiting at a user task
+// carry its assignment, the ones waiting at a timer carry its due time.
+type Token struct {
+	Id             string   `json:"id"`
+	NodeId         string   `json:"nodeId"`
+	FlowId         string   `json:"flowId"`
+	State          string   `json:"state"`
+	CreatedTime    string   `json:"createdTime"`
+	DueTime        string   `json:"dueTime,omitempty"`
+	Assignee       string   `json:"assignee,omitempty"`
+	CandidateUsers []string `json:"candidateUsers,omitempty"`
+}
+
+type HistoryEvent struct {
+	Time      string                 `json:"time"`
+	Type      string                 `json:"type"`
+	NodeId    string                 `json:"nodeId,omitempty"`
+	NodeName  string                 `json:"nodeName,omitempty"`
+	NodeType  string                 `json:"nodeType,omitempty"`
+	FlowId    string                 `json:"flowId,omitempty"`
+	TokenId   string                 `json:"tokenId,omitempty"`
+	User      string                 `json:"user,omitempty"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+// InstanceState is the persisted state of a process instance. The joins hold, per parallel
+// gateway, the incoming flows the tokens have arrived by and wait for the others.
+type InstanceState struct {
+	Variables  map[string]interface{} `json:"variables"`
+	Tokens     []*Token               `json:"tokens"`
+	Joins      map[string][]string    `json:"joins"`
+	History    []*HistoryEvent        `json:"history"`
+	TokenCount int                    `json:"tokenCount"`
+	IsEnded    bool                   `json:"isEnded"`
+}
+
+// ServiceTaskFunc executes a service task, the returned variables are merged into the instance variables.
+type ServiceTaskFunc func(node *Node, variables map[string]interface{}) (map[string]interface{}, error)
+
+// Engine runs the process instances of a model. It keeps no state itself: each call advances the
+// given instance state until all its tokens wait at a user task or a timer, or the instance ends.
+type Engine struct {
+	Model       *Model
+	CallService ServiceTaskFunc
+	Now         func() time.Time
+	Lang        string
+}
+
+func NewEngine(model *Model, callService ServiceTaskFunc, lang string) *Engine {
+	return &Engine{
+		Model:       model,
+		CallService: callService,
+		Now:         time.Now,
+		Lang:        lang,
+	}
+}
+
+func NewInstanceState(variables map[string]interface{}) *InstanceState {
+	if variables == nil {
+		variables = map[string]interface{}{}
+	}
+	return &InstanceState{
+		Variables: variables,
+		Tokens:    []*Token{},
+		Joins:     map[string][]string{},
+		History:   []*HistoryEvent{},
+	}
+}
+
+func (e *Engine) getTime() string {
+	return e.Now().Format(time.RFC3339)
+}
+
+func (e *Engine) addHistory(state *InstanceState, typ string, node *Node, token *Token, user string, variables map[string]interface{}) {
+	event := &HistoryEvent{Time: e.getTime(), Type: typ, User: user, Variables: variables}
+	if node != nil {
+		event.NodeId = node.Id
+		event.NodeName = node.Name
+		event.NodeType = node.Type
+	}
+	if token != nil {
+		event.TokenId = token.Id
+		event.FlowId = token.FlowId
+	}
+	state.History = append(state.History, event)
+}
+
+func (e *Engine) addToken(state *InstanceState, nodeId string, flowId string) {
+	state.TokenCount++
+	state.Tokens = append(state.Tokens, &Token{
+		Id:          fmt.Sprintf("token_%d", state.TokenCount),
+		NodeId:      nodeId,
+		FlowId:      flowId,
+		State:       TokenStateActive,
+		CreatedTime: e.getTime(),
+	})
+}
+
+func removeToken(state *InstanceState, token *Token) {
+	for i, t := range state.Tokens {
+		if t == token {
+			state.Tokens = append(state.Tokens[:i], state.Tokens[i+1:]...)
+			return
+		}
+	}
+}
+
+func getToken(state *InstanceState, tokenId string) *Token {
+	for _, token := range state.Tokens {
+		if token.Id == tokenId {
+			return token
+		}
+	}
+	return nil
+}
+
+// leave moves the token out of its node along the flows.
+func (e *Engine) leave(state *InstanceState, token *Token, node *Node, flowIds []string) {
+	removeToken(state, token)
+	e.addHistory(state, HistoryTypeNodeCompleted, node, token, "", nil)
+	for _, flowId := range flowIds {
+		e.addToken(state, e.Model.Flows[flowId].TargetRef, flowId)
+	}
+}
+
+// Start puts a token on the start event of the model and runs the instance up to its wait states.
+func (e *Engine) Start(state *InstanceState, user string) error {
+	if len(e.Model.StartNodes) == 0 {
+		return fmt.Errorf(i18n.Translate(e.Lang, "bpmn:The BPMN file has no start event"))
+	}
+
+	e.addHistory(state, HistoryTypeInstanceStarted, nil, nil, user, copyVariables(state.Variables))
+	e.addToken(state, e.Model.StartNodes[0], "")
+	return e.advance(state)
+}
+
+// CompleteUserTask completes the user task the token waits at, merges the variables submitted
+// with it and runs the instance up to its next wait states.
+func (e *Engine) CompleteUserTask(state *InstanceState, tokenId string, user string, variables map[string]interface{}) error {
+	token, err := e.GetUserTask(state, tokenId)
+	if err != nil {
+		return err
+	}
+	node := e.Model.Nodes[token.NodeId]
+
+	for key, value := range variables {
+		state.Variables[key] = value
+	}
+	e.addHistory(state, HistoryTypeTaskCompleted, node, token, user, variables)
+	e.leave(state, token, node, node.Outgoing)
+	return e.advance(state)
+}
+
+// GetUserTask returns the token waiting at a user task.
+func (e *Engine) GetUserTask(state *InstanceState, tokenId string) (*Token, error) {
+	token := getToken(state, tokenId)
+	if token == nil || token.State != TokenStateWaiting {
+		return nil, fmt.Errorf(i18n.Translate(e.Lang, "bpmn:The task: %s is not waiting"), tokenId)
+	}
+	node := e.Model.Nodes[token.NodeId]
+	if node == nil || node.Type != NodeTypeUserTask {
+		return nil, fmt.Errorf(i18n.Translate(e.Lang, "bpmn:The task: %s is not a user task"), tokenId)
+	}
+	return token, nil
+}
+
+// FireTimers fires the timers which are due and runs the instance up to its next wait states, it
+// tells whether any timer has fired.
+func (e *Engine) FireTimers(state *InstanceState) (bool, error) {
+	now := e.Now()
+	isFired := false
+	for _, token := range state.Tokens {
+		if token.State != TokenStateWaiting || token.DueTime == "" {
+			continue
+		}
+
+		dueTime, err := time.Parse(time.RFC3339, token.DueTime)
+		if err != nil || !now.Before(dueTime) {
+			token.State = TokenStateActive
+			isFired = true
+		}
+	}
+	if !isFired {
+		return false, nil
+	}
+	return true, e.advance(state)
+}
+
+// GetNextDueTime returns the earliest due time of the timers the instance waits for, empty if none.
+func GetNextDueTime(state *InstanceState) string {
+	res := ""
+	for _, token := range state.Tokens {
+		if token.State == TokenStateWaiting && token.DueTime != "" && (res == "" || token.DueTime < res) {
+			res = token.DueTime
+		}
+	}
+	return res
+}
+
+func (e *Engine) advance(state *InstanceState) error {
+	for step := 0; ; step++ {
+		if step >= maxEngineSteps {
+			return fmt.Errorf(i18n.Translate(e.Lang, "bpmn:The process exceeds %d steps without waiting, please check it for loops"), maxEngineSteps)
+		}
+
+		var token *Token
+		for _, t := range state.Tokens {
+			if t.State == TokenStateActive {
+				token = t
+				break
+			}
+		}
+		if token == nil {
+			break
+		}
+
+		err := e.execute(state, token)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(state.Tokens) == 0 && !state.IsEnded {
+		state.IsEnded = true
+		e.addHistory(state, HistoryTypeInstanceCompleted, nil, nil, "", nil)
+	}
+	return nil
+}
+
+func (e *Engine) execute(state *InstanceState, token *Token) error {
+	node, ok := e.Model.Nodes[token.NodeId]
+	if !ok {
+		return fmt.Errorf(i18n.Translate(e.Lang, "bpmn:The node: %s is not found"), token.NodeId)
+	}
+
+	// a fired timer or a token arriving at a join does not enter the node again
+	if token.DueTime == "" && !(node.Type == NodeTypeParallelGateway && len(node.Incoming) > 1) {
+		e.addHistory(state, HistoryTypeNodeEntered, node, token, "", nil)
+	}
+
+	switch node.Type {
+	case NodeTypeStartEvent, NodeTypeTask, NodeTypeManualTask, NodeTypeIntermediateThrowEvent:
+		e.leave(state, token, node, node.Outgoing)
+	case NodeTypeEndEvent:
+		removeToken(state, token)
+		e.addHistory(state, HistoryTypeNodeCompleted, node, token, "", nil)
+	case NodeTypeIntermediateCatchEvent:
+		if node.Timer == nil {
+			return fmt.Errorf(i18n.Translate(e.Lang, "bpmn:The intermediate catch event: %s is not a timer"), node.Id)
+		}
+		if token.DueTime != "" {
+			e.addHistory(state, HistoryTypeTimerFired, node, token, "", nil)
+			e.leave(state, token, node, node.Outgoing)
+			return nil
+		}
+
+		dueTime, err := node.Timer.GetDueTime(e.Now(), e.Lang)
+		if err != nil {
+			return err
+		}
+		token.State = TokenStateWaiting
+		token.DueTime = dueTime.Format(time.RFC3339)
+	case NodeTypeUserTask:
+		token.State = TokenStateWaiting
+		token.Assignee = strings.TrimSpace(ReplaceVariables(node.Attributes["assignee"], state.Variables))
+		token.CandidateUsers = []string{}
+		for _, user := range strings.Split(ReplaceVariables(node.Attributes["candidateUsers"], state.Variables), ",") {
+			if user = strings.TrimSpace(user); user != "" {
+				token.CandidateUsers = append(token.CandidateUsers, user)
+			}
+		}
+	case NodeTypeServiceTask:
+		if e.CallService == nil {
+			return fmt.Errorf(i18n.Translate(e.Lang, "bpmn:The service task: %s cannot be executed"), node.Id)
+		}
+
+		variables, err := e.CallService(node, state.Variables)
+		if err != nil {
+			return fmt.Errorf(i18n.Translate(e.Lang, "bpmn:The service task: %s failed: %s"), node.Id, err.Error())
+		}
+		for key, value := range variables {
+			state.Variables[key] = value
+		}
+		e.addHistory(state, HistoryTypeTaskCompleted, node, token, "", variables)
+		e.leave(state, token, node, node.Outgoing)
+	case NodeTypeExclusiveGateway:
+		flowId, err := e.getExclusiveFlow(state, node)
+		if err != nil {
+			return err
+		}
+		e.addHistory(state, HistoryTypeFlowTaken, node, &Token{Id: token.Id, FlowId: flowId}, "", nil)
+		e.leave(state, token, node, []string{flowId})
+	case NodeTypeParallelGateway:
+		if len(node.Incoming) <= 1 {
+			e.leave(state, token, node, node.Outgoing)
+			return nil
+		}
+
+		// the join waits for a token from each of its incoming flows
+		removeToken(state, token)
+		arrived := append(state.Joins[node.Id], token.FlowId)
+		state.Joins[node.Id] = arrived
+		for _, flowId := range node.Incoming {
+			if !containsString(arrived, flowId) {
+				return nil
+			}
+		}
+
+		state.Joins[node.Id] = removeJoinedFlows(arrived, node.Incoming)
+		if len(state.Joins[node.Id]) == 0 {
+			delete(state.Joins, node.Id)
+		}
+		e.addHistory(state, HistoryTypeNodeEntered, node, token, "", nil)
+		e.addHistory(state, HistoryTypeNodeCompleted, node, token, "", nil)
+		for _, flowId := range node.Outgoing {
+			e.addToken(state, e.Model.Flows[flowId].TargetRef, flowId)
+		}
+	default:
+		return fmt.Errorf(i18n.Translate(e.Lang, "bpmn:The BPMN element type: %s of node: %s is not supported"), node.Type, node.Id)
+	}
+	return nil
+}
+
+// getExclusiveFlow takes the first outgoing flow whose condition holds, the default flow when none does.
+func (e *Engine) getExclusiveFlow(state *InstanceState, node *Node) (string, error) {
+	for _, flowId := range node.Outgoing {
+		if flowId == node.Default {
+			continue
+		}
+
+		ok, err := EvaluateExpression(e.Model.Flows[flowId].Condition, state.Variables)
+		if err != nil {
+			return "", fmt.Errorf(i18n.Translate(e.Lang, "bpmn:The condition of the sequence flow: %s is invalid: %s"), flowId, err.Error())
+		}
+		if ok {
+			return flowId, nil
+		}
+	}
+
+	if node.Default != "" {
+		return node.Default, nil
+	}
+	return "", fmt.Errorf(i18n.Translate(e.Lang, "bpmn:No outgoing flow of the exclusive gateway: %s matches"), node.Id)
+}
+
+// removeJoinedFlows removes one arrival of each of the joined flows, keeping the extra arrivals
+// for the next activation of the join.
+func removeJoinedFlows(arrived []string, joined []string) []string {
+	res := append([]string{}, arrived...)
+	for _, flowId := range joined {
+		for i, arrivedFlowId := range res {
+			if arrivedFlowId == flowId {
+				res = append(res[:i], res[i+1:]...)
+				break
+			}
+		}
+	}
+	return res
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func copyVariables(variables map[string]interface{}) map[string]interface{} {
+	res := map[string]interface{}{}
+	for key, value := range variables {
+		res[key] = value
+	}
+	return res
+}
+
+// CanCompleteTask tells whether the user is the assignee or one of the candidate users of the
+// task the token waits at. A task without any assignment can be completed by anyone allowed to
+// handle the instance.
+func (token *Token) CanCompleteTask(user string) bool {
+	if token.Assignee == "" && len(token.CandidateUsers) == 0 {
+		return true
+	}
+	return token.Assignee == user || containsString(token.CandidateUsers, user)
+}
@@ -0,0 +1,135 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build skipCi
+// +build skipCi
+
+package bpmn
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestEngine(t *testing.T, now *time.Time) *Engine {
+	model, err := ParseModel(testProcessText, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	engine := NewEngine(model, func(node *Node, variables map[string]interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{"checked": true}, nil
+	}, "en")
+	engine.Now = func() time.Time { return *now }
+	return engine
+}
+
+func TestEngineDefaultFlow(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	engine := newTestEngine(t, &now)
+
+	state := NewInstanceState(map[string]interface{}{"amount": 10.0})
+	err := engine.Start(state, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !state.IsEnded || state.Variables["checked"] != true {
+		t.Errorf("expected the instance to end through the default flow with the service task result, got: %+v", state)
+	}
+}
+
+func TestEngineParallelUserTaskAndTimer(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	engine := newTestEngine(t, &now)
+
+	state := NewInstanceState(map[string]interface{}{"amount": 5000.0, "manager": "bob"})
+	err := engine.Start(state, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Tokens) != 2 {
+		t.Fatalf("expected 2 waiting tokens after the fork, got: %d", len(state.Tokens))
+	}
+
+	var task *Token
+	for _, token := range state.Tokens {
+		if token.NodeId == "Approve" {
+			task = token
+		}
+	}
+	if task == nil || task.Assignee != "bob" || !task.CanCompleteTask("bob") || task.CanCompleteTask("alice") {
+		t.Fatalf("expected the user task to be assigned to bob, got: %+v", task)
+	}
+	if GetNextDueTime(state) != "2025-01-02T00:00:00Z" {
+		t.Errorf("expected the timer to be due in a day, got: %s", GetNextDueTime(state))
+	}
+
+	err = engine.CompleteUserTask(state, task.Id, "bob", map[string]interface{}{"approved": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.IsEnded || len(state.Joins["Join"]) != 1 {
+		t.Fatalf("expected the join to wait for the timer, got: %+v", state)
+	}
+
+	isFired, err := engine.FireTimers(state)
+	if err != nil || isFired {
+		t.Fatalf("expected the timer not to fire before its due time, got: %v, %v", isFired, err)
+	}
+
+	now = now.Add(25 * time.Hour)
+	isFired, err = engine.FireTimers(state)
+	if err != nil || !isFired {
+		t.Fatalf("expected the timer to fire, got: %v, %v", isFired, err)
+	}
+	if !state.IsEnded || len(state.Joins) != 0 || state.Variables["approved"] != true {
+		t.Errorf("expected the instance to end after the join, got: %+v", state)
+	}
+}
+
+func TestEvaluateExpression(t *testing.T) {
+	variables := map[string]interface{}{
+		"amount":  1500.0,
+		"level":   "urgent",
+		"patient": map[string]interface{}{"age": "70"},
+	}
+
+	cases := []struct {
+		expression string
+		expected   bool
+	}{
+		{"", true},
+		{"${amount > 1000}", true},
+		{"${amount gt 1000 and level == 'urgent'}", true},
+		{"${amount &lt;= 1000 || level != \"urgent\"}", false},
+		{"${!(patient.age >= 65)}", false},
+		{"${missing == null}", true},
+		{"#{level eq 'normal'}", false},
+	}
+	for _, c := range cases {
+		result, err := EvaluateExpression(c.expression, variables)
+		if err != nil {
+			t.Errorf("unexpected error for %s: %v", c.expression, err)
+			continue
+		}
+		if result != c.expected {
+			t.Errorf("expected %v for %s, got: %v", c.expected, c.expression, result)
+		}
+	}
+
+	_, err := EvaluateExpression("${amount >}", variables)
+	if err == nil {
+		t.Errorf("expected an error for an incomplete expression")
+	}
+}
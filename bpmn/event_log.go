@@ -0,0 +1,246 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpmn
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/casibase/casibase/i18n"
+)
+
+// EventLogRow is an event of a case, the rows of all cases are grouped into the traces of a log.
+type EventLogRow struct {
+	CaseId    string    `json:"caseId"`
+	Activity  string    `json:"activity"`
+	Timestamp time.Time `json:"timestamp"`
+	Resource  string    `json:"resource"`
+}
+
+type Event struct {
+	Activity  string    `json:"activity"`
+	Timestamp time.Time `json:"timestamp"`
+	Resource  string    `json:"resource"`
+}
+
+// Trace is the events of a case in the order they happened.
+type Trace struct {
+	CaseId string   `json:"caseId"`
+	Events []*Event `json:"events"`
+}
+
+type EventLog struct {
+	Traces []*Trace `json:"traces"`
+}
+
+// the column names the CSV event logs use for the case id, the activity, the timestamp and the
+// resource, the XES attribute names among them
+var (
+	caseColumnNames      = []string{"case", "case id", "caseid", "case_id", "case:concept:name"}
+	activityColumnNames  = []string{"activity", "activity name", "concept:name", "event", "action"}
+	timestampColumnNames = []string{"timestamp", "time", "time:timestamp", "complete timestamp", "date"}
+	resourceColumnNames  = []string{"resource", "org:resource", "user"}
+)
+
+var eventTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"2006-01-02",
+	"2006/01/02",
+}
+
+// ParseEventTime parses the timestamp of an event, in one of the common date layouts or as Unix seconds.
+func ParseEventTime(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range eventTimeLayouts {
+		t, err := time.ParseInLocation(layout, value, time.Local)
+		if err == nil {
+			return t, true
+		}
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), true
+	}
+	return time.Time{}, false
+}
+
+// NewEventLog groups the rows by their case into traces, the cases in the order they first appear
+// and the events of each case by their timestamps, keeping the order of the rows for equal ones.
+func NewEventLog(rows []*EventLogRow) *EventLog {
+	log := &EventLog{Traces: []*Trace{}}
+	traces := map[string]*Trace{}
+	for _, row := range rows {
+		if row.CaseId == "" || row.Activity == "" {
+			continue
+		}
+
+		trace, ok := traces[row.CaseId]
+		if !ok {
+			trace = &Trace{CaseId: row.CaseId, Events: []*Event{}}
+			traces[row.CaseId] = trace
+			log.Traces = append(log.Traces, trace)
+		}
+		trace.Events = append(trace.Events, &Event{Activity: row.Activity, Timestamp: row.Timestamp, Resource: row.Resource})
+	}
+
+	for _, trace := range log.Traces {
+		sort.SliceStable(trace.Events, func(i, j int) bool {
+			return trace.Events[i].Timestamp.Before(trace.Events[j].Timestamp)
+		})
+	}
+	return log
+}
+
+// GetEventCount returns the number of events of all traces.
+func (log *EventLog) GetEventCount() int {
+	res := 0
+	for _, trace := range log.Traces {
+		res += len(trace.Events)
+	}
+	return res
+}
+
+func findColumn(header []string, column string, names []string) int {
+	if column != "" {
+		names = []string{column}
+	}
+	for _, name := range names {
+		for i, field := range header {
+			if strings.EqualFold(strings.TrimSpace(field), name) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// ParseCsvEventLog parses a CSV event log with a header row. The columns are the given ones, or
+// else found by their usual names like "case id", "activity" and "timestamp". The timestamp column
+// is optional, the rows of a case are then taken in the file order.
+func ParseCsvEventLog(data []byte, caseColumn string, activityColumn string, timestampColumn string, lang string) (*EventLog, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) < 2 {
+		return nil, fmt.Errorf(i18n.Translate(lang, "bpmn:The event log is empty"))
+	}
+
+	header := records[0]
+	caseIndex := findColumn(header, caseColumn, caseColumnNames)
+	activityIndex := findColumn(header, activityColumn, activityColumnNames)
+	timestampIndex := findColumn(header, timestampColumn, timestampColumnNames)
+	resourceIndex := findColumn(header, "", resourceColumnNames)
+	if caseIndex == -1 || activityIndex == -1 {
+		return nil, fmt.Errorf(i18n.Translate(lang, "bpmn:The event log has no case id or activity column"))
+	}
+
+	rows := []*EventLogRow{}
+	for i, record := range records[1:] {
+		getField := func(index int) string {
+			if index == -1 || index >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[index])
+		}
+
+		row := &EventLogRow{CaseId: getField(caseIndex), Activity: getField(activityIndex), Resource: getField(resourceIndex)}
+		if timestampIndex != -1 {
+			timestamp, ok := ParseEventTime(getField(timestampIndex))
+			if !ok {
+				return nil, fmt.Errorf(i18n.Translate(lang, "bpmn:The timestamp: %s of row: %d is invalid"), getField(timestampIndex), i+2)
+			}
+			row.Timestamp = timestamp
+		}
+		rows = append(rows, row)
+	}
+	return NewEventLog(rows), nil
+}
+
+func getXesAttribute(element *xmlElement, key string) string {
+	for i := range element.Children {
+		child := &element.Children[i]
+		if child.getAttr("key") == key {
+			return child.getAttr("value")
+		}
+	}
+	return ""
+}
+
+// ParseXesEventLog parses an XES event log. The events with a lifecycle transition other than
+// complete, like the start events of the activities, are left out.
+func ParseXesEventLog(data []byte, lang string) (*EventLog, error) {
+	var root xmlElement
+	err := xml.Unmarshal(data, &root)
+	if err != nil {
+		return nil, fmt.Errorf(i18n.Translate(lang, "bpmn:Error parsing XES file: %v"), err)
+	}
+
+	rows := []*EventLogRow{}
+	for i, trace := range root.getChildren("trace") {
+		caseId := getXesAttribute(trace, "concept:name")
+		if caseId == "" {
+			caseId = strconv.Itoa(i + 1)
+		}
+
+		for _, event := range trace.getChildren("event") {
+			transition := strings.ToLower(getXesAttribute(event, "lifecycle:transition"))
+			if transition != "" && transition != "complete" {
+				continue
+			}
+
+			row := &EventLogRow{
+				CaseId:   caseId,
+				Activity: getXesAttribute(event, "concept:name"),
+				Resource: getXesAttribute(event, "org:resource"),
+			}
+			if timestamp, ok := ParseEventTime(getXesAttribute(event, "time:timestamp")); ok {
+				row.Timestamp = timestamp
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf(i18n.Translate(lang, "bpmn:The event log is empty"))
+	}
+	return NewEventLog(rows), nil
+}
+
+// ParseEventLog parses a CSV or an XES event log by the extension of its file name.
+func ParseEventLog(fileName string, data []byte, caseColumn string, activityColumn string, timestampColumn string, lang string) (*EventLog, error) {
+	ext := strings.ToLower(filepath.Ext(fileName))
+	switch ext {
+	case ".csv":
+		return ParseCsvEventLog(data, caseColumn, activityColumn, timestampColumn, lang)
+	case ".xes":
+		return ParseXesEventLog(data, lang)
+	default:
+		return nil, fmt.Errorf(i18n.Translate(lang, "bpmn:The event log file type: %s is not supported"), ext)
+	}
+}
@@ -0,0 +1,375 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpmn
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// expressionParser evaluates the condition expressions of the sequence flows, like
+// ${amount > 1000 && level == "urgent"}. The expressions support the comparison operators, the
+// logical operators &&, ||, ! and their JUEL forms and, or, not, eq, ne, lt, gt, le, ge, the
+// parentheses, and number, string, boolean and null literals. The variables are looked up in the
+// instance variables, a dotted name like patient.age looks up the nested field.
+type expressionParser struct {
+	tokens    []string
+	position  int
+	variables map[string]interface{}
+}
+
+var expressionKeywords = map[string]string{
+	"and": "&&",
+	"or":  "||",
+	"not": "!",
+	"eq":  "==",
+	"ne":  "!=",
+	"lt":  "<",
+	"gt":  ">",
+	"le":  "<=",
+	"ge":  ">=",
+}
+
+func tokenizeExpression(expression string) ([]string, error) {
+	tokens := []string{}
+	runes := []rune(expression)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'':
+			j := i + 1
+			for j < len(runes) && runes[j] != r {
+				if runes[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("unterminated string in the expression: %s", expression)
+			}
+			tokens = append(tokens, string(runes[i:j+1]))
+			i = j + 1
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			j := i
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, string(runes[i:j]))
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_' || runes[j] == '.') {
+				j++
+			}
+			word := string(runes[i:j])
+			if operator, ok := expressionKeywords[word]; ok {
+				word = operator
+			}
+			tokens = append(tokens, word)
+			i = j
+		default:
+			if i+1 < len(runes) {
+				operator := string(runes[i : i+2])
+				if operator == "==" || operator == "!=" || operator == "<=" || operator == ">=" || operator == "&&" || operator == "||" {
+					tokens = append(tokens, operator)
+					i += 2
+					continue
+				}
+			}
+			if strings.ContainsRune("<>!()", r) {
+				tokens = append(tokens, string(r))
+				i++
+				continue
+			}
+			return nil, fmt.Errorf("unexpected character: %c in the expression: %s", r, expression)
+		}
+	}
+	return tokens, nil
+}
+
+func (p *expressionParser) peek() string {
+	if p.position >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.position]
+}
+
+func (p *expressionParser) next() string {
+	token := p.peek()
+	p.position++
+	return token
+}
+
+func (p *expressionParser) parseOr() (interface{}, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "||" {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = isTruthy(left) || isTruthy(right)
+	}
+	return left, nil
+}
+
+func (p *expressionParser) parseAnd() (interface{}, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "&&" {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = isTruthy(left) && isTruthy(right)
+	}
+	return left, nil
+}
+
+func (p *expressionParser) parseNot() (interface{}, error) {
+	if p.peek() == "!" {
+		p.next()
+		value, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return !isTruthy(value), nil
+	}
+	return p.parseComparison()
+}
+
+func (p *expressionParser) parseComparison() (interface{}, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	operator := p.peek()
+	switch operator {
+	case "==", "!=", "<", ">", "<=", ">=":
+		p.next()
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		return compareValues(left, operator, right), nil
+	default:
+		return left, nil
+	}
+}
+
+func (p *expressionParser) parsePrimary() (interface{}, error) {
+	token := p.next()
+	switch {
+	case token == "":
+		return nil, fmt.Errorf("unexpected end of the expression")
+	case token == "(":
+		value, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("missing ) in the expression")
+		}
+		return value, nil
+	case token[0] == '"' || token[0] == '\'':
+		return strings.ReplaceAll(token[1:len(token)-1], "\\"+token[:1], token[:1]), nil
+	case unicode.IsDigit(rune(token[0])) || token[0] == '.':
+		return strconv.ParseFloat(token, 64)
+	case token == "true":
+		return true, nil
+	case token == "false":
+		return false, nil
+	case token == "null":
+		return nil, nil
+	case unicode.IsLetter(rune(token[0])) || token[0] == '_' || token[0] >= 0x80:
+		return getVariable(p.variables, token), nil
+	default:
+		return nil, fmt.Errorf("unexpected token: %s in the expression", token)
+	}
+}
+
+// getVariable looks up the variable, following the dots of the name into the nested maps.
+func getVariable(variables map[string]interface{}, name string) interface{} {
+	if value, ok := variables[name]; ok {
+		return value
+	}
+
+	var value interface{} = variables
+	for _, field := range strings.Split(name, ".") {
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = fields[field]
+	}
+	return value
+}
+
+func getNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func isTruthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != "" && v != "false"
+	default:
+		if f, ok := getNumber(v); ok {
+			return f != 0
+		}
+		return true
+	}
+}
+
+// compareValues compares the values as numbers when both are numbers or numeric strings, as
+// booleans when either is a boolean, and as strings otherwise.
+func compareValues(left interface{}, operator string, right interface{}) bool {
+	if left == nil || right == nil {
+		switch operator {
+		case "==":
+			return left == nil && right == nil
+		case "!=":
+			return !(left == nil && right == nil)
+		default:
+			return false
+		}
+	}
+
+	var result int
+	leftNumber, isLeftNumber := getNumber(left)
+	rightNumber, isRightNumber := getNumber(right)
+	_, isLeftBool := left.(bool)
+	_, isRightBool := right.(bool)
+	if isLeftNumber && isRightNumber {
+		if leftNumber < rightNumber {
+			result = -1
+		} else if leftNumber > rightNumber {
+			result = 1
+		}
+	} else if isLeftBool || isRightBool {
+		if isTruthy(left) != isTruthy(right) {
+			result = 1
+		}
+		if operator != "==" && operator != "!=" {
+			return false
+		}
+	} else {
+		result = strings.Compare(fmt.Sprintf("%v", left), fmt.Sprintf("%v", right))
+	}
+
+	switch operator {
+	case "==":
+		return result == 0
+	case "!=":
+		return result != 0
+	case "<":
+		return result < 0
+	case ">":
+		return result > 0
+	case "<=":
+		return result <= 0
+	default:
+		return result >= 0
+	}
+}
+
+// EvaluateExpression evaluates the condition expression against the variables, an empty
+// expression is true.
+func EvaluateExpression(expression string, variables map[string]interface{}) (bool, error) {
+	expression = strings.TrimSpace(expression)
+	expression = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&", "&quot;", "\"").Replace(expression)
+	if strings.HasPrefix(expression, "${") && strings.HasSuffix(expression, "}") {
+		expression = expression[2 : len(expression)-1]
+	} else if strings.HasPrefix(expression, "#{") && strings.HasSuffix(expression, "}") {
+		expression = expression[2 : len(expression)-1]
+	}
+	if strings.TrimSpace(expression) == "" {
+		return true, nil
+	}
+
+	tokens, err := tokenizeExpression(expression)
+	if err != nil {
+		return false, err
+	}
+
+	parser := &expressionParser{tokens: tokens, variables: variables}
+	value, err := parser.parseOr()
+	if err != nil {
+		return false, fmt.Errorf("%s in the expression: %s", err.Error(), expression)
+	}
+	if parser.position != len(tokens) {
+		return false, fmt.Errorf("unexpected token: %s in the expression: %s", parser.peek(), expression)
+	}
+	return isTruthy(value), nil
+}
+
+var variablePlaceholderRegex = regexp.MustCompile(`\$\{\s*([^{}\s]+)\s*\}`)
+
+// ReplaceVariables replaces the ${name} placeholders of the text with the values of the variables,
+// the maps and arrays are written as JSON.
+func ReplaceVariables(text string, variables map[string]interface{}) string {
+	return variablePlaceholderRegex.ReplaceAllStringFunc(text, func(placeholder string) string {
+		name := variablePlaceholderRegex.FindStringSubmatch(placeholder)[1]
+		value := getVariable(variables, name)
+		switch v := value.(type) {
+		case nil:
+			return ""
+		case string:
+			return v
+		case map[string]interface{}, []interface{}:
+			bytes, err := json.Marshal(v)
+			if err != nil {
+				return ""
+			}
+			return string(bytes)
+		default:
+			return fmt.Sprintf("%v", v)
+		}
+	})
+}
@@ -0,0 +1,153 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build skipCi
+// +build skipCi
+
+package bpmn
+
+import (
+	"testing"
+)
+
+const testEventLogCsv = `Case ID,Activity,Timestamp
+1,Register,2025-01-01 09:00:00
+1,Blood test,2025-01-01 09:10:00
+1,X-ray,2025-01-01 09:20:00
+1,Diagnose,2025-01-01 10:00:00
+2,Register,2025-01-02 09:00:00
+2,X-ray,2025-01-02 09:10:00
+2,Blood test,2025-01-02 09:20:00
+2,Diagnose,2025-01-02 10:00:00
+3,Register,2025-01-03 09:00:00
+3,Blood test,2025-01-03 09:10:00
+3,X-ray,2025-01-03 09:20:00
+3,Diagnose,2025-01-03 10:00:00
+4,Register,2025-01-04 09:00:00
+4,X-ray,2025-01-04 09:10:00
+4,Blood test,2025-01-04 09:20:00
+4,Diagnose,2025-01-04 10:00:00
+`
+
+const testEventLogXes = `<?xml version="1.0" encoding="UTF-8"?>
+<log xes.version="1.0">
+  <trace>
+    <string key="concept:name" value="5"/>
+    <event>
+      <string key="concept:name" value="Register"/>
+      <string key="lifecycle:transition" value="complete"/>
+      <date key="time:timestamp" value="2025-01-05T09:00:00+08:00"/>
+    </event>
+    <event>
+      <string key="concept:name" value="Diagnose"/>
+      <string key="lifecycle:transition" value="start"/>
+      <date key="time:timestamp" value="2025-01-05T09:30:00+08:00"/>
+    </event>
+    <event>
+      <string key="concept:name" value="Diagnose"/>
+      <string key="lifecycle:transition" value="complete"/>
+      <date key="time:timestamp" value="2025-01-05T10:00:00+08:00"/>
+    </event>
+    <event>
+      <string key="concept:name" value="Prescribe"/>
+      <date key="time:timestamp" value="2025-01-05T10:10:00+08:00"/>
+    </event>
+  </trace>
+</log>`
+
+func TestParseEventLog(t *testing.T) {
+	log, err := ParseEventLog("log.csv", []byte(testEventLogCsv), "", "", "", "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(log.Traces) != 4 || log.GetEventCount() != 16 || log.Traces[1].Events[1].Activity != "X-ray" {
+		t.Errorf("expected 4 cases of 4 events, got: %+v", log)
+	}
+
+	log, err = ParseEventLog("log.xes", []byte(testEventLogXes), "", "", "", "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(log.Traces) != 1 || log.Traces[0].CaseId != "5" || log.GetEventCount() != 3 {
+		t.Errorf("expected a case of 3 completed events, got: %+v", log.Traces[0])
+	}
+
+	_, err = ParseEventLog("log.csv", []byte("Patient,Step\n1,Register\n"), "", "", "", "en")
+	if err == nil {
+		t.Errorf("expected an error for a log without case id and activity columns")
+	}
+	_, err = ParseEventLog("log.csv", []byte("Patient,Step\n1,Register\n"), "Patient", "Step", "", "en")
+	if err != nil {
+		t.Errorf("unexpected error for the given columns: %v", err)
+	}
+}
+
+func TestDiscoverAndCheckConformance(t *testing.T) {
+	log, err := ParseEventLog("log.csv", []byte(testEventLogCsv), "", "", "", "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bpmnText, err := DiscoverBpmn(log, "Visit", nil, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	model, err := ParseModel(bpmnText, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parallelGateways := 0
+	for _, node := range model.Nodes {
+		if node.Type == NodeTypeParallelGateway {
+			parallelGateways++
+		}
+	}
+	if parallelGateways != 2 {
+		t.Errorf("expected the tests to be discovered as parallel, got %d parallel gateways in: %s", parallelGateways, bpmnText)
+	}
+	if issues := ValidateModel(model, "en"); len(issues) != 0 {
+		t.Errorf("expected the discovered model to be valid, got: %+v", issues[0])
+	}
+
+	report, err := CheckConformance(model, log, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Fitness != 1 || report.FittingCaseCount != 4 {
+		t.Errorf("expected the log to fit its discovered model, got: %+v", report)
+	}
+
+	deviating := NewEventLog([]*EventLogRow{
+		{CaseId: "5", Activity: "Register"},
+		{CaseId: "5", Activity: "Diagnose"},
+		{CaseId: "5", Activity: "Prescribe"},
+	})
+	report, err = CheckConformance(model, deviating, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := report.Cases[0]
+	if result.IsFitting || result.Fitness >= 1 || result.Missing == 0 {
+		t.Errorf("expected the case skipping the tests not to fit, got: %+v", result)
+	}
+	types := map[string]string{}
+	for _, deviation := range result.Deviations {
+		types[deviation.Activity] = deviation.Type
+	}
+	if types["Diagnose"] != DeviationTypeUnexpectedActivity || types["Prescribe"] != DeviationTypeUnknownActivity {
+		t.Errorf("expected an unexpected diagnosis and an unknown prescription, got: %v", types)
+	}
+}
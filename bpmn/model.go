@@ -0,0 +1,463 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpmn
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/casibase/casibase/i18n"
+)
+
+const (
+	NodeTypeStartEvent             = "startEvent"
+	NodeTypeEndEvent               = "endEvent"
+	NodeTypeIntermediateCatchEvent = "intermediateCatchEvent"
+	NodeTypeIntermediateThrowEvent = "intermediateThrowEvent"
+	NodeTypeBoundaryEvent          = "boundaryEvent"
+	NodeTypeImplicitThrowEvent     = "implicitThrowEvent"
+	NodeTypeTask                   = "task"
+	NodeTypeUserTask               = "userTask"
+	NodeTypeServiceTask            = "serviceTask"
+	NodeTypeManualTask             = "manualTask"
+	NodeTypeScriptTask             = "scriptTask"
+	NodeTypeBusinessRuleTask       = "businessRuleTask"
+	NodeTypeSendTask               = "sendTask"
+	NodeTypeReceiveTask            = "receiveTask"
+	NodeTypeCallActivity           = "callActivity"
+	NodeTypeSubProcess             = "subProcess"
+	NodeTypeAdHocSubProcess        = "adHocSubProcess"
+	NodeTypeTransaction            = "transaction"
+	NodeTypeExclusiveGateway       = "exclusiveGateway"
+	NodeTypeParallelGateway        = "parallelGateway"
+	NodeTypeInclusiveGateway       = "inclusiveGateway"
+	NodeTypeEventBasedGateway      = "eventBasedGateway"
+	NodeTypeComplexGateway         = "complexGateway"
+)
+
+const (
+	EventDefinitionTimer            = "timer"
+	EventDefinitionMessage          = "message"
+	EventDefinitionSignal           = "signal"
+	EventDefinitionError            = "error"
+	EventDefinitionEscalation       = "escalation"
+	EventDefinitionConditional      = "conditional"
+	EventDefinitionCompensate       = "compensate"
+	EventDefinitionLink             = "link"
+	EventDefinitionTerminate        = "terminate"
+	EventDefinitionCancel           = "cancel"
+	EventDefinitionMultiple         = "multiple"
+	EventDefinitionParallelMultiple = "parallelMultiple"
+)
+
+const (
+	TimerTypeDuration = "timeDuration"
+	TimerTypeDate     = "timeDate"
+	TimerTypeCycle    = "timeCycle"
+)
+
+const (
+	LoopTypeStandard   = "standard"
+	LoopTypeParallel   = "parallel"
+	LoopTypeSequential = "sequential"
+)
+
+// the flow nodes of BPMN 2.0, the sub-processes among them contain flow nodes themselves
+var nodeTypes = map[string]bool{
+	NodeTypeStartEvent:             true,
+	NodeTypeEndEvent:               true,
+	NodeTypeIntermediateCatchEvent: true,
+	NodeTypeIntermediateThrowEvent: true,
+	NodeTypeBoundaryEvent:          true,
+	NodeTypeImplicitThrowEvent:     true,
+	NodeTypeTask:                   true,
+	NodeTypeUserTask:               true,
+	NodeTypeServiceTask:            true,
+	NodeTypeManualTask:             true,
+	NodeTypeScriptTask:             true,
+	NodeTypeBusinessRuleTask:       true,
+	NodeTypeSendTask:               true,
+	NodeTypeReceiveTask:            true,
+	NodeTypeCallActivity:           true,
+	NodeTypeSubProcess:             true,
+	NodeTypeAdHocSubProcess:        true,
+	NodeTypeTransaction:            true,
+	NodeTypeExclusiveGateway:       true,
+	NodeTypeParallelGateway:        true,
+	NodeTypeInclusiveGateway:       true,
+	NodeTypeEventBasedGateway:      true,
+	NodeTypeComplexGateway:         true,
+}
+
+// Timer is the timer event definition of an event, its value is an ISO-8601 duration like PT2H for
+// a timeDuration, a date like 2025-01-01T09:00:00Z for a timeDate, and a repeating interval like
+// R3/PT10M for a timeCycle.
+type Timer struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// Node is a flow node of a process. The attributes hold the extension attributes of the element by
+// their local name whatever their namespace, so that camunda:assignee, flowable:assignee and
+// casibase:assignee are all the assignee. The parent is the sub-process containing the node, empty
+// for the nodes of the process itself.
+type Node struct {
+	Id               string            `json:"id"`
+	Name             string            `json:"name"`
+	Type             string            `json:"type"`
+	ProcessId        string            `json:"processId"`
+	ParentId         string            `json:"parentId,omitempty"`
+	Lane             string            `json:"lane,omitempty"`
+	Incoming         []string          `json:"incoming"`
+	Outgoing         []string          `json:"outgoing"`
+	Default          string            `json:"default,omitempty"`
+	EventDefinition  string            `json:"eventDefinition,omitempty"`
+	EventRef         string            `json:"eventRef,omitempty"`
+	Timer            *Timer            `json:"timer,omitempty"`
+	Condition        string            `json:"condition,omitempty"`
+	AttachedToRef    string            `json:"attachedToRef,omitempty"`
+	CancelActivity   bool              `json:"cancelActivity"`
+	TriggeredByEvent bool              `json:"triggeredByEvent"`
+	LoopType         string            `json:"loopType,omitempty"`
+	Attributes       map[string]string `json:"attributes"`
+}
+
+// Flow is a sequence flow, its condition is the text of its conditionExpression or, for the
+// diagrams drawn before the condition expressions were supported, its name when it is an expression.
+type Flow struct {
+	Id        string `json:"id"`
+	Name      string `json:"name"`
+	SourceRef string `json:"sourceRef"`
+	TargetRef string `json:"targetRef"`
+	Condition string `json:"condition"`
+}
+
+// MessageFlow is a message flow of a collaboration, between the nodes or the pools of its participants.
+type MessageFlow struct {
+	Id        string `json:"id"`
+	Name      string `json:"name"`
+	SourceRef string `json:"sourceRef"`
+	TargetRef string `json:"targetRef"`
+}
+
+type Lane struct {
+	Id           string   `json:"id"`
+	Name         string   `json:"name"`
+	ParentId     string   `json:"parentId,omitempty"`
+	FlowNodeRefs []string `json:"flowNodeRefs"`
+}
+
+// Process is a process of the diagram, the pool of a participant in a collaboration.
+type Process struct {
+	Id           string  `json:"id"`
+	Name         string  `json:"name"`
+	IsExecutable bool    `json:"isExecutable"`
+	Participant  string  `json:"participant,omitempty"`
+	Lanes        []*Lane `json:"lanes"`
+}
+
+// Model is the semantic model of a BPMN diagram: the nodes and the flows of all its processes, and
+// the message flows between them. The main process is the first executable process, the first
+// process if none is marked executable, and its start events are where the instances start.
+type Model struct {
+	Id           string           `json:"id"`
+	Name         string           `json:"name"`
+	Processes    []*Process       `json:"processes"`
+	Nodes        map[string]*Node `json:"nodes"`
+	Flows        map[string]*Flow `json:"flows"`
+	MessageFlows []*MessageFlow   `json:"messageFlows"`
+	StartNodes   []string         `json:"startNodes"`
+	NodeIds      []string         `json:"nodeIds"`
+}
+
+type xmlElement struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr   `xml:",any,attr"`
+	Text     string       `xml:",chardata"`
+	Children []xmlElement `xml:",any"`
+}
+
+func (element *xmlElement) getAttr(name string) string {
+	for _, attr := range element.Attrs {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+func (element *xmlElement) getChild(name string) *xmlElement {
+	for i := range element.Children {
+		if element.Children[i].XMLName.Local == name {
+			return &element.Children[i]
+		}
+	}
+	return nil
+}
+
+func (element *xmlElement) getChildren(name string) []*xmlElement {
+	res := []*xmlElement{}
+	for i := range element.Children {
+		if element.Children[i].XMLName.Local == name {
+			res = append(res, &element.Children[i])
+		}
+	}
+	return res
+}
+
+func getNodeAttributes(element *xmlElement) map[string]string {
+	attributes := map[string]string{}
+	for _, attr := range element.Attrs {
+		switch attr.Name.Local {
+		case "id", "name", "default":
+		default:
+			attributes[attr.Name.Local] = attr.Value
+		}
+	}
+	return attributes
+}
+
+// setEventDefinition sets the event definition of an event, the events with several definitions
+// are the multiple events, triggered by any of them or, when parallelMultiple, by all of them.
+func setEventDefinition(node *Node, element *xmlElement) {
+	definitions := []*xmlElement{}
+	for i := range element.Children {
+		if strings.HasSuffix(element.Children[i].XMLName.Local, "EventDefinition") {
+			definitions = append(definitions, &element.Children[i])
+		}
+	}
+	if len(definitions) == 0 {
+		return
+	}
+	if len(definitions) > 1 {
+		node.EventDefinition = EventDefinitionMultiple
+		if element.getAttr("parallelMultiple") == "true" {
+			node.EventDefinition = EventDefinitionParallelMultiple
+		}
+		return
+	}
+
+	definition := definitions[0]
+	node.EventDefinition = strings.TrimSuffix(definition.XMLName.Local, "EventDefinition")
+	for _, ref := range []string{"messageRef", "signalRef", "errorRef", "escalationRef", "activityRef"} {
+		if value := definition.getAttr(ref); value != "" {
+			node.EventRef = value
+		}
+	}
+	if node.EventDefinition == EventDefinitionLink {
+		node.EventRef = definition.getAttr("name")
+	}
+
+	switch node.EventDefinition {
+	case EventDefinitionTimer:
+		for _, typ := range []string{TimerTypeDuration, TimerTypeDate, TimerTypeCycle} {
+			if child := definition.getChild(typ); child != nil {
+				node.Timer = &Timer{Type: typ, Value: strings.TrimSpace(child.Text)}
+				return
+			}
+		}
+		// the diagrams drawn before the timer definitions were supported keep the duration in the name
+		node.Timer = &Timer{Type: TimerTypeDuration, Value: definition.getAttr("name")}
+	case EventDefinitionConditional:
+		if condition := definition.getChild("condition"); condition != nil {
+			node.Condition = strings.TrimSpace(condition.Text)
+		}
+	}
+}
+
+func getLoopType(element *xmlElement) string {
+	if element.getChild("standardLoopCharacteristics") != nil {
+		return LoopTypeStandard
+	}
+	if loop := element.getChild("multiInstanceLoopCharacteristics"); loop != nil {
+		if loop.getAttr("isSequential") == "true" {
+			return LoopTypeSequential
+		}
+		return LoopTypeParallel
+	}
+	return ""
+}
+
+func parseLanes(process *Process, laneSet *xmlElement, parentId string) {
+	for _, element := range laneSet.getChildren("lane") {
+		lane := &Lane{
+			Id:           element.getAttr("id"),
+			Name:         element.getAttr("name"),
+			ParentId:     parentId,
+			FlowNodeRefs: []string{},
+		}
+		for _, ref := range element.getChildren("flowNodeRef") {
+			lane.FlowNodeRefs = append(lane.FlowNodeRefs, strings.TrimSpace(ref.Text))
+		}
+		process.Lanes = append(process.Lanes, lane)
+
+		if childLaneSet := element.getChild("childLaneSet"); childLaneSet != nil {
+			parseLanes(process, childLaneSet, lane.Id)
+		}
+	}
+}
+
+// parseFlowElements parses the flow nodes and the sequence flows of a process or a sub-process,
+// the sequence flows are collected in the document order to be linked once all nodes are known.
+func (model *Model) parseFlowElements(container *xmlElement, process *Process, parentId string, flows *[]*Flow) {
+	for i := range container.Children {
+		element := &container.Children[i]
+		typ := element.XMLName.Local
+		id := element.getAttr("id")
+		if typ == "sequenceFlow" {
+			flow := &Flow{
+				Id:        id,
+				Name:      element.getAttr("name"),
+				SourceRef: element.getAttr("sourceRef"),
+				TargetRef: element.getAttr("targetRef"),
+			}
+			if condition := element.getChild("conditionExpression"); condition != nil {
+				flow.Condition = strings.TrimSpace(condition.Text)
+			} else if strings.Contains(flow.Name, "${") {
+				flow.Condition = flow.Name
+			}
+			model.Flows[id] = flow
+			*flows = append(*flows, flow)
+			continue
+		}
+		if typ == "laneSet" && parentId == "" {
+			parseLanes(process, element, "")
+			continue
+		}
+		if !nodeTypes[typ] || id == "" {
+			continue
+		}
+
+		node := &Node{
+			Id:               id,
+			Name:             element.getAttr("name"),
+			Type:             typ,
+			ProcessId:        process.Id,
+			ParentId:         parentId,
+			Incoming:         []string{},
+			Outgoing:         []string{},
+			Default:          element.getAttr("default"),
+			AttachedToRef:    element.getAttr("attachedToRef"),
+			CancelActivity:   element.getAttr("cancelActivity") != "false",
+			TriggeredByEvent: element.getAttr("triggeredByEvent") == "true",
+			LoopType:         getLoopType(element),
+			Attributes:       getNodeAttributes(element),
+		}
+		setEventDefinition(node, element)
+		model.Nodes[id] = node
+		model.NodeIds = append(model.NodeIds, id)
+
+		if node.IsSubProcess() {
+			model.parseFlowElements(element, process, id, flows)
+		}
+	}
+}
+
+// IsSubProcess tells whether the node is an embedded sub-process with flow nodes of its own.
+func (node *Node) IsSubProcess() bool {
+	return node.Type == NodeTypeSubProcess || node.Type == NodeTypeAdHocSubProcess || node.Type == NodeTypeTransaction
+}
+
+// ParseModel parses the processes and the collaboration of the BPMN diagram into its semantic model.
+func ParseModel(bpmnText string, lang string) (*Model, error) {
+	var definitions xmlElement
+	err := xml.Unmarshal([]byte(bpmnText), &definitions)
+	if err != nil {
+		return nil, fmt.Errorf(i18n.Translate(lang, "bpmn:Error parsing BPMN file: %v"), err)
+	}
+
+	processElements := definitions.getChildren("process")
+	if len(processElements) == 0 {
+		return nil, fmt.Errorf(i18n.Translate(lang, "bpmn:The BPMN file has no process"))
+	}
+
+	model := &Model{
+		Processes:    []*Process{},
+		Nodes:        map[string]*Node{},
+		Flows:        map[string]*Flow{},
+		MessageFlows: []*MessageFlow{},
+		StartNodes:   []string{},
+		NodeIds:      []string{},
+	}
+
+	participants := map[string]string{}
+	if collaboration := definitions.getChild("collaboration"); collaboration != nil {
+		for _, participant := range collaboration.getChildren("participant") {
+			participants[participant.getAttr("processRef")] = participant.getAttr("id")
+		}
+		for _, messageFlow := range collaboration.getChildren("messageFlow") {
+			model.MessageFlows = append(model.MessageFlows, &MessageFlow{
+				Id:        messageFlow.getAttr("id"),
+				Name:      messageFlow.getAttr("name"),
+				SourceRef: messageFlow.getAttr("sourceRef"),
+				TargetRef: messageFlow.getAttr("targetRef"),
+			})
+		}
+	}
+
+	var mainProcess *Process
+	flows := []*Flow{}
+	for _, element := range processElements {
+		process := &Process{
+			Id:           element.getAttr("id"),
+			Name:         element.getAttr("name"),
+			IsExecutable: element.getAttr("isExecutable") == "true",
+			Participant:  participants[element.getAttr("id")],
+			Lanes:        []*Lane{},
+		}
+		model.Processes = append(model.Processes, process)
+		model.parseFlowElements(element, process, "", &flows)
+
+		if mainProcess == nil || (!mainProcess.IsExecutable && process.IsExecutable) {
+			mainProcess = process
+		}
+	}
+
+	model.Id = mainProcess.Id
+	model.Name = mainProcess.Name
+	for _, id := range model.NodeIds {
+		node := model.Nodes[id]
+		if node.Type == NodeTypeStartEvent && node.ProcessId == mainProcess.Id && node.ParentId == "" {
+			model.StartNodes = append(model.StartNodes, id)
+		}
+	}
+
+	for _, process := range model.Processes {
+		for _, lane := range process.Lanes {
+			for _, nodeId := range lane.FlowNodeRefs {
+				if node, ok := model.Nodes[nodeId]; ok {
+					node.Lane = lane.Id
+				}
+			}
+		}
+	}
+
+	// the flows are linked in the document order, which is the order the conditions are evaluated in
+	for _, flow := range flows {
+		source, ok := model.Nodes[flow.SourceRef]
+		if !ok {
+			return nil, fmt.Errorf(i18n.Translate(lang, "bpmn:The sequence flow: %s refers to the unknown node: %s"), flow.Id, flow.SourceRef)
+		}
+		target, ok := model.Nodes[flow.TargetRef]
+		if !ok {
+			return nil, fmt.Errorf(i18n.Translate(lang, "bpmn:The sequence flow: %s refers to the unknown node: %s"), flow.Id, flow.TargetRef)
+		}
+		source.Outgoing = append(source.Outgoing, flow.Id)
+		target.Incoming = append(target.Incoming, flow.Id)
+	}
+
+	return model, nil
+}
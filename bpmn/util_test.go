@@ -0,0 +1,46 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpmn
+
+// testProcessText is the process of the tests of both builds, with an exclusive gateway, a parallel
+// fork and join, a user task and a timer.
+const testProcessText = `<?xml version="1.0" encoding="UTF-8"?>
+<bpmn:definitions xmlns:bpmn="http://www.omg.org/spec/BPMN/20100524/MODEL" xmlns:camunda="http://camunda.org/schema/1.0/bpmn" id="Definitions_1">
+  <bpmn:process id="Process_1" isExecutable="true">
+    <bpmn:startEvent id="Start" />
+    <bpmn:sequenceFlow id="Flow_1" sourceRef="Start" targetRef="Check" />
+    <bpmn:serviceTask id="Check" name="Check" />
+    <bpmn:sequenceFlow id="Flow_2" sourceRef="Check" targetRef="Gateway" />
+    <bpmn:exclusiveGateway id="Gateway" default="Flow_Small" />
+    <bpmn:sequenceFlow id="Flow_Large" sourceRef="Gateway" targetRef="Fork">
+      <bpmn:conditionExpression>${amount &gt; 1000}</bpmn:conditionExpression>
+    </bpmn:sequenceFlow>
+    <bpmn:sequenceFlow id="Flow_Small" sourceRef="Gateway" targetRef="End" />
+    <bpmn:parallelGateway id="Fork" />
+    <bpmn:sequenceFlow id="Flow_3" sourceRef="Fork" targetRef="Approve" />
+    <bpmn:sequenceFlow id="Flow_4" sourceRef="Fork" targetRef="Wait" />
+    <bpmn:userTask id="Approve" name="Approve" camunda:assignee="${manager}" />
+    <bpmn:intermediateCatchEvent id="Wait">
+      <bpmn:timerEventDefinition>
+        <bpmn:timeDuration>P1D</bpmn:timeDuration>
+      </bpmn:timerEventDefinition>
+    </bpmn:intermediateCatchEvent>
+    <bpmn:sequenceFlow id="Flow_5" sourceRef="Approve" targetRef="Join" />
+    <bpmn:sequenceFlow id="Flow_6" sourceRef="Wait" targetRef="Join" />
+    <bpmn:parallelGateway id="Join" />
+    <bpmn:sequenceFlow id="Flow_7" sourceRef="Join" targetRef="End" />
+    <bpmn:endEvent id="End" />
+  </bpmn:process>
+</bpmn:definitions>`
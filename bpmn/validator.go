@@ -0,0 +1,252 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpmn
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/casibase/casibase/i18n"
+)
+
+const (
+	ValidationSeverityError   = "Error"
+	ValidationSeverityWarning = "Warning"
+)
+
+// ValidationIssue is a structural problem of a model. The errors make the process unable to run to
+// its end, the warnings are likely mistakes.
+type ValidationIssue struct {
+	Severity string `json:"severity"`
+	NodeId   string `json:"nodeId"`
+	Message  string `json:"message"`
+}
+
+type modelValidator struct {
+	model  *Model
+	lang   string
+	issues []*ValidationIssue
+}
+
+func (v *modelValidator) addIssue(severity string, nodeId string, message string) {
+	v.issues = append(v.issues, &ValidationIssue{
+		Severity: severity,
+		NodeId:   nodeId,
+		Message:  message,
+	})
+}
+
+// ValidateModel checks the model for the start events, the boundary events and the timers it
+// cannot run, the nodes unreachable from the start events, the branching gateways without a
+// default flow, and the parallel joins waiting for the branches of an exclusive choice, which
+// deadlock as only one of the branches is ever taken.
+func ValidateModel(model *Model, lang string) []*ValidationIssue {
+	v := &modelValidator{model: model, lang: lang, issues: []*ValidationIssue{}}
+
+	if len(model.StartNodes) == 0 {
+		v.addIssue(ValidationSeverityError, "", i18n.Translate(v.lang, "bpmn:The BPMN file has no start event"))
+	}
+
+	for _, id := range model.NodeIds {
+		v.validateNode(model.Nodes[id])
+	}
+
+	reachable := v.getReachableNodes("")
+	for _, id := range model.NodeIds {
+		node := model.Nodes[id]
+		if !reachable[id] && node.Attributes["isForCompensation"] != "true" {
+			v.addIssue(ValidationSeverityWarning, id, fmt.Sprintf(i18n.Translate(v.lang, "bpmn:The node: %s is unreachable from the start events"), id))
+		}
+	}
+
+	v.validateJoins(reachable)
+	return v.issues
+}
+
+func (v *modelValidator) validateNode(node *Node) {
+	if node.Type == NodeTypeBoundaryEvent {
+		activity, ok := v.model.Nodes[node.AttachedToRef]
+		if !ok || activity.Type == NodeTypeStartEvent || activity.Type == NodeTypeEndEvent || isGateway(activity) {
+			v.addIssue(ValidationSeverityError, node.Id, fmt.Sprintf(i18n.Translate(v.lang, "bpmn:The boundary event: %s is not attached to an activity"), node.Id))
+		}
+	}
+
+	if node.Timer != nil {
+		_, err := node.Timer.GetDueTime(time.Now(), v.lang)
+		if err != nil {
+			v.addIssue(ValidationSeverityError, node.Id, fmt.Sprintf(i18n.Translate(v.lang, "bpmn:The timer of the event: %s is invalid: %s"), node.Id, err.Error()))
+		}
+	}
+
+	if node.Type == NodeTypeStartEvent && len(node.Incoming) != 0 {
+		v.addIssue(ValidationSeverityError, node.Id, fmt.Sprintf(i18n.Translate(v.lang, "bpmn:The start event: %s has incoming flows"), node.Id))
+	}
+	if node.Type == NodeTypeEndEvent && len(node.Outgoing) != 0 {
+		v.addIssue(ValidationSeverityError, node.Id, fmt.Sprintf(i18n.Translate(v.lang, "bpmn:The end event: %s has outgoing flows"), node.Id))
+	}
+
+	if node.Default != "" && !containsString(node.Outgoing, node.Default) {
+		v.addIssue(ValidationSeverityError, node.Id, fmt.Sprintf(i18n.Translate(v.lang, "bpmn:The default flow: %s of the node: %s is not one of its outgoing flows"), node.Default, node.Id))
+	}
+
+	// a branching gateway whose flows are all conditional gets stuck when none of the conditions holds
+	if (node.Type == NodeTypeExclusiveGateway || node.Type == NodeTypeInclusiveGateway) && len(node.Outgoing) > 1 && node.Default == "" {
+		isAllConditional := true
+		for _, flowId := range node.Outgoing {
+			if v.model.Flows[flowId].Condition == "" {
+				isAllConditional = false
+			}
+		}
+		if isAllConditional {
+			v.addIssue(ValidationSeverityWarning, node.Id, fmt.Sprintf(i18n.Translate(v.lang, "bpmn:The gateway: %s has no default flow"), node.Id))
+		}
+	}
+}
+
+func isGateway(node *Node) bool {
+	switch node.Type {
+	case NodeTypeExclusiveGateway, NodeTypeParallelGateway, NodeTypeInclusiveGateway, NodeTypeEventBasedGateway, NodeTypeComplexGateway:
+		return true
+	default:
+		return false
+	}
+}
+
+// getSuccessors returns the nodes a token can move to from the node: the targets of its outgoing
+// flows, the boundary events attached to it, and the start events and the event sub-processes of
+// a sub-process.
+func (v *modelValidator) getSuccessors(node *Node) []string {
+	res := []string{}
+	for _, flowId := range node.Outgoing {
+		res = append(res, v.model.Flows[flowId].TargetRef)
+	}
+	for _, id := range v.model.NodeIds {
+		other := v.model.Nodes[id]
+		if other.Type == NodeTypeBoundaryEvent && other.AttachedToRef == node.Id {
+			res = append(res, id)
+		}
+		if node.IsSubProcess() && other.ParentId == node.Id && isEntryNode(other) {
+			res = append(res, id)
+		}
+	}
+	return res
+}
+
+// isEntryNode tells whether the node is entered without an incoming flow when its process or
+// sub-process is: its start events, its event sub-processes, and the catching link events.
+func isEntryNode(node *Node) bool {
+	if len(node.Incoming) != 0 {
+		return false
+	}
+	return node.Type == NodeTypeStartEvent || node.TriggeredByEvent ||
+		(node.Type == NodeTypeIntermediateCatchEvent && node.EventDefinition == EventDefinitionLink)
+}
+
+// getReachableNodes returns the nodes reachable from the entry nodes of the processes without
+// going through the blocked node.
+func (v *modelValidator) getReachableNodes(blocked string) map[string]bool {
+	queue := []string{}
+	for _, id := range v.model.NodeIds {
+		node := v.model.Nodes[id]
+		if node.ParentId == "" && isEntryNode(node) && id != blocked {
+			queue = append(queue, id)
+		}
+	}
+	return v.walk(queue, blocked)
+}
+
+func (v *modelValidator) walk(queue []string, blocked string) map[string]bool {
+	res := map[string]bool{}
+	for _, id := range queue {
+		res[id] = true
+	}
+	for len(queue) != 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, successor := range v.getSuccessors(v.model.Nodes[id]) {
+			if !res[successor] && successor != blocked {
+				res[successor] = true
+				queue = append(queue, successor)
+			}
+		}
+	}
+	return res
+}
+
+// validateJoins finds the parallel joins deadlocking behind an exclusive or event-based gateway: the
+// gateway is on every path to the join, and no single branch of it reaches all the incoming
+// flows of the join. The gateways in a loop are skipped, as they may be passed several times.
+func (v *modelValidator) validateJoins(reachable map[string]bool) {
+	for _, joinId := range v.model.NodeIds {
+		join := v.model.Nodes[joinId]
+		if join.Type != NodeTypeParallelGateway || len(join.Incoming) < 2 || !reachable[joinId] {
+			continue
+		}
+
+		for _, gatewayId := range v.model.NodeIds {
+			gateway := v.model.Nodes[gatewayId]
+			if (gateway.Type != NodeTypeExclusiveGateway && gateway.Type != NodeTypeEventBasedGateway) || len(gateway.Outgoing) < 2 || !reachable[gatewayId] {
+				continue
+			}
+			if v.isDeadlocking(join, gateway) {
+				v.addIssue(ValidationSeverityError, joinId, fmt.Sprintf(i18n.Translate(v.lang, "bpmn:The parallel join: %s deadlocks, as its incoming flows come from the different branches of the gateway: %s"), joinId, gatewayId))
+				break
+			}
+		}
+	}
+}
+
+func (v *modelValidator) isDeadlocking(join *Node, gateway *Node) bool {
+	// the gateway must be on every path to the join and not in a loop
+	if v.getReachableNodes(gateway.Id)[join.Id] {
+		return false
+	}
+	for _, successor := range v.getSuccessors(gateway) {
+		if v.walk([]string{successor}, join.Id)[gateway.Id] {
+			return false
+		}
+	}
+
+	// the branches of the gateway each incoming flow of the join is reachable from
+	var common map[string]bool
+	for _, flowId := range join.Incoming {
+		source := v.model.Flows[flowId].SourceRef
+		branches := map[string]bool{}
+		for _, branchId := range gateway.Outgoing {
+			if branchId == flowId {
+				branches[branchId] = true
+				continue
+			}
+			target := v.model.Flows[branchId].TargetRef
+			if target != join.Id && v.walk([]string{target}, join.Id)[source] {
+				branches[branchId] = true
+			}
+		}
+		if len(branches) == 0 {
+			return false
+		}
+
+		if common == nil {
+			common = branches
+			continue
+		}
+		for branchId := range common {
+			if !branches[branchId] {
+				delete(common, branchId)
+			}
+		}
+	}
+	return len(common) == 0
+}
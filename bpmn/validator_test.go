@@ -0,0 +1,218 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build skipCi
+// +build skipCi
+
+package bpmn
+
+import (
+	"testing"
+	"time"
+)
+
+const testCollaborationText = `<?xml version="1.0" encoding="UTF-8"?>
+<bpmn:definitions xmlns:bpmn="http://www.omg.org/spec/BPMN/20100524/MODEL" id="Definitions_1">
+  <bpmn:collaboration id="Collaboration_1">
+    <bpmn:participant id="Participant_Clinic" processRef="Process_Clinic" />
+    <bpmn:participant id="Participant_Lab" processRef="Process_Lab" />
+    <bpmn:messageFlow id="Message_1" sourceRef="Send" targetRef="Receive" />
+  </bpmn:collaboration>
+  <bpmn:process id="Process_Clinic" isExecutable="true">
+    <bpmn:laneSet id="LaneSet_1">
+      <bpmn:lane id="Lane_Doctor" name="Doctor">
+        <bpmn:flowNodeRef>Start</bpmn:flowNodeRef>
+        <bpmn:flowNodeRef>Review</bpmn:flowNodeRef>
+      </bpmn:lane>
+    </bpmn:laneSet>
+    <bpmn:startEvent id="Start" />
+    <bpmn:sequenceFlow id="Flow_1" sourceRef="Start" targetRef="Review" />
+    <bpmn:subProcess id="Review" name="Review">
+      <bpmn:startEvent id="Review_Start" />
+      <bpmn:sequenceFlow id="Flow_2" sourceRef="Review_Start" targetRef="Send" />
+      <bpmn:sendTask id="Send" name="Send sample" />
+      <bpmn:sequenceFlow id="Flow_3" sourceRef="Send" targetRef="Review_End" />
+      <bpmn:endEvent id="Review_End" />
+      <bpmn:multiInstanceLoopCharacteristics isSequential="true" />
+    </bpmn:subProcess>
+    <bpmn:boundaryEvent id="Timeout" attachedToRef="Review" cancelActivity="false">
+      <bpmn:timerEventDefinition>
+        <bpmn:timeCycle>R3/PT10M</bpmn:timeCycle>
+      </bpmn:timerEventDefinition>
+    </bpmn:boundaryEvent>
+    <bpmn:sequenceFlow id="Flow_4" sourceRef="Timeout" targetRef="Remind" />
+    <bpmn:scriptTask id="Remind" />
+    <bpmn:sequenceFlow id="Flow_5" sourceRef="Remind" targetRef="Remind_End" />
+    <bpmn:endEvent id="Remind_End" />
+    <bpmn:sequenceFlow id="Flow_6" sourceRef="Review" targetRef="Decide" />
+    <bpmn:inclusiveGateway id="Decide" default="Flow_8" />
+    <bpmn:sequenceFlow id="Flow_7" sourceRef="Decide" targetRef="End">
+      <bpmn:conditionExpression>${urgent}</bpmn:conditionExpression>
+    </bpmn:sequenceFlow>
+    <bpmn:sequenceFlow id="Flow_8" sourceRef="Decide" targetRef="End" />
+    <bpmn:endEvent id="End">
+      <bpmn:terminateEventDefinition />
+    </bpmn:endEvent>
+    <bpmn:userTask id="Orphan" />
+  </bpmn:process>
+  <bpmn:process id="Process_Lab">
+    <bpmn:startEvent id="Receive">
+      <bpmn:messageEventDefinition messageRef="Message_Sample" />
+    </bpmn:startEvent>
+  </bpmn:process>
+</bpmn:definitions>`
+
+const testDeadlockText = `<?xml version="1.0" encoding="UTF-8"?>
+<bpmn:definitions xmlns:bpmn="http://www.omg.org/spec/BPMN/20100524/MODEL" id="Definitions_1">
+  <bpmn:process id="Process_1" isExecutable="true">
+    <bpmn:startEvent id="Start" />
+    <bpmn:sequenceFlow id="Flow_1" sourceRef="Start" targetRef="Choice" />
+    <bpmn:exclusiveGateway id="Choice" />
+    <bpmn:sequenceFlow id="Flow_A" sourceRef="Choice" targetRef="TaskA">
+      <bpmn:conditionExpression>${a}</bpmn:conditionExpression>
+    </bpmn:sequenceFlow>
+    <bpmn:sequenceFlow id="Flow_B" sourceRef="Choice" targetRef="TaskB">
+      <bpmn:conditionExpression>${!a}</bpmn:conditionExpression>
+    </bpmn:sequenceFlow>
+    <bpmn:task id="TaskA" />
+    <bpmn:task id="TaskB" />
+    <bpmn:sequenceFlow id="Flow_2" sourceRef="TaskA" targetRef="Join" />
+    <bpmn:sequenceFlow id="Flow_3" sourceRef="TaskB" targetRef="Join" />
+    <bpmn:parallelGateway id="Join" />
+    <bpmn:sequenceFlow id="Flow_4" sourceRef="Join" targetRef="End" />
+    <bpmn:endEvent id="End" />
+  </bpmn:process>
+</bpmn:definitions>`
+
+func getIssueNodes(issues []*ValidationIssue, severity string) map[string]bool {
+	res := map[string]bool{}
+	for _, issue := range issues {
+		if issue.Severity == severity {
+			res[issue.NodeId] = true
+		}
+	}
+	return res
+}
+
+func TestParseModel(t *testing.T) {
+	model, err := ParseModel(testCollaborationText, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if model.Id != "Process_Clinic" || len(model.Processes) != 2 || len(model.MessageFlows) != 1 {
+		t.Fatalf("expected the executable process with 2 pools and a message flow, got: %+v", model)
+	}
+	if len(model.StartNodes) != 1 || model.StartNodes[0] != "Start" {
+		t.Errorf("expected the start event of the main process only, got: %v", model.StartNodes)
+	}
+
+	send := model.Nodes["Send"]
+	if send == nil || send.Type != NodeTypeSendTask || send.ParentId != "Review" {
+		t.Errorf("expected the send task inside the sub-process, got: %+v", send)
+	}
+	review := model.Nodes["Review"]
+	if review.Lane != "Lane_Doctor" || review.LoopType != LoopTypeSequential {
+		t.Errorf("expected the sub-process in the doctor lane with a sequential loop, got: %+v", review)
+	}
+	timeout := model.Nodes["Timeout"]
+	if timeout.AttachedToRef != "Review" || timeout.CancelActivity || timeout.Timer == nil || timeout.Timer.Type != TimerTypeCycle {
+		t.Errorf("expected a non-interrupting timer cycle on the sub-process, got: %+v", timeout)
+	}
+	if model.Nodes["Receive"].EventDefinition != EventDefinitionMessage || model.Nodes["Receive"].EventRef != "Message_Sample" {
+		t.Errorf("expected a message start event, got: %+v", model.Nodes["Receive"])
+	}
+	if model.Nodes["End"].EventDefinition != EventDefinitionTerminate {
+		t.Errorf("expected a terminate end event, got: %+v", model.Nodes["End"])
+	}
+	if model.Flows["Flow_7"].Condition != "${urgent}" {
+		t.Errorf("expected the condition of the condition expression, got: %s", model.Flows["Flow_7"].Condition)
+	}
+}
+
+func TestValidateModel(t *testing.T) {
+	model, err := ParseModel(testCollaborationText, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issues := ValidateModel(model, "en")
+	warnings := getIssueNodes(issues, ValidationSeverityWarning)
+	errors := getIssueNodes(issues, ValidationSeverityError)
+	if len(errors) != 0 {
+		t.Errorf("expected no errors, got: %v", errors)
+	}
+	if len(warnings) != 1 || !warnings["Orphan"] {
+		t.Errorf("expected only the orphan task to be unreachable, got: %v", warnings)
+	}
+
+	model, err = ParseModel(testDeadlockText, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issues = ValidateModel(model, "en")
+	warnings = getIssueNodes(issues, ValidationSeverityWarning)
+	errors = getIssueNodes(issues, ValidationSeverityError)
+	if !errors["Join"] || len(errors) != 1 {
+		t.Errorf("expected the join to deadlock, got: %v", errors)
+	}
+	if !warnings["Choice"] {
+		t.Errorf("expected the gateway without a default flow to be reported, got: %v", warnings)
+	}
+
+	model, err = ParseModel(testProcessText, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issues = ValidateModel(model, "en"); len(issues) != 0 {
+		t.Errorf("expected a fork followed by a join to be valid, got: %+v", issues[0])
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	cases := []struct {
+		duration string
+		expected time.Duration
+	}{
+		{"P3D", 3 * 24 * time.Hour},
+		{"PT1H30M", 90 * time.Minute},
+		{"P1W", 7 * 24 * time.Hour},
+		{"P1DT12H", 36 * time.Hour},
+		{"PT0.5S", 500 * time.Millisecond},
+		{"P1Y", 365 * 24 * time.Hour},
+	}
+	for _, c := range cases {
+		duration, err := ParseDuration(c.duration, "en")
+		if err != nil {
+			t.Errorf("unexpected error for %s: %v", c.duration, err)
+			continue
+		}
+		if duration != c.expected {
+			t.Errorf("expected %v for %s, got: %v", c.expected, c.duration, duration)
+		}
+	}
+
+	for _, duration := range []string{"", "P", "PT", "3D", "P1H", "PT1D"} {
+		if _, err := ParseDuration(duration, "en"); err == nil {
+			t.Errorf("expected an error for %s", duration)
+		}
+	}
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	dueTime, err := (&Timer{Type: TimerTypeCycle, Value: "R/2025-02-01T00:00:00Z/P1D"}).GetDueTime(now, "en")
+	if err != nil || !dueTime.Equal(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the cycle to start at its start date, got: %v, %v", dueTime, err)
+	}
+}
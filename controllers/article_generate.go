@@ -0,0 +1,187 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"fmt"
+
+	"github.com/casibase/casibase/object"
+	"github.com/casibase/casibase/util"
+)
+
+func (c *ApiController) getArticleToGenerate(id string) (*object.Article, bool) {
+	if !c.RequireAdmin() {
+		return nil, false
+	}
+
+	article, err := object.GetArticle(id)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return nil, false
+	}
+	if article == nil {
+		c.ResponseError(fmt.Sprintf(c.T("controllers:The article: %s is not found"), id))
+		return nil, false
+	}
+
+	return article, true
+}
+
+// GenerateArticle
+// @Title GenerateArticle
+// @Tag Article API
+// @Description generate the draft blocks of the article from their prompts with the knowledge of its store, and translate them to English
+// @Param id query string true "The id (owner/name) of the article"
+// @Success 200 {object} object.Article The Response object
+// @router /generate-article [post]
+func (c *ApiController) GenerateArticle() {
+	id := c.Input().Get("id")
+
+	article, ok := c.getArticleToGenerate(id)
+	if !ok {
+		return
+	}
+
+	err := object.GenerateArticle(article, c.GetAcceptLanguage())
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(article)
+}
+
+// GenerateArticleBlock
+// @Title GenerateArticleBlock
+// @Tag Article API
+// @Description regenerate a block of the article from its prompt, and translate it to English
+// @Param id query string true "The id (owner/name) of the article"
+// @Param index query int true "The index of the block"
+// @Success 200 {object} object.Article The Response object
+// @router /generate-article-block [post]
+func (c *ApiController) GenerateArticleBlock() {
+	id := c.Input().Get("id")
+	index := util.ParseInt(c.Input().Get("index"))
+
+	article, ok := c.getArticleToGenerate(id)
+	if !ok {
+		return
+	}
+
+	err := object.GenerateArticleBlock(article, index, c.GetAcceptLanguage())
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(article)
+}
+
+// TranslateArticleBlock
+// @Title TranslateArticleBlock
+// @Tag Article API
+// @Description translate the text of a block of the article to English following its glossary
+// @Param id query string true "The id (owner/name) of the article"
+// @Param index query int true "The index of the block"
+// @Success 200 {object} object.Article The Response object
+// @router /translate-article-block [post]
+func (c *ApiController) TranslateArticleBlock() {
+	id := c.Input().Get("id")
+	index := util.ParseInt(c.Input().Get("index"))
+
+	article, ok := c.getArticleToGenerate(id)
+	if !ok {
+		return
+	}
+
+	err := object.TranslateArticleBlock(article, index, c.GetAcceptLanguage())
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(article)
+}
+
+// UpdateArticleBlockState
+// @Title UpdateArticleBlockState
+// @Tag Article API
+// @Description mark a block of the article as reviewed, or set it back to draft
+// @Param id query string true "The id (owner/name) of the article"
+// @Param index query int true "The index of the block"
+// @Param state query string true "Draft or Reviewed"
+// @Success 200 {object} object.Article The Response object
+// @router /update-article-block-state [post]
+func (c *ApiController) UpdateArticleBlockState() {
+	id := c.Input().Get("id")
+	index := util.ParseInt(c.Input().Get("index"))
+	state := c.Input().Get("state")
+
+	article, ok := c.getArticleToGenerate(id)
+	if !ok {
+		return
+	}
+
+	err := object.SetArticleBlockState(article, index, state, c.GetAcceptLanguage())
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(article)
+}
+
+// ExportArticle
+// @Title ExportArticle
+// @Tag Article API
+// @Description export the article to a DOCX or Markdown file
+// @Param id query string true "The id (owner/name) of the article"
+// @Param format query string false "docx or md, docx by default"
+// @Param language query string false "en for the English text, the original text otherwise"
+// @Success 200 {file} file The DOCX or Markdown file
+// @router /export-article [get]
+func (c *ApiController) ExportArticle() {
+	id := c.Input().Get("id")
+	format := c.Input().Get("format")
+	language := c.Input().Get("language")
+
+	article, ok := c.getArticleToGenerate(id)
+	if !ok {
+		return
+	}
+
+	var data []byte
+	var err error
+	contentType := "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	filename := fmt.Sprintf("%s.docx", article.Name)
+	if format == "md" {
+		data = object.ExportArticleMarkdown(article, language)
+		contentType = "text/markdown; charset=utf-8"
+		filename = fmt.Sprintf("%s.md", article.Name)
+	} else {
+		data, err = object.ExportArticleDocx(article, language)
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
+	}
+
+	c.Ctx.Output.Header("Content-Type", contentType)
+	c.Ctx.Output.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	err = c.Ctx.Output.Body(data)
+	if err != nil {
+		c.ResponseError(err.Error())
+	}
+}
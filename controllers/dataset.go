@@ -0,0 +1,198 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/beego/beego/utils/pagination"
+	"github.com/casibase/casibase/object"
+	"github.com/casibase/casibase/util"
+)
+
+// GetDatasets
+// @Title GetDatasets
+// @Tag Dataset API
+// @Description get all evaluation datasets
+// @Param   pageSize     query    string  false        "The size of each page"
+// @Param   p     query    string  false        "The number of the page"
+// @Success 200 {array} object.Dataset The Response object
+// @router /get-datasets [get]
+func (c *ApiController) GetDatasets() {
+	owner := c.Input().Get("owner")
+	limit := c.Input().Get("pageSize")
+	page := c.Input().Get("p")
+	field := c.Input().Get("field")
+	value := c.Input().Get("value")
+	sortField := c.Input().Get("sortField")
+	sortOrder := c.Input().Get("sortOrder")
+
+	if limit == "" || page == "" {
+		datasets, err := object.GetDatasets(owner)
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
+
+		c.ResponseOk(datasets)
+	} else {
+		limit := util.ParseInt(limit)
+		count, err := object.GetDatasetCount(owner, field, value)
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
+
+		paginator := pagination.SetPaginator(c.Ctx, limit, count)
+		datasets, err := object.GetPaginationDatasets(owner, paginator.Offset(), limit, field, value, sortField, sortOrder)
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
+
+		c.ResponseOk(datasets, paginator.Nums())
+	}
+}
+
+// GetDataset
+// @Title GetDataset
+// @Tag Dataset API
+// @Description get dataset
+// @Param   id     query    string  true        "The id ( owner/name ) of the dataset"
+// @Success 200 {object} object.Dataset The Response object
+// @router /get-dataset [get]
+func (c *ApiController) GetDataset() {
+	id := c.Input().Get("id")
+
+	dataset, err := object.GetDataset(id)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(dataset)
+}
+
+// UpdateDataset
+// @Title UpdateDataset
+// @Tag Dataset API
+// @Description update dataset
+// @Param   id     query    string  true        "The id ( owner/name ) of the dataset"
+// @Param   body    body   object.Dataset  true        "The details of the dataset"
+// @Success 200 {object} controllers.Response The Response object
+// @router /update-dataset [post]
+func (c *ApiController) UpdateDataset() {
+	id := c.Input().Get("id")
+
+	var dataset object.Dataset
+	err := json.Unmarshal(c.Ctx.Input.RequestBody, &dataset)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(object.UpdateDataset(id, &dataset))
+}
+
+// AddDataset
+// @Title AddDataset
+// @Tag Dataset API
+// @Description add a dataset
+// @Param   body    body   object.Dataset  true        "The details of the dataset"
+// @Success 200 {object} controllers.Response The Response object
+// @router /add-dataset [post]
+func (c *ApiController) AddDataset() {
+	var dataset object.Dataset
+	err := json.Unmarshal(c.Ctx.Input.RequestBody, &dataset)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(object.AddDataset(&dataset))
+}
+
+// DeleteDataset
+// @Title DeleteDataset
+// @Tag Dataset API
+// @Description delete a dataset
+// @Param   body    body   object.Dataset  true        "The details of the dataset"
+// @Success 200 {object} controllers.Response The Response object
+// @router /delete-dataset [post]
+func (c *ApiController) DeleteDataset() {
+	var dataset object.Dataset
+	err := json.Unmarshal(c.Ctx.Input.RequestBody, &dataset)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(object.DeleteDataset(&dataset))
+}
+
+// UploadDataset
+// @Title UploadDataset
+// @Tag Dataset API
+// @Description replace the items of a dataset with the questions of a CSV, XLSX, JSON or JSONL file
+// @Param   id     query    string  true        "The id ( owner/name ) of the dataset"
+// @Param   file   formData file    true        "The dataset file"
+// @Success 200 {object} object.Dataset The Response object
+// @router /upload-dataset [post]
+func (c *ApiController) UploadDataset() {
+	if !c.RequireAdmin() {
+		return
+	}
+
+	id := c.Input().Get("id")
+
+	dataset, err := object.GetDataset(id)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+	if dataset == nil {
+		c.ResponseError("Dataset not found")
+		return
+	}
+
+	file, header, err := c.GetFile("file")
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	items, err := object.ParseDatasetItems(header.Filename, data, c.GetAcceptLanguage())
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	dataset.Items = items
+	_, err = object.UpdateDataset(id, dataset)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(dataset)
+}
@@ -0,0 +1,212 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/beego/beego/utils/pagination"
+	"github.com/casibase/casibase/object"
+	"github.com/casibase/casibase/util"
+)
+
+// GetEvaluations
+// @Title GetEvaluations
+// @Tag Evaluation API
+// @Description get all evaluations
+// @Param   pageSize     query    string  false        "The size of each page"
+// @Param   p     query    string  false        "The number of the page"
+// @Success 200 {array} object.Evaluation The Response object
+// @router /get-evaluations [get]
+func (c *ApiController) GetEvaluations() {
+	owner := c.Input().Get("owner")
+	limit := c.Input().Get("pageSize")
+	page := c.Input().Get("p")
+	field := c.Input().Get("field")
+	value := c.Input().Get("value")
+	sortField := c.Input().Get("sortField")
+	sortOrder := c.Input().Get("sortOrder")
+
+	if limit == "" || page == "" {
+		evaluations, err := object.GetEvaluations(owner)
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
+
+		c.ResponseOk(evaluations)
+	} else {
+		limit := util.ParseInt(limit)
+		count, err := object.GetEvaluationCount(owner, field, value)
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
+
+		paginator := pagination.SetPaginator(c.Ctx, limit, count)
+		evaluations, err := object.GetPaginationEvaluations(owner, paginator.Offset(), limit, field, value, sortField, sortOrder)
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
+
+		c.ResponseOk(evaluations, paginator.Nums())
+	}
+}
+
+// GetEvaluation
+// @Title GetEvaluation
+// @Tag Evaluation API
+// @Description get evaluation
+// @Param   id     query    string  true        "The id ( owner/name ) of the evaluation"
+// @Success 200 {object} object.Evaluation The Response object
+// @router /get-evaluation [get]
+func (c *ApiController) GetEvaluation() {
+	id := c.Input().Get("id")
+
+	evaluation, err := object.GetEvaluation(id)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(evaluation)
+}
+
+// UpdateEvaluation
+// @Title UpdateEvaluation
+// @Tag Evaluation API
+// @Description update evaluation
+// @Param   id     query    string  true        "The id ( owner/name ) of the evaluation"
+// @Param   body    body   object.Evaluation  true        "The details of the evaluation"
+// @Success 200 {object} controllers.Response The Response object
+// @router /update-evaluation [post]
+func (c *ApiController) UpdateEvaluation() {
+	id := c.Input().Get("id")
+
+	var evaluation object.Evaluation
+	err := json.Unmarshal(c.Ctx.Input.RequestBody, &evaluation)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(object.UpdateEvaluation(id, &evaluation))
+}
+
+// AddEvaluation
+// @Title AddEvaluation
+// @Tag Evaluation API
+// @Description add a evaluation
+// @Param   body    body   object.Evaluation  true        "The details of the evaluation"
+// @Success 200 {object} controllers.Response The Response object
+// @router /add-evaluation [post]
+func (c *ApiController) AddEvaluation() {
+	var evaluation object.Evaluation
+	err := json.Unmarshal(c.Ctx.Input.RequestBody, &evaluation)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(object.AddEvaluation(&evaluation))
+}
+
+// DeleteEvaluation
+// @Title DeleteEvaluation
+// @Tag Evaluation API
+// @Description delete a evaluation
+// @Param   body    body   object.Evaluation  true        "The details of the evaluation"
+// @Success 200 {object} controllers.Response The Response object
+// @router /delete-evaluation [post]
+func (c *ApiController) DeleteEvaluation() {
+	var evaluation object.Evaluation
+	err := json.Unmarshal(c.Ctx.Input.RequestBody, &evaluation)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(object.DeleteEvaluation(&evaluation))
+}
+
+// RunEvaluation
+// @Title RunEvaluation
+// @Tag Evaluation API
+// @Description run the dataset of the evaluation against its store configuration in the background
+// @Param   id     query    string  true        "The id ( owner/name ) of the evaluation"
+// @Success 200 {object} controllers.Response The Response object
+// @router /run-evaluation [post]
+func (c *ApiController) RunEvaluation() {
+	if !c.IsAdmin() {
+		c.ResponseError(c.T("auth:this operation requires admin privilege"))
+		return
+	}
+
+	id := c.Input().Get("id")
+
+	evaluation, err := object.GetEvaluation(id)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+	if evaluation == nil {
+		c.ResponseError(fmt.Sprintf(c.T("controllers:The evaluation: %s is not found"), id))
+		return
+	}
+
+	err = object.StartEvaluation(evaluation, c.GetAcceptLanguage())
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(evaluation)
+}
+
+// GetEvaluationDiff
+// @Title GetEvaluationDiff
+// @Tag Evaluation API
+// @Description compare the metrics and the per-question results of two evaluations
+// @Param   base     query    string  true        "The id ( owner/name ) of the base evaluation"
+// @Param   target     query    string  true        "The id ( owner/name ) of the target evaluation"
+// @Success 200 {object} object.EvaluationDiff The Response object
+// @router /get-evaluation-diff [get]
+func (c *ApiController) GetEvaluationDiff() {
+	baseId := c.Input().Get("base")
+	targetId := c.Input().Get("target")
+
+	base, err := object.GetEvaluation(baseId)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+	target, err := object.GetEvaluation(targetId)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+	if base == nil {
+		c.ResponseError(fmt.Sprintf(c.T("controllers:The evaluation: %s is not found"), baseId))
+		return
+	}
+	if target == nil {
+		c.ResponseError(fmt.Sprintf(c.T("controllers:The evaluation: %s is not found"), targetId))
+		return
+	}
+
+	c.ResponseOk(object.DiffEvaluations(base, target))
+}
@@ -141,7 +141,7 @@ func (c *ApiController) AddForm() {
 		return
 	}
 
-	success, err := object.AddForm(&form)
+	success, err := object.AddForm(&form, c.GetAcceptLanguage())
 	if err != nil {
 		c.ResponseError(err.Error())
 		return
@@ -0,0 +1,253 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/beego/beego/utils/pagination"
+	"github.com/casibase/casibase/object"
+	"github.com/casibase/casibase/util"
+)
+
+// getSubmittableForm returns the form of the id if it accepts submissions from the current user:
+// anyone for a public form and the signed-in users otherwise.
+func (c *ApiController) getSubmittableForm(id string) (*object.Form, bool) {
+	form, err := object.GetForm(id)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return nil, false
+	}
+	if form == nil || form.Category != object.FormCategoryForm {
+		c.ResponseError(fmt.Sprintf(c.T("object:the form: %s is not found"), id))
+		return nil, false
+	}
+
+	if !form.IsPublic {
+		_, ok := c.RequireSignedIn()
+		if !ok {
+			return nil, false
+		}
+	}
+
+	return form, true
+}
+
+// GetPublicForm
+// @Title GetPublicForm
+// @Tag Form API
+// @Description get the fields of a form to fill in, without signing in for a public form
+// @Param id query string true "The id (owner/name) of the form"
+// @Success 200 {object} object.Form The Response object
+// @router /get-public-form [get]
+func (c *ApiController) GetPublicForm() {
+	id := c.Input().Get("id")
+
+	form, ok := c.getSubmittableForm(id)
+	if !ok {
+		return
+	}
+
+	form.Store = ""
+	form.Workflow = ""
+	c.ResponseOk(form)
+}
+
+// SubmitForm
+// @Title SubmitForm
+// @Tag Form API
+// @Description submit the values of the fields of a form, the files being sent as {"name": ..., "data": "data:...;base64,..."}
+// @Param id query string true "The id (owner/name) of the form"
+// @Param body body object true "The values of the fields by name"
+// @Success 200 {object} object.FormSubmission The Response object
+// @router /submit-form [post]
+func (c *ApiController) SubmitForm() {
+	id := c.Input().Get("id")
+
+	form, ok := c.getSubmittableForm(id)
+	if !ok {
+		return
+	}
+
+	values := map[string]interface{}{}
+	err := json.Unmarshal(c.Ctx.Input.RequestBody, &values)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	submission, err := object.SubmitForm(form, c.GetSessionUsername(), c.getRemoteClientIp(), values, c.GetAcceptLanguage())
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(submission.Name)
+}
+
+// GetFormSubmissions
+// @Title GetFormSubmissions
+// @Tag Form API
+// @Description get the submissions of a form
+// @Param owner query string true "The owner of the form"
+// @Param form query string true "The name of the form"
+// @Success 200 {array} object.FormSubmission The Response object
+// @router /get-form-submissions [get]
+func (c *ApiController) GetFormSubmissions() {
+	if !c.RequireAdmin() {
+		return
+	}
+
+	owner := c.Input().Get("owner")
+	form := c.Input().Get("form")
+	limit := c.Input().Get("pageSize")
+	page := c.Input().Get("p")
+	field := c.Input().Get("field")
+	value := c.Input().Get("value")
+	sortField := c.Input().Get("sortField")
+	sortOrder := c.Input().Get("sortOrder")
+
+	if limit == "" || page == "" {
+		submissions, err := object.GetFormSubmissions(owner, form)
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
+
+		c.ResponseOk(submissions)
+	} else {
+		limit := util.ParseInt(limit)
+		count, err := object.GetFormSubmissionCount(owner, form, field, value)
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
+
+		paginator := pagination.SetPaginator(c.Ctx, limit, count)
+		submissions, err := object.GetPaginationFormSubmissions(owner, form, paginator.Offset(), limit, field, value, sortField, sortOrder)
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
+		c.ResponseOk(submissions, paginator.Nums())
+	}
+}
+
+// GetFormSubmission
+// @Title GetFormSubmission
+// @Tag Form API
+// @Description get form submission
+// @Param id query string true "The id (owner/name) of the form submission"
+// @Success 200 {object} object.FormSubmission The Response object
+// @router /get-form-submission [get]
+func (c *ApiController) GetFormSubmission() {
+	if !c.RequireAdmin() {
+		return
+	}
+
+	id := c.Input().Get("id")
+
+	submission, err := object.GetFormSubmission(id)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(submission)
+}
+
+// DeleteFormSubmission
+// @Title DeleteFormSubmission
+// @Tag Form API
+// @Description delete form submission, with the knowledge it added to the store of its form
+// @Param body body object.FormSubmission true "The details of the form submission"
+// @Success 200 {object} controllers.Response The Response object
+// @router /delete-form-submission [post]
+func (c *ApiController) DeleteFormSubmission() {
+	if !c.RequireAdmin() {
+		return
+	}
+
+	var submission object.FormSubmission
+	err := json.Unmarshal(c.Ctx.Input.RequestBody, &submission)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	success, err := object.DeleteFormSubmission(&submission)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(success)
+}
+
+// ExportFormSubmissions
+// @Title ExportFormSubmissions
+// @Tag Form API
+// @Description export the submissions of a form to a CSV or XLSX file
+// @Param id query string true "The id (owner/name) of the form"
+// @Param format query string false "csv or xlsx, xlsx by default"
+// @Success 200 {file} file The CSV or XLSX file
+// @router /export-form-submissions [get]
+func (c *ApiController) ExportFormSubmissions() {
+	if !c.RequireAdmin() {
+		return
+	}
+
+	id := c.Input().Get("id")
+	format := c.Input().Get("format")
+
+	form, err := object.GetForm(id)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+	if form == nil {
+		c.ResponseError(fmt.Sprintf(c.T("object:the form: %s is not found"), id))
+		return
+	}
+
+	submissions, err := object.GetFormSubmissions(form.Owner, form.Name)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	var data []byte
+	contentType := "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	filename := fmt.Sprintf("%s.xlsx", form.Name)
+	if format == "csv" {
+		data, err = object.ExportFormSubmissionsCsv(form, submissions, c.GetAcceptLanguage())
+		contentType = "text/csv; charset=utf-8"
+		filename = fmt.Sprintf("%s.csv", form.Name)
+	} else {
+		data, err = object.ExportFormSubmissionsXlsx(form, submissions, c.GetAcceptLanguage())
+	}
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.Ctx.Output.Header("Content-Type", contentType)
+	c.Ctx.Output.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	err = c.Ctx.Output.Body(data)
+	if err != nil {
+		c.ResponseError(err.Error())
+	}
+}
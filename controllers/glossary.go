@@ -0,0 +1,160 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"encoding/json"
+
+	"github.com/beego/beego/utils/pagination"
+	"github.com/casibase/casibase/object"
+	"github.com/casibase/casibase/util"
+)
+
+// GetGlossaries
+// @Title GetGlossaries
+// @Tag Glossary API
+// @Description get the glossaries of an organization
+// @Param owner query string true "The owner of the glossaries"
+// @Success 200 {array} object.Glossary The Response object
+// @router /get-glossaries [get]
+func (c *ApiController) GetGlossaries() {
+	owner := c.Input().Get("owner")
+	limit := c.Input().Get("pageSize")
+	page := c.Input().Get("p")
+	field := c.Input().Get("field")
+	value := c.Input().Get("value")
+	sortField := c.Input().Get("sortField")
+	sortOrder := c.Input().Get("sortOrder")
+
+	if limit == "" || page == "" {
+		glossaries, err := object.GetGlossaries(owner)
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
+
+		c.ResponseOk(glossaries)
+	} else {
+		limit := util.ParseInt(limit)
+		count, err := object.GetGlossaryCount(owner, field, value)
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
+
+		paginator := pagination.SetPaginator(c.Ctx, limit, count)
+		glossaries, err := object.GetPaginationGlossaries(owner, paginator.Offset(), limit, field, value, sortField, sortOrder)
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
+
+		c.ResponseOk(glossaries, paginator.Nums())
+	}
+}
+
+// GetGlossary
+// @Title GetGlossary
+// @Tag Glossary API
+// @Description get glossary
+// @Param id query string true "The id (owner/name) of the glossary"
+// @Success 200 {object} object.Glossary The Response object
+// @router /get-glossary [get]
+func (c *ApiController) GetGlossary() {
+	id := c.Input().Get("id")
+
+	glossary, err := object.GetGlossary(id)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(glossary)
+}
+
+// UpdateGlossary
+// @Title UpdateGlossary
+// @Tag Glossary API
+// @Description update glossary
+// @Param id query string true "The id (owner/name) of the glossary"
+// @Param body body object.Glossary true "The details of the glossary"
+// @Success 200 {object} controllers.Response The Response object
+// @router /update-glossary [post]
+func (c *ApiController) UpdateGlossary() {
+	id := c.Input().Get("id")
+
+	var glossary object.Glossary
+	err := json.Unmarshal(c.Ctx.Input.RequestBody, &glossary)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	success, err := object.UpdateGlossary(id, &glossary, c.GetAcceptLanguage())
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(success)
+}
+
+// AddGlossary
+// @Title AddGlossary
+// @Tag Glossary API
+// @Description add glossary
+// @Param body body object.Glossary true "The details of the glossary"
+// @Success 200 {object} controllers.Response The Response object
+// @router /add-glossary [post]
+func (c *ApiController) AddGlossary() {
+	var glossary object.Glossary
+	err := json.Unmarshal(c.Ctx.Input.RequestBody, &glossary)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	success, err := object.AddGlossary(&glossary, c.GetAcceptLanguage())
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(success)
+}
+
+// DeleteGlossary
+// @Title DeleteGlossary
+// @Tag Glossary API
+// @Description delete glossary
+// @Param body body object.Glossary true "The details of the glossary"
+// @Success 200 {object} controllers.Response The Response object
+// @router /delete-glossary [post]
+func (c *ApiController) DeleteGlossary() {
+	var glossary object.Glossary
+	err := json.Unmarshal(c.Ctx.Input.RequestBody, &glossary)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	success, err := object.DeleteGlossary(&glossary)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(success)
+}
@@ -0,0 +1,165 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/ThinkInAIXYZ/go-mcp/server"
+	"github.com/ThinkInAIXYZ/go-mcp/transport"
+	"github.com/beego/beego/logs"
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+	"github.com/casibase/casibase/object"
+)
+
+var (
+	mcpOnce        sync.Once
+	mcpHttpHandler *transport.StreamableHTTPHandler
+	mcpSseHandler  *transport.SSEHandler
+	mcpErr         error
+)
+
+// initMcpServers starts the streamable HTTP and the SSE MCP servers on their first request.
+func initMcpServers() error {
+	mcpOnce.Do(func() {
+		var httpTransport, sseTransport transport.ServerTransport
+		httpTransport, mcpHttpHandler, mcpErr = transport.NewStreamableHTTPServerTransportAndHandler()
+		if mcpErr != nil {
+			return
+		}
+
+		sseTransport, mcpSseHandler, mcpErr = transport.NewSSEServerTransportAndHandler("/api/mcp/message")
+		if mcpErr != nil {
+			return
+		}
+
+		for _, t := range []transport.ServerTransport{httpTransport, sseTransport} {
+			var srv *server.Server
+			srv, mcpErr = object.NewMcpServer(t)
+			if mcpErr != nil {
+				return
+			}
+
+			go func() {
+				err := srv.Run()
+				if err != nil {
+					logs.Error("MCP server stopped: %s", err.Error())
+				}
+			}()
+		}
+	})
+	return mcpErr
+}
+
+// getMcpUser authenticates the MCP request with the session, a provider key or a Casdoor access token.
+// The callers authenticated by a provider key are never administrators and are limited to the stores
+// using the provider, which are returned as the second value. It is nil for the other callers.
+func (c *ApiController) getMcpUser() (*casdoorsdk.User, []string, error) {
+	user := c.GetSessionUser()
+	if user != nil {
+		return user, nil, nil
+	}
+
+	token := strings.TrimPrefix(c.Ctx.Request.Header.Get("Authorization"), "Bearer ")
+	if token == "" {
+		return nil, nil, nil
+	}
+
+	provider, err := object.GetProviderByProviderKey(token, c.GetAcceptLanguage())
+	if err == nil && provider != nil {
+		stores, err := object.GetStoresByProvider("admin", provider.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		storeNames := []string{}
+		for _, store := range stores {
+			storeNames = append(storeNames, store.Name)
+		}
+
+		user = &casdoorsdk.User{
+			Owner: provider.Owner,
+			Name:  provider.Name,
+		}
+		return user, storeNames, nil
+	}
+
+	claims, err := casdoorsdk.ParseJwtToken(token)
+	if err == nil {
+		return &claims.User, nil, nil
+	}
+	return nil, nil, nil
+}
+
+func (c *ApiController) serveMcp(getHandler func() http.Handler) {
+	user, stores, err := c.getMcpUser()
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+	if user == nil {
+		c.Ctx.Output.SetStatus(http.StatusUnauthorized)
+		c.ResponseError(c.T("auth:Please sign in first"))
+		return
+	}
+
+	err = initMcpServers()
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	ctx := object.WithMcpUser(c.Ctx.Request.Context(), user, stores, c.GetAcceptLanguage())
+	getHandler().ServeHTTP(c.Ctx.ResponseWriter, c.Ctx.Request.WithContext(ctx))
+}
+
+// HandleMcp
+// @Title HandleMcp
+// @Tag MCP API
+// @Description serve the MCP protocol over streamable HTTP, authenticated by the session, a provider key or a Casdoor access token in the "Authorization: Bearer" header
+// @Success 200 {object} controllers.Response The Response object
+// @router /mcp [get,post,delete]
+func (c *ApiController) HandleMcp() {
+	c.serveMcp(func() http.Handler {
+		return mcpHttpHandler.HandleMCP()
+	})
+}
+
+// HandleMcpSse
+// @Title HandleMcpSse
+// @Tag MCP API
+// @Description open the event stream of the MCP protocol over SSE, the messages are posted to /api/mcp/message
+// @Success 200 {object} controllers.Response The Response object
+// @router /mcp/sse [get]
+func (c *ApiController) HandleMcpSse() {
+	c.serveMcp(func() http.Handler {
+		return mcpSseHandler.HandleSSE()
+	})
+}
+
+// HandleMcpMessage
+// @Title HandleMcpMessage
+// @Tag MCP API
+// @Param   sessionID     query    string  true        "The session ID of the SSE stream"
+// @Description receive the messages of the MCP protocol over SSE
+// @Success 200 {object} controllers.Response The Response object
+// @router /mcp/message [post]
+func (c *ApiController) HandleMcpMessage() {
+	c.serveMcp(func() http.Handler {
+		return mcpSseHandler.HandleMessage()
+	})
+}
@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/casibase/casibase/agent"
 	"github.com/casibase/casibase/conf"
@@ -36,6 +37,7 @@ import (
 // @router /get-message-answer [get]
 func (c *ApiController) GetMessageAnswer() {
 	id := c.Input().Get("id")
+	requestTime := time.Now()
 
 	c.Ctx.ResponseWriter.Header().Set("Content-Type", "text/event-stream")
 	c.Ctx.ResponseWriter.Header().Set("Cache-Control", "no-cache")
@@ -98,7 +100,19 @@ func (c *ApiController) GetMessageAnswer() {
 		return
 	}
 
+	// the chats of a store running an experiment are answered with the settings of their variant
+	baseModelProvider := store.ModelProvider
+	variant := store.GetExperimentVariant(chat)
+	if variant != nil {
+		chat.Experiment = store.Experiment.Name
+		chat.Variant = variant.Name
+		message.Experiment = chat.Experiment
+		message.Variant = chat.Variant
+		store = store.WithVariant(variant)
+	}
+
 	question := store.Welcome
+	imageCommand := ""
 	var questionMessage *object.Message
 	if message.ReplyTo != "Welcome" {
 		questionMessage, err = object.GetMessage(util.GetId("admin", message.ReplyTo))
@@ -113,6 +127,8 @@ func (c *ApiController) GetMessageAnswer() {
 
 		question = questionMessage.Text
 
+		imageCommand, question = object.GetImageCommand(question)
+
 		question, err = refineQuestionTextViaParsingUrlContent(question, c.GetAcceptLanguage())
 		if err != nil {
 			c.ResponseErrorStream(message, err.Error())
@@ -139,8 +155,9 @@ func (c *ApiController) GetMessageAnswer() {
 		}
 	}
 
+	// the model picked for the chat wins, unless it is the store's one the variant replaces
 	modelProviderName := store.ModelProvider
-	if chat.ModelProvider != "" {
+	if chat.ModelProvider != "" && (variant == nil || variant.ModelProvider == "" || chat.ModelProvider != baseModelProvider) {
 		modelProviderName = chat.ModelProvider
 	}
 
@@ -173,6 +190,7 @@ func (c *ApiController) GetMessageAnswer() {
 		c.ResponseErrorStream(message, err.Error())
 		return
 	}
+	defer agentClients.Release()
 
 	webSearchEnabled := false
 	if questionMessage != nil {
@@ -180,21 +198,64 @@ func (c *ApiController) GetMessageAnswer() {
 	}
 	agentClients = agent.MergeBuiltinAndWebSearchTools(agentClients, store.BuiltinTools, webSearchEnabled)
 
+	knowledgeSearch := object.NewKnowledgeSearch(store, embeddingProvider, embeddingProviderObj, modelProvider, c.GetAcceptLanguage())
+	knowledgeSearch.BindTool(agentClients)
+	object.NewCodeInterpreter(store, message, getOriginFromHost(c.Ctx.Request.Host), c.GetAcceptLanguage()).BindTool(agentClients)
+	store.BindWebTools(agentClients, c.GetAcceptLanguage())
+	storeDelegation := object.NewStoreDelegation(store, c.GetAcceptLanguage())
+	agentClients = storeDelegation.BindTools(agentClients)
+
 	knowledgeCount := store.KnowledgeCount
 	if knowledgeCount <= 0 {
 		knowledgeCount = 10
 	}
 
-	knowledge, vectorScores, embeddingResult, err := object.GetNearestKnowledge(store.Name, store.VectorStores, store.SearchProvider, embeddingProvider, embeddingProviderObj, modelProvider, "admin", question, knowledgeCount, c.GetAcceptLanguage())
-	if err != nil && err.Error() != "no knowledge vectors found" {
-		err = fmt.Errorf(c.T("message_answer:object.GetNearestKnowledge() error, %s"), err.Error())
-		c.ResponseErrorStream(message, err.Error())
-		return
+	// Image commands are answered by the image model provider without any knowledge
+	var knowledge []*model.RawMessage
+	var vectorScores []object.VectorScore
+	var embeddingResult *embedding.EmbeddingResult
+	if imageCommand == "" {
+		knowledge, vectorScores, embeddingResult, err = object.GetNearestKnowledge(store.Name, store.VectorStores, store.SearchProvider, embeddingProvider, embeddingProviderObj, modelProvider, "admin", question, knowledgeCount, c.GetAcceptLanguage())
+		if err != nil && err.Error() != "no knowledge vectors found" {
+			err = fmt.Errorf(c.T("message_answer:object.GetNearestKnowledge() error, %s"), err.Error())
+			c.ResponseErrorStream(message, err.Error())
+			return
+		}
+
+		knowledge = append(knowledge, store.GetMcpKnowledge(c.Ctx.Request.Context(), agentClients)...)
 	}
 	if embeddingResult == nil {
 		embeddingResult = &embedding.EmbeddingResult{}
 	}
 
+	history, summary, err := object.GetChatMemory(store, chat, message.CreatedTime)
+	if err != nil {
+		c.ResponseErrorStream(message, err.Error())
+		return
+	}
+
+	// Answers depending on tools or web search are never served from the semantic cache, nor the
+	// ones of experiment variants which would otherwise share their answers
+	var answerCache *object.AnswerCache
+	var cacheVector []float32
+	isCacheEnabled := store.EnableSemanticCache && agentClients == nil && questionMessage != nil && imageCommand == "" && variant == nil
+	if isCacheEnabled {
+		var cacheEmbeddingResult *embedding.EmbeddingResult
+		answerCache, cacheVector, cacheEmbeddingResult, err = object.GetCachedAnswer(store, embeddingProvider, embeddingProviderObj, modelProvider.Name, question, knowledge, history, summary, c.GetAcceptLanguage())
+		if err != nil {
+			c.ResponseErrorStream(message, err.Error())
+			return
+		}
+		if cacheEmbeddingResult != nil {
+			embeddingResult.TokenCount += cacheEmbeddingResult.TokenCount
+			embeddingResult.Price = model.AddPrices(embeddingResult.Price, cacheEmbeddingResult.Price)
+			if embeddingResult.Currency == "" {
+				embeddingResult.Currency = cacheEmbeddingResult.Currency
+			}
+		}
+	}
+	cacheQuestion := question
+
 	writer := &RefinedWriter{*c.Ctx.ResponseWriter, *NewCleaner(6), []byte{}, []byte{}, []byte{}, []byte{}, []byte{}}
 
 	if questionMessage != nil {
@@ -209,12 +270,6 @@ func (c *ApiController) GetMessageAnswer() {
 		}
 	}
 
-	history, err := object.GetRecentRawMessages(chat.Name, message.CreatedTime, store.MemoryLimit)
-	if err != nil {
-		c.ResponseErrorStream(message, err.Error())
-		return
-	}
-
 	fmt.Printf("Question: [%s]\n", question)
 	fmt.Printf("Knowledge: [\n")
 	for i, k := range knowledge {
@@ -224,8 +279,8 @@ func (c *ApiController) GetMessageAnswer() {
 	// fmt.Printf("Refined Question: [%s]\n", realQuestion)
 	fmt.Printf("Answer: [")
 
-	prompt := store.Prompt
-	if modelProvider.Type != "Dummy" && !isReasonModel(modelProvider.SubType) {
+	prompt := object.GetPromptWithSummary(store.GetPrompt(c.Ctx.Request.Context(), agentClients), summary)
+	if modelProvider.Type != "Dummy" && !isReasonModel(modelProvider.SubType) && imageCommand == "" {
 		if modelProvider.Type == "Alibaba Cloud" && webSearchEnabled {
 			prompt, err = getPromptWithCarrier(prompt, store.SuggestionCount, chat.NeedTitle)
 		} else {
@@ -237,26 +292,57 @@ func (c *ApiController) GetMessageAnswer() {
 		}
 	}
 
+	// the answers of the model are traced, the cached and image answers are not unless they fail
+	agentTrace := model.NewAgentTrace()
+	startTime := time.Now()
+	defer func() {
+		if message.ErrorText == "" {
+			return
+		}
+
+		err := object.AddTraceForMessage(message, modelProvider.Name, question, prompt, history, knowledge, agentClients, agentTrace, startTime)
+		if err != nil {
+			fmt.Printf("GetMessageAnswer() error, failed to save the trace of the failed answer: %s\n", err.Error())
+		}
+	}()
 	var modelResult *model.ModelResult
-	if agentClients != nil {
+	if answerCache != nil {
+		err = writeCachedAnswer(writer, answerCache)
+		modelResult = &model.ModelResult{Currency: embeddingResult.Currency}
+		message.IsCached = true
+	} else if imageCommand != "" {
+		var imageAnswer string
+		origin := getOriginFromHost(c.Ctx.Request.Host)
+		imageAnswer, modelResult, err = object.GetImageAnswer(store, message, imageCommand, question, origin, c.GetAcceptLanguage())
+		if err == nil {
+			_, err = writer.Write([]byte(fmt.Sprintf("event: message\ndata: %s\n\n", imageAnswer)))
+		}
+	} else if agentClients != nil {
 		messages := &model.AgentMessages{
 			Messages:  []*model.RawMessage{},
 			ToolCalls: nil,
 		}
 		agentInfo := &model.AgentInfo{
-			AgentClients:  agentClients,
-			AgentMessages: messages,
+			AgentClients:       agentClients,
+			AgentMessages:      messages,
+			Context:            c.Ctx.Request.Context(),
+			MaxSteps:           store.AgentMaxSteps,
+			TimeoutSeconds:     store.AgentTimeoutSeconds,
+			ToolTimeoutSeconds: store.ToolTimeoutSeconds,
+			ApproveToolCalls:   getToolCallApprover(message, writer),
+			Trace:              agentTrace,
 		}
 		modelResult, err = model.QueryTextWithTools(modelProviderObj, question, writer, history, prompt, knowledge, agentInfo, c.GetAcceptLanguage())
 	} else {
 		if isReasonModel(modelProvider.SubType) {
 			modelResult, err = QueryCarrierText(question, writer, history, prompt, knowledge, modelProviderObj, chat.NeedTitle, store.SuggestionCount, c.GetAcceptLanguage())
 		} else {
-			modelResult, err = modelProviderObj.QueryText(question, writer, history, prompt, knowledge, nil, c.GetAcceptLanguage())
+			modelResult, err = agentTrace.QueryText(modelProviderObj, question, writer, history, prompt, knowledge, nil, c.GetAcceptLanguage())
 		}
 	}
 	if err != nil {
 		if strings.Contains(err.Error(), "write tcp") {
+			message.ErrorText = err.Error()
 			c.ResponseError(err.Error())
 			return
 		}
@@ -264,6 +350,8 @@ func (c *ApiController) GetMessageAnswer() {
 		return
 	}
 
+	// add the retrievals of the knowledge_search tool to the ones made up front
+	vectorScores = append(vectorScores, knowledgeSearch.GetVectorScores()...)
 	if len(vectorScores) > 0 {
 		bytes, err := json.Marshal(vectorScores)
 		if err == nil {
@@ -301,7 +389,7 @@ func (c *ApiController) GetMessageAnswer() {
 
 	answer := writer.MessageString()
 	message.ReasonText = writer.ReasonString()
-	message.ToolCalls = model.GetToolCallsFromWriter(writer.ToolString())
+	message.ToolCalls = append(model.GetToolCallsFromWriter(writer.ToolString()), storeDelegation.GetToolCalls()...)
 	searchString := writer.SearchString()
 	if searchString != "" {
 		var searchResults []model.SearchResult
@@ -311,6 +399,8 @@ func (c *ApiController) GetMessageAnswer() {
 		}
 	}
 
+	// roll the costs of the child stores the question was delegated to up into the answer
+	model.AddModelResults(modelResult, storeDelegation.GetModelResult())
 	message.TokenCount = modelResult.TotalTokenCount
 	message.Price = modelResult.TotalPrice
 	message.Currency = modelResult.Currency
@@ -334,8 +424,17 @@ func (c *ApiController) GetMessageAnswer() {
 
 	message.VectorScores = vectorScores
 
+	if isCacheEnabled && answerCache == nil {
+		err = object.AddAnswerCacheForStore(store, embeddingProvider.Name, modelProvider.Name, cacheQuestion, knowledge, history, summary, cacheVector, answer, message.ReasonText)
+		if err != nil {
+			c.ResponseErrorStream(message, err.Error())
+			return
+		}
+	}
+
 	// Normalize price precision before persisting or creating transactions
 	message.Price = model.AddPrices(message.Price, 0)
+	message.Latency = time.Since(requestTime).Milliseconds()
 
 	// Add transaction for message with price
 	err = object.AddTransactionForMessage(message)
@@ -350,6 +449,12 @@ func (c *ApiController) GetMessageAnswer() {
 		return
 	}
 
+	err = object.AddTraceForMessage(message, modelProvider.Name, question, prompt, history, knowledge, agentClients, agentTrace, startTime)
+	if err != nil {
+		c.ResponseErrorStream(message, err.Error())
+		return
+	}
+
 	chat.TokenCount += message.TokenCount
 	chat.Price += message.Price
 	if chat.Currency == "" {
@@ -378,6 +483,8 @@ func (c *ApiController) GetMessageAnswer() {
 		c.ResponseErrorStream(message, err.Error())
 		return
 	}
+
+	object.UpdateChatMemory(store, chat, message.CreatedTime, modelProvider.Name, modelProviderObj, c.GetAcceptLanguage())
 }
 
 // GetAnswer
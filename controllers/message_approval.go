@@ -0,0 +1,109 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/casibase/casibase/model"
+	"github.com/casibase/casibase/object"
+	"github.com/sashabaranov/go-openai"
+)
+
+// getToolCallApprover persists the tool calls requiring approval on the message, sends them
+// to the client as an "approval" event and waits for the decisions of the chat user.
+func getToolCallApprover(message *object.Message, writer *RefinedWriter) model.ToolCallApprover {
+	return func(ctx context.Context, toolCalls []openai.ToolCall) (map[string]bool, error) {
+		pendingToolCalls, err := object.AddPendingToolCalls(message, toolCalls)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := json.Marshal(pendingToolCalls)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = writer.ResponseWriter.Write([]byte(fmt.Sprintf("event: approval\ndata: %s\n\n", data)))
+		if err != nil {
+			return nil, err
+		}
+		if flusher, ok := writer.ResponseWriter.(http.Flusher); ok {
+			flusher.Flush()
+		}
+
+		return object.WaitForToolCallDecisions(ctx, message, pendingToolCalls)
+	}
+}
+
+func (c *ApiController) decideToolCall(isApproved bool) {
+	id := c.Input().Get("id")
+	toolCallId := c.Input().Get("toolCallId")
+
+	message, err := object.GetMessage(id)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+	if message == nil {
+		c.ResponseError(fmt.Sprintf(c.T("object:The message: %s is not found"), id))
+		return
+	}
+
+	ok := c.IsCurrentUser(message.User)
+	if !ok {
+		return
+	}
+
+	user := c.GetSessionUsername()
+	if user == "" {
+		user = message.User
+	}
+
+	success, err := object.DecideToolCall(message, toolCallId, isApproved, user, c.GetAcceptLanguage())
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(success)
+}
+
+// ApproveToolCall
+// @Title ApproveToolCall
+// @Tag Message API
+// @Description approve a tool call of an answer waiting for approval
+// @Param id query string true "The id of the answer message"
+// @Param toolCallId query string true "The id of the pending tool call"
+// @Success 200 {object} controllers.Response The Response object
+// @router /approve-tool-call [post]
+func (c *ApiController) ApproveToolCall() {
+	c.decideToolCall(true)
+}
+
+// RejectToolCall
+// @Title RejectToolCall
+// @Tag Message API
+// @Description reject a tool call of an answer waiting for approval
+// @Param id query string true "The id of the answer message"
+// @Param toolCallId query string true "The id of the pending tool call"
+// @Success 200 {object} controllers.Response The Response object
+// @router /reject-tool-call [post]
+func (c *ApiController) RejectToolCall() {
+	c.decideToolCall(false)
+}
@@ -0,0 +1,46 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/casibase/casibase/object"
+)
+
+var cachedAnswerChunkRegex = regexp.MustCompile(`\S+\s*|\s+`)
+
+// writeCachedAnswer streams a cached answer through the writer in word-sized chunks,
+// so it goes through the same cleaning and buffering as a freshly generated answer.
+func writeCachedAnswer(writer *RefinedWriter, answerCache *object.AnswerCache) error {
+	if answerCache.ReasonText != "" {
+		_, err := writer.Write([]byte(fmt.Sprintf("event: reason\ndata: %s\n\n", answerCache.ReasonText)))
+		if err != nil {
+			return err
+		}
+	}
+
+	chunks := cachedAnswerChunkRegex.FindAllString(answerCache.Answer, -1)
+	for _, chunk := range chunks {
+		_, err := writer.Write([]byte(fmt.Sprintf("event: message\ndata: %s\n\n", chunk)))
+		if err != nil {
+			return err
+		}
+		writer.Flush()
+	}
+
+	return nil
+}
@@ -7,6 +7,8 @@ package controllers
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
 
 	"github.com/beego/beego/utils/pagination"
 	"github.com/casibase/casibase/object"
@@ -148,7 +150,7 @@ func (c *ApiController) UpdateScale() {
 			return
 		}
 	}
-	success, err := object.UpdateScale(id, &s)
+	success, err := object.UpdateScale(id, &s, c.GetAcceptLanguage())
 	if err != nil {
 		c.ResponseError(err.Error())
 		return
@@ -172,7 +174,7 @@ func (c *ApiController) AddScale() {
 	} else {
 		s.State = object.ScaleStatePublic
 	}
-	success, err := object.AddScale(&s)
+	success, err := object.AddScale(&s, c.GetAcceptLanguage())
 	if err != nil {
 		c.ResponseError(err.Error())
 		return
@@ -213,3 +215,153 @@ func (c *ApiController) DeleteScale() {
 	}
 	c.ResponseOk(success)
 }
+
+// getOwnedScale returns the scale of the id if the user is an admin or its owner.
+func (c *ApiController) getOwnedScale(id string) (*object.Scale, bool) {
+	s, err := object.GetScale(id)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return nil, false
+	}
+	if s == nil {
+		c.ResponseError(fmt.Sprintf(c.T("object:The scale: %s does not exist"), id))
+		return nil, false
+	}
+	if !c.IsAdmin() && !c.IsPreviewMode() && s.Owner != c.GetSessionUsername() {
+		c.ResponseError(c.T("auth:Unauthorized operation"))
+		return nil, false
+	}
+	return s, true
+}
+
+// GetScaleVersions
+// @Title GetScaleVersions
+// @Tag Scale API
+// @Description get the versions of the rubric of a scale, the newest first
+// @Param id query string true "The id (owner/name) of the scale"
+// @Success 200 {array} object.ScaleVersion The Response object
+// @router /get-scale-versions [get]
+func (c *ApiController) GetScaleVersions() {
+	id := c.Input().Get("id")
+	s, ok := c.getOwnedScale(id)
+	if !ok {
+		return
+	}
+
+	scaleVersions, err := object.GetScaleVersions(s.Owner, s.Name)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+	c.ResponseOk(scaleVersions)
+}
+
+// GetScaleVersion
+// @Title GetScaleVersion
+// @Tag Scale API
+// @Description get a scale as it was at a version, like the version a task was graded against
+// @Param id query string true "The id (owner/name) of the scale"
+// @Param version query string true "The version of the scale"
+// @Success 200 {object} object.Scale The Response object
+// @router /get-scale-version [get]
+func (c *ApiController) GetScaleVersion() {
+	id := c.Input().Get("id")
+	version := util.ParseInt(c.Input().Get("version"))
+
+	s, err := object.GetScaleOfVersion(id, version)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+	if s == nil {
+		c.ResponseError(fmt.Sprintf(c.T("object:The version: %d of the scale: %s does not exist"), version, id))
+		return
+	}
+	if !c.IsAdmin() && !c.IsPreviewMode() && s.Owner != c.GetSessionUsername() {
+		c.ResponseError(c.T("auth:Unauthorized operation"))
+		return
+	}
+	c.ResponseOk(s)
+}
+
+// ImportScale
+// @Title ImportScale
+// @Tag Scale API
+// @Description import the rubric of a scale from an XLSX or JSON file, as a new version of the scale or as a new scale
+// @Param owner query string true "The owner of the scale"
+// @Param name query string true "The name of the scale"
+// @Param file formData file true "The XLSX or JSON file of the scale"
+// @Success 200 {object} object.Scale The Response object
+// @router /import-scale [post]
+func (c *ApiController) ImportScale() {
+	username, ok := c.RequireSignedIn()
+	if !ok {
+		return
+	}
+
+	owner := c.Input().Get("owner")
+	name := c.Input().Get("name")
+	if !c.IsAdmin() && owner != username {
+		c.ResponseError(c.T("auth:Unauthorized operation"))
+		return
+	}
+
+	file, header, err := c.GetFile("file")
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	s, err := object.ImportScale(owner, name, header.Filename, data, c.GetAcceptLanguage())
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+	c.ResponseOk(s)
+}
+
+// ExportScale
+// @Title ExportScale
+// @Tag Scale API
+// @Description export the rubric of a scale to an XLSX or JSON file
+// @Param id query string true "The id (owner/name) of the scale"
+// @Param format query string false "The format of the file: xlsx or json, xlsx by default"
+// @Success 200 {file} file The XLSX or JSON file
+// @router /export-scale [get]
+func (c *ApiController) ExportScale() {
+	id := c.Input().Get("id")
+	s, ok := c.getOwnedScale(id)
+	if !ok {
+		return
+	}
+
+	var data []byte
+	var err error
+	contentType := "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	filename := fmt.Sprintf("%s.xlsx", s.Name)
+	if c.Input().Get("format") == "json" {
+		data, err = object.ExportScaleJson(s)
+		contentType = "application/json"
+		filename = fmt.Sprintf("%s.json", s.Name)
+	} else {
+		data, err = object.ExportScaleXlsx(s, c.GetAcceptLanguage())
+	}
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.Ctx.Output.Header("Content-Type", contentType)
+	c.Ctx.Output.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	err = c.Ctx.Output.Body(data)
+	if err != nil {
+		c.ResponseError(err.Error())
+	}
+}
@@ -16,6 +16,7 @@ package controllers
 
 import (
 	"encoding/json"
+	"fmt"
 	"sort"
 
 	"github.com/beego/beego/utils/pagination"
@@ -322,3 +323,36 @@ func (c *ApiController) GetStoreNames() {
 
 	c.ResponseOk(storeNames)
 }
+
+// GetStoreExperimentReport
+// @Title GetStoreExperimentReport
+// @Tag Store API
+// @Description get the like and dislike rates, token cost and latency of the variants of the store's experiment
+// @Param   id     query    string  true        "The id ( owner/name ) of the store"
+// @Success 200 {object} object.ExperimentReport The Response object
+// @router /get-store-experiment-report [get]
+func (c *ApiController) GetStoreExperimentReport() {
+	if !c.RequireAdmin() {
+		return
+	}
+
+	id := c.Input().Get("id")
+
+	store, err := object.GetStore(id)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+	if store == nil {
+		c.ResponseError(fmt.Sprintf(c.T("account:The store: %s is not found"), id))
+		return
+	}
+
+	report, err := object.GetExperimentReport(store)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(report)
+}
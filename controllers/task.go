@@ -16,6 +16,7 @@ package controllers
 
 import (
 	"encoding/json"
+	"strings"
 
 	"github.com/beego/beego/logs"
 	"github.com/beego/beego/utils/pagination"
@@ -251,6 +252,8 @@ func (c *ApiController) DeleteTask() {
 // @Tag Task API
 // @Description analyze task document and generate structured report
 // @Param id query string true "The id (owner/name) of the task"
+// @Param providers query string false "The comma-separated model providers to sample, the provider of the task by default"
+// @Param sampleCount query string false "The number of samples per provider the scores are averaged over, 1 by default"
 // @Success 200 {object} object.TaskResult The Response object
 // @router /analyze-task [post]
 func (c *ApiController) AnalyzeTask() {
@@ -277,7 +280,16 @@ func (c *ApiController) AnalyzeTask() {
 		}
 	}
 
-	result, err := object.AnalyzeTask(task, c.GetAcceptLanguage())
+	providers := []string{}
+	if value := c.Input().Get("providers"); value != "" {
+		providers = strings.Split(value, ",")
+	}
+	sampleCount := 1
+	if value := c.Input().Get("sampleCount"); value != "" {
+		sampleCount = util.ParseInt(value)
+	}
+
+	result, err := object.AnalyzeTaskWithSamples(task, providers, sampleCount, c.GetAcceptLanguage())
 	if err != nil {
 		logs.Error("[analyze-task] AnalyzeTask failed id=%s: %v", id, err)
 		c.ResponseError(err.Error())
@@ -293,6 +305,7 @@ func (c *ApiController) AnalyzeTask() {
 	}
 	task.Result = string(resultBytes)
 	task.Score = result.Score
+	task.ScaleVersion = result.ScaleVersion
 	logs.Info("[analyze-task] saving task id=%s resultBytes=%d", id, len(resultBytes))
 	_, err = object.UpdateTask(id, task)
 	if err != nil {
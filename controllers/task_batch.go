@@ -0,0 +1,247 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/beego/beego/utils/pagination"
+	"github.com/casibase/casibase/object"
+	"github.com/casibase/casibase/util"
+)
+
+// getOwnedTaskBatch returns the task batch of the id if the user is an admin or its owner.
+func (c *ApiController) getOwnedTaskBatch(id string) (*object.TaskBatch, bool) {
+	taskBatch, err := object.GetTaskBatch(id)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return nil, false
+	}
+	if taskBatch == nil {
+		c.ResponseError(fmt.Sprintf(c.T("object:The task batch: %s does not exist"), id))
+		return nil, false
+	}
+
+	if !c.IsAdmin() && !c.IsPreviewMode() && taskBatch.Owner != c.GetSessionUsername() {
+		c.ResponseError(c.T("auth:Unauthorized operation"))
+		return nil, false
+	}
+	return taskBatch, true
+}
+
+// GetTaskBatches
+// @Title GetTaskBatches
+// @Tag Task API
+// @Description get task batches
+// @Param owner query string true "The owner of task batches"
+// @Success 200 {array} object.TaskBatch The Response object
+// @router /get-task-batches [get]
+func (c *ApiController) GetTaskBatches() {
+	owner := c.Input().Get("owner")
+	limit := c.Input().Get("pageSize")
+	page := c.Input().Get("p")
+	field := c.Input().Get("field")
+	value := c.Input().Get("value")
+	sortField := c.Input().Get("sortField")
+	sortOrder := c.Input().Get("sortOrder")
+
+	if c.IsAdmin() {
+		owner = ""
+	} else {
+		owner = c.GetSessionUsername()
+	}
+
+	if limit == "" || page == "" {
+		taskBatches, err := object.GetTaskBatches(owner)
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
+
+		c.ResponseOk(taskBatches)
+	} else {
+		limit := util.ParseInt(limit)
+		count, err := object.GetTaskBatchCount(owner, field, value)
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
+
+		paginator := pagination.SetPaginator(c.Ctx, limit, count)
+		taskBatches, err := object.GetPaginationTaskBatches(owner, paginator.Offset(), limit, field, value, sortField, sortOrder)
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
+
+		c.ResponseOk(taskBatches, paginator.Nums())
+	}
+}
+
+// GetTaskBatch
+// @Title GetTaskBatch
+// @Tag Task API
+// @Description get task batch with the progress of its tasks
+// @Param id query string true "The id (owner/name) of the task batch"
+// @Success 200 {object} object.TaskBatch The Response object
+// @router /get-task-batch [get]
+func (c *ApiController) GetTaskBatch() {
+	id := c.Input().Get("id")
+
+	taskBatch, ok := c.getOwnedTaskBatch(id)
+	if !ok {
+		return
+	}
+
+	c.ResponseOk(taskBatch)
+}
+
+// AddTaskBatch
+// @Title AddTaskBatch
+// @Tag Task API
+// @Description add a task batch, which is analyzed in the background
+// @Param body body object.TaskBatch true "The details of the task batch"
+// @Success 200 {object} controllers.Response The Response object
+// @router /add-task-batch [post]
+func (c *ApiController) AddTaskBatch() {
+	username, ok := c.RequireSignedIn()
+	if !ok {
+		return
+	}
+
+	var taskBatch object.TaskBatch
+	err := json.Unmarshal(c.Ctx.Input.RequestBody, &taskBatch)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	if !c.IsAdmin() && taskBatch.Owner != username {
+		c.ResponseError(c.T("auth:Unauthorized operation"))
+		return
+	}
+
+	success, err := object.AddTaskBatch(&taskBatch, c.GetAcceptLanguage())
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(success)
+}
+
+// DeleteTaskBatch
+// @Title DeleteTaskBatch
+// @Tag Task API
+// @Description delete task batch
+// @Param body body object.TaskBatch true "The details of the task batch"
+// @Success 200 {object} controllers.Response The Response object
+// @router /delete-task-batch [post]
+func (c *ApiController) DeleteTaskBatch() {
+	var taskBatch object.TaskBatch
+	err := json.Unmarshal(c.Ctx.Input.RequestBody, &taskBatch)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	_, ok := c.getOwnedTaskBatch(taskBatch.GetId())
+	if !ok {
+		return
+	}
+
+	success, err := object.DeleteTaskBatch(&taskBatch)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(success)
+}
+
+// RetryTaskBatch
+// @Title RetryTaskBatch
+// @Tag Task API
+// @Description analyze the failed tasks of a finished task batch again
+// @Param id query string true "The id (owner/name) of the task batch"
+// @Success 200 {object} object.TaskBatch The Response object
+// @router /retry-task-batch [post]
+func (c *ApiController) RetryTaskBatch() {
+	id := c.Input().Get("id")
+
+	taskBatch, ok := c.getOwnedTaskBatch(id)
+	if !ok {
+		return
+	}
+
+	err := object.RetryTaskBatch(taskBatch, c.GetAcceptLanguage())
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(taskBatch)
+}
+
+// ExportTaskResults
+// @Title ExportTaskResults
+// @Tag Task API
+// @Description export the analysis results of the tasks of a task batch, or of all the tasks of the user, to an XLSX file
+// @Param taskBatch query string false "The id (owner/name) of the task batch"
+// @Success 200 {file} file The XLSX file
+// @router /export-task-results [get]
+func (c *ApiController) ExportTaskResults() {
+	username, ok := c.RequireSignedIn()
+	if !ok {
+		return
+	}
+
+	var tasks []*object.Task
+	var err error
+	filename := "tasks.xlsx"
+	if id := c.Input().Get("taskBatch"); id != "" {
+		taskBatch, ok := c.getOwnedTaskBatch(id)
+		if !ok {
+			return
+		}
+
+		tasks, err = object.GetTaskBatchTasks(taskBatch)
+		filename = fmt.Sprintf("%s.xlsx", taskBatch.Name)
+	} else {
+		owner := username
+		if c.IsAdmin() {
+			owner = ""
+		}
+		tasks, err = object.GetTasks(owner)
+	}
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	data, err := object.ExportTaskResults(tasks, c.GetAcceptLanguage())
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.Ctx.Output.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	c.Ctx.Output.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	err = c.Ctx.Output.Body(data)
+	if err != nil {
+		c.ResponseError(err.Error())
+	}
+}
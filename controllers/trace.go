@@ -0,0 +1,124 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"github.com/casibase/casibase/object"
+)
+
+func (c *ApiController) canViewTrace(user string) bool {
+	if c.IsAdmin() || c.IsPreviewMode() {
+		return true
+	}
+	return c.GetSessionUsername() == user
+}
+
+// GetTraces
+// @Title GetTraces
+// @Tag Trace API
+// @Description get the execution trace of a message and its replays
+// @Param   id     query    string  true        "The id (owner/name) of the message"
+// @Success 200 {array} object.Trace The Response object
+// @router /get-traces [get]
+func (c *ApiController) GetTraces() {
+	id := c.Input().Get("id")
+
+	message, err := object.GetMessage(id)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+	if message == nil {
+		c.ResponseError("Message not found")
+		return
+	}
+
+	if !c.canViewTrace(message.User) {
+		c.ResponseError(c.T("auth:Unauthorized operation"))
+		return
+	}
+
+	traces, err := object.GetTraces(message.Owner, message.Name)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(traces)
+}
+
+// GetTrace
+// @Title GetTrace
+// @Tag Trace API
+// @Description get trace
+// @Param   id     query    string  true        "The id (owner/name) of the trace"
+// @Success 200 {object} object.Trace The Response object
+// @router /get-trace [get]
+func (c *ApiController) GetTrace() {
+	id := c.Input().Get("id")
+
+	trace, err := object.GetTrace(id)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+	if trace == nil {
+		c.ResponseOk(nil)
+		return
+	}
+
+	if !c.canViewTrace(trace.User) {
+		c.ResponseError(c.T("auth:Unauthorized operation"))
+		return
+	}
+
+	c.ResponseOk(trace)
+}
+
+// ReplayTrace
+// @Title ReplayTrace
+// @Tag Trace API
+// @Description re-run a traced answer against another model provider with the recorded tool results
+// @Param   id     query    string  true        "The id (owner/name) of the trace"
+// @Param   modelProvider     query    string  true        "The name of the model provider to replay with"
+// @Success 200 {object} object.Trace The Response object
+// @router /replay-trace [post]
+func (c *ApiController) ReplayTrace() {
+	if !c.IsAdmin() {
+		c.ResponseError(c.T("auth:this operation requires admin privilege"))
+		return
+	}
+
+	id := c.Input().Get("id")
+	modelProvider := c.Input().Get("modelProvider")
+
+	trace, err := object.GetTrace(id)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+	if trace == nil {
+		c.ResponseError("Trace not found")
+		return
+	}
+
+	replay, err := object.ReplayTrace(c.Ctx.Request.Context(), trace, modelProvider, c.GetAcceptLanguage())
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(replay)
+}
@@ -0,0 +1,98 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"encoding/json"
+
+	"github.com/casibase/casibase/object"
+)
+
+// TranslateText
+// @Title TranslateText
+// @Tag Translation API
+// @Description translate a text with a model provider following the terms of the glossaries, e.g. a chat answer
+// @Param body body object.TranslationRequest true "The text, the target language (English by default), the model provider (the one of the default store by default) and the ids of the glossaries"
+// @Success 200 {object} object.TranslationResult The Response object
+// @router /translate-text [post]
+func (c *ApiController) TranslateText() {
+	_, ok := c.RequireSignedIn()
+	if !ok {
+		return
+	}
+
+	var request object.TranslationRequest
+	err := json.Unmarshal(c.Ctx.Input.RequestBody, &request)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	result, err := object.Translate(&request, c.GetAcceptLanguage())
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(result)
+}
+
+// TranslateVideoLabels
+// @Title TranslateVideoLabels
+// @Tag Translation API
+// @Description translate the labels of a video to English following the terms of its glossaries
+// @Param id query string true "The id (owner/name) of the video"
+// @Param provider query string false "The model provider, the one of the default store by default"
+// @Success 200 {object} object.Video The Response object
+// @router /translate-video-labels [post]
+func (c *ApiController) TranslateVideoLabels() {
+	if !c.RequireAdmin() {
+		return
+	}
+
+	id := c.Input().Get("id")
+	provider := c.Input().Get("provider")
+
+	video, result, err := object.TranslateVideoLabels(id, provider, c.GetAcceptLanguage())
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(video, result)
+}
+
+// DeleteTranslationCaches
+// @Title DeleteTranslationCaches
+// @Tag Translation API
+// @Description clear the cached translations of a model provider, or all of them
+// @Param provider query string false "The model provider"
+// @Success 200 {object} controllers.Response The Response object
+// @router /delete-translation-caches [post]
+func (c *ApiController) DeleteTranslationCaches() {
+	if !c.RequireAdmin() {
+		return
+	}
+
+	provider := c.Input().Get("provider")
+
+	count, err := object.DeleteTranslationCaches(provider)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(count)
+}
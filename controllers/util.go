@@ -221,6 +221,12 @@ func (c *ApiController) getClientIp() string {
 	return res
 }
 
+// getRemoteClientIp returns the IP address of the client, trusting the forwarded headers only from
+// the proxies of the "trustedProxies" config, for the uses where a spoofed address matters.
+func (c *ApiController) getRemoteClientIp() string {
+	return util.GetClientIPFromRequest(c.Ctx.Request, conf.GetConfigString("trustedProxies"))
+}
+
 func (c *ApiController) getUserAgent() string {
 	res := c.Ctx.Request.UserAgent()
 	return res
@@ -173,3 +173,27 @@ func (c *ApiController) DeleteWorkflow() {
 
 	c.ResponseOk(success)
 }
+
+// ValidateWorkflow
+// @Title ValidateWorkflow
+// @Tag Workflow API
+// @Description check the process of a workflow for unreachable nodes, gateways without default flows, deadlocking joins and invalid timers
+// @Param body body object.Workflow true "The details of the workflow"
+// @Success 200 {array} bpmn.ValidationIssue The Response object
+// @router /validate-workflow [post]
+func (c *ApiController) ValidateWorkflow() {
+	var workflow object.Workflow
+	err := json.Unmarshal(c.Ctx.Input.RequestBody, &workflow)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	issues, err := object.ValidateWorkflow(&workflow, c.GetAcceptLanguage())
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(issues)
+}
@@ -0,0 +1,297 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/casibase/casibase/object"
+)
+
+// GetWorkflowInstances
+// @Title GetWorkflowInstances
+// @Tag Workflow Instance API
+// @Description get the workflow instances, all of them for an admin and the ones the user takes part in otherwise
+// @Param   owner     query    string  true        "The owner of the workflow instances"
+// @Param   workflow     query    string  false        "The name of the workflow"
+// @Success 200 {array} object.WorkflowInstance The Response object
+// @router /get-workflow-instances [get]
+func (c *ApiController) GetWorkflowInstances() {
+	user, ok := c.RequireSignedIn()
+	if !ok {
+		return
+	}
+
+	owner := c.Input().Get("owner")
+	workflow := c.Input().Get("workflow")
+
+	instances, err := object.GetWorkflowInstances(owner, workflow)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	if !c.IsAdmin() {
+		res := []*object.WorkflowInstance{}
+		for _, instance := range instances {
+			if object.CanViewWorkflowInstance(instance, user) {
+				res = append(res, instance)
+			}
+		}
+		instances = res
+	}
+
+	c.ResponseOk(instances)
+}
+
+func (c *ApiController) getViewableWorkflowInstance(id string) (*object.WorkflowInstance, bool) {
+	user, ok := c.RequireSignedIn()
+	if !ok {
+		return nil, false
+	}
+
+	instance, err := object.GetWorkflowInstance(id)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return nil, false
+	}
+	if instance == nil {
+		c.ResponseError("Workflow instance not found")
+		return nil, false
+	}
+
+	if !c.IsAdmin() && !object.CanViewWorkflowInstance(instance, user) {
+		c.ResponseError(c.T("auth:Unauthorized operation"))
+		return nil, false
+	}
+
+	return instance, true
+}
+
+// GetWorkflowInstance
+// @Title GetWorkflowInstance
+// @Tag Workflow Instance API
+// @Description get workflow instance
+// @Param   id     query    string  true        "The id ( owner/name ) of the workflow instance"
+// @Success 200 {object} object.WorkflowInstance The Response object
+// @router /get-workflow-instance [get]
+func (c *ApiController) GetWorkflowInstance() {
+	id := c.Input().Get("id")
+
+	instance, ok := c.getViewableWorkflowInstance(id)
+	if !ok {
+		return
+	}
+
+	c.ResponseOk(instance)
+}
+
+// GetWorkflowInstanceHistory
+// @Title GetWorkflowInstanceHistory
+// @Tag Workflow Instance API
+// @Description get the history of the nodes, flows, tasks and timers of a workflow instance
+// @Param   id     query    string  true        "The id ( owner/name ) of the workflow instance"
+// @Success 200 {array} bpmn.HistoryEvent The Response object
+// @router /get-workflow-instance-history [get]
+func (c *ApiController) GetWorkflowInstanceHistory() {
+	id := c.Input().Get("id")
+
+	instance, ok := c.getViewableWorkflowInstance(id)
+	if !ok {
+		return
+	}
+
+	c.ResponseOk(instance.History)
+}
+
+// StartWorkflowInstance
+// @Title StartWorkflowInstance
+// @Tag Workflow Instance API
+// @Description start an instance of the process of a workflow
+// @Param   id     query    string  true        "The id ( owner/name ) of the workflow"
+// @Param   body    body   object  false        "The variables of the instance"
+// @Success 200 {object} object.WorkflowInstance The Response object
+// @router /start-workflow-instance [post]
+func (c *ApiController) StartWorkflowInstance() {
+	user, ok := c.RequireSignedIn()
+	if !ok {
+		return
+	}
+
+	id := c.Input().Get("id")
+
+	variables := map[string]interface{}{}
+	if len(c.Ctx.Input.RequestBody) != 0 {
+		err := json.Unmarshal(c.Ctx.Input.RequestBody, &variables)
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
+	}
+
+	workflow, err := object.GetWorkflow(id)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+	if workflow == nil {
+		c.ResponseError(fmt.Sprintf(c.T("object:The workflow: %s is not found"), id))
+		return
+	}
+	if !object.CanStartWorkflow(workflow, user, c.IsAdmin()) {
+		c.ResponseError(fmt.Sprintf(c.T("object:You are not allowed to start the workflow: %s"), id))
+		return
+	}
+
+	instance, err := object.StartWorkflowInstance(workflow, user, variables, c.GetAcceptLanguage())
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(instance)
+}
+
+// CompleteWorkflowTask
+// @Title CompleteWorkflowTask
+// @Tag Workflow Instance API
+// @Description complete a user task of a workflow instance and run the instance on
+// @Param   id     query    string  true        "The id ( owner/name ) of the workflow instance"
+// @Param   token     query    string  true        "The token waiting at the user task"
+// @Param   body    body   object  false        "The variables set by the task"
+// @Success 200 {object} object.WorkflowInstance The Response object
+// @router /complete-workflow-task [post]
+func (c *ApiController) CompleteWorkflowTask() {
+	user, ok := c.RequireSignedIn()
+	if !ok {
+		return
+	}
+
+	id := c.Input().Get("id")
+	tokenId := c.Input().Get("token")
+
+	variables := map[string]interface{}{}
+	if len(c.Ctx.Input.RequestBody) != 0 {
+		err := json.Unmarshal(c.Ctx.Input.RequestBody, &variables)
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
+	}
+
+	instance, err := object.GetWorkflowInstance(id)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+	if instance == nil {
+		c.ResponseError("Workflow instance not found")
+		return
+	}
+
+	err = object.CompleteWorkflowTask(instance, tokenId, user, c.IsAdmin(), variables, c.GetAcceptLanguage())
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(instance)
+}
+
+// GetWorkflowTasks
+// @Title GetWorkflowTasks
+// @Tag Workflow Instance API
+// @Description get the user tasks waiting for the user, all of them for an admin
+// @Param   owner     query    string  true        "The owner of the workflow instances"
+// @Success 200 {array} object.WorkflowTask The Response object
+// @router /get-workflow-tasks [get]
+func (c *ApiController) GetWorkflowTasks() {
+	user, ok := c.RequireSignedIn()
+	if !ok {
+		return
+	}
+
+	owner := c.Input().Get("owner")
+
+	tasks, err := object.GetWorkflowTasks(owner, user, c.IsAdmin())
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(tasks)
+}
+
+// CancelWorkflowInstance
+// @Title CancelWorkflowInstance
+// @Tag Workflow Instance API
+// @Description cancel a running workflow instance
+// @Param   id     query    string  true        "The id ( owner/name ) of the workflow instance"
+// @Success 200 {object} object.WorkflowInstance The Response object
+// @router /cancel-workflow-instance [post]
+func (c *ApiController) CancelWorkflowInstance() {
+	if !c.RequireAdmin() {
+		return
+	}
+
+	id := c.Input().Get("id")
+
+	instance, err := object.GetWorkflowInstance(id)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+	if instance == nil {
+		c.ResponseError("Workflow instance not found")
+		return
+	}
+
+	err = object.CancelWorkflowInstance(instance, c.GetAcceptLanguage())
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(instance)
+}
+
+// DeleteWorkflowInstance
+// @Title DeleteWorkflowInstance
+// @Tag Workflow Instance API
+// @Description delete workflow instance
+// @Param   body    body   object.WorkflowInstance  true        "The details of the workflow instance"
+// @Success 200 {object} controllers.Response The Response object
+// @router /delete-workflow-instance [post]
+func (c *ApiController) DeleteWorkflowInstance() {
+	if !c.RequireAdmin() {
+		return
+	}
+
+	var instance object.WorkflowInstance
+	err := json.Unmarshal(c.Ctx.Input.RequestBody, &instance)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	success, err := object.DeleteWorkflowInstance(&instance)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(success)
+}
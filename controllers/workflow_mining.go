@@ -0,0 +1,153 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"io"
+
+	"github.com/casibase/casibase/bpmn"
+	"github.com/casibase/casibase/object"
+	"github.com/casibase/casibase/util"
+)
+
+// getWorkflowEventLog returns the event log of the request: the records or the cases of the owner
+// when the source is "records" or "caases", or else the uploaded CSV or XES file.
+func (c *ApiController) getWorkflowEventLog(owner string) (*bpmn.EventLog, bool) {
+	var log *bpmn.EventLog
+	var err error
+	switch c.Input().Get("source") {
+	case "records":
+		log, err = object.GetRecordEventLog(owner, c.Input().Get("caseField"))
+	case "caases":
+		log, err = object.GetCaaseEventLog(owner)
+	default:
+		file, header, err := c.GetFile("file")
+		if err != nil {
+			c.ResponseError(err.Error())
+			return nil, false
+		}
+		defer file.Close()
+
+		data, err := io.ReadAll(file)
+		if err != nil {
+			c.ResponseError(err.Error())
+			return nil, false
+		}
+
+		log, err = bpmn.ParseEventLog(header.Filename, data, c.Input().Get("caseColumn"), c.Input().Get("activityColumn"), c.Input().Get("timestampColumn"), c.GetAcceptLanguage())
+		if err != nil {
+			c.ResponseError(err.Error())
+			return nil, false
+		}
+	}
+	if err != nil {
+		c.ResponseError(err.Error())
+		return nil, false
+	}
+
+	return log, true
+}
+
+// DiscoverWorkflow
+// @Title DiscoverWorkflow
+// @Tag Workflow API
+// @Description discover a workflow from an event log with the heuristic miner
+// @Param   owner     query    string  true        "The owner of the workflow"
+// @Param   name     query    string  true        "The name of the new workflow"
+// @Param   source     query    string  false        "The source of the event log: records, caases, or empty for the uploaded file"
+// @Param   caseField     query    string  false        "The field of the records the cases are given by"
+// @Param   caseColumn     query    string  false        "The case id column of the CSV file"
+// @Param   activityColumn     query    string  false        "The activity column of the CSV file"
+// @Param   timestampColumn     query    string  false        "The timestamp column of the CSV file"
+// @Param   dependencyThreshold     query    string  false        "The dependency measure a relation needs to become a flow"
+// @Param   minFrequency     query    string  false        "The number of times a relation must be observed"
+// @Param   andThreshold     query    string  false        "The parallelism measure two branches need to become parallel"
+// @Success 200 {object} object.Workflow The Response object
+// @router /discover-workflow [post]
+func (c *ApiController) DiscoverWorkflow() {
+	if !c.RequireAdmin() {
+		return
+	}
+
+	owner := c.Input().Get("owner")
+	name := c.Input().Get("name")
+
+	log, ok := c.getWorkflowEventLog(owner)
+	if !ok {
+		return
+	}
+
+	options := bpmn.NewDiscoveryOptions()
+	if value := c.Input().Get("dependencyThreshold"); value != "" {
+		options.DependencyThreshold = util.ParseFloat(value)
+	}
+	if value := c.Input().Get("minFrequency"); value != "" {
+		options.MinFrequency = util.ParseInt(value)
+	}
+	if value := c.Input().Get("andThreshold"); value != "" {
+		options.AndThreshold = util.ParseFloat(value)
+	}
+
+	workflow, err := object.DiscoverWorkflow(owner, name, log, options, c.GetAcceptLanguage())
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(workflow)
+}
+
+// CheckWorkflowConformance
+// @Title CheckWorkflowConformance
+// @Tag Workflow API
+// @Description replay the cases of an event log on a standard workflow and report the fitness and the per-case deviations
+// @Param   id     query    string  true        "The id ( owner/name ) of the standard workflow"
+// @Param   source     query    string  false        "The source of the event log: records, caases, or empty for the uploaded file"
+// @Param   caseField     query    string  false        "The field of the records the cases are given by"
+// @Param   caseColumn     query    string  false        "The case id column of the CSV file"
+// @Param   activityColumn     query    string  false        "The activity column of the CSV file"
+// @Param   timestampColumn     query    string  false        "The timestamp column of the CSV file"
+// @Success 200 {object} bpmn.ConformanceReport The Response object
+// @router /check-workflow-conformance [post]
+func (c *ApiController) CheckWorkflowConformance() {
+	if !c.RequireAdmin() {
+		return
+	}
+
+	id := c.Input().Get("id")
+
+	workflow, err := object.GetWorkflow(id)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+	if workflow == nil {
+		c.ResponseError("Workflow not found")
+		return
+	}
+
+	log, ok := c.getWorkflowEventLog(workflow.Owner)
+	if !ok {
+		return
+	}
+
+	report, err := object.CheckWorkflowConformance(workflow, log, c.GetAcceptLanguage())
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(report)
+}
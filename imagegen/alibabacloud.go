@@ -0,0 +1,105 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imagegen
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/casibase/casibase/i18n"
+	"github.com/casibase/dashscopego"
+	"github.com/casibase/dashscopego/wanx"
+)
+
+type AlibabacloudImageGenerationProvider struct {
+	subType string
+	apiKey  string
+}
+
+func NewAlibabacloudImageGenerationProvider(subType string, apiKey string) (*AlibabacloudImageGenerationProvider, error) {
+	return &AlibabacloudImageGenerationProvider{subType: subType, apiKey: apiKey}, nil
+}
+
+func (p *AlibabacloudImageGenerationProvider) GetPricing() string {
+	return `URL:
+https://help.aliyun.com/zh/model-studio/models
+
+| Model              | Price per image |
+|--------------------|-----------------|
+| wanx2.1-t2i-plus   | 0.20 yuan       |
+| wanx2.1-t2i-turbo  | 0.14 yuan       |
+| wanx2.0-t2i-turbo  | 0.04 yuan       |
+| wanx-v1            | 0.16 yuan       |
+`
+}
+
+func (p *AlibabacloudImageGenerationProvider) calculatePrice(res *ImageResult, lang string) error {
+	priceTable := map[string]float64{
+		"wanx2.1-t2i-plus":  0.20,
+		"wanx2.1-t2i-turbo": 0.14,
+		"wanx2.0-t2i-turbo": 0.04,
+		"wanx-v1":           0.16,
+	}
+
+	if priceItem, ok := priceTable[p.subType]; ok {
+		res.Price = getImagePrice(res.ImageCount, priceItem)
+		res.Currency = "CNY"
+		return nil
+	} else {
+		return fmt.Errorf(i18n.Translate(lang, "embedding:calculatePrice() error: unknown model type: %s"), p.subType)
+	}
+}
+
+func (p *AlibabacloudImageGenerationProvider) GenerateImages(ctx context.Context, prompt string, size string, n int, lang string) ([]*Image, *ImageResult, error) {
+	if size == "" {
+		size = defaultImageSize
+	}
+
+	cli := dashscopego.NewTongyiClient(p.subType, p.apiKey)
+	req := &wanx.ImageSynthesisRequest{
+		Model: p.subType,
+		Input: wanx.ImageSynthesisInput{
+			Prompt: prompt,
+		},
+		Params: wanx.ImageSynthesisParams{
+			Size: strings.Replace(size, "x", "*", 1),
+			N:    n,
+		},
+		Download: true,
+	}
+
+	blobs, err := cli.CreateImageGeneration(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	images := []*Image{}
+	for _, blob := range blobs {
+		images = append(images, &Image{Data: blob.Data, MimeType: http.DetectContentType(blob.Data)})
+	}
+
+	res := &ImageResult{ImageCount: len(images)}
+	err = p.calculatePrice(res, lang)
+	if err != nil {
+		return nil, nil, err
+	}
+	return images, res, nil
+}
+
+func (p *AlibabacloudImageGenerationProvider) EditImage(ctx context.Context, prompt string, image *Image, mask *Image, size string, n int, lang string) ([]*Image, *ImageResult, error) {
+	return nil, nil, fmt.Errorf(i18n.Translate(lang, "imagegen:the image generation provider type: %s does not support image editing"), "Alibaba Cloud")
+}
@@ -0,0 +1,196 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imagegen
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/casibase/casibase/i18n"
+)
+
+const comfyUiPollInterval = time.Second
+
+// ComfyUiImageGenerationProvider queues the default text-to-image workflow on a ComfyUI
+// server and waits for its output, the sub type is the checkpoint file name.
+type ComfyUiImageGenerationProvider struct {
+	subType       string
+	providerUrl   string
+	pricePerImage float64
+	currency      string
+}
+
+type comfyUiHistoryItem struct {
+	Status struct {
+		StatusStr string `json:"status_str"`
+		Completed bool   `json:"completed"`
+	} `json:"status"`
+	Outputs map[string]struct {
+		Images []struct {
+			Filename  string `json:"filename"`
+			Subfolder string `json:"subfolder"`
+			Type      string `json:"type"`
+		} `json:"images"`
+	} `json:"outputs"`
+}
+
+func NewComfyUiImageGenerationProvider(subType string, providerUrl string, pricePerImage float64, currency string) (*ComfyUiImageGenerationProvider, error) {
+	return &ComfyUiImageGenerationProvider{
+		subType:       subType,
+		providerUrl:   strings.TrimSuffix(providerUrl, "/"),
+		pricePerImage: pricePerImage,
+		currency:      currency,
+	}, nil
+}
+
+func (p *ComfyUiImageGenerationProvider) GetPricing() string {
+	return "Self-hosted, the price per image is configured in the provider"
+}
+
+func (p *ComfyUiImageGenerationProvider) getWorkflow(prompt string, width int, height int, n int) map[string]interface{} {
+	return map[string]interface{}{
+		"3": map[string]interface{}{
+			"class_type": "KSampler",
+			"inputs": map[string]interface{}{
+				"seed":         rand.Int63n(1 << 48),
+				"steps":        20,
+				"cfg":          7,
+				"sampler_name": "euler",
+				"scheduler":    "normal",
+				"denoise":      1,
+				"model":        []interface{}{"4", 0},
+				"positive":     []interface{}{"6", 0},
+				"negative":     []interface{}{"7", 0},
+				"latent_image": []interface{}{"5", 0},
+			},
+		},
+		"4": map[string]interface{}{
+			"class_type": "CheckpointLoaderSimple",
+			"inputs":     map[string]interface{}{"ckpt_name": p.subType},
+		},
+		"5": map[string]interface{}{
+			"class_type": "EmptyLatentImage",
+			"inputs":     map[string]interface{}{"width": width, "height": height, "batch_size": n},
+		},
+		"6": map[string]interface{}{
+			"class_type": "CLIPTextEncode",
+			"inputs":     map[string]interface{}{"text": prompt, "clip": []interface{}{"4", 1}},
+		},
+		"7": map[string]interface{}{
+			"class_type": "CLIPTextEncode",
+			"inputs":     map[string]interface{}{"text": "", "clip": []interface{}{"4", 1}},
+		},
+		"8": map[string]interface{}{
+			"class_type": "VAEDecode",
+			"inputs":     map[string]interface{}{"samples": []interface{}{"3", 0}, "vae": []interface{}{"4", 2}},
+		},
+		"9": map[string]interface{}{
+			"class_type": "SaveImage",
+			"inputs":     map[string]interface{}{"filename_prefix": "casibase", "images": []interface{}{"8", 0}},
+		},
+	}
+}
+
+func (p *ComfyUiImageGenerationProvider) getHistoryItem(ctx context.Context, promptId string) (*comfyUiHistoryItem, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/history/%s", p.providerUrl, promptId), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	history := map[string]*comfyUiHistoryItem{}
+	err = json.Unmarshal(data, &history)
+	if err != nil {
+		return nil, err
+	}
+	return history[promptId], nil
+}
+
+func (p *ComfyUiImageGenerationProvider) GenerateImages(ctx context.Context, prompt string, size string, n int, lang string) ([]*Image, *ImageResult, error) {
+	width, height, err := parseImageSize(size)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var queueResp struct {
+		PromptId string `json:"prompt_id"`
+	}
+	body := map[string]interface{}{"prompt": p.getWorkflow(prompt, width, height, n)}
+	err = postJson(ctx, p.providerUrl+"/prompt", body, &queueResp)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var historyItem *comfyUiHistoryItem
+	for historyItem == nil || !historyItem.Status.Completed {
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-time.After(comfyUiPollInterval):
+		}
+
+		historyItem, err = p.getHistoryItem(ctx, queueResp.PromptId)
+		if err != nil {
+			return nil, nil, err
+		}
+		if historyItem != nil && historyItem.Status.StatusStr == "error" {
+			return nil, nil, fmt.Errorf("ComfyUI prompt: %s failed", queueResp.PromptId)
+		}
+	}
+
+	images := []*Image{}
+	for _, output := range historyItem.Outputs {
+		for _, item := range output.Images {
+			query := url.Values{}
+			query.Set("filename", item.Filename)
+			query.Set("subfolder", item.Subfolder)
+			query.Set("type", item.Type)
+
+			image, err := DownloadImage(ctx, http.DefaultClient, fmt.Sprintf("%s/view?%s", p.providerUrl, query.Encode()))
+			if err != nil {
+				return nil, nil, err
+			}
+			images = append(images, image)
+		}
+	}
+
+	res := &ImageResult{
+		ImageCount: len(images),
+		Price:      getImagePrice(len(images), p.pricePerImage),
+		Currency:   p.currency,
+	}
+	return images, res, nil
+}
+
+func (p *ComfyUiImageGenerationProvider) EditImage(ctx context.Context, prompt string, image *Image, mask *Image, size string, n int, lang string) ([]*Image, *ImageResult, error) {
+	return nil, nil, fmt.Errorf(i18n.Translate(lang, "imagegen:the image generation provider type: %s does not support image editing"), "ComfyUI")
+}
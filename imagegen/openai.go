@@ -0,0 +1,172 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imagegen
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/casibase/casibase/i18n"
+	"github.com/casibase/casibase/model"
+	"github.com/casibase/casibase/proxy"
+	"github.com/openai/openai-go/v2"
+	"github.com/openai/openai-go/v2/packages/param"
+)
+
+type OpenAiImageGenerationProvider struct {
+	subType   string
+	secretKey string
+}
+
+func NewOpenAiImageGenerationProvider(subType string, secretKey string) (*OpenAiImageGenerationProvider, error) {
+	return &OpenAiImageGenerationProvider{subType: subType, secretKey: secretKey}, nil
+}
+
+func (p *OpenAiImageGenerationProvider) GetPricing() string {
+	return `URL:
+https://openai.com/api/pricing/
+
+| Model       | Quality  | 1024x1024 | 1024x1536 / 1536x1024 | 1024x1792 / 1792x1024 |
+|-------------|----------|-----------|-----------------------|-----------------------|
+| gpt-image-1 | Medium   | $0.042    | $0.063                | -                     |
+| dall-e-3    | Standard | $0.040    | -                     | $0.080                |
+| dall-e-2    | Standard | $0.020    | -                     | -                     |
+`
+}
+
+func (p *OpenAiImageGenerationProvider) calculatePrice(res *ImageResult, size string, lang string) error {
+	isLarge := size != "" && size != defaultImageSize && size != "512x512" && size != "256x256"
+
+	var pricePerImage float64
+	switch {
+	case strings.HasPrefix(p.subType, "gpt-image-1"):
+		pricePerImage = 0.042
+		if isLarge {
+			pricePerImage = 0.063
+		}
+	case strings.HasPrefix(p.subType, "dall-e-3"):
+		pricePerImage = 0.04
+		if isLarge {
+			pricePerImage = 0.08
+		}
+	case strings.HasPrefix(p.subType, "dall-e-2"):
+		pricePerImage = 0.02
+	default:
+		return fmt.Errorf(i18n.Translate(lang, "embedding:calculatePrice() error: unknown model type: %s"), p.subType)
+	}
+
+	res.Price = getImagePrice(res.ImageCount, pricePerImage)
+	res.Currency = "USD"
+	return nil
+}
+
+func (p *OpenAiImageGenerationProvider) getImages(ctx context.Context, resp *openai.ImagesResponse) ([]*Image, error) {
+	images := []*Image{}
+	for _, item := range resp.Data {
+		if item.B64JSON != "" {
+			data, err := base64.StdEncoding.DecodeString(item.B64JSON)
+			if err != nil {
+				return nil, err
+			}
+			images = append(images, &Image{Data: data, MimeType: http.DetectContentType(data)})
+		} else if item.URL != "" {
+			image, err := DownloadImage(ctx, proxy.ProxyHttpClient, item.URL)
+			if err != nil {
+				return nil, err
+			}
+			images = append(images, image)
+		}
+	}
+	return images, nil
+}
+
+func (p *OpenAiImageGenerationProvider) GenerateImages(ctx context.Context, prompt string, size string, n int, lang string) ([]*Image, *ImageResult, error) {
+	client := model.GetOpenAiClientFromToken(p.secretKey)
+
+	if size == "" {
+		size = defaultImageSize
+	}
+	req := openai.ImageGenerateParams{
+		Prompt: prompt,
+		Model:  p.subType,
+		Size:   openai.ImageGenerateParamsSize(size),
+		N:      param.NewOpt[int64](int64(n)),
+	}
+	// gpt-image-1 always returns base64 and rejects the response_format parameter
+	if strings.HasPrefix(p.subType, "dall-e") {
+		req.ResponseFormat = openai.ImageGenerateParamsResponseFormatB64JSON
+	}
+
+	resp, err := client.Images.Generate(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	images, err := p.getImages(ctx, resp)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res := &ImageResult{ImageCount: len(images)}
+	err = p.calculatePrice(res, size, lang)
+	if err != nil {
+		return nil, nil, err
+	}
+	return images, res, nil
+}
+
+func (p *OpenAiImageGenerationProvider) EditImage(ctx context.Context, prompt string, image *Image, mask *Image, size string, n int, lang string) ([]*Image, *ImageResult, error) {
+	client := model.GetOpenAiClientFromToken(p.secretKey)
+
+	if size == "" {
+		size = defaultImageSize
+	}
+	req := openai.ImageEditParams{
+		Image: openai.ImageEditParamsImageUnion{
+			OfFile: openai.File(bytes.NewReader(image.Data), "image.png", image.MimeType),
+		},
+		Prompt: prompt,
+		Model:  p.subType,
+		Size:   openai.ImageEditParamsSize(size),
+		N:      param.NewOpt[int64](int64(n)),
+	}
+	if mask != nil {
+		req.Mask = openai.File(bytes.NewReader(mask.Data), "mask.png", mask.MimeType)
+	}
+	if strings.HasPrefix(p.subType, "dall-e") {
+		req.ResponseFormat = openai.ImageEditParamsResponseFormatB64JSON
+	}
+
+	resp, err := client.Images.Edit(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	images, err := p.getImages(ctx, resp)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res := &ImageResult{ImageCount: len(images)}
+	err = p.calculatePrice(res, size, lang)
+	if err != nil {
+		return nil, nil, err
+	}
+	return images, res, nil
+}
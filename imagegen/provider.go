@@ -0,0 +1,56 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imagegen
+
+import (
+	"context"
+)
+
+type Image struct {
+	Data     []byte
+	MimeType string
+}
+
+type ImageResult struct {
+	ImageCount int
+	Price      float64
+	Currency   string
+}
+
+type ImageGenerationProvider interface {
+	GetPricing() string
+	GenerateImages(ctx context.Context, prompt string, size string, n int, lang string) ([]*Image, *ImageResult, error)
+	EditImage(ctx context.Context, prompt string, image *Image, mask *Image, size string, n int, lang string) ([]*Image, *ImageResult, error)
+}
+
+func GetImageGenerationProvider(typ string, subType string, clientSecret string, providerUrl string, pricePerImage float64, currency string) (ImageGenerationProvider, error) {
+	var p ImageGenerationProvider
+	var err error
+
+	if typ == "OpenAI" {
+		p, err = NewOpenAiImageGenerationProvider(subType, clientSecret)
+	} else if typ == "Alibaba Cloud" {
+		p, err = NewAlibabacloudImageGenerationProvider(subType, clientSecret)
+	} else if typ == "Stable Diffusion" {
+		p, err = NewStableDiffusionImageGenerationProvider(subType, providerUrl, pricePerImage, currency)
+	} else if typ == "ComfyUI" {
+		p, err = NewComfyUiImageGenerationProvider(subType, providerUrl, pricePerImage, currency)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
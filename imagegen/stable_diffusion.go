@@ -0,0 +1,135 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imagegen
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// StableDiffusionImageGenerationProvider calls the API of a Stable Diffusion WebUI (AUTOMATIC1111)
+// server started with --api, the sub type is the checkpoint to use, empty for the loaded one.
+type StableDiffusionImageGenerationProvider struct {
+	subType       string
+	providerUrl   string
+	pricePerImage float64
+	currency      string
+}
+
+func NewStableDiffusionImageGenerationProvider(subType string, providerUrl string, pricePerImage float64, currency string) (*StableDiffusionImageGenerationProvider, error) {
+	return &StableDiffusionImageGenerationProvider{
+		subType:       subType,
+		providerUrl:   strings.TrimSuffix(providerUrl, "/"),
+		pricePerImage: pricePerImage,
+		currency:      currency,
+	}, nil
+}
+
+func (p *StableDiffusionImageGenerationProvider) GetPricing() string {
+	return "Self-hosted, the price per image is configured in the provider"
+}
+
+func postJson(ctx context.Context, url string, body interface{}, res interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	// local endpoints are called directly instead of through the proxy
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("request to %s failed, status: %s, body: %s", url, resp.Status, string(respData))
+	}
+
+	return json.Unmarshal(respData, res)
+}
+
+func (p *StableDiffusionImageGenerationProvider) query(ctx context.Context, path string, body map[string]interface{}, size string, n int) ([]*Image, *ImageResult, error) {
+	width, height, err := parseImageSize(size)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body["width"] = width
+	body["height"] = height
+	body["batch_size"] = n
+	if p.subType != "" {
+		body["override_settings"] = map[string]interface{}{"sd_model_checkpoint": p.subType}
+	}
+
+	var resp struct {
+		Images []string `json:"images"`
+	}
+	err = postJson(ctx, p.providerUrl+path, body, &resp)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	images := []*Image{}
+	for _, item := range resp.Images {
+		data, err := base64.StdEncoding.DecodeString(item)
+		if err != nil {
+			return nil, nil, err
+		}
+		images = append(images, &Image{Data: data, MimeType: http.DetectContentType(data)})
+	}
+
+	res := &ImageResult{
+		ImageCount: len(images),
+		Price:      getImagePrice(len(images), p.pricePerImage),
+		Currency:   p.currency,
+	}
+	return images, res, nil
+}
+
+func (p *StableDiffusionImageGenerationProvider) GenerateImages(ctx context.Context, prompt string, size string, n int, lang string) ([]*Image, *ImageResult, error) {
+	body := map[string]interface{}{
+		"prompt": prompt,
+	}
+	return p.query(ctx, "/sdapi/v1/txt2img", body, size, n)
+}
+
+func (p *StableDiffusionImageGenerationProvider) EditImage(ctx context.Context, prompt string, image *Image, mask *Image, size string, n int, lang string) ([]*Image, *ImageResult, error) {
+	body := map[string]interface{}{
+		"prompt":             prompt,
+		"init_images":        []string{base64.StdEncoding.EncodeToString(image.Data)},
+		"denoising_strength": 0.75,
+	}
+	if mask != nil {
+		body["mask"] = base64.StdEncoding.EncodeToString(mask.Data)
+	}
+	return p.query(ctx, "/sdapi/v1/img2img", body, size, n)
+}
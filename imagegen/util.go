@@ -0,0 +1,81 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imagegen
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const defaultImageSize = "1024x1024"
+
+func getImagePrice(imageCount int, pricePerImage float64) float64 {
+	res := float64(imageCount) * pricePerImage
+	res = math.Round(res*1e8) / 1e8
+	return res
+}
+
+// parseImageSize parses a "1024x1024" or "1024*1024" size into width and height.
+func parseImageSize(size string) (int, int, error) {
+	if size == "" {
+		size = defaultImageSize
+	}
+
+	tokens := strings.FieldsFunc(size, func(r rune) bool {
+		return r == 'x' || r == '*'
+	})
+	if len(tokens) != 2 {
+		return 0, 0, fmt.Errorf("invalid image size: %s", size)
+	}
+
+	width, err := strconv.Atoi(tokens[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	height, err := strconv.Atoi(tokens[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	return width, height, nil
+}
+
+func DownloadImage(ctx context.Context, client *http.Client, url string) (*Image, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download image: %s, status: %s", url, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Image{Data: data, MimeType: http.DetectContentType(data)}, nil
+}
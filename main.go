@@ -40,9 +40,14 @@ func main() {
 	util.InitIpDb()
 	util.InitParser()
 	object.InitCleanupChats()
+	object.InitAnswerCachePurge()
 	object.InitStoreCount()
 	object.InitCommitRecordsTask()
 	object.InitScanJobProcessor()
+	object.InitWorkflowTimerProcessor()
+	object.InitTaskBatchProcessor()
+	object.InitEvaluations()
+	object.InitCodeSandbox()
 	object.InitMessageTransactionRetry()
 
 	beego.SetStaticPath("/swagger", "swagger")
@@ -0,0 +1,215 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/casibase/casibase/i18n"
+	"github.com/sashabaranov/go-openai"
+)
+
+const (
+	AgentTraceStepModel = "Model"
+	AgentTraceStepTool  = "Tool"
+)
+
+// AgentTraceStep is a model turn or a tool call of an answer. The tool calls have the turn of the
+// model turn requesting them, Duration is in milliseconds.
+type AgentTraceStep struct {
+	Type      string `json:"type"`
+	Turn      int    `json:"turn"`
+	StartTime string `json:"startTime"`
+	Duration  int64  `json:"duration"`
+	Error     string `json:"error,omitempty"`
+
+	Text               string   `json:"text,omitempty"`
+	ToolCallIds        []string `json:"toolCallIds,omitempty"`
+	PromptTokenCount   int      `json:"promptTokenCount,omitempty"`
+	ResponseTokenCount int      `json:"responseTokenCount,omitempty"`
+	TotalTokenCount    int      `json:"totalTokenCount,omitempty"`
+	Price              float64  `json:"price,omitempty"`
+
+	ToolCallId string `json:"toolCallId,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Arguments  string `json:"arguments,omitempty"`
+	Result     string `json:"result,omitempty"`
+}
+
+// AgentTrace records the model turns and the tool calls of an answer in the order they end.
+type AgentTrace struct {
+	mutex sync.Mutex
+	turn  int
+	steps []*AgentTraceStep
+}
+
+func NewAgentTrace() *AgentTrace {
+	return &AgentTrace{}
+}
+
+// GetSteps returns the steps recorded so far.
+func (t *AgentTrace) GetSteps() []*AgentTraceStep {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return append([]*AgentTraceStep{}, t.steps...)
+}
+
+// agentTraceWriter passes the events to the writer while keeping the text of the model turn.
+type agentTraceWriter struct {
+	io.Writer
+	text bytes.Buffer
+}
+
+func (w *agentTraceWriter) Flush() {
+	if flusher, ok := w.Writer.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *agentTraceWriter) Write(p []byte) (n int, err error) {
+	s := string(p)
+	if strings.HasPrefix(s, "event: message\ndata: ") && strings.HasSuffix(s, "\n\n") {
+		w.text.WriteString(strings.TrimSuffix(strings.TrimPrefix(s, "event: message\ndata: "), "\n\n"))
+	}
+	return w.Writer.Write(p)
+}
+
+// QueryText queries the model like p.QueryText and records the turn, the trace may be nil.
+func (t *AgentTrace) QueryText(p ModelProvider, question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo, lang string) (*ModelResult, error) {
+	if t == nil {
+		return p.QueryText(question, writer, history, prompt, knowledgeMessages, agentInfo, lang)
+	}
+
+	traceWriter := &agentTraceWriter{Writer: writer}
+	startTime := time.Now()
+	modelResult, err := p.QueryText(question, traceWriter, history, prompt, knowledgeMessages, agentInfo, lang)
+
+	step := &AgentTraceStep{
+		Type:      AgentTraceStepModel,
+		StartTime: startTime.Format(time.RFC3339Nano),
+		Duration:  time.Since(startTime).Milliseconds(),
+		Text:      traceWriter.text.String(),
+	}
+	if err != nil {
+		step.Error = err.Error()
+	}
+	if modelResult != nil {
+		step.PromptTokenCount = modelResult.PromptTokenCount
+		step.ResponseTokenCount = modelResult.ResponseTokenCount
+		step.TotalTokenCount = modelResult.TotalTokenCount
+		step.Price = modelResult.TotalPrice
+	}
+	if err == nil && agentInfo != nil && agentInfo.AgentMessages != nil {
+		for _, toolCall := range getToolCalls(agentInfo.AgentMessages) {
+			step.ToolCallIds = append(step.ToolCallIds, toolCall.ID)
+		}
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	step.Turn = t.turn
+	t.turn++
+	t.steps = append(t.steps, step)
+	return modelResult, err
+}
+
+func (t *AgentTrace) addToolStep(toolCall openai.ToolCall, startTime time.Time, result string, errorText string) {
+	if t == nil {
+		return
+	}
+
+	step := &AgentTraceStep{
+		Type:       AgentTraceStepTool,
+		StartTime:  startTime.Format(time.RFC3339Nano),
+		Duration:   time.Since(startTime).Milliseconds(),
+		Error:      errorText,
+		ToolCallId: toolCall.ID,
+		Name:       toolCall.Function.Name,
+		Arguments:  toolCall.Function.Arguments,
+		Result:     result,
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	step.Turn = t.turn - 1
+	t.steps = append(t.steps, step)
+}
+
+// AgentReplay answers the tool calls with the results recorded in a trace instead of calling the
+// tools, so that an answer can be re-run with another model for regression comparison.
+type AgentReplay struct {
+	mutex sync.Mutex
+	steps []*AgentTraceStep
+	used  map[*AgentTraceStep]bool
+}
+
+func NewAgentReplay(steps []*AgentTraceStep) *AgentReplay {
+	replay := &AgentReplay{used: map[*AgentTraceStep]bool{}}
+	for _, step := range steps {
+		if step.Type == AgentTraceStepTool {
+			replay.steps = append(replay.steps, step)
+		}
+	}
+	return replay
+}
+
+// normalizeArguments formats the JSON arguments so that the spacing and the key order don't matter.
+func normalizeArguments(arguments string) string {
+	var v interface{}
+	if err := json.Unmarshal([]byte(arguments), &v); err != nil {
+		return arguments
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return arguments
+	}
+	return string(data)
+}
+
+// getToolResult returns the recorded result and error of the first unused call of the tool with the
+// same arguments, or else of the first unused call of the tool.
+func (r *AgentReplay) getToolResult(toolCall openai.ToolCall, lang string) (string, string, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	arguments := normalizeArguments(toolCall.Function.Arguments)
+	var res *AgentTraceStep
+	for _, step := range r.steps {
+		if r.used[step] || step.Name != toolCall.Function.Name {
+			continue
+		}
+		if normalizeArguments(step.Arguments) == arguments {
+			res = step
+			break
+		}
+		if res == nil {
+			res = step
+		}
+	}
+
+	if res == nil {
+		return "", "", fmt.Errorf(i18n.Translate(lang, "model:the call of the tool: %s is not recorded in the trace"), toolCall.Function.Name)
+	}
+	r.used[res] = true
+	return res.Result, res.Error, nil
+}
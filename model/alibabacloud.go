@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"strings"
 
 	"github.com/casibase/casibase/i18n"
@@ -147,20 +148,60 @@ func (p *AlibabacloudModelProvider) QueryText(question string, writer io.Writer,
 		return flushDataThink(data, typ, writer, lang)
 	}
 
-	req := &qwen.Request[*qwen.TextContent]{
-		Model: p.subType,
-		Input: qwen.Input[*qwen.TextContent]{
-			Messages: buildMessages(question, history, prompt, knowledgeMessages),
-		},
-		Parameters:  params,
-		StreamingFn: streamCallbackFn,
+	capability := getVisionCapability(visionFamilyQwen, p.subType)
+	err := checkQuestionImages(question, capability, p.subType, lang)
+	if err != nil {
+		return nil, err
 	}
 
-	resp, err := cli.CreateCompletion(ctx, req)
+	questionMessage := &RawMessage{Text: question, Author: "User"}
+	imageTokenCount, err := prepareRawMessageImages([]*RawMessage{questionMessage}, capability, p.subType, lang)
 	if err != nil {
 		return nil, err
 	}
 
+	var resp *dashscopego.TextQwenResponse
+	if len(questionMessage.Parts) > 0 {
+		messages, tempFiles, err := buildVLMessages(questionMessage, history, prompt, knowledgeMessages)
+		defer func() {
+			for _, tempFile := range tempFiles {
+				os.Remove(tempFile)
+			}
+		}()
+		if err != nil {
+			return nil, err
+		}
+
+		req := &qwen.Request[*qwen.VLContentList]{
+			Model: p.subType,
+			Input: qwen.Input[*qwen.VLContentList]{
+				Messages: messages,
+			},
+			Parameters:  params,
+			StreamingFn: streamCallbackFn,
+		}
+
+		vlResp, err := cli.CreateVLCompletion(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		resp = &dashscopego.TextQwenResponse{Usage: vlResp.Usage}
+	} else {
+		req := &qwen.Request[*qwen.TextContent]{
+			Model: p.subType,
+			Input: qwen.Input[*qwen.TextContent]{
+				Messages: buildMessages(question, history, prompt, knowledgeMessages),
+			},
+			Parameters:  params,
+			StreamingFn: streamCallbackFn,
+		}
+
+		resp, err = cli.CreateCompletion(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if resp.Output.SearchInfo != nil && resp.Output.SearchInfo.SearchResults != nil && len(resp.Output.SearchInfo.SearchResults) > 0 {
 		searchResultsJSON, _ := json.Marshal(resp.Output.SearchInfo.SearchResults)
 		flushDataThink(string(searchResultsJSON), "search", writer, lang)
@@ -170,6 +211,7 @@ func (p *AlibabacloudModelProvider) QueryText(question string, writer io.Writer,
 		PromptTokenCount:   resp.Usage.InputTokens,
 		ResponseTokenCount: resp.Usage.OutputTokens,
 		TotalTokenCount:    resp.Usage.TotalTokens,
+		ImageTokenCount:    imageTokenCount,
 	}
 
 	err = p.calculatePrice(modelResult, lang)
@@ -213,3 +255,38 @@ func buildMessages(question string, history []*RawMessage, prompt string, knowle
 
 	return messages
 }
+
+// buildVLMessages builds the messages of a Qwen-VL request. The SDK only uploads the last image
+// of a message, from a URL or a local file, so that image is written to a temporary file that the
+// caller removes afterwards when it has no URL, and the other images are sent as data URLs.
+func buildVLMessages(questionMessage *RawMessage, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage) ([]qwen.Message[*qwen.VLContentList], []string, error) {
+	var messages []qwen.Message[*qwen.VLContentList]
+	var tempFiles []string
+	for _, textMessage := range buildMessages(questionMessage.Text, history, prompt, knowledgeMessages) {
+		content := qwen.NewVLContentList()
+		content.SetText(textMessage.Content.Text)
+		messages = append(messages, qwen.Message[*qwen.VLContentList]{
+			Role:    textMessage.Role,
+			Content: content,
+		})
+	}
+
+	questionContent := messages[len(messages)-1].Content
+	imageParts := getImageParts(questionMessage.Parts)
+	for i, part := range imageParts {
+		url := part.Url
+		if url == "" && i < len(imageParts)-1 {
+			url = part.getDataUrl()
+		} else if url == "" {
+			tempFile, err := writeImagePartToTempFile(part)
+			if err != nil {
+				return nil, tempFiles, err
+			}
+			tempFiles = append(tempFiles, tempFile)
+			url = "file://" + tempFile
+		}
+		questionContent.SetImage(url)
+	}
+
+	return messages, tempFiles, nil
+}
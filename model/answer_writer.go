@@ -0,0 +1,49 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"bytes"
+	"strings"
+)
+
+// AnswerWriter keeps the answer text and the tool calls of a model queried without a client
+// reading the events, like a structured output, a delegated store or a replayed trace.
+// The other events are dropped.
+type AnswerWriter struct {
+	bytes.Buffer
+	toolData []string
+}
+
+func (w *AnswerWriter) Flush() {}
+
+func (w *AnswerWriter) Write(p []byte) (n int, err error) {
+	s := string(p)
+	if strings.HasPrefix(s, "event: message\ndata: ") && strings.HasSuffix(s, "\n\n") {
+		data := strings.TrimSuffix(strings.TrimPrefix(s, "event: message\ndata: "), "\n\n")
+		return w.Buffer.WriteString(data)
+	} else if strings.HasPrefix(s, "event: tool\ndata: ") && strings.HasSuffix(s, "\n\n") {
+		w.toolData = append(w.toolData, strings.TrimSuffix(strings.TrimPrefix(s, "event: tool\ndata: "), "\n\n"))
+		return len(p), nil
+	} else if strings.HasPrefix(s, "event: ") && strings.HasSuffix(s, "\n\n") {
+		return len(p), nil
+	}
+	return w.Buffer.Write(p)
+}
+
+// ToolString returns the tool calls written so far, one JSON object per line like GetToolCallsFromWriter expects.
+func (w *AnswerWriter) ToolString() string {
+	return strings.Join(w.toolData, "\n")
+}
@@ -32,12 +32,19 @@ type ClaudeModelProvider struct {
 	secretKey      string
 	budgetTokens   int
 	enableThinking bool
+	responseSchema *ResponseSchema
 }
 
 func NewClaudeModelProvider(subType string, secretKey string, enableThinking bool, budgetTokens int) (*ClaudeModelProvider, error) {
 	return &ClaudeModelProvider{subType: subType, secretKey: secretKey, enableThinking: enableThinking, budgetTokens: budgetTokens}, nil
 }
 
+func (p *ClaudeModelProvider) WithResponseSchema(schema *ResponseSchema) ModelProvider {
+	provider := *p
+	provider.responseSchema = schema
+	return &provider
+}
+
 func (p *ClaudeModelProvider) GetPricing() string {
 	return `URL:
 https://docs.anthropic.com/en/docs/about-claude/pricing
@@ -124,7 +131,27 @@ func (p *ClaudeModelProvider) QueryText(question string, writer io.Writer, histo
 		historyMessage := history[i]
 		messages = append(messages, anthropic.NewAssistantMessage(anthropic.NewTextBlock(historyMessage.Text)))
 	}
-	messages = append(messages, anthropic.NewUserMessage(anthropic.NewTextBlock(question)))
+
+	capability := getVisionCapability(visionFamilyClaude, p.subType)
+	err := checkQuestionImages(question, capability, p.subType, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	questionMessage := &RawMessage{Text: question, Author: "User"}
+	imageTokenCount, err := prepareRawMessageImages([]*RawMessage{questionMessage}, capability, p.subType, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	questionBlocks := []anthropic.ContentBlockParamUnion{}
+	for _, part := range getImageParts(questionMessage.Parts) {
+		questionBlocks = append(questionBlocks, anthropic.NewImageBlockBase64(part.MimeType, part.getBase64()))
+	}
+	if questionMessage.Text != "" || len(questionBlocks) == 0 {
+		questionBlocks = append(questionBlocks, anthropic.NewTextBlock(questionMessage.Text))
+	}
+	messages = append(messages, anthropic.NewUserMessage(questionBlocks...))
 
 	messageParams := anthropic.MessageNewParams{
 		MaxTokens:     int64(maxTokens),
@@ -133,7 +160,30 @@ func (p *ClaudeModelProvider) QueryText(question string, writer io.Writer, histo
 		StopSequences: []string{"```\n"},
 		System:        textBlockList,
 	}
-	if p.enableThinking {
+	// Claude has no JSON mode, the schema is enforced by forcing the model to call a tool
+	// whose input schema is the response schema, and the tool input is streamed as the answer.
+	// Forced tool use is not compatible with extended thinking, so thinking is skipped then.
+	useResponseTool := p.responseSchema != nil && p.responseSchema.Schema["type"] == "object"
+	if useResponseTool {
+		inputSchema := anthropic.ToolInputSchemaParam{
+			Properties:  p.responseSchema.Schema["properties"],
+			Required:    getSchemaRequired(p.responseSchema.Schema),
+			ExtraFields: map[string]any{},
+		}
+		for key, value := range p.responseSchema.Schema {
+			if key != "type" && key != "properties" && key != "required" {
+				inputSchema.ExtraFields[key] = value
+			}
+		}
+
+		tool := anthropic.ToolUnionParamOfTool(inputSchema, p.responseSchema.getName())
+		if p.responseSchema.Description != "" {
+			tool.OfTool.Description = anthropic.String(p.responseSchema.Description)
+		}
+		messageParams.Tools = []anthropic.ToolUnionParam{tool}
+		messageParams.ToolChoice = anthropic.ToolChoiceParamOfTool(p.responseSchema.getName())
+		messageParams.StopSequences = nil
+	} else if p.enableThinking {
 		messageParams.Thinking = anthropic.ThinkingConfigParamUnion{
 			OfEnabled: &anthropic.ThinkingConfigEnabledParam{
 				BudgetTokens: int64(p.budgetTokens),
@@ -155,7 +205,7 @@ func (p *ClaudeModelProvider) QueryText(question string, writer io.Writer, histo
 		return nil
 	}
 
-	modelResult := &ModelResult{}
+	modelResult := &ModelResult{ImageTokenCount: imageTokenCount}
 	for stream.Next() {
 		event := stream.Current()
 
@@ -175,6 +225,13 @@ func (p *ClaudeModelProvider) QueryText(question string, writer io.Writer, histo
 				if err != nil {
 					return nil, err
 				}
+			case anthropic.InputJSONDelta:
+				if useResponseTool {
+					err := flushData("message", deltaVariant.PartialJSON)
+					if err != nil {
+						return nil, err
+					}
+				}
 			}
 		case anthropic.MessageDeltaEvent:
 			outputTokens := int(eventVariant.Usage.OutputTokens)
@@ -187,7 +244,7 @@ func (p *ClaudeModelProvider) QueryText(question string, writer io.Writer, histo
 	}
 	modelResult.TotalTokenCount = modelResult.PromptTokenCount + modelResult.ResponseTokenCount
 
-	err := p.calculatePrice(modelResult, lang)
+	err = p.calculatePrice(modelResult, lang)
 	if err != nil {
 		return nil, err
 	}
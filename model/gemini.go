@@ -27,11 +27,12 @@ import (
 )
 
 type GeminiModelProvider struct {
-	subType     string
-	secretKey   string
-	temperature float32
-	topP        float32
-	topK        int
+	subType        string
+	secretKey      string
+	temperature    float32
+	topP           float32
+	topK           int
+	responseSchema *ResponseSchema
 }
 
 func NewGeminiModelProvider(subType string, secretKey string, temperature float32, topP float32, topK int) (*GeminiModelProvider, error) {
@@ -45,6 +46,56 @@ func NewGeminiModelProvider(subType string, secretKey string, temperature float3
 	return p, nil
 }
 
+func (p *GeminiModelProvider) WithResponseSchema(schema *ResponseSchema) ModelProvider {
+	provider := *p
+	provider.responseSchema = schema
+	return &provider
+}
+
+// getGenaiSchema converts a JSON schema into the OpenAPI subset accepted by Gemini's responseSchema.
+func getGenaiSchema(schema JsonSchema) *genai.Schema {
+	res := &genai.Schema{}
+	for _, typ := range getSchemaTypes(schema) {
+		if typ == "null" {
+			res.Nullable = genai.Ptr(true)
+		} else if res.Type == "" {
+			res.Type = genai.Type(strings.ToUpper(typ))
+		}
+	}
+
+	if description, ok := schema["description"].(string); ok {
+		res.Description = description
+	}
+	for _, item := range getSchemaEnum(schema) {
+		res.Enum = append(res.Enum, fmt.Sprint(item))
+	}
+	if minimum, ok := getSchemaNumber(schema, "minimum"); ok {
+		res.Minimum = genai.Ptr(minimum)
+	}
+	if maximum, ok := getSchemaNumber(schema, "maximum"); ok {
+		res.Maximum = genai.Ptr(maximum)
+	}
+	if minItems, ok := getSchemaNumber(schema, "minItems"); ok {
+		res.MinItems = genai.Ptr(int64(minItems))
+	}
+	if maxItems, ok := getSchemaNumber(schema, "maxItems"); ok {
+		res.MaxItems = genai.Ptr(int64(maxItems))
+	}
+	if items, ok := getSchemaMap(schema["items"]); ok {
+		res.Items = getGenaiSchema(items)
+	}
+	if properties, ok := getSchemaMap(schema["properties"]); ok {
+		res.Properties = map[string]*genai.Schema{}
+		for name, property := range properties {
+			if propertySchema, ok := getSchemaMap(property); ok {
+				res.Properties[name] = getGenaiSchema(propertySchema)
+			}
+		}
+	}
+	res.Required = getSchemaRequired(schema)
+	return res
+}
+
 func (p *GeminiModelProvider) GetPricing() string {
 	return `URL: https://ai.google.dev/gemini-api/docs/pricing
 | Model                                          | Input Price (per 1M tokens)              | Output Price (per 1M tokens)              |
@@ -304,16 +355,40 @@ func (p *GeminiModelProvider) QueryText(question string, writer io.Writer, histo
 
 	// https://cloud.google.com/vertex-ai/generative-ai/docs/multimodal/get-token-count#gemini-get-token-count-samples-drest
 	// has to use CountToken() to get
-	contents := []*genai.Content{
-		genai.NewContentFromText(question, genai.RoleUser),
+	capability := getVisionCapability(visionFamilyGemini, p.subType)
+	err = checkQuestionImages(question, capability, p.subType, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	questionMessage := &RawMessage{Text: question, Author: genai.RoleUser}
+	imageTokenCount, err := prepareRawMessageImages([]*RawMessage{questionMessage}, capability, p.subType, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	questionContent := genai.NewContentFromText(questionMessage.Text, genai.RoleUser)
+	for _, part := range getImageParts(questionMessage.Parts) {
+		questionContent.Parts = append(questionContent.Parts, genai.NewPartFromBytes(part.Data, part.MimeType))
 	}
+
+	// the image parts are counted by the API as well
+	contents := []*genai.Content{questionContent}
 	promptTokenCountResp, err := client.Models.CountTokens(ctx, p.subType, contents, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	messages := GenaiRawMessagesToMessages(question, history)
-	resp, err := model.GenerateContent(ctx, p.subType, messages, nil)
+	messages[len(messages)-1] = questionContent
+	var config *genai.GenerateContentConfig
+	if p.responseSchema != nil {
+		config = &genai.GenerateContentConfig{
+			ResponseMIMEType: "application/json",
+			ResponseSchema:   getGenaiSchema(p.responseSchema.Schema),
+		}
+	}
+	resp, err := model.GenerateContent(ctx, p.subType, messages, config)
 	if err != nil {
 		return nil, err
 	}
@@ -344,6 +419,7 @@ func (p *GeminiModelProvider) QueryText(question string, writer io.Writer, histo
 		PromptTokenCount:   promptTokenCount,
 		ResponseTokenCount: respTokenCount,
 		TotalTokenCount:    promptTokenCount + respTokenCount,
+		ImageTokenCount:    imageTokenCount,
 	}
 
 	err = p.calculatePrice(modelResult, lang)
@@ -49,6 +49,12 @@ func getGitHubClientFromToken(authToken string, providerUrl string) *openai.Clie
 	return c
 }
 
+func (p *GitHubModelProvider) WithResponseSchema(schema *ResponseSchema) ModelProvider {
+	return &GitHubModelProvider{
+		LocalModelProvider: p.LocalModelProvider.WithResponseSchema(schema).(*LocalModelProvider),
+	}
+}
+
 func (p *GitHubModelProvider) GetPricing() string {
 	return `GitHub model API usage are free but rate limited by requests per minute, requests per day, tokens per request, and concurrent requests.
 URL:
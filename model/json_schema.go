@@ -0,0 +1,228 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// JsonSchema is a JSON schema document kept as a plain map, so that it can be
+// handed to every provider SDK as is.
+type JsonSchema map[string]interface{}
+
+func (s JsonSchema) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}(s))
+}
+
+func getSchemaMap(value interface{}) (JsonSchema, bool) {
+	switch v := value.(type) {
+	case JsonSchema:
+		return v, true
+	case map[string]interface{}:
+		return v, true
+	default:
+		return nil, false
+	}
+}
+
+func getSchemaNumber(schema JsonSchema, key string) (float64, bool) {
+	switch v := schema[key].(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+func getSchemaTypes(schema JsonSchema) []string {
+	switch v := schema["type"].(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []interface{}:
+		res := []string{}
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				res = append(res, s)
+			}
+		}
+		return res
+	default:
+		return nil
+	}
+}
+
+func getJsonValueType(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func isJsonTypeMatched(expected string, actual string) bool {
+	return expected == actual || (expected == "number" && actual == "integer")
+}
+
+// ValidateJsonSchema checks a value decoded by encoding/json against the schema and returns
+// one message per violation. The supported keywords are the subset used by structured output:
+// type, properties, required, additionalProperties, items, enum, minimum, maximum,
+// minItems, maxItems, minLength and maxLength.
+func ValidateJsonSchema(schema JsonSchema, value interface{}) []string {
+	return validateJsonSchema(schema, value, "$")
+}
+
+func validateJsonSchema(schema JsonSchema, value interface{}, path string) []string {
+	errors := []string{}
+
+	types := getSchemaTypes(schema)
+	if len(types) > 0 {
+		actual := getJsonValueType(value)
+		matched := false
+		for _, expected := range types {
+			if isJsonTypeMatched(expected, actual) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return append(errors, fmt.Sprintf("%s: expected %s, got %s", path, strings.Join(types, " or "), actual))
+		}
+	}
+
+	if enum := getSchemaEnum(schema); len(enum) > 0 {
+		found := false
+		for _, item := range enum {
+			if fmt.Sprint(item) == fmt.Sprint(value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errors = append(errors, fmt.Sprintf("%s: value %v is not one of %v", path, value, enum))
+		}
+	}
+
+	switch v := value.(type) {
+	case float64:
+		if minimum, ok := getSchemaNumber(schema, "minimum"); ok && v < minimum {
+			errors = append(errors, fmt.Sprintf("%s: %v is less than the minimum %v", path, v, minimum))
+		}
+		if maximum, ok := getSchemaNumber(schema, "maximum"); ok && v > maximum {
+			errors = append(errors, fmt.Sprintf("%s: %v is greater than the maximum %v", path, v, maximum))
+		}
+	case string:
+		length := len([]rune(v))
+		if minLength, ok := getSchemaNumber(schema, "minLength"); ok && float64(length) < minLength {
+			errors = append(errors, fmt.Sprintf("%s: length %d is less than %v", path, length, minLength))
+		}
+		if maxLength, ok := getSchemaNumber(schema, "maxLength"); ok && float64(length) > maxLength {
+			errors = append(errors, fmt.Sprintf("%s: length %d is greater than %v", path, length, maxLength))
+		}
+	case []interface{}:
+		if minItems, ok := getSchemaNumber(schema, "minItems"); ok && float64(len(v)) < minItems {
+			errors = append(errors, fmt.Sprintf("%s: %d items is less than %v", path, len(v), minItems))
+		}
+		if maxItems, ok := getSchemaNumber(schema, "maxItems"); ok && float64(len(v)) > maxItems {
+			errors = append(errors, fmt.Sprintf("%s: %d items is greater than %v", path, len(v), maxItems))
+		}
+		if items, ok := getSchemaMap(schema["items"]); ok {
+			for i, item := range v {
+				errors = append(errors, validateJsonSchema(items, item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	case map[string]interface{}:
+		properties, _ := getSchemaMap(schema["properties"])
+		for _, name := range getSchemaRequired(schema) {
+			if _, ok := v[name]; !ok {
+				errors = append(errors, fmt.Sprintf("%s: missing required property \"%s\"", path, name))
+			}
+		}
+
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			propertySchema, ok := getSchemaMap(properties[name])
+			if ok {
+				errors = append(errors, validateJsonSchema(propertySchema, v[name], path+"."+name)...)
+			} else if additionalProperties, isBool := schema["additionalProperties"].(bool); isBool && !additionalProperties {
+				errors = append(errors, fmt.Sprintf("%s: unexpected property \"%s\"", path, name))
+			}
+		}
+	}
+
+	return errors
+}
+
+func getSchemaRequired(schema JsonSchema) []string {
+	switch v := schema["required"].(type) {
+	case []string:
+		return v
+	case []interface{}:
+		res := []string{}
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				res = append(res, s)
+			}
+		}
+		return res
+	default:
+		return nil
+	}
+}
+
+func getSchemaEnum(schema JsonSchema) []interface{} {
+	switch v := schema["enum"].(type) {
+	case []interface{}:
+		return v
+	case []string:
+		res := []interface{}{}
+		for _, item := range v {
+			res = append(res, item)
+		}
+		return res
+	default:
+		return nil
+	}
+}
@@ -17,6 +17,7 @@ package model
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
 	"math/rand"
@@ -44,6 +45,7 @@ type LocalModelProvider struct {
 	inputPricePerThousandTokens  float64
 	outputPricePerThousandTokens float64
 	currency                     string
+	responseSchema               *ResponseSchema
 }
 
 func NewLocalModelProvider(typ string, subType string, secretKey string, temperature float32, topP float32, frequencyPenalty float32, presencePenalty float32, providerUrl string, compatibleProvider string, inputPricePerThousandTokens float64, outputPricePerThousandTokens float64, Currency string) (*LocalModelProvider, error) {
@@ -76,6 +78,33 @@ func getLocalClientFromUrl(authToken string, url string) *openai.Client {
 	return c
 }
 
+func (p *LocalModelProvider) WithResponseSchema(schema *ResponseSchema) ModelProvider {
+	provider := *p
+	provider.responseSchema = schema
+	return &provider
+}
+
+// isUnsupportedResponseFormatError reports whether an OpenAI-compatible server refused the request because of response_format.
+func isUnsupportedResponseFormatError(err error) bool {
+	var apiErr *openai.APIError
+	if errors.As(err, &apiErr) {
+		if apiErr.Param != nil && strings.HasPrefix(*apiErr.Param, "response_format") {
+			return true
+		}
+		if apiErr.HTTPStatusCode != http.StatusBadRequest && apiErr.HTTPStatusCode != http.StatusUnprocessableEntity {
+			return false
+		}
+	}
+
+	var reqErr *openai.RequestError
+	if errors.As(err, &reqErr) && reqErr.HTTPStatusCode != http.StatusBadRequest && reqErr.HTTPStatusCode != http.StatusUnprocessableEntity {
+		return false
+	}
+
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "response_format") || strings.Contains(message, "json_schema")
+}
+
 func (p *LocalModelProvider) GetPricing() string {
 	return getOpenAIModelPrice()
 }
@@ -208,8 +237,19 @@ func (p *LocalModelProvider) QueryText(question string, writer io.Writer, histor
 			rawMessages = append(rawMessages, agentInfo.AgentMessages.Messages...)
 		}
 
+		capability := getVisionCapability(visionFamilyOpenAi, model)
+		err = checkQuestionImages(question, capability, model, lang)
+		if err != nil {
+			return nil, err
+		}
+
 		var messages []openai.ChatCompletionMessage
-		if IsVisionModel(p.subType) {
+		if capability.SupportsImage {
+			modelResult.ImageTokenCount, err = prepareRawMessageImages(rawMessages, capability, model, lang)
+			if err != nil {
+				return nil, err
+			}
+
 			messages, err = OpenaiRawMessagesToGptVisionMessages(rawMessages)
 			if err != nil {
 				return nil, err
@@ -224,7 +264,8 @@ func (p *LocalModelProvider) QueryText(question string, writer io.Writer, histor
 			return nil, err
 		}
 
-		modelResult.PromptTokenCount = promptTokenCount
+		// the images are not seen by the tokenizer, their estimated cost is added on top
+		modelResult.PromptTokenCount = promptTokenCount + modelResult.ImageTokenCount
 		modelResult.TotalTokenCount = modelResult.PromptTokenCount + modelResult.ResponseTokenCount
 		err = p.CalculatePrice(modelResult, lang)
 		if err != nil {
@@ -242,6 +283,17 @@ func (p *LocalModelProvider) QueryText(question string, writer io.Writer, histor
 		}
 
 		req := ChatCompletionRequest(model, messages, temperature, topP, frequencyPenalty, presencePenalty)
+		if p.responseSchema != nil {
+			// Ollama and most OpenAI-compatible servers accept response_format as well
+			req.ResponseFormat = &openai.ChatCompletionResponseFormat{
+				Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
+				JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
+					Name:        p.responseSchema.getName(),
+					Description: p.responseSchema.Description,
+					Schema:      p.responseSchema.Schema,
+				},
+			}
+		}
 		if agentInfo != nil && agentInfo.AgentClients != nil {
 			tools, err := reverseToolsToOpenAi(agentInfo.AgentClients.Tools)
 			if err != nil {
@@ -255,6 +307,11 @@ func (p *LocalModelProvider) QueryText(question string, writer io.Writer, histor
 			ctx,
 			req,
 		)
+		if err != nil && req.ResponseFormat != nil && isUnsupportedResponseFormatError(err) {
+			// the server rejects response_format, fall back to the prompt-only structured output
+			req.ResponseFormat = nil
+			respStream, err = client.CreateChatCompletionStream(ctx, req)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -15,11 +15,6 @@
 package model
 
 import (
-	"encoding/base64"
-	"fmt"
-	"io"
-	"net/http"
-	"path/filepath"
 	"regexp"
 	"strings"
 
@@ -45,28 +40,6 @@ func extractImagesURL(message string) ([]string, string) {
 	return urls, message
 }
 
-func getImageRefinedText(text string) (string, error) {
-	ext := filepath.Ext(text)
-	if ext != "" {
-		ext = ext[1:]
-	}
-
-	resp, err := http.Get(text)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
-	}
-
-	base64Data := base64.StdEncoding.EncodeToString(data)
-	res := fmt.Sprintf("data:image/%s;base64,%s", ext, base64Data)
-	return res, nil
-}
-
 func IsVisionModel(subType string) bool {
 	visionModels := []string{
 		// GPT-5.4 series (latest)
@@ -113,7 +86,7 @@ func OpenaiRawMessagesToGptVisionMessages(messages []*RawMessage) ([]openai.Chat
 			role = openai.ChatMessageRoleUser
 		}
 
-		urls, messageText := extractImagesURL(message.Text)
+		parts, messageText := getRawMessageParts(message)
 
 		item := openai.ChatCompletionMessage{
 			Role: role,
@@ -138,16 +111,17 @@ func OpenaiRawMessagesToGptVisionMessages(messages []*RawMessage) ([]openai.Chat
 			}
 		}
 
-		for _, url := range urls {
-			imageText, err := getImageRefinedText(url)
+		for _, part := range getImageParts(parts) {
+			// the parts are usually loaded by prepareRawMessageImages already
+			err := part.load(defaultImageMaxBytes)
 			if err != nil {
-				return []openai.ChatCompletionMessage{}, err
+				return nil, err
 			}
 
 			item.MultiContent = append(item.MultiContent, openai.ChatMessagePart{
 				Type: openai.ChatMessagePartTypeImageURL,
 				ImageURL: &openai.ChatMessageImageURL{
-					URL:    imageText,
+					URL:    part.getDataUrl(),
 					Detail: openai.ImageURLDetailAuto,
 				},
 			})
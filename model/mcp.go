@@ -17,9 +17,12 @@ package model
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/ThinkInAIXYZ/go-mcp/protocol"
 	"github.com/casibase/casibase/agent"
@@ -33,9 +36,34 @@ type AgentMessages struct {
 	ToolCalls any
 }
 
+const (
+	defaultAgentMaxSteps       = 10
+	defaultAgentTimeoutSeconds = 300
+	defaultToolTimeoutSeconds  = 60
+)
+
+const agentStepLimitPrompt = "The tool call limit of this conversation has been reached. Do not call any more tools, answer the question with the tool results gathered so far and mention that the answer may be incomplete."
+
+// ToolCallApprover asks a human to approve the tool calls that require approval, it blocks
+// until every call is decided or the context is done, and returns the approved call IDs.
+type ToolCallApprover func(ctx context.Context, toolCalls []openai.ToolCall) (map[string]bool, error)
+
+// AgentInfo carries the tools of an agent loop and its guardrails. Context is canceled when
+// the client disconnects, the zero limits fall back to the defaults above. Without an
+// ApproveToolCalls function the calls requiring approval are rejected. The loop is recorded
+// in Trace when set, and the tool calls are answered from Replay instead of the tools when set.
 type AgentInfo struct {
 	AgentClients  *agent.AgentClients
 	AgentMessages *AgentMessages
+
+	Context            context.Context
+	MaxSteps           int
+	TimeoutSeconds     int
+	ToolTimeoutSeconds int
+	ApproveToolCalls   ToolCallApprover
+
+	Trace  *AgentTrace
+	Replay *AgentReplay
 }
 
 type ToolCallResponse struct {
@@ -45,10 +73,13 @@ type ToolCallResponse struct {
 	ToolName string      `json:"toolName"`
 }
 
+// ToolCall is a tool call of an answer. Store is set for the calls made by a store the
+// question was delegated to, it is empty for the calls of the chat's own store.
 type ToolCall struct {
 	Name      string `json:"name"`
 	Arguments string `json:"arguments"`
 	Content   string `json:"content"`
+	Store     string `json:"store,omitempty"`
 }
 
 func reverseToolsToOpenAi(tools []*protocol.Tool) ([]openai.Tool, error) {
@@ -96,9 +127,67 @@ func handleToolCallsParameters(toolCall openai.ToolCall, toolCalls []openai.Tool
 	return toolCalls, toolCallsMap
 }
 
+func getToolCalls(agentMessages *AgentMessages) []openai.ToolCall {
+	toolCalls, ok := agentMessages.ToolCalls.([]openai.ToolCall)
+	if ok {
+		return toolCalls
+	}
+
+	toolCalls = []openai.ToolCall{}
+	responseFunctionToolCalls, ok := agentMessages.ToolCalls.([]responses.ResponseFunctionToolCall)
+	if !ok {
+		return toolCalls
+	}
+	for _, responseFunctionToolCall := range responseFunctionToolCalls {
+		toolCalls = append(toolCalls, openai.ToolCall{
+			ID:       responseFunctionToolCall.ID,
+			Type:     "function",
+			Function: openai.FunctionCall{Name: responseFunctionToolCall.Name, Arguments: responseFunctionToolCall.Arguments},
+		})
+	}
+	return toolCalls
+}
+
+func (agentInfo *AgentInfo) getContext() context.Context {
+	if agentInfo.Context == nil {
+		return context.Background()
+	}
+	return agentInfo.Context
+}
+
+func (agentInfo *AgentInfo) getMaxSteps() int {
+	if agentInfo.MaxSteps <= 0 {
+		return defaultAgentMaxSteps
+	}
+	return agentInfo.MaxSteps
+}
+
+func (agentInfo *AgentInfo) getTimeout() time.Duration {
+	if agentInfo.TimeoutSeconds <= 0 {
+		return defaultAgentTimeoutSeconds * time.Second
+	}
+	return time.Duration(agentInfo.TimeoutSeconds) * time.Second
+}
+
+func (agentInfo *AgentInfo) getToolTimeout() time.Duration {
+	if agentInfo.ToolTimeoutSeconds <= 0 {
+		return defaultToolTimeoutSeconds * time.Second
+	}
+	return time.Duration(agentInfo.ToolTimeoutSeconds) * time.Second
+}
+
+// QueryTextWithTools runs the agent loop: the tool calls requested by the model are executed
+// concurrently and their results are sent back to the model, until it answers without tools.
+// The loop stops at the step limit or the time budget with a final answer written from the tool
+// results so far, and returns an error as soon as the request context is canceled.
 func QueryTextWithTools(p ModelProvider, question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo, lang string) (*ModelResult, error) {
+	// the time spent waiting for approvals does not count against the time budget
+	requestCtx := agentInfo.getContext()
+	startTime := time.Now()
+	var waitingTime time.Duration
+
 	var messages []*RawMessage
-	modelResult, err := p.QueryText(question, writer, history, prompt, knowledgeMessages, agentInfo, lang)
+	modelResult, err := agentInfo.Trace.QueryText(p, question, writer, history, prompt, knowledgeMessages, agentInfo, lang)
 	if err != nil {
 		return nil, err
 	}
@@ -107,55 +196,63 @@ func QueryTextWithTools(p ModelProvider, question string, writer io.Writer, hist
 		return modelResult, nil
 	}
 
-	toolCalls, ok := agentInfo.AgentMessages.ToolCalls.([]openai.ToolCall)
-	if !ok {
-		responseFunctionToolCalls := agentInfo.AgentMessages.ToolCalls.([]responses.ResponseFunctionToolCall)
-		for _, responseFunctionToolCall := range responseFunctionToolCalls {
-			toolCalls = append(toolCalls, openai.ToolCall{
-				ID:       responseFunctionToolCall.ID,
-				Type:     "function",
-				Function: openai.FunctionCall{Name: responseFunctionToolCall.Name, Arguments: responseFunctionToolCall.Arguments},
-			})
+	toolCalls := getToolCalls(agentInfo.AgentMessages)
+	for step := 0; len(toolCalls) > 0; step++ {
+		if requestCtx.Err() != nil {
+			return nil, requestCtx.Err()
+		}
+		leftTime := agentInfo.getTimeout() - (time.Since(startTime) - waitingTime)
+		if step >= agentInfo.getMaxSteps() || leftTime <= 0 {
+			return queryFinalTextWithoutTools(p, question, writer, history, prompt, knowledgeMessages, messages, modelResult, agentInfo.Trace, lang)
 		}
-	}
-
-	for len(toolCalls) > 0 {
-		for _, toolCall := range toolCalls {
-			serverName, toolName := agent.GetServerNameAndToolNameFromId(toolCall.Function.Name)
 
-			messages = append(messages, &RawMessage{
-				Text:     "Call result from " + toolCall.Function.Name,
-				Author:   "AI",
-				ToolCall: toolCall,
-			})
+		waitingStartTime := time.Now()
+		var refusals map[string]string
+		refusals, err = getToolCallRefusals(requestCtx, toolCalls, agentInfo, lang)
+		if err != nil {
+			return nil, err
+		}
+		waitingTime += time.Since(waitingStartTime)
 
-			messages, err = callTools(toolCall, serverName, toolName, agentInfo.AgentClients, messages, writer, lang)
-			if err != nil {
-				return nil, err
-			}
+		ctx, cancel := context.WithTimeout(requestCtx, leftTime)
+		messages, err = callTools(ctx, toolCalls, refusals, agentInfo, messages, writer, lang)
+		cancel()
+		if err != nil {
+			return nil, err
 		}
+		if requestCtx.Err() != nil {
+			return nil, requestCtx.Err()
+		}
+
 		agentInfo.AgentMessages.Messages = messages
-		modelResult, err = p.QueryText(question, writer, history, prompt, knowledgeMessages, agentInfo, lang)
+		agentInfo.AgentMessages.ToolCalls = nil
+		var stepResult *ModelResult
+		stepResult, err = agentInfo.Trace.QueryText(p, question, writer, history, prompt, knowledgeMessages, agentInfo, lang)
 		if err != nil {
 			return nil, err
 		}
-		toolCalls, ok = agentInfo.AgentMessages.ToolCalls.([]openai.ToolCall)
-		if !ok {
-			toolCalls = []openai.ToolCall{}
-			responseFunctionToolCalls := agentInfo.AgentMessages.ToolCalls.([]responses.ResponseFunctionToolCall)
-			for _, responseFunctionToolCall := range responseFunctionToolCalls {
-				toolCalls = append(toolCalls, openai.ToolCall{
-					ID:       responseFunctionToolCall.ID,
-					Type:     "function",
-					Function: openai.FunctionCall{Name: responseFunctionToolCall.Name, Arguments: responseFunctionToolCall.Arguments},
-				})
-			}
-		}
+		AddModelResults(modelResult, stepResult)
+
+		toolCalls = getToolCalls(agentInfo.AgentMessages)
 	}
 
-	for _, mcpClient := range agentInfo.AgentClients.Clients {
-		mcpClient.Close()
+	return modelResult, nil
+}
+
+// queryFinalTextWithoutTools asks the model for a final answer based on the tool results so far,
+// without offering any tools so that the agent loop ends.
+func queryFinalTextWithoutTools(p ModelProvider, question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, messages []*RawMessage, modelResult *ModelResult, trace *AgentTrace, lang string) (*ModelResult, error) {
+	finalPrompt := fmt.Sprintf("%s\n\n%s", prompt, agentStepLimitPrompt)
+	finalAgentInfo := &AgentInfo{
+		AgentMessages: &AgentMessages{Messages: messages},
 	}
+
+	finalResult, err := trace.QueryText(p, question, writer, history, strings.TrimSpace(finalPrompt), knowledgeMessages, finalAgentInfo, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	AddModelResults(modelResult, finalResult)
 	return modelResult, nil
 }
 
@@ -167,34 +264,125 @@ func createToolMessage(toolCall openai.ToolCall, text string) *RawMessage {
 	}
 }
 
-func callTools(toolCall openai.ToolCall, serverName, toolName string, agentClients *agent.AgentClients, messages []*RawMessage, writer io.Writer, lang string) ([]*RawMessage, error) {
-	var arguments map[string]interface{}
-	ctx := context.Background()
+// getToolCallRefusals applies the tool policies to the tool calls of one model turn, asking for
+// approval where required, and returns the reasons of the refused calls by call ID.
+func getToolCallRefusals(ctx context.Context, toolCalls []openai.ToolCall, agentInfo *AgentInfo, lang string) (map[string]string, error) {
+	refusals := map[string]string{}
+	approvalToolCalls := []openai.ToolCall{}
+	for _, toolCall := range toolCalls {
+		switch agentInfo.AgentClients.GetToolPolicy(toolCall.Function.Name) {
+		case agent.ToolPolicyDeny:
+			refusals[toolCall.ID] = fmt.Sprintf(i18n.Translate(lang, "model:the tool: %s is denied by its policy"), toolCall.Function.Name)
+		case agent.ToolPolicyApproval:
+			approvalToolCalls = append(approvalToolCalls, toolCall)
+		}
+	}
+
+	if len(approvalToolCalls) == 0 {
+		return refusals, nil
+	}
+
+	approvedIds := map[string]bool{}
+	if agentInfo.ApproveToolCalls != nil {
+		var err error
+		approvedIds, err = agentInfo.ApproveToolCalls(ctx, approvalToolCalls)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, toolCall := range approvalToolCalls {
+		if !approvedIds[toolCall.ID] {
+			refusals[toolCall.ID] = fmt.Sprintf(i18n.Translate(lang, "model:the call of the tool: %s is rejected by the user"), toolCall.Function.Name)
+		}
+	}
+	return refusals, nil
+}
+
+// callTools executes the tool calls of one model turn concurrently, each with its own timeout,
+// and appends the calls and their results to the messages in the order requested by the model.
+// The refused calls are not executed, their reason is returned to the model as the tool error.
+func callTools(ctx context.Context, toolCalls []openai.ToolCall, refusals map[string]string, agentInfo *AgentInfo, messages []*RawMessage, writer io.Writer, lang string) ([]*RawMessage, error) {
+	toolMessages := make([]*RawMessage, len(toolCalls))
+	toolData := make([]*ToolCall, len(toolCalls))
+	errs := make([]error, len(toolCalls))
+
+	var wg sync.WaitGroup
+	for i, toolCall := range toolCalls {
+		wg.Add(1)
+		go func(i int, toolCall openai.ToolCall) {
+			defer wg.Done()
+
+			toolCtx, cancel := context.WithTimeout(ctx, agentInfo.getToolTimeout())
+			defer cancel()
+			toolMessages[i], toolData[i], errs[i] = callTool(toolCtx, toolCall, refusals[toolCall.ID], agentInfo, lang)
+		}(i, toolCall)
+	}
+	wg.Wait()
 
-	if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &arguments); err != nil {
-		return nil, fmt.Errorf(i18n.Translate(lang, "model:failed to parse tool arguments: %v"), err)
+	for i, toolCall := range toolCalls {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+
+		messages = append(messages, &RawMessage{
+			Text:     "Call result from " + toolCall.Function.Name,
+			Author:   "AI",
+			ToolCall: toolCall,
+		})
+
+		toolJSON, err := json.Marshal(toolData[i])
+		if err == nil {
+			_ = flushDataThink(string(toolJSON), "tool", writer, lang)
+		}
+
+		messages = append(messages, toolMessages[i])
 	}
+	return messages, nil
+}
+
+func callTool(ctx context.Context, toolCall openai.ToolCall, refusal string, agentInfo *AgentInfo, lang string) (*RawMessage, *ToolCall, error) {
+	startTime := time.Now()
+	agentClients := agentInfo.AgentClients
+
+	serverName, toolName := agent.GetServerNameAndToolNameFromId(toolCall.Function.Name)
 
 	var result *protocol.CallToolResult
 	var err error
+	var replayResult, replayError string
+	isReplayed := false
+
+	// the arguments the model got wrong are returned to it as the tool error, like the other failures
+	var arguments map[string]interface{}
+	argumentsErr := json.Unmarshal([]byte(toolCall.Function.Arguments), &arguments)
 
-	if serverName == "" {
+	if refusal != "" {
+		err = errors.New(refusal)
+	} else if argumentsErr != nil {
+		err = fmt.Errorf(i18n.Translate(lang, "model:failed to parse tool arguments: %v"), argumentsErr)
+	} else if agentInfo.Replay != nil {
+		replayResult, replayError, err = agentInfo.Replay.getToolResult(toolCall, lang)
+		isReplayed = err == nil
+	} else if serverName == "" {
 		// builtin tools
 		if agentClients.BuiltinToolReg == nil {
-			return messages, nil
+			err = fmt.Errorf(i18n.Translate(lang, "model:the tool: %s is not available"), toolCall.Function.Name)
+		} else {
+			result, err = agentClients.BuiltinToolReg.ExecuteTool(ctx, toolName, arguments)
 		}
-		result, err = agentClients.BuiltinToolReg.ExecuteTool(ctx, toolName, arguments)
+	} else if _, ok := agentClients.Clients[serverName]; !ok {
+		// MCP tools of a server that is not connected
+		err = fmt.Errorf(i18n.Translate(lang, "model:the tool: %s is not available"), toolCall.Function.Name)
 	} else {
 		// MCP tools
-		mcpClient, ok := agentClients.Clients[serverName]
-		if !ok {
-			return messages, nil
-		}
-		req := &protocol.CallToolRequest{
-			Name:      toolName,
-			Arguments: arguments,
-		}
-		result, err = mcpClient.CallTool(ctx, req)
+		result, err = agentClients.CallTool(ctx, serverName, toolName, arguments)
+	}
+
+	if err == nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = fmt.Errorf(i18n.Translate(lang, "model:the tool: %s timed out"), toolCall.Function.Name)
 	}
 
 	response := &ToolCallResponse{
@@ -204,6 +392,10 @@ func callTools(toolCall openai.ToolCall, serverName, toolName string, agentClien
 	if err != nil {
 		response.Success = false
 		response.Error = err.Error()
+	} else if isReplayed {
+		response.Success = replayError == ""
+		response.Data = replayResult
+		response.Error = replayError
 	} else if result.IsError {
 		response.Success = false
 		contentBytes, err := json.Marshal(result.Content)
@@ -224,29 +416,24 @@ func callTools(toolCall openai.ToolCall, serverName, toolName string, agentClien
 
 	responseJson, err := json.Marshal(response)
 	if err != nil {
-		return nil, fmt.Errorf(i18n.Translate(lang, "model:failed to marshal tool response: %v"), err)
+		return nil, nil, fmt.Errorf(i18n.Translate(lang, "model:failed to marshal tool response: %v"), err)
 	}
 
 	var contentStr string
 	if !response.Success {
 		contentStr = response.Error
+		agentInfo.Trace.addToolStep(toolCall, startTime, "", response.Error)
 	} else {
 		contentStr = response.Data.(string)
+		agentInfo.Trace.addToolStep(toolCall, startTime, contentStr, "")
 	}
 
-	toolData := ToolCall{
+	toolData := &ToolCall{
 		Name:      toolCall.Function.Name,
 		Arguments: toolCall.Function.Arguments,
 		Content:   contentStr,
 	}
-	toolJSON, err := json.Marshal(toolData)
-	if err == nil {
-		if err := flushDataThink(string(toolJSON), "tool", writer, lang); err == nil {
-		}
-	}
-
-	messages = append(messages, createToolMessage(toolCall, string(responseJson)))
-	return messages, nil
+	return createToolMessage(toolCall, string(responseJson)), toolData, nil
 }
 
 func GetToolCallsFromWriter(toolMessage string) []ToolCall {
@@ -0,0 +1,255 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build skipCi
+// +build skipCi
+
+package model
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/casibase/casibase/agent"
+	"github.com/casibase/casibase/agent/builtin_tool"
+	"github.com/sashabaranov/go-openai"
+)
+
+type blockingTool struct{}
+
+func (t *blockingTool) GetName() string             { return "block" }
+func (t *blockingTool) GetDescription() string      { return "Blocks until the context is done" }
+func (t *blockingTool) GetInputSchema() interface{} { return map[string]interface{}{"type": "object"} }
+
+func (t *blockingTool) Execute(ctx context.Context, arguments map[string]interface{}) (*protocol.CallToolResult, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// fakeToolProvider requests the tool on every turn where tools are offered.
+type fakeToolProvider struct {
+	toolName     string
+	calls        int
+	finalPrompts []string
+}
+
+func (p *fakeToolProvider) GetPricing() string {
+	return ""
+}
+
+func (p *fakeToolProvider) QueryText(question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo, lang string) (*ModelResult, error) {
+	p.calls++
+	if agentInfo.AgentClients == nil {
+		p.finalPrompts = append(p.finalPrompts, prompt)
+		return &ModelResult{TotalTokenCount: 1}, nil
+	}
+
+	agentInfo.AgentMessages.ToolCalls = []openai.ToolCall{
+		{ID: fmt.Sprintf("call_%d", p.calls), Type: "function", Function: openai.FunctionCall{Name: p.toolName, Arguments: "{}"}},
+		{ID: fmt.Sprintf("call_%d_2", p.calls), Type: "function", Function: openai.FunctionCall{Name: p.toolName, Arguments: "{}"}},
+	}
+	return &ModelResult{TotalTokenCount: 1}, nil
+}
+
+func newTestAgentInfo() *AgentInfo {
+	registry := builtin_tool.NewToolRegistry()
+	registry.RegisterTool(&blockingTool{})
+	return &AgentInfo{
+		AgentClients:  &agent.AgentClients{BuiltinToolReg: registry},
+		AgentMessages: &AgentMessages{},
+	}
+}
+
+func TestQueryTextWithToolsStepLimit(t *testing.T) {
+	p := &fakeToolProvider{toolName: "current_time"}
+	agentInfo := newTestAgentInfo()
+	agentInfo.MaxSteps = 2
+
+	modelResult, err := QueryTextWithTools(p, "What time is it?", io.Discard, nil, "prompt", nil, agentInfo, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the first turn, two tool steps and the final answer without tools
+	if p.calls != 4 {
+		t.Errorf("expected 4 model calls, got %d", p.calls)
+	}
+	if modelResult.TotalTokenCount != 4 {
+		t.Errorf("expected the token counts of all turns to be added, got %d", modelResult.TotalTokenCount)
+	}
+	if len(p.finalPrompts) != 1 || !strings.Contains(p.finalPrompts[0], agentStepLimitPrompt) {
+		t.Errorf("expected one final answer with the step limit prompt, got %v", p.finalPrompts)
+	}
+	// each step adds the tool call and the tool result for both calls
+	if len(agentInfo.AgentMessages.Messages) != 8 {
+		t.Errorf("expected 8 agent messages, got %d", len(agentInfo.AgentMessages.Messages))
+	}
+}
+
+func TestQueryTextWithToolsToolTimeout(t *testing.T) {
+	p := &fakeToolProvider{toolName: "block"}
+	agentInfo := newTestAgentInfo()
+	agentInfo.MaxSteps = 1
+	agentInfo.ToolTimeoutSeconds = 1
+
+	_, err := QueryTextWithTools(p, "Block", io.Discard, nil, "prompt", nil, agentInfo, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages := agentInfo.AgentMessages.Messages
+	if len(messages) != 4 {
+		t.Fatalf("expected 4 agent messages, got %d", len(messages))
+	}
+	for _, message := range []*RawMessage{messages[1], messages[3]} {
+		if message.Author != "Tool" || !strings.Contains(message.Text, "timed out") {
+			t.Errorf("expected a timed out tool result, got %s: %s", message.Author, message.Text)
+		}
+	}
+}
+
+func TestQueryTextWithToolsCanceled(t *testing.T) {
+	p := &fakeToolProvider{toolName: "current_time"}
+	agentInfo := newTestAgentInfo()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	agentInfo.Context = ctx
+
+	_, err := QueryTextWithTools(p, "What time is it?", io.Discard, nil, "prompt", nil, agentInfo, "en")
+	if err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if p.calls != 1 {
+		t.Errorf("expected the loop to stop after the first turn, got %d model calls", p.calls)
+	}
+}
+
+func TestQueryTextWithToolsApproval(t *testing.T) {
+	p := &fakeToolProvider{toolName: "current_time"}
+	agentInfo := newTestAgentInfo()
+	agentInfo.MaxSteps = 1
+	agentInfo.AgentClients.ToolPolicies = map[string]string{"current_time": agent.ToolPolicyApproval}
+
+	var approvalCalls [][]openai.ToolCall
+	agentInfo.ApproveToolCalls = func(ctx context.Context, toolCalls []openai.ToolCall) (map[string]bool, error) {
+		approvalCalls = append(approvalCalls, toolCalls)
+		return map[string]bool{toolCalls[0].ID: true}, nil
+	}
+
+	_, err := QueryTextWithTools(p, "What time is it?", io.Discard, nil, "prompt", nil, agentInfo, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(approvalCalls) != 1 || len(approvalCalls[0]) != 2 {
+		t.Fatalf("expected one approval request for both calls, got %v", approvalCalls)
+	}
+
+	messages := agentInfo.AgentMessages.Messages
+	if len(messages) != 4 {
+		t.Fatalf("expected 4 agent messages, got %d", len(messages))
+	}
+	if !strings.Contains(messages[1].Text, `"success":true`) {
+		t.Errorf("expected the approved call to run, got %s", messages[1].Text)
+	}
+	if !strings.Contains(messages[3].Text, "rejected") {
+		t.Errorf("expected the other call to be rejected, got %s", messages[3].Text)
+	}
+}
+
+func TestQueryTextWithToolsDenied(t *testing.T) {
+	p := &fakeToolProvider{toolName: "current_time"}
+	agentInfo := newTestAgentInfo()
+	agentInfo.MaxSteps = 1
+	agentInfo.AgentClients.ToolPolicies = map[string]string{"current_time": agent.ToolPolicyDeny}
+
+	_, err := QueryTextWithTools(p, "What time is it?", io.Discard, nil, "prompt", nil, agentInfo, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, message := range agentInfo.AgentMessages.Messages {
+		if message.Author == "Tool" && !strings.Contains(message.Text, "denied") {
+			t.Errorf("expected the call to be denied, got %s", message.Text)
+		}
+	}
+}
+
+func TestQueryTextWithToolsTraceAndReplay(t *testing.T) {
+	p := &fakeToolProvider{toolName: "current_time"}
+	agentInfo := newTestAgentInfo()
+	agentInfo.MaxSteps = 1
+	agentInfo.Trace = NewAgentTrace()
+
+	_, err := QueryTextWithTools(p, "What time is it?", io.Discard, nil, "prompt", nil, agentInfo, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the first turn, its two tool calls, the second turn and the final answer
+	steps := agentInfo.Trace.GetSteps()
+	if len(steps) != 5 {
+		t.Fatalf("expected 5 trace steps, got %d", len(steps))
+	}
+	if steps[0].Type != AgentTraceStepModel || len(steps[0].ToolCallIds) != 2 || steps[3].Type != AgentTraceStepModel || steps[4].Turn != 2 {
+		t.Errorf("expected the model turns around the tool calls, got %v, %v", steps[0], steps[4])
+	}
+	for _, step := range steps[1:3] {
+		if step.Type != AgentTraceStepTool || step.Turn != 0 || step.Result == "" || step.Error != "" {
+			t.Errorf("expected a successful tool call of the first turn, got %v", step)
+		}
+	}
+
+	replayInfo := &AgentInfo{
+		AgentClients:  &agent.AgentClients{},
+		AgentMessages: &AgentMessages{},
+		MaxSteps:      1,
+		Trace:         NewAgentTrace(),
+		Replay:        NewAgentReplay(steps),
+	}
+	_, err = QueryTextWithTools(&fakeToolProvider{toolName: "current_time"}, "What time is it?", io.Discard, nil, "prompt", nil, replayInfo, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replaySteps := replayInfo.Trace.GetSteps()
+	if len(replaySteps) != 5 {
+		t.Fatalf("expected 5 replay steps, got %d", len(replaySteps))
+	}
+	results := map[string]bool{steps[1].Result: true, steps[2].Result: true}
+	for _, step := range replaySteps[1:3] {
+		if !results[step.Result] {
+			t.Errorf("expected the recorded tool result, got %v", step)
+		}
+	}
+
+	replayInfo = &AgentInfo{
+		AgentClients:  &agent.AgentClients{},
+		AgentMessages: &AgentMessages{},
+		MaxSteps:      1,
+		Trace:         NewAgentTrace(),
+		Replay:        NewAgentReplay(nil),
+	}
+	_, err = QueryTextWithTools(&fakeToolProvider{toolName: "current_time"}, "What time is it?", io.Discard, nil, "prompt", nil, replayInfo, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if step := replayInfo.Trace.GetSteps()[1]; !strings.Contains(step.Error, "not recorded") {
+		t.Errorf("expected an error for the call not recorded, got %v", step)
+	}
+}
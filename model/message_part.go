@@ -0,0 +1,356 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/casibase/casibase/i18n"
+	"github.com/casibase/casibase/proxy"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	MessagePartTypeImage = "image"
+	MessagePartTypeFile  = "file"
+)
+
+const (
+	visionFamilyOpenAi = "OpenAI"
+	visionFamilyClaude = "Claude"
+	visionFamilyGemini = "Gemini"
+	visionFamilyQwen   = "Qwen"
+)
+
+const (
+	defaultImageMaxBytes = 20 * 1024 * 1024
+	imageDownloadTimeout = 60 * time.Second
+	// a decoded image takes 4 bytes per pixel, so this caps the memory of a small but huge image to 200 MB
+	maxImagePixels = 50 * 1000 * 1000
+)
+
+// MessagePart is a non-text part of a RawMessage, like an image attached to a chat message.
+// Either Url or Data is set, Data is filled from Url on demand.
+type MessagePart struct {
+	Type     string
+	Name     string
+	MimeType string
+	Url      string
+	Data     []byte
+	Width    int
+	Height   int
+}
+
+// VisionCapability describes how a model accepts image input. Images larger than
+// MaxDimension on their longest side are downscaled, and images still larger than
+// MaxBytes afterwards are rejected, the downloaded ones being rejected beyond MaxBytes already.
+type VisionCapability struct {
+	Family        string
+	SupportsImage bool
+	MaxDimension  int
+	MaxBytes      int
+}
+
+var dataUrlRegex = regexp.MustCompile(`data:(image/[a-zA-Z0-9.+\-]+);base64,([a-zA-Z0-9+/=]+)`)
+
+// isOpenVisionModel matches the open-weight and compatible vision models served by Ollama or OpenAI-compatible endpoints.
+func isOpenVisionModel(subType string) bool {
+	subType = strings.ToLower(subType)
+	patterns := []string{"vision", "-vl", "llava", "minicpm-v", "moondream", "gemma3", "pixtral", "qvq", "omni"}
+	for _, pattern := range patterns {
+		if strings.Contains(subType, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+func getVisionCapability(family string, subType string) *VisionCapability {
+	res := &VisionCapability{Family: family, MaxDimension: 2048, MaxBytes: defaultImageMaxBytes}
+	switch family {
+	case visionFamilyOpenAi:
+		res.SupportsImage = IsVisionModel(subType) || isOpenVisionModel(subType)
+	case visionFamilyClaude:
+		// all Claude 3 and later models accept images, 1568px is the largest size not resized by the API
+		res.SupportsImage = strings.Contains(subType, "claude-3") || strings.Contains(subType, "-4")
+		res.MaxDimension = 1568
+		res.MaxBytes = 5 * 1024 * 1024
+	case visionFamilyGemini:
+		res.SupportsImage = strings.HasPrefix(subType, "gemini") && !strings.Contains(subType, "embedding") && !strings.Contains(subType, "tts")
+		res.MaxDimension = 3072
+	case visionFamilyQwen:
+		res.SupportsImage = isOpenVisionModel(subType)
+		res.MaxBytes = 10 * 1024 * 1024
+	}
+	return res
+}
+
+// GetMessageParts extracts the images embedded in a message text, as image URLs,
+// <img> tags or base64 data URLs, and returns them with the remaining text.
+func GetMessageParts(text string) ([]*MessagePart, string) {
+	parts := []*MessagePart{}
+	for _, match := range dataUrlRegex.FindAllStringSubmatch(text, -1) {
+		data, err := base64.StdEncoding.DecodeString(match[2])
+		if err != nil {
+			continue
+		}
+		parts = append(parts, &MessagePart{Type: MessagePartTypeImage, MimeType: match[1], Data: data})
+	}
+	text = dataUrlRegex.ReplaceAllString(text, "")
+
+	urls, text := extractImagesURL(text)
+	for _, url := range urls {
+		parts = append(parts, &MessagePart{Type: MessagePartTypeImage, Url: url})
+	}
+
+	return parts, strings.TrimSpace(text)
+}
+
+// getRawMessageParts returns the typed parts of a message, falling back to the images embedded in its text.
+func getRawMessageParts(message *RawMessage) ([]*MessagePart, string) {
+	if len(message.Parts) > 0 {
+		return message.Parts, message.Text
+	}
+	return GetMessageParts(message.Text)
+}
+
+func getImageParts(parts []*MessagePart) []*MessagePart {
+	res := []*MessagePart{}
+	for _, part := range parts {
+		if part.Type == MessagePartTypeImage {
+			res = append(res, part)
+		}
+	}
+	return res
+}
+
+// load downloads the image of Url with a client refusing non-public addresses, reading no
+// more than maxBytes of it.
+func (part *MessagePart) load(maxBytes int) error {
+	if part.Data == nil && part.Url != "" {
+		resp, err := proxy.GetSafeHttpClient(part.Url, imageDownloadTimeout).Get(part.Url)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("failed to download image: %s, status: %s", part.Url, resp.Status)
+		}
+
+		data, err := io.ReadAll(io.LimitReader(resp.Body, int64(maxBytes)+1))
+		if err != nil {
+			return err
+		}
+		if len(data) > maxBytes {
+			return fmt.Errorf("failed to download image: %s, the image is larger than %d bytes", part.Url, maxBytes)
+		}
+
+		part.Data = data
+		if part.MimeType == "" {
+			part.MimeType = strings.Split(resp.Header.Get("Content-Type"), ";")[0]
+		}
+	}
+
+	if part.MimeType == "" || !strings.HasPrefix(part.MimeType, "image/") {
+		part.MimeType = http.DetectContentType(part.Data)
+	}
+
+	if part.Width == 0 || part.Height == 0 {
+		config, _, err := image.DecodeConfig(bytes.NewReader(part.Data))
+		if err != nil {
+			return err
+		}
+		part.Width = config.Width
+		part.Height = config.Height
+	}
+	return nil
+}
+
+// downscale resizes the image so that its longest side is maxDimension, keeping PNG
+// images as PNG and encoding all the other formats as JPEG.
+func (part *MessagePart) downscale(maxDimension int) error {
+	// check the size in the header before decoding, a few KB of data can declare a gigapixel image
+	config, _, err := image.DecodeConfig(bytes.NewReader(part.Data))
+	if err != nil {
+		return err
+	}
+	if int64(config.Width)*int64(config.Height) > maxImagePixels {
+		return fmt.Errorf("the image size: %dx%d exceeds the limit of %d pixels", config.Width, config.Height, maxImagePixels)
+	}
+	part.Width = config.Width
+	part.Height = config.Height
+
+	src, _, err := image.Decode(bytes.NewReader(part.Data))
+	if err != nil {
+		return err
+	}
+
+	scale := float64(maxDimension) / float64(max(part.Width, part.Height))
+	width := max(1, int(math.Round(float64(part.Width)*scale)))
+	height := max(1, int(math.Round(float64(part.Height)*scale)))
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Over, nil)
+
+	var buf bytes.Buffer
+	if part.MimeType == "image/png" {
+		err = png.Encode(&buf, dst)
+	} else {
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 90})
+		part.MimeType = "image/jpeg"
+	}
+	if err != nil {
+		return err
+	}
+
+	part.Data = buf.Bytes()
+	part.Url = ""
+	part.Width = width
+	part.Height = height
+	return nil
+}
+
+func (part *MessagePart) getBase64() string {
+	return base64.StdEncoding.EncodeToString(part.Data)
+}
+
+func (part *MessagePart) getDataUrl() string {
+	return fmt.Sprintf("data:%s;base64,%s", part.MimeType, part.getBase64())
+}
+
+// getImageTokenCount estimates the prompt tokens of an image following each vendor's documented formula.
+func getImageTokenCount(family string, width int, height int) int {
+	w, h := float64(width), float64(height)
+	switch family {
+	case visionFamilyClaude:
+		return int(math.Ceil(w * h / 750))
+	case visionFamilyGemini:
+		if w <= 384 && h <= 384 {
+			return 258
+		}
+		return int(math.Ceil(w/768)*math.Ceil(h/768)) * 258
+	case visionFamilyQwen:
+		return int(math.Ceil(w/28)*math.Ceil(h/28)) + 2
+	default:
+		// fit in 2048x2048, then scale the shortest side down to 768, and count 512px tiles
+		if scale := 2048 / math.Max(w, h); scale < 1 {
+			w, h = w*scale, h*scale
+		}
+		if scale := 768 / math.Min(w, h); scale < 1 {
+			w, h = w*scale, h*scale
+		}
+		return 85 + 170*int(math.Ceil(w/512)*math.Ceil(h/512))
+	}
+}
+
+// prepareImageParts loads the images, rejects them when the model has no vision support,
+// downscales the oversized ones and returns their estimated prompt token count.
+func prepareImageParts(parts []*MessagePart, capability *VisionCapability, subType string, lang string) (int, error) {
+	imageTokenCount := 0
+	for _, part := range getImageParts(parts) {
+		if !capability.SupportsImage {
+			return 0, fmt.Errorf(i18n.Translate(lang, "model:the model: %s does not support image input"), subType)
+		}
+
+		err := part.load(capability.MaxBytes)
+		if err != nil {
+			return 0, err
+		}
+
+		if max(part.Width, part.Height) > capability.MaxDimension {
+			err = part.downscale(capability.MaxDimension)
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		if len(part.Data) > capability.MaxBytes {
+			return 0, fmt.Errorf(i18n.Translate(lang, "model:the image size: %d bytes exceeds the model's limit: %d bytes"), len(part.Data), capability.MaxBytes)
+		}
+
+		imageTokenCount += getImageTokenCount(capability.Family, part.Width, part.Height)
+	}
+	return imageTokenCount, nil
+}
+
+// prepareRawMessageImages does prepareImageParts on every message and stores the parts
+// on the messages, so that the provider adapters can encode them in their native format.
+// Models without vision support keep the messages as they are, see checkQuestionImages.
+func prepareRawMessageImages(messages []*RawMessage, capability *VisionCapability, subType string, lang string) (int, error) {
+	if !capability.SupportsImage {
+		return 0, nil
+	}
+
+	imageTokenCount := 0
+	for _, message := range messages {
+		parts, text := getRawMessageParts(message)
+		if len(getImageParts(parts)) == 0 {
+			continue
+		}
+
+		tokenCount, err := prepareImageParts(parts, capability, subType, lang)
+		if err != nil {
+			return 0, err
+		}
+		imageTokenCount += tokenCount
+		message.Parts = parts
+		message.Text = text
+	}
+	return imageTokenCount, nil
+}
+
+// checkQuestionImages rejects a question with images for a model without vision support.
+// Images in the history are not checked so that a chat can switch to a text-only model.
+func checkQuestionImages(question string, capability *VisionCapability, subType string, lang string) error {
+	if capability.SupportsImage {
+		return nil
+	}
+
+	parts, _ := GetMessageParts(question)
+	if len(getImageParts(parts)) > 0 {
+		return fmt.Errorf(i18n.Translate(lang, "model:the model: %s does not support image input"), subType)
+	}
+	return nil
+}
+
+// writeImagePartToTempFile is used by the SDKs that only upload local files or public URLs.
+func writeImagePartToTempFile(part *MessagePart) (string, error) {
+	ext := strings.TrimPrefix(part.MimeType, "image/")
+	file, err := os.CreateTemp("", fmt.Sprintf("casibase-image-*.%s", ext))
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	_, err = file.Write(part.Data)
+	if err != nil {
+		return "", err
+	}
+	return file.Name(), nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build skipCi
+// +build skipCi
+
+package model
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"testing"
+)
+
+func TestPrepareImageParts(t *testing.T) {
+	var buf bytes.Buffer
+	err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4000, 1000)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text := `What is in this picture? <img src="data:image/png;base64,` + (&MessagePart{Data: buf.Bytes()}).getBase64() + `">`
+	parts, text := GetMessageParts(text)
+	if len(parts) != 1 || text != "What is in this picture?" {
+		t.Fatalf("unexpected parts: %d, text: %q", len(parts), text)
+	}
+
+	_, err = prepareImageParts(parts, getVisionCapability(visionFamilyOpenAi, "gpt-3.5-turbo"), "gpt-3.5-turbo", "en")
+	if err == nil {
+		t.Errorf("expected an error for a model without vision support")
+	}
+
+	imageTokenCount, err := prepareImageParts(parts, getVisionCapability(visionFamilyClaude, "claude-sonnet-4-0"), "claude-sonnet-4-0", "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parts[0].Width != 1568 || parts[0].Height != 392 || parts[0].MimeType != "image/png" {
+		t.Errorf("unexpected downscaled image: %dx%d %s", parts[0].Width, parts[0].Height, parts[0].MimeType)
+	}
+	if imageTokenCount != 820 {
+		t.Errorf("imageTokenCount = %d, want 820", imageTokenCount)
+	}
+}
+
+func TestGetImageTokenCount(t *testing.T) {
+	tests := []struct {
+		family string
+		width  int
+		height int
+		want   int
+	}{
+		{visionFamilyOpenAi, 1024, 1024, 765},
+		{visionFamilyOpenAi, 2048, 4096, 1105},
+		{visionFamilyGemini, 300, 300, 258},
+		{visionFamilyClaude, 1000, 1000, 1334},
+	}
+
+	for _, test := range tests {
+		if got := getImageTokenCount(test.family, test.width, test.height); got != test.want {
+			t.Errorf("getImageTokenCount(%s, %d, %d) = %d, want %d", test.family, test.width, test.height, got, test.want)
+		}
+	}
+}
+
+func TestDownscaleRejectsHugeImage(t *testing.T) {
+	// only the PNG header of a 100000x100000 image, image.Decode would allocate 40 GB for it
+	ihdr := []byte("IHDR\x00\x01\x86\xa0\x00\x01\x86\xa0\x08\x06\x00\x00\x00")
+	var buf bytes.Buffer
+	buf.WriteString("\x89PNG\r\n\x1a\n")
+	_ = binary.Write(&buf, binary.BigEndian, uint32(len(ihdr)-4))
+	buf.Write(ihdr)
+	_ = binary.Write(&buf, binary.BigEndian, crc32.ChecksumIEEE(ihdr))
+
+	part := &MessagePart{Type: MessagePartTypeImage, Data: buf.Bytes(), MimeType: "image/png"}
+	err := part.load(defaultImageMaxBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if part.Width != 100000 || part.Height != 100000 {
+		t.Fatalf("unexpected image size: %dx%d", part.Width, part.Height)
+	}
+
+	err = part.downscale(2048)
+	if err == nil {
+		t.Errorf("expected an error for an image of %dx%d pixels", part.Width, part.Height)
+	}
+}
@@ -40,6 +40,7 @@ type OpenAiModelProvider struct {
 	topP             float32
 	frequencyPenalty float32
 	presencePenalty  float32
+	responseSchema   *ResponseSchema
 }
 
 func NewOpenAiModelProvider(subType string, secretKey string, temperature float32, topP float32, frequencyPenalty float32, presencePenalty float32) (*OpenAiModelProvider, error) {
@@ -262,6 +263,12 @@ Image models:
 `
 }
 
+func (p *OpenAiModelProvider) WithResponseSchema(schema *ResponseSchema) ModelProvider {
+	provider := *p
+	provider.responseSchema = schema
+	return &provider
+}
+
 func (p *OpenAiModelProvider) GetPricing() string {
 	return getOpenAIModelPrice()
 }
@@ -306,11 +313,19 @@ func (p *OpenAiModelProvider) QueryText(question string, writer io.Writer, histo
 		var messages responses.ResponseInputParam
 		var toolCalls []responses.ResponseFunctionToolCall
 
-		if IsVisionModel(model) {
-			messages, err = openaiRawMessagesToGptVisionMessages(rawMessages)
+		capability := getVisionCapability(visionFamilyOpenAi, model)
+		err = checkQuestionImages(question, capability, model, lang)
+		if err != nil {
+			return nil, err
+		}
+
+		if capability.SupportsImage {
+			modelResult.ImageTokenCount, err = prepareRawMessageImages(rawMessages, capability, model, lang)
 			if err != nil {
 				return nil, err
 			}
+
+			messages = openaiRawMessagesToGptVisionMessages(rawMessages)
 		} else {
 			messages = openaiRawMessagesToMessages(rawMessages)
 		}
@@ -343,6 +358,18 @@ func (p *OpenAiModelProvider) QueryText(question string, writer io.Writer, histo
 			TopP:         param.NewOpt[float64](float64(topP)),
 			Reasoning:    shared.ReasoningParam{Summary: "auto"},
 		}
+		if p.responseSchema != nil {
+			jsonSchemaConfig := &responses.ResponseFormatTextJSONSchemaConfigParam{
+				Name:   p.responseSchema.getName(),
+				Schema: p.responseSchema.Schema,
+			}
+			if p.responseSchema.Description != "" {
+				jsonSchemaConfig.Description = param.NewOpt[string](p.responseSchema.Description)
+			}
+			req.Text = responses.ResponseTextConfigParam{
+				Format: responses.ResponseFormatTextConfigUnionParam{OfJSONSchema: jsonSchemaConfig},
+			}
+		}
 		if agentInfo != nil && agentInfo.AgentClients != nil {
 			agentTools, err := reverseMcpToolsToOpenAi(agentInfo.AgentClients.Tools)
 			if err != nil {
@@ -597,7 +624,7 @@ func openaiRawMessagesToMessages(messages []*RawMessage) responses.ResponseInput
 	return res
 }
 
-func openaiRawMessagesToGptVisionMessages(messages []*RawMessage) (responses.ResponseInputParam, error) {
+func openaiRawMessagesToGptVisionMessages(messages []*RawMessage) responses.ResponseInputParam {
 	var res responses.ResponseInputParam
 	for _, message := range messages {
 		var role responses.EasyInputMessageRole
@@ -642,7 +669,7 @@ func openaiRawMessagesToGptVisionMessages(messages []*RawMessage) (responses.Res
 			role = responses.EasyInputMessageRoleUser
 		}
 
-		urls, messageText := extractImagesURL(message.Text)
+		parts, messageText := getRawMessageParts(message)
 
 		var itemContentList responses.ResponseInputMessageContentListParam
 		if len(messageText) > 0 {
@@ -655,14 +682,10 @@ func openaiRawMessagesToGptVisionMessages(messages []*RawMessage) (responses.Res
 				},
 			})
 		}
-		for _, url := range urls {
-			imageText, err := getImageRefinedText(url)
-			if err != nil {
-				return res, err
-			}
+		for _, part := range getImageParts(parts) {
 			itemContentList = append(itemContentList, responses.ResponseInputContentUnionParam{
 				OfInputImage: &responses.ResponseInputImageParam{
-					ImageURL: param.NewOpt[string](imageText),
+					ImageURL: param.NewOpt[string](part.getDataUrl()),
 				},
 			})
 		}
@@ -679,7 +702,7 @@ func openaiRawMessagesToGptVisionMessages(messages []*RawMessage) (responses.Res
 		}
 		res = append(res, item)
 	}
-	return res, nil
+	return res
 }
 
 func openaiNumTokensFromMessages(messages responses.ResponseInputParam, model string) (int, error) {
@@ -27,6 +27,7 @@ type ModelResult struct {
 	ResponseTokenCount int
 	TotalTokenCount    int
 	ImageCount         int
+	ImageTokenCount    int
 	TotalPrice         float64
 	Currency           string
 }
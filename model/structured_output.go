@@ -0,0 +1,188 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/casibase/casibase/i18n"
+)
+
+const structuredOutputMaxRepairs = 2
+
+const structuredOutputInstruction = `
+
+Return only a JSON value that conforms to the following JSON schema, without markdown code fences or any other text:
+%s`
+
+const structuredOutputRepairQuestion = `The following output was expected to be a JSON value conforming to the JSON schema below, but it is invalid.
+
+JSON schema:
+%s
+
+Output:
+%s
+
+Problems:
+%s
+
+Fix the output and return only the corrected JSON value, without markdown code fences or any other text.`
+
+type ResponseSchema struct {
+	Name        string
+	Description string
+	Schema      JsonSchema
+}
+
+// StructuredOutputProvider is implemented by model providers that can constrain their
+// output to a JSON schema natively (response_format, responseSchema, forced tool use...).
+// WithResponseSchema returns a copy of the provider so that the schema never leaks into
+// the other queries of the provider.
+type StructuredOutputProvider interface {
+	WithResponseSchema(schema *ResponseSchema) ModelProvider
+}
+
+// AddModelResults adds the token counts and the price of other to modelResult.
+func AddModelResults(modelResult *ModelResult, other *ModelResult) {
+	if other == nil {
+		return
+	}
+
+	modelResult.PromptTokenCount += other.PromptTokenCount
+	modelResult.ResponseTokenCount += other.ResponseTokenCount
+	modelResult.TotalTokenCount += other.TotalTokenCount
+	modelResult.ImageCount += other.ImageCount
+	modelResult.ImageTokenCount += other.ImageTokenCount
+	modelResult.TotalPrice = AddPrices(modelResult.TotalPrice, other.TotalPrice)
+	if modelResult.Currency == "" {
+		modelResult.Currency = other.Currency
+	}
+}
+
+// extractJsonText removes markdown code fences and any text around the outermost JSON value.
+func extractJsonText(text string) string {
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "```") {
+		text = strings.TrimPrefix(text, "```json")
+		text = strings.TrimPrefix(text, "```")
+		text = strings.TrimSuffix(strings.TrimSpace(text), "```")
+		text = strings.TrimSpace(text)
+	}
+
+	start := strings.IndexAny(text, "{[")
+	if start < 0 {
+		return text
+	}
+	closing := "}"
+	if text[start] == '[' {
+		closing = "]"
+	}
+	end := strings.LastIndex(text, closing)
+	if end < start {
+		return text[start:]
+	}
+	return text[start : end+1]
+}
+
+func validateStructuredOutput(schema *ResponseSchema, text string) (string, []string) {
+	jsonText := extractJsonText(text)
+
+	var value interface{}
+	err := json.Unmarshal([]byte(jsonText), &value)
+	if err != nil {
+		return jsonText, []string{fmt.Sprintf("the output is not valid JSON: %s", err.Error())}
+	}
+
+	return jsonText, ValidateJsonSchema(schema.Schema, value)
+}
+
+func queryStructuredTextOnce(p ModelProvider, question string, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, lang string) (string, *ModelResult, error) {
+	var writer AnswerWriter
+	modelResult, err := p.QueryText(question, &writer, history, prompt, knowledgeMessages, nil, lang)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return writer.String(), modelResult, nil
+}
+
+// QueryStructuredText queries the model for a JSON value conforming to the schema. Providers
+// implementing StructuredOutputProvider use their native JSON mode, the others are instructed
+// through the question. The output is validated in both cases, and invalid output is sent back
+// to the model for repair up to structuredOutputMaxRepairs times. The returned text is the
+// validated JSON and the model result accumulates the usage of all attempts.
+func QueryStructuredText(p ModelProvider, schema *ResponseSchema, question string, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, lang string) (string, *ModelResult, error) {
+	schemaBytes, err := json.MarshalIndent(schema.Schema, "", "  ")
+	if err != nil {
+		return "", nil, err
+	}
+
+	if structuredProvider, ok := p.(StructuredOutputProvider); ok {
+		p = structuredProvider.WithResponseSchema(schema)
+	}
+
+	// The instruction is kept even for native providers, some of them (Gemini, Ollama)
+	// produce better results when the schema is also described in the prompt.
+	question = question + fmt.Sprintf(structuredOutputInstruction, string(schemaBytes))
+
+	modelResult := &ModelResult{}
+	answer, attemptResult, err := queryStructuredTextOnce(p, question, history, prompt, knowledgeMessages, lang)
+	if err != nil {
+		return "", nil, err
+	}
+	AddModelResults(modelResult, attemptResult)
+
+	jsonText, problems := validateStructuredOutput(schema, answer)
+	for i := 0; i < structuredOutputMaxRepairs && len(problems) > 0; i++ {
+		repairQuestion := fmt.Sprintf(structuredOutputRepairQuestion, string(schemaBytes), answer, strings.Join(problems, "\n"))
+		answer, attemptResult, err = queryStructuredTextOnce(p, repairQuestion, nil, prompt, nil, lang)
+		if err != nil {
+			return "", nil, err
+		}
+		AddModelResults(modelResult, attemptResult)
+
+		jsonText, problems = validateStructuredOutput(schema, answer)
+	}
+
+	if len(problems) > 0 {
+		return "", modelResult, fmt.Errorf(i18n.Translate(lang, "model:the model output does not conform to the JSON schema: %s"), strings.Join(problems, "; "))
+	}
+
+	return jsonText, modelResult, nil
+}
+
+// QueryStructured is QueryStructuredText followed by unmarshalling the validated JSON into v.
+func QueryStructured(p ModelProvider, schema *ResponseSchema, v interface{}, question string, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, lang string) (*ModelResult, error) {
+	jsonText, modelResult, err := QueryStructuredText(p, schema, question, history, prompt, knowledgeMessages, lang)
+	if err != nil {
+		return modelResult, err
+	}
+
+	err = json.Unmarshal([]byte(jsonText), v)
+	if err != nil {
+		return modelResult, err
+	}
+
+	return modelResult, nil
+}
+
+func (schema *ResponseSchema) getName() string {
+	if schema.Name == "" {
+		return "response"
+	}
+	return schema.Name
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build skipCi
+// +build skipCi
+
+package model
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+type fakeStructuredProvider struct {
+	answers []string
+	calls   int
+}
+
+func (p *fakeStructuredProvider) GetPricing() string {
+	return ""
+}
+
+func (p *fakeStructuredProvider) QueryText(question string, writer io.Writer, history []*RawMessage, prompt string, knowledgeMessages []*RawMessage, agentInfo *AgentInfo, lang string) (*ModelResult, error) {
+	answer := p.answers[p.calls]
+	p.calls++
+	_, err := fmt.Fprintf(writer, "event: message\ndata: %s\n\n", answer)
+	if err != nil {
+		return nil, err
+	}
+	return &ModelResult{TotalTokenCount: 10}, nil
+}
+
+var testResponseSchema = &ResponseSchema{
+	Name: "person",
+	Schema: JsonSchema{
+		"type": "object",
+		"properties": JsonSchema{
+			"name": JsonSchema{"type": "string"},
+			"age":  JsonSchema{"type": "integer", "minimum": 0},
+			"tags": JsonSchema{"type": "array", "items": JsonSchema{"type": "string"}},
+		},
+		"required":             []string{"name", "age"},
+		"additionalProperties": false,
+	},
+}
+
+func TestValidateJsonSchema(t *testing.T) {
+	tests := []struct {
+		text     string
+		problems int
+	}{
+		{`{"name": "Alice", "age": 30, "tags": ["a"]}`, 0},
+		{"```json\n{\"name\": \"Alice\", \"age\": 30}\n```", 0},
+		{`{"name": "Alice"}`, 1},
+		{`{"name": "Alice", "age": -1, "tags": [1], "extra": true}`, 3},
+		{`not json`, 1},
+	}
+
+	for _, test := range tests {
+		_, problems := validateStructuredOutput(testResponseSchema, test.text)
+		if len(problems) != test.problems {
+			t.Errorf("validateStructuredOutput(%q) = %v, want %d problems", test.text, problems, test.problems)
+		}
+	}
+}
+
+func TestQueryStructured(t *testing.T) {
+	p := &fakeStructuredProvider{answers: []string{`{"name": "Alice"}`, `{"name": "Alice", "age": 30}`}}
+
+	var person struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+	modelResult, err := QueryStructured(p, testResponseSchema, &person, "Who is Alice?", nil, "", nil, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if person.Name != "Alice" || person.Age != 30 || p.calls != 2 || modelResult.TotalTokenCount != 20 {
+		t.Errorf("unexpected result: %+v, calls: %d, modelResult: %+v", person, p.calls, modelResult)
+	}
+}
+
+func TestIsUnsupportedResponseFormatError(t *testing.T) {
+	param := "response_format"
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&openai.APIError{HTTPStatusCode: 400, Param: &param, Message: "Unsupported parameter"}, true},
+		{&openai.APIError{HTTPStatusCode: 400, Message: "response_format json_schema is not supported"}, true},
+		{&openai.APIError{HTTPStatusCode: 401, Message: "invalid api key"}, false},
+		{&openai.RequestError{HTTPStatusCode: 500, Err: fmt.Errorf("response_format")}, false},
+		{fmt.Errorf("connection refused"), false},
+	}
+
+	for _, test := range tests {
+		if got := isUnsupportedResponseFormatError(test.err); got != test.want {
+			t.Errorf("isUnsupportedResponseFormatError(%v) = %v, want %v", test.err, got, test.want)
+		}
+	}
+}
+
+func TestGitHubWithResponseSchema(t *testing.T) {
+	p, err := NewGitHubModelProvider("GitHub", "gpt-4o", "token", 1, 1, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider, ok := p.(StructuredOutputProvider).WithResponseSchema(testResponseSchema).(*GitHubModelProvider)
+	if !ok {
+		t.Fatalf("WithResponseSchema() returned %T, want *GitHubModelProvider", provider)
+	}
+	if provider.responseSchema != testResponseSchema || p.(*GitHubModelProvider).responseSchema != nil {
+		t.Errorf("WithResponseSchema() did not copy the provider")
+	}
+}
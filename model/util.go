@@ -32,6 +32,7 @@ type RawMessage struct {
 	TextTokenCount int
 	ToolCall       openai.ToolCall
 	ToolCallID     string
+	Parts          []*MessagePart
 }
 
 type SearchResult struct {
@@ -329,4 +329,54 @@ func (a *Adapter) createTable() {
 	if err != nil {
 		panic(err)
 	}
+
+	err = a.engine.Sync2(new(AnswerCache))
+	if err != nil {
+		panic(err)
+	}
+
+	err = a.engine.Sync2(new(Trace))
+	if err != nil {
+		panic(err)
+	}
+
+	err = a.engine.Sync2(new(Dataset))
+	if err != nil {
+		panic(err)
+	}
+
+	err = a.engine.Sync2(new(Evaluation))
+	if err != nil {
+		panic(err)
+	}
+
+	err = a.engine.Sync2(new(WorkflowInstance))
+	if err != nil {
+		panic(err)
+	}
+
+	err = a.engine.Sync2(new(TaskBatch))
+	if err != nil {
+		panic(err)
+	}
+
+	err = a.engine.Sync2(new(ScaleVersion))
+	if err != nil {
+		panic(err)
+	}
+
+	err = a.engine.Sync2(new(FormSubmission))
+	if err != nil {
+		panic(err)
+	}
+
+	err = a.engine.Sync2(new(Glossary))
+	if err != nil {
+		panic(err)
+	}
+
+	err = a.engine.Sync2(new(TranslationCache))
+	if err != nil {
+		panic(err)
+	}
 }
@@ -0,0 +1,274 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/beego/beego/logs"
+	"github.com/casibase/casibase/embedding"
+	"github.com/casibase/casibase/model"
+	"github.com/casibase/casibase/util"
+	"github.com/robfig/cron/v3"
+	"xorm.io/core"
+)
+
+const (
+	defaultSemanticCacheThreshold = 0.95
+	defaultSemanticCacheMinutes   = 60 * 24
+)
+
+// AnswerCache is a previously generated answer of a store, looked up by the
+// embedding of the normalized question when the store's semantic cache is enabled.
+type AnswerCache struct {
+	Owner       string `xorm:"varchar(100) notnull pk" json:"owner"`
+	Name        string `xorm:"varchar(100) notnull pk" json:"name"`
+	CreatedTime string `xorm:"varchar(100)" json:"createdTime"`
+
+	Store             string  `xorm:"varchar(100) index" json:"store"`
+	Fingerprint       string  `xorm:"varchar(100) index" json:"fingerprint"`
+	EmbeddingProvider string  `xorm:"varchar(100)" json:"embeddingProvider"`
+	ModelProvider     string  `xorm:"varchar(100)" json:"modelProvider"`
+	Question          string  `xorm:"mediumtext" json:"question"`
+	Answer            string  `xorm:"mediumtext" json:"answer"`
+	ReasonText        string  `xorm:"mediumtext" json:"reasonText"`
+	HitCount          int     `json:"hitCount"`
+	LastHitTime       string  `xorm:"varchar(100)" json:"lastHitTime"`
+	Score             float32 `xorm:"-" json:"score"`
+
+	Data      []float32 `xorm:"mediumtext" json:"data"`
+	Dimension int       `json:"dimension"`
+}
+
+func getAnswerCaches(owner string, storeName string, fingerprint string) ([]*AnswerCache, error) {
+	answerCaches := []*AnswerCache{}
+	err := adapter.engine.Desc("created_time").Find(&answerCaches, &AnswerCache{Owner: owner, Store: storeName, Fingerprint: fingerprint})
+	if err != nil {
+		return answerCaches, err
+	}
+
+	return answerCaches, nil
+}
+
+func AddAnswerCache(answerCache *AnswerCache) (bool, error) {
+	affected, err := adapter.engine.Insert(answerCache)
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+func DeleteAnswerCachesByStore(owner string, storeName string) (bool, error) {
+	affected, err := adapter.engine.Where("owner = ? AND store = ?", owner, storeName).Delete(&AnswerCache{})
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+func (answerCache *AnswerCache) GetId() string {
+	return fmt.Sprintf("%s/%s", answerCache.Owner, answerCache.Name)
+}
+
+var cacheQuestionPunctuationRegex = regexp.MustCompile(`[\s?？!！.。,，;；:：]+$`)
+
+func normalizeCacheQuestion(question string) string {
+	res := strings.ToLower(strings.TrimSpace(question))
+	res = strings.Join(strings.Fields(res), " ")
+	res = cacheQuestionPunctuationRegex.ReplaceAllString(res, "")
+	return res
+}
+
+// getAnswerCacheFingerprint identifies everything besides the question that the
+// answer depends on: the prompt, the model, the retrieved knowledge and the chat
+// so far, so that a follow-up question is never answered from another chat.
+func getAnswerCacheFingerprint(store *Store, modelProviderName string, knowledge []*model.RawMessage, history []*model.RawMessage, summary string) string {
+	hasher := sha256.New()
+	hasher.Write([]byte(store.Prompt))
+	hasher.Write([]byte{0})
+	hasher.Write([]byte(modelProviderName))
+	for _, k := range knowledge {
+		hasher.Write([]byte{0})
+		hasher.Write([]byte(k.Text))
+	}
+	hasher.Write([]byte{1})
+	hasher.Write([]byte(summary))
+	for _, h := range history {
+		hasher.Write([]byte{0})
+		hasher.Write([]byte(h.Author))
+		hasher.Write([]byte{0})
+		hasher.Write([]byte(h.Text))
+	}
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+func (store *Store) getSemanticCacheThreshold() float32 {
+	if store.SemanticCacheThreshold <= 0 || store.SemanticCacheThreshold > 1 {
+		return defaultSemanticCacheThreshold
+	}
+	return store.SemanticCacheThreshold
+}
+
+func (store *Store) getSemanticCacheMinutes() int {
+	if store.SemanticCacheMinutes <= 0 {
+		return defaultSemanticCacheMinutes
+	}
+	return store.SemanticCacheMinutes
+}
+
+// GetCachedAnswer embeds the normalized question and returns the most similar cached
+// answer above the store's threshold within its TTL, or nil when there is no hit.
+// The question vector is returned as well so that a fresh answer can be cached by AddAnswerCacheForStore.
+func GetCachedAnswer(store *Store, embeddingProvider *Provider, embeddingProviderObj embedding.EmbeddingProvider, modelProviderName string, question string, knowledge []*model.RawMessage, history []*model.RawMessage, summary string, lang string) (*AnswerCache, []float32, *embedding.EmbeddingResult, error) {
+	normalizedQuestion := normalizeCacheQuestion(question)
+	if normalizedQuestion == "" {
+		return nil, nil, nil, nil
+	}
+
+	vector, embeddingResult, err := queryVectorSafe(embeddingProviderObj, normalizedQuestion, embeddingProvider.Name, lang)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	fingerprint := getAnswerCacheFingerprint(store, modelProviderName, knowledge, history, summary)
+	answerCaches, err := getAnswerCaches(store.Owner, store.Name, fingerprint)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	sinceTime := time.Now().Add(-time.Minute * time.Duration(store.getSemanticCacheMinutes()))
+	threshold := store.getSemanticCacheThreshold()
+	vectorNorm := norm(vector)
+
+	var res *AnswerCache
+	for _, answerCache := range answerCaches {
+		if answerCache.EmbeddingProvider != embeddingProvider.Name || len(answerCache.Data) != len(vector) {
+			continue
+		}
+
+		createdTime, err := time.Parse(time.RFC3339, answerCache.CreatedTime)
+		if err != nil || createdTime.Before(sinceTime) {
+			continue
+		}
+
+		score := cosineSimilarity(vector, answerCache.Data, vectorNorm)
+		if score >= threshold && (res == nil || score > res.Score) {
+			answerCache.Score = score
+			res = answerCache
+		}
+	}
+
+	if res != nil {
+		res.HitCount += 1
+		res.LastHitTime = util.GetCurrentTime()
+		_, err = adapter.engine.ID(core.PK{res.Owner, res.Name}).Cols("hit_count", "last_hit_time").Update(res)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	return res, vector, embeddingResult, nil
+}
+
+func AddAnswerCacheForStore(store *Store, embeddingProviderName string, modelProviderName string, question string, knowledge []*model.RawMessage, history []*model.RawMessage, summary string, vector []float32, answer string, reasonText string) error {
+	if len(vector) == 0 || answer == "" {
+		return nil
+	}
+
+	answerCache := &AnswerCache{
+		Owner:             store.Owner,
+		Name:              fmt.Sprintf("answer_cache_%s", util.GetRandomName()),
+		CreatedTime:       util.GetCurrentTime(),
+		Store:             store.Name,
+		Fingerprint:       getAnswerCacheFingerprint(store, modelProviderName, knowledge, history, summary),
+		EmbeddingProvider: embeddingProviderName,
+		ModelProvider:     modelProviderName,
+		Question:          normalizeCacheQuestion(question),
+		Answer:            answer,
+		ReasonText:        reasonText,
+		Data:              vector,
+		Dimension:         len(vector),
+	}
+
+	_, err := AddAnswerCache(answerCache)
+	return err
+}
+
+// purgeExpiredAnswerCaches deletes the cached answers older than the TTL of their store,
+// the ones of deleted stores included, since they are never served again.
+func purgeExpiredAnswerCaches() error {
+	stores, err := GetGlobalStores()
+	if err != nil {
+		return err
+	}
+
+	storeMinutes := map[string]int{}
+	for _, store := range stores {
+		storeMinutes[store.GetId()] = store.getSemanticCacheMinutes()
+	}
+
+	answerCaches := []*AnswerCache{}
+	err = adapter.engine.Cols("owner", "name", "store", "created_time").Find(&answerCaches)
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	for _, answerCache := range answerCaches {
+		minutes, ok := storeMinutes[util.GetIdFromOwnerAndName(answerCache.Owner, answerCache.Store)]
+		if ok {
+			createdTime, err := time.Parse(time.RFC3339, answerCache.CreatedTime)
+			if err == nil && createdTime.After(time.Now().Add(-time.Minute*time.Duration(minutes))) {
+				continue
+			}
+		}
+
+		_, err = adapter.engine.ID(core.PK{answerCache.Owner, answerCache.Name}).Delete(&AnswerCache{})
+		if err != nil {
+			return err
+		}
+		count += 1
+	}
+
+	if count > 0 {
+		logs.Info("Purged %d expired answer caches", count)
+	}
+	return nil
+}
+
+func purgeExpiredAnswerCachesNoError() {
+	err := purgeExpiredAnswerCaches()
+	if err != nil {
+		logs.Error("purgeExpiredAnswerCachesNoError() error: %s", err.Error())
+	}
+}
+
+func InitAnswerCachePurge() {
+	cronJob := cron.New()
+	schedule := fmt.Sprintf("@every %ds", 3600)
+	_, err := cronJob.AddFunc(schedule, purgeExpiredAnswerCachesNoError)
+	if err != nil {
+		panic(err)
+	}
+
+	cronJob.Start()
+}
@@ -21,12 +21,19 @@ import (
 	"xorm.io/core"
 )
 
+const (
+	ArticleBlockStateDraft     = "Draft"
+	ArticleBlockStateGenerated = "Generated"
+	ArticleBlockStateReviewed  = "Reviewed"
+)
+
 type Block struct {
-	Type   string `json:"type"`
-	Text   string `json:"text"`
-	TextEn string `json:"textEn"`
-	Prompt string `json:"prompt"`
-	State  string `json:"state"`
+	Type      string `json:"type"`
+	Text      string `json:"text"`
+	TextEn    string `json:"textEn"`
+	Prompt    string `json:"prompt"`
+	State     string `json:"state"`
+	ErrorText string `json:"errorText,omitempty"`
 }
 
 type Article struct {
@@ -36,11 +43,13 @@ type Article struct {
 
 	DisplayName string `xorm:"varchar(100)" json:"displayName"`
 	Workflow    string `xorm:"varchar(100)" json:"workflow"`
+	Store       string `xorm:"varchar(100)" json:"store"`
 	Type        string `xorm:"varchar(100)" json:"type"`
 
-	Text     string   `xorm:"mediumtext" json:"text"`
-	Content  []*Block `xorm:"mediumtext" json:"content"`
-	Glossary []string `xorm:"varchar(200)" json:"glossary"`
+	Text       string   `xorm:"mediumtext" json:"text"`
+	Content    []*Block `xorm:"mediumtext" json:"content"`
+	Glossary   []string `xorm:"varchar(200)" json:"glossary"`
+	Glossaries []string `xorm:"varchar(500)" json:"glossaries"`
 }
 
 func GetMaskedArticle(article *Article, isMaskEnabled bool) *Article {
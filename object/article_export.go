@@ -0,0 +1,102 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/carmel/gooxml/document"
+)
+
+// getArticleBlockText returns the English text of the block for "en" and its original text otherwise,
+// falling back to the other one when it is empty.
+func getArticleBlockText(block *Block, language string) string {
+	text, otherText := block.Text, block.TextEn
+	if language == "en" {
+		text, otherText = otherText, text
+	}
+	if text == "" {
+		text = otherText
+	}
+	return strings.TrimSpace(text)
+}
+
+var articleMarkdownPrefixes = map[string]string{
+	"Title":    "# ",
+	"Header 1": "## ",
+	"Header 2": "### ",
+	"Header 3": "#### ",
+}
+
+var articleDocxStyles = map[string]string{
+	"Title":    "Title",
+	"Header 1": "Heading1",
+	"Header 2": "Heading2",
+	"Header 3": "Heading3",
+}
+
+func ExportArticleMarkdown(article *Article, language string) []byte {
+	parts := []string{}
+	for _, block := range article.Content {
+		text := getArticleBlockText(block, language)
+		if text == "" {
+			continue
+		}
+
+		if block.Type == "Abstract" {
+			text = fmt.Sprintf("> %s", strings.ReplaceAll(text, "\n", "\n> "))
+		}
+		parts = append(parts, articleMarkdownPrefixes[block.Type]+text)
+	}
+
+	return []byte(strings.Join(parts, "\n\n") + "\n")
+}
+
+func ExportArticleDocx(article *Article, language string) ([]byte, error) {
+	doc := document.New()
+	for _, block := range article.Content {
+		text := getArticleBlockText(block, language)
+		if text == "" {
+			continue
+		}
+
+		style := articleDocxStyles[block.Type]
+		for _, line := range strings.Split(text, "\n") {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+
+			para := doc.AddParagraph()
+			if style != "" {
+				para.SetStyle(style)
+			}
+			run := para.AddRun()
+			run.AddText(line)
+			if block.Type == "Abstract" {
+				run.Properties().SetItalic(true)
+			}
+		}
+	}
+
+	var buffer bytes.Buffer
+	err := doc.Save(&buffer)
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
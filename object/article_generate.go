@@ -0,0 +1,280 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/casibase/casibase/embedding"
+	"github.com/casibase/casibase/i18n"
+	"github.com/casibase/casibase/model"
+)
+
+const articlePrompt = "You are a professional writer. You write the requested part of an article based on the provided knowledge, without inventing facts."
+
+// articleGenerator holds the providers of the store used to generate the blocks of an article.
+type articleGenerator struct {
+	store                *Store
+	modelProvider        *Provider
+	modelProviderObj     model.ModelProvider
+	embeddingProvider    *Provider
+	embeddingProviderObj embedding.EmbeddingProvider
+	translator           *translator
+	lang                 string
+}
+
+func newArticleGenerator(article *Article, lang string) (*articleGenerator, error) {
+	if article.Store == "" {
+		return nil, fmt.Errorf(i18n.Translate(lang, "object:The store of the article: %s is empty"), article.GetId())
+	}
+
+	store, err := getStore("admin", article.Store)
+	if err != nil {
+		return nil, err
+	}
+	if store == nil {
+		return nil, fmt.Errorf(i18n.Translate(lang, "account:The store: %s is not found"), article.Store)
+	}
+
+	modelProvider, modelProviderObj, err := GetModelProviderFromContext("admin", store.ModelProvider, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	embeddingProvider, embeddingProviderObj, err := GetEmbeddingProviderFromContext("admin", store.EmbeddingProvider, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	// the terms of the article come first as they are more specific than the ones of its glossaries
+	terms, err := getGlossaryTerms(article.Glossaries, defaultTranslationLanguage, lang)
+	if err != nil {
+		return nil, err
+	}
+	terms = append(parseGlossary(article.Glossary), terms...)
+
+	t, err := newTranslator(modelProvider.Name, defaultTranslationLanguage, terms, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	generator := &articleGenerator{
+		store:                store,
+		modelProvider:        modelProvider,
+		modelProviderObj:     modelProviderObj,
+		embeddingProvider:    embeddingProvider,
+		embeddingProviderObj: embeddingProviderObj,
+		translator:           t,
+		lang:                 lang,
+	}
+	return generator, nil
+}
+
+func (g *articleGenerator) query(question string, knowledge []*model.RawMessage, prompt string) (string, error) {
+	var writer MyWriter
+	_, err := g.modelProviderObj.QueryText(question, &writer, []*model.RawMessage{}, prompt, knowledge, nil, g.lang)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(writer.String()), nil
+}
+
+func getPreviousBlockText(article *Article, index int) string {
+	for i := index - 1; i >= 0; i-- {
+		if article.Content[i].Text != "" {
+			return article.Content[i].Text
+		}
+	}
+	return ""
+}
+
+func (g *articleGenerator) generateText(article *Article, index int) (string, error) {
+	block := article.Content[index]
+
+	knowledgeCount := g.store.KnowledgeCount
+	if knowledgeCount <= 0 {
+		knowledgeCount = 5
+	}
+
+	knowledge, _, _, err := GetNearestKnowledge(g.store.Name, g.store.VectorStores, g.store.SearchProvider, g.embeddingProvider, g.embeddingProviderObj, g.modelProvider, "admin", block.Prompt, knowledgeCount, g.lang)
+	if err != nil && err.Error() != "no knowledge vectors found" {
+		return "", err
+	}
+
+	question := fmt.Sprintf("Write the \"%s\" block of the article titled \"%s\".\n", block.Type, article.DisplayName)
+	if previousText := getPreviousBlockText(article, index); previousText != "" {
+		question += fmt.Sprintf("The previous block of the article is:\n%s\n", previousText)
+	}
+	question += fmt.Sprintf("The instruction for this block is:\n%s\nOnly respond with the content of the block.", block.Prompt)
+
+	prompt := g.store.Prompt
+	if prompt == "" {
+		prompt = articlePrompt
+	}
+	return g.query(question, knowledge, prompt)
+}
+
+func (g *articleGenerator) translateBlock(block *Block) error {
+	result, err := g.translator.translate(block.Text)
+	if err != nil {
+		return err
+	}
+
+	block.TextEn = result.Text
+	block.ErrorText = ""
+	if len(result.MissingTerms) != 0 {
+		block.ErrorText = fmt.Sprintf(i18n.Translate(g.lang, "object:The translation does not follow the glossary terms: %s"), getGlossaryTermsText(result.MissingTerms))
+	}
+	return nil
+}
+
+func (g *articleGenerator) generateBlock(article *Article, index int) error {
+	block := article.Content[index]
+	if block.Prompt == "" {
+		return fmt.Errorf(i18n.Translate(g.lang, "object:The prompt of the block: %d is empty"), index+1)
+	}
+
+	text, err := g.generateText(article, index)
+	if err != nil {
+		return err
+	}
+
+	block.Text = text
+	err = g.translateBlock(block)
+	if err != nil {
+		return err
+	}
+
+	block.State = ArticleBlockStateGenerated
+	return nil
+}
+
+func getArticleBlock(article *Article, index int, lang string) (*Block, error) {
+	if index < 0 || index >= len(article.Content) {
+		return nil, fmt.Errorf(i18n.Translate(lang, "object:The block: %d is not found"), index+1)
+	}
+	return article.Content[index], nil
+}
+
+func getArticleBlockState(block *Block) string {
+	if block.State == "" {
+		return ArticleBlockStateDraft
+	}
+	return block.State
+}
+
+// GenerateArticleBlock (re)generates the text of the block from its prompt with the knowledge of
+// the article's store, then translates it to English.
+func GenerateArticleBlock(article *Article, index int, lang string) error {
+	_, err := getArticleBlock(article, index, lang)
+	if err != nil {
+		return err
+	}
+
+	generator, err := newArticleGenerator(article, lang)
+	if err != nil {
+		return err
+	}
+
+	err = generator.generateBlock(article, index)
+	if err != nil {
+		return err
+	}
+
+	_, err = UpdateArticle(article.GetId(), article)
+	return err
+}
+
+// GenerateArticle generates the draft blocks having a prompt, in order so that each block follows
+// the previous one. The article is saved after each block to keep the progress.
+func GenerateArticle(article *Article, lang string) error {
+	generator, err := newArticleGenerator(article, lang)
+	if err != nil {
+		return err
+	}
+
+	for i, block := range article.Content {
+		if block.Prompt == "" || getArticleBlockState(block) != ArticleBlockStateDraft {
+			continue
+		}
+
+		err = generator.generateBlock(article, i)
+		if err != nil {
+			block.ErrorText = err.Error()
+		}
+
+		_, err = UpdateArticle(article.GetId(), article)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TranslateArticleBlock translates the text of the block to English again, after it has been edited.
+func TranslateArticleBlock(article *Article, index int, lang string) error {
+	block, err := getArticleBlock(article, index, lang)
+	if err != nil {
+		return err
+	}
+
+	generator, err := newArticleGenerator(article, lang)
+	if err != nil {
+		return err
+	}
+
+	err = generator.translateBlock(block)
+	if err != nil {
+		return err
+	}
+
+	_, err = UpdateArticle(article.GetId(), article)
+	return err
+}
+
+// checkArticleBlockState checks the transition of a block: a generated block can be reviewed and
+// any block can go back to draft, while only the generation makes a block generated.
+func checkArticleBlockState(block *Block, state string, lang string) error {
+	switch state {
+	case ArticleBlockStateDraft:
+		return nil
+	case ArticleBlockStateReviewed:
+		if getArticleBlockState(block) == ArticleBlockStateDraft {
+			return fmt.Errorf(i18n.Translate(lang, "object:The block should be generated before being reviewed"))
+		}
+		return nil
+	default:
+		return fmt.Errorf(i18n.Translate(lang, "object:The block state: %s is invalid"), state)
+	}
+}
+
+func SetArticleBlockState(article *Article, index int, state string, lang string) error {
+	block, err := getArticleBlock(article, index, lang)
+	if err != nil {
+		return err
+	}
+
+	err = checkArticleBlockState(block, state, lang)
+	if err != nil {
+		return err
+	}
+
+	block.State = state
+	_, err = UpdateArticle(article.GetId(), article)
+	return err
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build skipCi
+// +build skipCi
+
+package object
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/carmel/gooxml/document"
+)
+
+func TestCheckArticleBlockState(t *testing.T) {
+	tests := []struct {
+		current string
+		state   string
+		valid   bool
+	}{
+		{"", ArticleBlockStateReviewed, false},
+		{ArticleBlockStateGenerated, ArticleBlockStateReviewed, true},
+		{ArticleBlockStateReviewed, ArticleBlockStateDraft, true},
+		{ArticleBlockStateDraft, ArticleBlockStateGenerated, false},
+	}
+	for _, test := range tests {
+		err := checkArticleBlockState(&Block{State: test.current}, test.state, "en")
+		if (err == nil) != test.valid {
+			t.Errorf("unexpected result for %q -> %q: %v", test.current, test.state, err)
+		}
+	}
+}
+
+func newTestArticle() *Article {
+	return &Article{
+		Owner: "admin",
+		Name:  "article_1",
+		Content: []*Block{
+			{Type: "Title", Text: "标题", TextEn: "Title"},
+			{Type: "Header 1", Text: "简介", TextEn: "Introduction"},
+			{Type: "Text", Text: "第一段\n第二段", TextEn: ""},
+		},
+	}
+}
+
+func TestExportArticleMarkdown(t *testing.T) {
+	data := string(ExportArticleMarkdown(newTestArticle(), "en"))
+	expected := "# Title\n\n## Introduction\n\n第一段\n第二段\n"
+	if data != expected {
+		t.Errorf("unexpected Markdown export:\n%s", data)
+	}
+}
+
+func TestExportArticleDocx(t *testing.T) {
+	data, err := ExportArticleDocx(newTestArticle(), "zh")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := document.Read(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	texts := []string{}
+	for _, para := range doc.Paragraphs() {
+		for _, run := range para.Runs() {
+			texts = append(texts, run.Text())
+		}
+	}
+	if strings.Join(texts, "|") != "标题|简介|第一段|第二段" {
+		t.Errorf("unexpected DOCX paragraphs: %v", texts)
+	}
+}
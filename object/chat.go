@@ -48,6 +48,13 @@ type Chat struct {
 	IsHidden      bool     `json:"isHidden"`
 	IsDeleted     bool     `json:"isDeleted"`
 	NeedTitle     bool     `json:"needTitle"`
+	Experiment    string   `xorm:"varchar(100)" json:"experiment"`
+	Variant       string   `xorm:"varchar(100)" json:"variant"`
+
+	Summary           string  `xorm:"mediumtext" json:"summary"`
+	SummaryTime       string  `xorm:"varchar(100)" json:"summaryTime"`
+	SummaryTokenCount int     `json:"summaryTokenCount"`
+	SummaryPrice      float64 `json:"summaryPrice"`
 }
 
 func GetGlobalChats() ([]*Chat, error) {
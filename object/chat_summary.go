@@ -0,0 +1,220 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/beego/beego/logs"
+	"github.com/casibase/casibase/model"
+	"xorm.io/core"
+)
+
+const defaultSummaryThreshold = 20
+
+const summaryPrompt = "You maintain the memory of a conversation between a user and an AI assistant. Merge the previous summary and the new conversation turns into one concise summary in the language of the conversation. Keep the facts, names, numbers, decisions, user preferences and open questions that may matter later, and drop greetings and small talk. Reply with the summary only."
+
+// GetPromptWithSummary injects the rolling summary of the earlier conversation into the system prompt.
+func GetPromptWithSummary(prompt string, summary string) string {
+	if summary == "" {
+		return prompt
+	}
+
+	res := fmt.Sprintf("Summary of the earlier conversation:\n%s", summary)
+	if prompt != "" {
+		res = fmt.Sprintf("%s\n\n%s", prompt, res)
+	}
+	return res
+}
+
+func getSummaryQuestion(summary string, messages []*Message) string {
+	var sb strings.Builder
+	if summary != "" {
+		sb.WriteString(fmt.Sprintf("Previous summary:\n%s\n\n", summary))
+	}
+
+	sb.WriteString("New conversation turns:\n")
+	for _, message := range messages {
+		author := "User"
+		if message.Author == "AI" {
+			author = "Assistant"
+		}
+		sb.WriteString(fmt.Sprintf("%s: %s\n", author, message.Text))
+	}
+
+	sb.WriteString("\nWrite the updated summary.")
+	return sb.String()
+}
+
+// updateChatSummary folds the messages, in chronological order, into the summary of the chat,
+// bills the summarisation to the chat's user and adds its cost to the chat.
+func updateChatSummary(chat *Chat, messages []*Message, modelProviderName string, modelProviderObj model.ModelProvider, lang string) error {
+	question := getSummaryQuestion(chat.Summary, messages)
+
+	var writer MyWriter
+	modelResult, err := modelProviderObj.QueryText(question, &writer, []*model.RawMessage{}, summaryPrompt, []*model.RawMessage{}, nil, lang)
+	if err != nil {
+		return err
+	}
+
+	summary := strings.TrimSpace(writer.String())
+	if summary == "" {
+		return nil
+	}
+
+	modelResult.TotalPrice = model.AddPrices(modelResult.TotalPrice, 0)
+	err = AddTransactionForChatSummary(chat, modelProviderName, modelResult)
+	if err != nil {
+		return err
+	}
+
+	// the answers of the chat may have updated its costs in the meantime
+	chat, err = getChat(chat.Owner, chat.Name)
+	if err != nil {
+		return err
+	}
+	if chat == nil {
+		return nil
+	}
+
+	chat.Summary = summary
+	chat.SummaryTime = messages[len(messages)-1].CreatedTime
+	chat.SummaryTokenCount += modelResult.TotalTokenCount
+	chat.SummaryPrice = model.AddPrices(chat.SummaryPrice, modelResult.TotalPrice)
+	if chat.Currency == "" {
+		chat.Currency = modelResult.Currency
+	}
+	if chat.Currency == modelResult.Currency {
+		chat.TokenCount += modelResult.TotalTokenCount
+		chat.Price = model.AddPrices(chat.Price, modelResult.TotalPrice)
+	}
+
+	_, err = adapter.engine.ID(core.PK{chat.Owner, chat.Name}).Cols("summary", "summary_time", "summary_token_count", "summary_price", "token_count", "price", "currency").Update(chat)
+	return err
+}
+
+// getUnsummarizedMessages returns the messages of the chat created after its summary and
+// up to createdTime, the latest first.
+func getUnsummarizedMessages(chat *Chat, createdTime string) ([]*Message, error) {
+	messages := []*Message{}
+	session := adapter.engine.Where("created_time <= ?", createdTime)
+	if chat.SummaryTime != "" {
+		session = session.And("created_time > ?", chat.SummaryTime)
+	}
+	err := session.Desc("created_time").Find(&messages, &Message{Owner: chat.Owner, Chat: chat.Name})
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// GetChatMemory returns the recent history of a chat like GetRecentRawMessages and the rolling
+// summary of the older turns. With summary memory enabled, the whole history since the last
+// summary is returned, UpdateChatMemory keeping it short once the answer is sent.
+func GetChatMemory(store *Store, chat *Chat, createdTime string) ([]*model.RawMessage, string, error) {
+	if !store.EnableSummaryMemory {
+		history, err := GetRecentRawMessages(chat.Name, createdTime, store.MemoryLimit)
+		return history, "", err
+	}
+
+	messages, err := getUnsummarizedMessages(chat, createdTime)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// skip the answer being generated and its question
+	if len(messages) <= 2 {
+		return []*model.RawMessage{}, chat.Summary, nil
+	}
+
+	history, err := getRawMessagesFromMessages(messages[2:])
+	if err != nil {
+		return nil, "", err
+	}
+	return history, chat.Summary, nil
+}
+
+var (
+	summarizingChats = map[string]bool{}
+	summarizingLock  sync.Mutex
+)
+
+// UpdateChatMemory is called once an answer is sent, when the history since the last summary
+// exceeds the store's threshold, everything older than the last MemoryLimit turns is summarised
+// by the store's model and persisted on the chat. It runs in the background and a failed
+// summarisation is only logged, the history is then summarised after the next answer.
+func UpdateChatMemory(store *Store, chat *Chat, createdTime string, modelProviderName string, modelProviderObj model.ModelProvider, lang string) {
+	if !store.EnableSummaryMemory {
+		return
+	}
+
+	chatId := chat.GetId()
+	summarizingLock.Lock()
+	if summarizingChats[chatId] {
+		summarizingLock.Unlock()
+		return
+	}
+	summarizingChats[chatId] = true
+	summarizingLock.Unlock()
+
+	go func() {
+		defer func() {
+			summarizingLock.Lock()
+			delete(summarizingChats, chatId)
+			summarizingLock.Unlock()
+		}()
+
+		err := updateChatMemory(store, chat, createdTime, modelProviderName, modelProviderObj, lang)
+		if err != nil {
+			logs.Error("UpdateChatMemory() error, failed to summarize the chat: %s, %s", chatId, err.Error())
+		}
+	}()
+}
+
+func updateChatMemory(store *Store, chat *Chat, createdTime string, modelProviderName string, modelProviderObj model.ModelProvider, lang string) error {
+	chat, err := getChat(chat.Owner, chat.Name)
+	if err != nil || chat == nil {
+		return err
+	}
+
+	messages, err := getUnsummarizedMessages(chat, createdTime)
+	if err != nil {
+		return err
+	}
+
+	threshold := store.SummaryThreshold
+	if threshold <= 0 {
+		threshold = defaultSummaryThreshold
+	}
+
+	keepCount := 2 * store.MemoryLimit
+	if len(messages) <= threshold || len(messages) <= keepCount {
+		return nil
+	}
+
+	olderMessages := []*Message{}
+	for i := len(messages) - 1; i >= keepCount; i-- {
+		if messages[i].Text != "" {
+			olderMessages = append(olderMessages, messages[i])
+		}
+	}
+	if len(olderMessages) == 0 {
+		return nil
+	}
+
+	return updateChatSummary(chat, olderMessages, modelProviderName, modelProviderObj, lang)
+}
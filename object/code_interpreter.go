@@ -0,0 +1,164 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/beego/beego/logs"
+	"github.com/casibase/casibase/agent"
+	"github.com/casibase/casibase/agent/builtin_tool/code"
+	"github.com/casibase/casibase/proxy"
+)
+
+const maxChatFileBytes = 20 * 1024 * 1024
+
+var chatFileUrlRegex = regexp.MustCompile(`data:[a-zA-Z]+/[a-zA-Z0-9\-\.\+]+;base64,[a-zA-Z0-9+/=]+|https?://[^\s"'<>]+`)
+
+const chatFileDownloadTimeout = 30 * time.Second
+
+// CodeInterpreter provides the files of a chat to the execute_code tool and saves the files
+// generated by the code through the store's storage provider.
+type CodeInterpreter struct {
+	store   *Store
+	message *Message
+	origin  string
+	lang    string
+}
+
+func NewCodeInterpreter(store *Store, message *Message, origin string, lang string) *CodeInterpreter {
+	return &CodeInterpreter{
+		store:   store,
+		message: message,
+		origin:  origin,
+		lang:    lang,
+	}
+}
+
+// InitCodeSandbox logs at startup why the execute_code tool can't run any code on this server,
+// the stores selecting it would only get the error at the first call otherwise.
+func InitCodeSandbox() {
+	err := codetools.CheckSandbox()
+	if err != nil {
+		logs.Error("InitCodeSandbox() error, the execute_code tool can't run any code: %s", err.Error())
+	}
+}
+
+// BindTool binds the execute_code tool to the chat when the store has selected it.
+func (ci *CodeInterpreter) BindTool(agentClients *agent.AgentClients) {
+	tool, ok := ci.store.getSelectedBuiltinTool(agentClients, "execute_code")
+	if !ok {
+		return
+	}
+
+	codeTool, ok := tool.(*codetools.ExecuteCodeTool)
+	if !ok {
+		return
+	}
+	agentClients.BuiltinToolReg.RegisterTool(codeTool.WithFiles(ci.LoadFiles, ci.SaveFile))
+}
+
+func readChatFile(ctx context.Context, fileUrl string) ([]byte, error) {
+	if strings.HasPrefix(fileUrl, "data:") {
+		tokens := strings.SplitN(fileUrl, ",", 2)
+		return base64.StdEncoding.DecodeString(tokens[1])
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// the URLs come from the chat text, the private addresses are refused
+	resp, err := proxy.GetSafeHttpClient(fileUrl, chatFileDownloadTimeout).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download the file: %s, status: %s", fileUrl, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxChatFileBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxChatFileBytes {
+		return nil, fmt.Errorf("the file: %s is larger than %d bytes", fileUrl, maxChatFileBytes)
+	}
+	return data, nil
+}
+
+// LoadFiles returns the files attached to the questions of the chat, a later file replaces an
+// earlier one with the same name.
+func (ci *CodeInterpreter) LoadFiles(ctx context.Context) ([]*codetools.File, error) {
+	messages, err := GetChatMessages(ci.message.Chat)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*codetools.File{}
+	indexes := map[string]int{}
+	for _, message := range messages {
+		if message.Author == "AI" || message.FileName == "" {
+			continue
+		}
+
+		fileUrl := chatFileUrlRegex.FindString(message.Text)
+		if fileUrl == "" {
+			continue
+		}
+
+		data, err := readChatFile(ctx, fileUrl)
+		if err != nil {
+			return nil, err
+		}
+
+		file := &codetools.File{Name: message.FileName, Data: data}
+		if i, ok := indexes[file.Name]; ok {
+			res[i] = file
+		} else {
+			indexes[file.Name] = len(res)
+			res = append(res, file)
+		}
+	}
+	return res, nil
+}
+
+// SaveFile saves a file generated by the code next to the chat's files and returns its URL.
+func (ci *CodeInterpreter) SaveFile(ctx context.Context, file *codetools.File) (string, error) {
+	storageProviderObj, err := ci.store.GetStorageProviderObj(ci.lang)
+	if err != nil {
+		return "", err
+	}
+
+	message := ci.message
+	key := fmt.Sprintf("%s/%s/%s/%s_%s", message.Organization, message.User, message.Chat, message.Name, file.Name)
+	fileUrl, err := storageProviderObj.PutObject(message.User, message.Chat, key, bytes.NewBuffer(file.Data))
+	if err != nil {
+		return "", err
+	}
+
+	return getUrlFromPath(fileUrl, ci.origin)
+}
@@ -0,0 +1,286 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/casibase/casibase/i18n"
+	"github.com/casibase/casibase/util"
+	"github.com/tealeg/xlsx"
+	"xorm.io/core"
+)
+
+// DatasetItem is a question of an evaluation dataset with its expected answer and the files
+// expected to be retrieved for it. Both expectations are optional.
+type DatasetItem struct {
+	Question        string   `json:"question"`
+	ExpectedAnswer  string   `json:"expectedAnswer"`
+	ExpectedSources []string `json:"expectedSources"`
+}
+
+// Dataset is a set of questions to evaluate the stores against, see Evaluation.
+type Dataset struct {
+	Owner       string `xorm:"varchar(100) notnull pk" json:"owner"`
+	Name        string `xorm:"varchar(100) notnull pk" json:"name"`
+	CreatedTime string `xorm:"varchar(100)" json:"createdTime"`
+	DisplayName string `xorm:"varchar(200)" json:"displayName"`
+
+	Description string         `xorm:"varchar(500)" json:"description"`
+	Items       []*DatasetItem `xorm:"mediumtext" json:"items"`
+}
+
+func GetDatasetCount(owner, field, value string) (int64, error) {
+	session := GetDbSession(owner, -1, -1, field, value, "", "")
+	return session.Count(&Dataset{})
+}
+
+func GetDatasets(owner string) ([]*Dataset, error) {
+	datasets := []*Dataset{}
+	err := adapter.engine.Desc("created_time").Find(&datasets, &Dataset{Owner: owner})
+	if err != nil {
+		return datasets, err
+	}
+	return datasets, nil
+}
+
+func GetPaginationDatasets(owner string, offset, limit int, field, value, sortField, sortOrder string) ([]*Dataset, error) {
+	datasets := []*Dataset{}
+	session := GetDbSession(owner, offset, limit, field, value, sortField, sortOrder)
+	err := session.Find(&datasets)
+	if err != nil {
+		return datasets, err
+	}
+
+	return datasets, nil
+}
+
+func getDataset(owner string, name string) (*Dataset, error) {
+	if owner == "" || name == "" {
+		return nil, nil
+	}
+
+	dataset := Dataset{Owner: owner, Name: name}
+	existed, err := adapter.engine.Get(&dataset)
+	if err != nil {
+		return &dataset, err
+	}
+
+	if existed {
+		return &dataset, nil
+	} else {
+		return nil, nil
+	}
+}
+
+func GetDataset(id string) (*Dataset, error) {
+	owner, name, err := util.GetOwnerAndNameFromIdWithError(id)
+	if err != nil {
+		return nil, err
+	}
+	return getDataset(owner, name)
+}
+
+func UpdateDataset(id string, dataset *Dataset) (bool, error) {
+	owner, name, err := util.GetOwnerAndNameFromIdWithError(id)
+	if err != nil {
+		return false, err
+	}
+	if _, err := getDataset(owner, name); err != nil {
+		return false, err
+	}
+
+	affected, err := adapter.engine.ID(core.PK{owner, name}).AllCols().Update(dataset)
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+func AddDataset(dataset *Dataset) (bool, error) {
+	affected, err := adapter.engine.Insert(dataset)
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+func DeleteDataset(dataset *Dataset) (bool, error) {
+	affected, err := adapter.engine.ID(core.PK{dataset.Owner, dataset.Name}).Delete(&Dataset{})
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+func (dataset *Dataset) GetId() string {
+	return fmt.Sprintf("%s/%s", dataset.Owner, dataset.Name)
+}
+
+var datasetSourceSeparatorRegex = regexp.MustCompile(`[;|\n]+`)
+
+func getDatasetSources(text string) []string {
+	res := []string{}
+	for _, source := range datasetSourceSeparatorRegex.Split(text, -1) {
+		source = strings.TrimSpace(source)
+		if source != "" {
+			res = append(res, source)
+		}
+	}
+	return res
+}
+
+// getDatasetColumn returns the field of the header cell, the headers are matched loosely so that
+// "Expected answer", "expected_answer" and "answer" are all the expected answer.
+func getDatasetColumn(header string) string {
+	header = strings.ToLower(strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.TrimSpace(header)))
+	switch header {
+	case "question", "query":
+		return "question"
+	case "answer", "expectedanswer", "groundtruth":
+		return "expectedAnswer"
+	case "source", "sources", "expectedsource", "expectedsources", "file", "files":
+		return "expectedSources"
+	default:
+		return ""
+	}
+}
+
+func getDatasetItemsFromRows(rows [][]string, lang string) ([]*DatasetItem, error) {
+	if len(rows) == 0 {
+		return nil, fmt.Errorf(i18n.Translate(lang, "object:The dataset file is empty"))
+	}
+
+	columns := map[string]int{}
+	for i, header := range rows[0] {
+		if column := getDatasetColumn(header); column != "" {
+			if _, ok := columns[column]; !ok {
+				columns[column] = i
+			}
+		}
+	}
+	if _, ok := columns["question"]; !ok {
+		return nil, fmt.Errorf(i18n.Translate(lang, "object:The dataset file has no question column"))
+	}
+
+	getCell := func(row []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	items := []*DatasetItem{}
+	for _, row := range rows[1:] {
+		question := getCell(row, "question")
+		if question == "" {
+			continue
+		}
+
+		items = append(items, &DatasetItem{
+			Question:        question,
+			ExpectedAnswer:  getCell(row, "expectedAnswer"),
+			ExpectedSources: getDatasetSources(getCell(row, "expectedSources")),
+		})
+	}
+	return items, nil
+}
+
+func getDatasetItemsFromJson(data []byte, isJsonl bool, lang string) ([]*DatasetItem, error) {
+	items := []*DatasetItem{}
+	if isJsonl {
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" {
+				continue
+			}
+
+			var item DatasetItem
+			err := json.Unmarshal([]byte(line), &item)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, &item)
+		}
+	} else {
+		err := json.Unmarshal(data, &items)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res := []*DatasetItem{}
+	for _, item := range items {
+		if strings.TrimSpace(item.Question) == "" {
+			continue
+		}
+		if item.ExpectedSources == nil {
+			item.ExpectedSources = []string{}
+		}
+		res = append(res, item)
+	}
+	return res, nil
+}
+
+// ParseDatasetItems reads the questions of a dataset file: a CSV or XLSX sheet with a header row
+// naming the question, answer and sources columns (the sources separated by ";" or "|"), or a JSON
+// array or JSON lines of dataset items.
+func ParseDatasetItems(fileName string, data []byte, lang string) ([]*DatasetItem, error) {
+	ext := strings.ToLower(filepath.Ext(fileName))
+	switch ext {
+	case ".csv":
+		reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+		reader.FieldsPerRecord = -1
+		rows, err := reader.ReadAll()
+		if err != nil {
+			return nil, err
+		}
+		return getDatasetItemsFromRows(rows, lang)
+	case ".xlsx":
+		file, err := xlsx.OpenBinary(data)
+		if err != nil {
+			return nil, err
+		}
+		if len(file.Sheets) == 0 {
+			return nil, fmt.Errorf(i18n.Translate(lang, "object:The dataset file is empty"))
+		}
+
+		rows := [][]string{}
+		for _, row := range file.Sheets[0].Rows {
+			line := []string{}
+			for _, cell := range row.Cells {
+				line = append(line, cell.String())
+			}
+			rows = append(rows, line)
+		}
+		return getDatasetItemsFromRows(rows, lang)
+	case ".json":
+		return getDatasetItemsFromJson(data, false, lang)
+	case ".jsonl":
+		return getDatasetItemsFromJson(data, true, lang)
+	default:
+		return nil, fmt.Errorf(i18n.Translate(lang, "object:The dataset file type: %s is not supported, please use CSV, XLSX, JSON or JSONL"), ext)
+	}
+}
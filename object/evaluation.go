@@ -0,0 +1,597 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/beego/beego/logs"
+	"github.com/casibase/casibase/i18n"
+	"github.com/casibase/casibase/model"
+	"github.com/casibase/casibase/util"
+	"xorm.io/core"
+)
+
+const (
+	EvaluationStatePending  = "Pending"
+	EvaluationStateRunning  = "Running"
+	EvaluationStateFinished = "Finished"
+	EvaluationStateError    = "Error"
+)
+
+const defaultEvaluationKnowledgeCount = 5
+
+const evaluationJudgePrompt = `You are evaluating the answer of a retrieval-augmented assistant.
+
+Question:
+%s
+
+Retrieved context:
+%s
+
+Answer:
+%s
+
+Score the answer from 0 to 1 on:
+- faithfulness: the share of the claims of the answer that are supported by the retrieved context, 1 when every claim is supported.
+- relevance: how directly and completely the answer addresses the question, regardless of its correctness.
+Give a one sentence reason.`
+
+var evaluationJudgeSchema = &model.ResponseSchema{
+	Name:        "evaluation_judgement",
+	Description: "The faithfulness and the relevance of an answer",
+	Schema: model.JsonSchema{
+		"type": "object",
+		"properties": model.JsonSchema{
+			"faithfulness": model.JsonSchema{"type": "number", "minimum": 0, "maximum": 1},
+			"relevance":    model.JsonSchema{"type": "number", "minimum": 0, "maximum": 1},
+			"reason":       model.JsonSchema{"type": "string"},
+		},
+		"required": []string{"faithfulness", "relevance", "reason"},
+	},
+}
+
+type evaluationJudgement struct {
+	Faithfulness float64 `json:"faithfulness"`
+	Relevance    float64 `json:"relevance"`
+	Reason       string  `json:"reason"`
+}
+
+// EvaluationResult is the result of a question of the dataset. The retrieval metrics are only
+// computed when the question has expected sources, the exact match and F1 when it has an expected
+// answer, and the faithfulness and relevance when the evaluation has a judge provider.
+type EvaluationResult struct {
+	Question         string   `json:"question"`
+	ExpectedAnswer   string   `json:"expectedAnswer"`
+	ExpectedSources  []string `json:"expectedSources"`
+	Answer           string   `json:"answer"`
+	RetrievedSources []string `json:"retrievedSources"`
+
+	HasRetrieval     bool    `json:"hasRetrieval"`
+	Hit              float64 `json:"hit"`
+	ReciprocalRank   float64 `json:"reciprocalRank"`
+	ContextPrecision float64 `json:"contextPrecision"`
+
+	HasAnswer  bool    `json:"hasAnswer"`
+	ExactMatch float64 `json:"exactMatch"`
+	F1         float64 `json:"f1"`
+
+	IsJudged     bool    `json:"isJudged"`
+	Faithfulness float64 `json:"faithfulness"`
+	Relevance    float64 `json:"relevance"`
+	JudgeReason  string  `json:"judgeReason"`
+
+	Latency    int64   `json:"latency"`
+	TokenCount int     `json:"tokenCount"`
+	Price      float64 `json:"price"`
+	ErrorText  string  `json:"errorText"`
+}
+
+// EvaluationMetrics averages the metrics over the questions they apply to, the latency is in milliseconds.
+type EvaluationMetrics struct {
+	Count      int `json:"count"`
+	ErrorCount int `json:"errorCount"`
+
+	HitRate          float64 `json:"hitRate"`
+	Mrr              float64 `json:"mrr"`
+	ContextPrecision float64 `json:"contextPrecision"`
+	ExactMatch       float64 `json:"exactMatch"`
+	F1               float64 `json:"f1"`
+	Faithfulness     float64 `json:"faithfulness"`
+	Relevance        float64 `json:"relevance"`
+	AverageLatency   int64   `json:"averageLatency"`
+}
+
+// Evaluation is a run of a dataset against a store. The providers left empty are the store's
+// ones, a split or embedding provider differing from the store's re-indexes the store's files
+// into temporary vectors for the run.
+type Evaluation struct {
+	Owner       string `xorm:"varchar(100) notnull pk" json:"owner"`
+	Name        string `xorm:"varchar(100) notnull pk" json:"name"`
+	CreatedTime string `xorm:"varchar(100)" json:"createdTime"`
+	UpdatedTime string `xorm:"varchar(100)" json:"updatedTime"`
+	DisplayName string `xorm:"varchar(200)" json:"displayName"`
+
+	Dataset           string `xorm:"varchar(100)" json:"dataset"`
+	Store             string `xorm:"varchar(100)" json:"store"`
+	SplitProvider     string `xorm:"varchar(100)" json:"splitProvider"`
+	EmbeddingProvider string `xorm:"varchar(100)" json:"embeddingProvider"`
+	SearchProvider    string `xorm:"varchar(100)" json:"searchProvider"`
+	ModelProvider     string `xorm:"varchar(100)" json:"modelProvider"`
+	JudgeProvider     string `xorm:"varchar(100)" json:"judgeProvider"`
+	KnowledgeCount    int    `json:"knowledgeCount"`
+
+	State      string              `xorm:"varchar(100)" json:"state"`
+	Runner     string              `xorm:"varchar(100)" json:"runner"`
+	Progress   int                 `json:"progress"`
+	ErrorText  string              `xorm:"mediumtext" json:"errorText"`
+	Metrics    *EvaluationMetrics  `xorm:"mediumtext" json:"metrics"`
+	Results    []*EvaluationResult `xorm:"mediumtext" json:"results"`
+	TokenCount int                 `json:"tokenCount"`
+	Price      float64             `json:"price"`
+	Currency   string              `xorm:"varchar(100)" json:"currency"`
+}
+
+func GetEvaluationCount(owner, field, value string) (int64, error) {
+	session := GetDbSession(owner, -1, -1, field, value, "", "")
+	return session.Count(&Evaluation{})
+}
+
+func GetEvaluations(owner string) ([]*Evaluation, error) {
+	evaluations := []*Evaluation{}
+	err := adapter.engine.Desc("created_time").Omit("results").Find(&evaluations, &Evaluation{Owner: owner})
+	if err != nil {
+		return evaluations, err
+	}
+	return evaluations, nil
+}
+
+func GetPaginationEvaluations(owner string, offset, limit int, field, value, sortField, sortOrder string) ([]*Evaluation, error) {
+	evaluations := []*Evaluation{}
+	session := GetDbSession(owner, offset, limit, field, value, sortField, sortOrder)
+	err := session.Omit("results").Find(&evaluations)
+	if err != nil {
+		return evaluations, err
+	}
+
+	return evaluations, nil
+}
+
+func getEvaluation(owner string, name string) (*Evaluation, error) {
+	if owner == "" || name == "" {
+		return nil, nil
+	}
+
+	evaluation := Evaluation{Owner: owner, Name: name}
+	existed, err := adapter.engine.Get(&evaluation)
+	if err != nil {
+		return &evaluation, err
+	}
+
+	if existed {
+		return &evaluation, nil
+	} else {
+		return nil, nil
+	}
+}
+
+func GetEvaluation(id string) (*Evaluation, error) {
+	owner, name, err := util.GetOwnerAndNameFromIdWithError(id)
+	if err != nil {
+		return nil, err
+	}
+	return getEvaluation(owner, name)
+}
+
+// UpdateEvaluation updates the settings of the evaluation, its state and results are only
+// changed by its run.
+func UpdateEvaluation(id string, evaluation *Evaluation) (bool, error) {
+	owner, name, err := util.GetOwnerAndNameFromIdWithError(id)
+	if err != nil {
+		return false, err
+	}
+	if _, err := getEvaluation(owner, name); err != nil {
+		return false, err
+	}
+
+	evaluation.UpdatedTime = util.GetCurrentTime()
+	affected, err := adapter.engine.ID(core.PK{owner, name}).AllCols().Omit("state", "runner", "progress", "error_text", "metrics", "results", "token_count", "price", "currency").Update(evaluation)
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+func updateEvaluationRun(evaluation *Evaluation) error {
+	evaluation.UpdatedTime = util.GetCurrentTime()
+	_, err := adapter.engine.ID(core.PK{evaluation.Owner, evaluation.Name}).Cols("updated_time", "state", "runner", "progress", "error_text", "metrics", "results", "token_count", "price", "currency").Update(evaluation)
+	return err
+}
+
+func AddEvaluation(evaluation *Evaluation) (bool, error) {
+	affected, err := adapter.engine.Insert(evaluation)
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+func DeleteEvaluation(evaluation *Evaluation) (bool, error) {
+	affected, err := adapter.engine.ID(core.PK{evaluation.Owner, evaluation.Name}).Delete(&Evaluation{})
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+func (evaluation *Evaluation) GetId() string {
+	return fmt.Sprintf("%s/%s", evaluation.Owner, evaluation.Name)
+}
+
+// InitEvaluations fails the evaluations this instance was running when it stopped, their runs
+// are lost and they can be started again.
+func InitEvaluations() {
+	hostname, err := os.Hostname()
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = adapter.engine.Table(&Evaluation{}).
+		Where("state = ? AND runner = ?", EvaluationStateRunning, hostname).
+		Update(map[string]interface{}{"state": EvaluationStateError, "runner": "", "error_text": "the evaluation was interrupted by a restart"})
+	if err != nil {
+		panic(err)
+	}
+}
+
+// StartEvaluation runs the evaluation in the background, its progress and results are saved
+// after each question.
+func StartEvaluation(evaluation *Evaluation, lang string) error {
+	if evaluation.State == EvaluationStateRunning {
+		return fmt.Errorf(i18n.Translate(lang, "object:The evaluation: %s is already running"), evaluation.GetId())
+	}
+
+	dataset, err := getDataset(evaluation.Owner, evaluation.Dataset)
+	if err != nil {
+		return err
+	}
+	if dataset == nil {
+		return fmt.Errorf(i18n.Translate(lang, "object:The dataset: %s is not found"), evaluation.Dataset)
+	}
+
+	store, err := getStore(evaluation.Owner, evaluation.Store)
+	if err != nil {
+		return err
+	}
+	if store == nil {
+		return fmt.Errorf(i18n.Translate(lang, "account:The store: %s is not found"), evaluation.Store)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return err
+	}
+
+	evaluation.State = EvaluationStateRunning
+	evaluation.Runner = hostname
+	evaluation.Progress = 0
+	evaluation.ErrorText = ""
+	evaluation.Metrics = nil
+	evaluation.Results = []*EvaluationResult{}
+	evaluation.TokenCount = 0
+	evaluation.Price = 0
+	evaluation.Currency = ""
+	evaluation.UpdatedTime = util.GetCurrentTime()
+
+	// the evaluation is started by one request only, the others see it running in the database
+	affected, err := adapter.engine.ID(core.PK{evaluation.Owner, evaluation.Name}).
+		Where("state <> ?", EvaluationStateRunning).
+		Cols("updated_time", "state", "runner", "progress", "error_text", "metrics", "results", "token_count", "price", "currency").
+		Update(evaluation)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf(i18n.Translate(lang, "object:The evaluation: %s is already running"), evaluation.GetId())
+	}
+
+	go func() {
+		err := runEvaluation(evaluation, dataset, store, lang)
+		if err != nil {
+			logs.Error("runEvaluation() error, evaluation: %s, %s", evaluation.GetId(), err.Error())
+			evaluation.State = EvaluationStateError
+			evaluation.ErrorText = err.Error()
+		} else {
+			evaluation.State = EvaluationStateFinished
+		}
+		evaluation.Runner = ""
+
+		err = updateEvaluationRun(evaluation)
+		if err != nil {
+			logs.Error("runEvaluation() error, failed to save evaluation: %s, %s", evaluation.GetId(), err.Error())
+		}
+	}()
+	return nil
+}
+
+func getEvaluationProviderName(name string, defaultName string) string {
+	if name != "" {
+		return name
+	}
+	return defaultName
+}
+
+func runEvaluation(evaluation *Evaluation, dataset *Dataset, store *Store, lang string) error {
+	modelProviderName := getEvaluationProviderName(evaluation.ModelProvider, store.ModelProvider)
+	modelProvider, _, err := GetModelProviderFromContext("admin", modelProviderName, lang)
+	if err != nil {
+		return err
+	}
+
+	embeddingProvider, embeddingProviderObj, err := getEmbeddingProviderFromName("admin", getEvaluationProviderName(evaluation.EmbeddingProvider, store.EmbeddingProvider), lang)
+	if err != nil {
+		return err
+	}
+
+	searchProvider, err := GetSearchProvider(getEvaluationProviderName(evaluation.SearchProvider, store.SearchProvider), "admin")
+	if err != nil {
+		return err
+	}
+
+	// the store's vectors are only valid for its own split and embedding providers
+	relatedStores := append(append([]string{}, store.VectorStores...), store.Name)
+	isReindexed := (evaluation.SplitProvider != "" && evaluation.SplitProvider != store.SplitProvider) ||
+		(evaluation.EmbeddingProvider != "" && evaluation.EmbeddingProvider != store.EmbeddingProvider)
+	if isReindexed {
+		tempStoreName := fmt.Sprintf("%s_eval_%s", store.Name, evaluation.Name)
+		defer func() {
+			_, err := DeleteVectorsByStore(store.Owner, tempStoreName)
+			if err != nil {
+				logs.Error("runEvaluation() error, failed to delete the vectors of: %s, %s", tempStoreName, err.Error())
+			}
+		}()
+
+		err = indexEvaluationStore(store, tempStoreName, getEvaluationProviderName(evaluation.SplitProvider, store.SplitProvider), embeddingProvider, modelProvider, lang)
+		if err != nil {
+			return err
+		}
+		relatedStores = []string{tempStoreName}
+	}
+
+	knowledgeCount := evaluation.KnowledgeCount
+	if knowledgeCount <= 0 {
+		knowledgeCount = defaultEvaluationKnowledgeCount
+	}
+
+	for _, item := range dataset.Items {
+		result := &EvaluationResult{
+			Question:         item.Question,
+			ExpectedAnswer:   item.ExpectedAnswer,
+			ExpectedSources:  item.ExpectedSources,
+			RetrievedSources: []string{},
+		}
+		startTime := time.Now()
+		modelResult := &model.ModelResult{}
+
+		vectors, embeddingResult, err := searchProvider.Search(relatedStores, embeddingProvider.Name, embeddingProviderObj, modelProvider.Name, item.Question, knowledgeCount, lang)
+		if embeddingResult != nil {
+			model.AddModelResults(modelResult, &model.ModelResult{TotalTokenCount: embeddingResult.TokenCount, TotalPrice: embeddingResult.Price, Currency: embeddingResult.Currency})
+		}
+		if err != nil && err.Error() != "no knowledge vectors found" {
+			result.ErrorText = err.Error()
+		}
+
+		knowledge := []*model.RawMessage{}
+		contexts := []string{}
+		for _, vector := range vectors {
+			result.RetrievedSources = append(result.RetrievedSources, vector.File)
+			knowledge = append(knowledge, &model.RawMessage{Text: vector.Text, Author: "System", TextTokenCount: vector.TokenCount})
+			contexts = append(contexts, vector.Text)
+		}
+
+		if len(item.ExpectedSources) > 0 && result.ErrorText == "" {
+			result.HasRetrieval = true
+			result.Hit, result.ReciprocalRank, result.ContextPrecision = getRetrievalMetrics(result.RetrievedSources, item.ExpectedSources)
+		}
+
+		if result.ErrorText == "" {
+			var answerResult *model.ModelResult
+			result.Answer, answerResult, err = GetAnswerWithContext(modelProvider.Name, item.Question, []*model.RawMessage{}, knowledge, store.Prompt, lang)
+			model.AddModelResults(modelResult, answerResult)
+			if err != nil {
+				result.ErrorText = err.Error()
+			}
+		}
+		result.Latency = time.Since(startTime).Milliseconds()
+
+		if result.ErrorText == "" && item.ExpectedAnswer != "" {
+			result.HasAnswer = true
+			result.ExactMatch = getExactMatch(result.Answer, item.ExpectedAnswer)
+			result.F1 = getF1Score(result.Answer, item.ExpectedAnswer)
+		}
+
+		if result.ErrorText == "" && evaluation.JudgeProvider != "" {
+			var judgement evaluationJudgement
+			question := fmt.Sprintf(evaluationJudgePrompt, item.Question, strings.Join(contexts, "\n\n"), result.Answer)
+			judgeResult, err := GetStructuredAnswer(evaluation.JudgeProvider, evaluationJudgeSchema, &judgement, question, lang)
+			model.AddModelResults(modelResult, judgeResult)
+			if err != nil {
+				result.JudgeReason = err.Error()
+			} else {
+				result.IsJudged = true
+				result.Faithfulness = judgement.Faithfulness
+				result.Relevance = judgement.Relevance
+				result.JudgeReason = judgement.Reason
+			}
+		}
+
+		result.TokenCount = modelResult.TotalTokenCount
+		result.Price = model.AddPrices(modelResult.TotalPrice, 0)
+		evaluation.Results = append(evaluation.Results, result)
+		evaluation.Progress = len(evaluation.Results)
+		evaluation.TokenCount += modelResult.TotalTokenCount
+		evaluation.Price = model.AddPrices(evaluation.Price, modelResult.TotalPrice)
+		if evaluation.Currency == "" {
+			evaluation.Currency = modelResult.Currency
+		}
+		evaluation.Metrics = getEvaluationMetrics(evaluation.Results)
+
+		err = updateEvaluationRun(evaluation)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// indexEvaluationStore splits and embeds the files of the store into the vectors of a temporary store.
+func indexEvaluationStore(store *Store, tempStoreName string, splitProviderName string, embeddingProvider *Provider, modelProvider *Provider, lang string) error {
+	storageProviderObj, err := store.GetStorageProviderObj(lang)
+	if err != nil {
+		return err
+	}
+
+	embeddingProviderObj, err := embeddingProvider.GetEmbeddingProvider(lang)
+	if err != nil {
+		return err
+	}
+
+	_, err = DeleteVectorsByStore(store.Owner, tempStoreName)
+	if err != nil {
+		return err
+	}
+
+	_, err = addVectorsForStore(storageProviderObj, embeddingProviderObj, "", store.Owner, tempStoreName, splitProviderName, embeddingProvider.Name, modelProvider.SubType, lang)
+	return err
+}
+
+func getEvaluationMetrics(results []*EvaluationResult) *EvaluationMetrics {
+	metrics := &EvaluationMetrics{Count: len(results)}
+	retrievalCount, answerCount, judgedCount := 0, 0, 0
+	var latency int64
+	for _, result := range results {
+		if result.ErrorText != "" {
+			metrics.ErrorCount++
+			continue
+		}
+		latency += result.Latency
+
+		if result.HasRetrieval {
+			retrievalCount++
+			metrics.HitRate += result.Hit
+			metrics.Mrr += result.ReciprocalRank
+			metrics.ContextPrecision += result.ContextPrecision
+		}
+		if result.HasAnswer {
+			answerCount++
+			metrics.ExactMatch += result.ExactMatch
+			metrics.F1 += result.F1
+		}
+		if result.IsJudged {
+			judgedCount++
+			metrics.Faithfulness += result.Faithfulness
+			metrics.Relevance += result.Relevance
+		}
+	}
+
+	if retrievalCount > 0 {
+		metrics.HitRate /= float64(retrievalCount)
+		metrics.Mrr /= float64(retrievalCount)
+		metrics.ContextPrecision /= float64(retrievalCount)
+	}
+	if answerCount > 0 {
+		metrics.ExactMatch /= float64(answerCount)
+		metrics.F1 /= float64(answerCount)
+	}
+	if judgedCount > 0 {
+		metrics.Faithfulness /= float64(judgedCount)
+		metrics.Relevance /= float64(judgedCount)
+	}
+	if okCount := metrics.Count - metrics.ErrorCount; okCount > 0 {
+		metrics.AverageLatency = latency / int64(okCount)
+	}
+	return metrics
+}
+
+// EvaluationItemDiff is a question whose results differ between two evaluations, one of the
+// results is nil when the question is only in one of them.
+type EvaluationItemDiff struct {
+	Question string            `json:"question"`
+	Base     *EvaluationResult `json:"base"`
+	Target   *EvaluationResult `json:"target"`
+}
+
+// EvaluationDiff compares a target evaluation to a base one, Delta is the target metrics minus the base ones.
+type EvaluationDiff struct {
+	Base   *EvaluationMetrics    `json:"base"`
+	Target *EvaluationMetrics    `json:"target"`
+	Delta  *EvaluationMetrics    `json:"delta"`
+	Items  []*EvaluationItemDiff `json:"items"`
+}
+
+func isEvaluationResultChanged(base *EvaluationResult, target *EvaluationResult) bool {
+	return base.ErrorText != target.ErrorText || base.Hit != target.Hit || base.ReciprocalRank != target.ReciprocalRank ||
+		base.ContextPrecision != target.ContextPrecision || base.ExactMatch != target.ExactMatch || base.F1 != target.F1 ||
+		base.Faithfulness != target.Faithfulness || base.Relevance != target.Relevance
+}
+
+func DiffEvaluations(base *Evaluation, target *Evaluation) *EvaluationDiff {
+	baseMetrics := getEvaluationMetrics(base.Results)
+	targetMetrics := getEvaluationMetrics(target.Results)
+	diff := &EvaluationDiff{
+		Base:   baseMetrics,
+		Target: targetMetrics,
+		Delta: &EvaluationMetrics{
+			Count:            targetMetrics.Count - baseMetrics.Count,
+			ErrorCount:       targetMetrics.ErrorCount - baseMetrics.ErrorCount,
+			HitRate:          targetMetrics.HitRate - baseMetrics.HitRate,
+			Mrr:              targetMetrics.Mrr - baseMetrics.Mrr,
+			ContextPrecision: targetMetrics.ContextPrecision - baseMetrics.ContextPrecision,
+			ExactMatch:       targetMetrics.ExactMatch - baseMetrics.ExactMatch,
+			F1:               targetMetrics.F1 - baseMetrics.F1,
+			Faithfulness:     targetMetrics.Faithfulness - baseMetrics.Faithfulness,
+			Relevance:        targetMetrics.Relevance - baseMetrics.Relevance,
+			AverageLatency:   targetMetrics.AverageLatency - baseMetrics.AverageLatency,
+		},
+		Items: []*EvaluationItemDiff{},
+	}
+
+	targetResults := map[string]*EvaluationResult{}
+	for _, result := range target.Results {
+		targetResults[result.Question] = result
+	}
+
+	questions := map[string]bool{}
+	for _, baseResult := range base.Results {
+		questions[baseResult.Question] = true
+		targetResult := targetResults[baseResult.Question]
+		if targetResult == nil || isEvaluationResultChanged(baseResult, targetResult) {
+			diff.Items = append(diff.Items, &EvaluationItemDiff{Question: baseResult.Question, Base: baseResult, Target: targetResult})
+		}
+	}
+	for _, targetResult := range target.Results {
+		if !questions[targetResult.Question] {
+			diff.Items = append(diff.Items, &EvaluationItemDiff{Question: targetResult.Question, Target: targetResult})
+		}
+	}
+	return diff
+}
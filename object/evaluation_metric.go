@@ -0,0 +1,134 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"path"
+	"strings"
+	"unicode"
+)
+
+// isSourceMatched tells whether the retrieved file is the expected source, the source may be
+// given as the file name or as a path suffix of the file.
+func isSourceMatched(file string, source string) bool {
+	file = strings.ToLower(strings.Trim(file, "/"))
+	source = strings.ToLower(strings.Trim(source, "/"))
+	if file == "" || source == "" {
+		return false
+	}
+	return file == source || strings.HasSuffix(file, "/"+source) || path.Base(file) == source
+}
+
+func isRelevantFile(file string, sources []string) bool {
+	for _, source := range sources {
+		if isSourceMatched(file, source) {
+			return true
+		}
+	}
+	return false
+}
+
+// getRetrievalMetrics computes the metrics of the files of the retrieved chunks in rank order:
+// hit@k is 1 when a chunk of an expected source is retrieved, the reciprocal rank is the one of
+// the first such chunk, and the context precision averages the precision@i over the ranks i of
+// the relevant chunks, so that relevant chunks ranked first score higher.
+func getRetrievalMetrics(files []string, sources []string) (float64, float64, float64) {
+	hit, reciprocalRank, precisionSum := 0.0, 0.0, 0.0
+	relevantCount := 0
+	for i, file := range files {
+		if !isRelevantFile(file, sources) {
+			continue
+		}
+
+		relevantCount++
+		if hit == 0 {
+			hit = 1
+			reciprocalRank = 1 / float64(i+1)
+		}
+		precisionSum += float64(relevantCount) / float64(i+1)
+	}
+
+	if relevantCount == 0 {
+		return 0, 0, 0
+	}
+	return hit, reciprocalRank, precisionSum / float64(relevantCount)
+}
+
+// getAnswerTokens splits the normalized answer into words, each CJK character being a word.
+func getAnswerTokens(text string) []string {
+	tokens := []string{}
+	word := []rune{}
+	flushWord := func() {
+		if len(word) > 0 {
+			tokens = append(tokens, string(word))
+			word = []rune{}
+		}
+	}
+
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r):
+			flushWord()
+			tokens = append(tokens, string(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			word = append(word, r)
+		default:
+			flushWord()
+		}
+	}
+	flushWord()
+	return tokens
+}
+
+// getExactMatch is 1 when the answers have the same words, ignoring the case and the punctuation.
+func getExactMatch(answer string, expectedAnswer string) float64 {
+	if strings.Join(getAnswerTokens(answer), " ") == strings.Join(getAnswerTokens(expectedAnswer), " ") {
+		return 1
+	}
+	return 0
+}
+
+// getF1Score is the harmonic mean of the precision and the recall of the words of the answer
+// against the words of the expected answer.
+func getF1Score(answer string, expectedAnswer string) float64 {
+	answerTokens := getAnswerTokens(answer)
+	expectedTokens := getAnswerTokens(expectedAnswer)
+	if len(answerTokens) == 0 || len(expectedTokens) == 0 {
+		if len(answerTokens) == len(expectedTokens) {
+			return 1
+		}
+		return 0
+	}
+
+	expectedCounts := map[string]int{}
+	for _, token := range expectedTokens {
+		expectedCounts[token]++
+	}
+
+	commonCount := 0
+	for _, token := range answerTokens {
+		if expectedCounts[token] > 0 {
+			expectedCounts[token]--
+			commonCount++
+		}
+	}
+	if commonCount == 0 {
+		return 0
+	}
+
+	precision := float64(commonCount) / float64(len(answerTokens))
+	recall := float64(commonCount) / float64(len(expectedTokens))
+	return 2 * precision * recall / (precision + recall)
+}
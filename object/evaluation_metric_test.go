@@ -0,0 +1,86 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build skipCi
+// +build skipCi
+
+package object
+
+import "testing"
+
+func TestGetRetrievalMetrics(t *testing.T) {
+	files := []string{"docs/intro.md", "papers/attention.pdf", "docs/faq.md", "papers/Attention.pdf"}
+
+	hit, reciprocalRank, contextPrecision := getRetrievalMetrics(files, []string{"attention.pdf"})
+	if hit != 1 || !isAlmostEqual(reciprocalRank, 0.5) {
+		t.Errorf("got hit %v and reciprocal rank %v, want 1 and 0.5", hit, reciprocalRank)
+	}
+	// the relevant chunks are ranked 2 and 4: (1/2 + 2/4) / 2
+	if !isAlmostEqual(contextPrecision, 0.5) {
+		t.Errorf("got context precision %v, want 0.5", contextPrecision)
+	}
+
+	hit, reciprocalRank, contextPrecision = getRetrievalMetrics(files, []string{"missing.pdf"})
+	if hit != 0 || reciprocalRank != 0 || contextPrecision != 0 {
+		t.Errorf("got %v, %v, %v for a missing source, want zeros", hit, reciprocalRank, contextPrecision)
+	}
+
+	if isSourceMatched("docs/intro.md", "intro") || !isSourceMatched("/docs/intro.md", "docs/intro.md") {
+		t.Errorf("isSourceMatched() matches partial names or misses path suffixes")
+	}
+}
+
+func TestGetAnswerMetrics(t *testing.T) {
+	if getExactMatch("The Transformer.", "the transformer") != 1 {
+		t.Errorf("getExactMatch() should ignore the case and the punctuation")
+	}
+	if getExactMatch("The Transformer model", "the transformer") != 0 {
+		t.Errorf("getExactMatch() should not match a longer answer")
+	}
+
+	// 2 common words out of 4 and 2: precision 0.5, recall 1
+	if f1 := getF1Score("it is the transformer", "the transformer"); !isAlmostEqual(f1, 2.0/3) {
+		t.Errorf("got F1 %v, want 2/3", f1)
+	}
+	if f1 := getF1Score("注意力机制", "注意力"); !isAlmostEqual(f1, 2*0.6/1.6) {
+		t.Errorf("got F1 %v for CJK text, want 0.75", f1)
+	}
+	if f1 := getF1Score("", "the transformer"); f1 != 0 {
+		t.Errorf("got F1 %v for an empty answer, want 0", f1)
+	}
+}
+
+func TestGetEvaluationMetrics(t *testing.T) {
+	results := []*EvaluationResult{
+		{Question: "a", HasRetrieval: true, Hit: 1, ReciprocalRank: 1, ContextPrecision: 1, HasAnswer: true, F1: 0.5, Latency: 100},
+		{Question: "b", HasRetrieval: true, Hit: 0, HasAnswer: false, Latency: 300},
+		{Question: "c", ErrorText: "timeout"},
+	}
+
+	metrics := getEvaluationMetrics(results)
+	if metrics.Count != 3 || metrics.ErrorCount != 1 {
+		t.Errorf("got count %d and error count %d, want 3 and 1", metrics.Count, metrics.ErrorCount)
+	}
+	if !isAlmostEqual(metrics.HitRate, 0.5) || !isAlmostEqual(metrics.F1, 0.5) || metrics.AverageLatency != 200 {
+		t.Errorf("got hit rate %v, F1 %v and latency %d, want 0.5, 0.5 and 200", metrics.HitRate, metrics.F1, metrics.AverageLatency)
+	}
+
+	diff := DiffEvaluations(&Evaluation{Results: results}, &Evaluation{Results: results[:2]})
+	if len(diff.Items) != 1 || diff.Items[0].Question != "c" || diff.Items[0].Target != nil {
+		t.Errorf("got %d diff items, want only the question missing from the target", len(diff.Items))
+	}
+	if diff.Delta.ErrorCount != -1 {
+		t.Errorf("got error count delta %d, want -1", diff.Delta.ErrorCount)
+	}
+}
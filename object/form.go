@@ -22,12 +22,37 @@ import (
 	"xorm.io/core"
 )
 
+// FormCategoryForm is the category of the forms that collect submissions, whose items are the fields to fill in.
+const FormCategoryForm = "Form"
+
+const (
+	FormItemTypeText           = "Text"
+	FormItemTypeTextArea       = "TextArea"
+	FormItemTypeNumber         = "Number"
+	FormItemTypeEmail          = "Email"
+	FormItemTypeDate           = "Date"
+	FormItemTypeSelect         = "Select"
+	FormItemTypeMultipleSelect = "Multiple Select"
+	FormItemTypeCheckbox       = "Checkbox"
+	FormItemTypeFile           = "File"
+)
+
 type FormItem struct {
 	Name    string `json:"name"`
 	Label   string `json:"label"`
 	Type    string `json:"type"`
 	Visible bool   `json:"visible"`
 	Width   string `json:"width"`
+
+	// The validation rules of the fields of the forms of FormCategoryForm
+	Required    bool     `json:"required,omitempty"`
+	Regex       string   `json:"regex,omitempty"`
+	MinValue    *float64 `json:"minValue,omitempty"`
+	MaxValue    *float64 `json:"maxValue,omitempty"`
+	MaxLength   int      `json:"maxLength,omitempty"`
+	Options     []string `json:"options,omitempty"`
+	FileTypes   []string `json:"fileTypes,omitempty"`
+	MaxFileSize int      `json:"maxFileSize,omitempty"` // in MB
 }
 
 type Form struct {
@@ -42,7 +67,11 @@ type Form struct {
 	Tag         string `xorm:"varchar(100)" json:"tag"`
 	Url         string `xorm:"varchar(100)" json:"url"`
 
-	FormItems []*FormItem `xorm:"varchar(5000)" json:"formItems"`
+	FormItems []*FormItem `xorm:"mediumtext" json:"formItems"`
+
+	IsPublic bool   `json:"isPublic"`
+	Store    string `xorm:"varchar(100)" json:"store"`
+	Workflow string `xorm:"varchar(100)" json:"workflow"`
 }
 
 func GetMaskedForm(form *Form, isMaskEnabled bool) *Form {
@@ -126,6 +155,11 @@ func UpdateForm(id string, form *Form, lang string) (bool, error) {
 		return false, nil
 	}
 
+	err = ValidateForm(form, lang)
+	if err != nil {
+		return false, err
+	}
+
 	_, err = adapter.engine.ID(core.PK{owner, name}).AllCols().Update(form)
 	if err != nil {
 		return false, err
@@ -135,7 +169,12 @@ func UpdateForm(id string, form *Form, lang string) (bool, error) {
 	return true, nil
 }
 
-func AddForm(form *Form) (bool, error) {
+func AddForm(form *Form, lang string) (bool, error) {
+	err := ValidateForm(form, lang)
+	if err != nil {
+		return false, err
+	}
+
 	affected, err := adapter.engine.Insert(form)
 	if err != nil {
 		return false, err
@@ -0,0 +1,82 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"bytes"
+	"encoding/csv"
+
+	"github.com/casibase/casibase/i18n"
+	"github.com/tealeg/xlsx"
+)
+
+// getFormSubmissionRows returns the header and one row per submission, with a column per field of the form.
+func getFormSubmissionRows(form *Form, submissions []*FormSubmission, lang string) [][]string {
+	header := []string{
+		i18n.Translate(lang, "object:Name"),
+		i18n.Translate(lang, "object:Created time"),
+		i18n.Translate(lang, "object:User"),
+		i18n.Translate(lang, "object:Client IP"),
+	}
+	for _, item := range form.FormItems {
+		header = append(header, item.getLabel())
+	}
+	header = append(header, i18n.Translate(lang, "object:Workflow instance"), i18n.Translate(lang, "object:Error"))
+
+	rows := [][]string{header}
+	for _, submission := range submissions {
+		row := []string{submission.Name, submission.CreatedTime, submission.User, submission.ClientIp}
+		for _, item := range form.FormItems {
+			row = append(row, formatFormValue(submission.Values[item.Name]))
+		}
+		row = append(row, submission.WorkflowInstance, submission.ErrorText)
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// ExportFormSubmissionsCsv writes the submissions of the form to a CSV file.
+func ExportFormSubmissionsCsv(form *Form, submissions []*FormSubmission, lang string) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	err := writer.WriteAll(getFormSubmissionRows(form, submissions, lang))
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// ExportFormSubmissionsXlsx writes the submissions of the form to an XLSX workbook.
+func ExportFormSubmissionsXlsx(form *Form, submissions []*FormSubmission, lang string) ([]byte, error) {
+	file := xlsx.NewFile()
+	sheet, err := file.AddSheet(i18n.Translate(lang, "object:Submissions"))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, values := range getFormSubmissionRows(form, submissions, lang) {
+		row := sheet.AddRow()
+		for _, value := range values {
+			row.AddCell().SetString(value)
+		}
+	}
+
+	var buffer bytes.Buffer
+	err = file.Write(&buffer)
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,297 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/casibase/casibase/i18n"
+	"github.com/casibase/casibase/util"
+	"xorm.io/core"
+)
+
+// the anonymous submissions of a client IP address are limited to formSubmissionLimit per
+// formSubmissionLimitMinutes, they upload files and embed knowledge at the expense of the owner
+const (
+	formSubmissionLimit        = 10
+	formSubmissionLimitMinutes = 60
+)
+
+type FormSubmission struct {
+	Owner       string `xorm:"varchar(100) notnull pk" json:"owner"`
+	Name        string `xorm:"varchar(100) notnull pk" json:"name"`
+	CreatedTime string `xorm:"varchar(100)" json:"createdTime"`
+
+	Form     string                 `xorm:"varchar(100) index" json:"form"`
+	User     string                 `xorm:"varchar(100)" json:"user"`
+	ClientIp string                 `xorm:"varchar(100) index" json:"clientIp"`
+	Values   map[string]interface{} `xorm:"mediumtext" json:"values"`
+
+	Store            string `xorm:"varchar(100)" json:"store"`
+	WorkflowInstance string `xorm:"varchar(100)" json:"workflowInstance"`
+	ErrorText        string `xorm:"mediumtext" json:"errorText"`
+}
+
+func GetFormSubmissions(owner string, form string) ([]*FormSubmission, error) {
+	submissions := []*FormSubmission{}
+	err := adapter.engine.Desc("created_time").Find(&submissions, &FormSubmission{Owner: owner, Form: form})
+	if err != nil {
+		return submissions, err
+	}
+
+	return submissions, nil
+}
+
+func getNearFormSubmissionCount(clientIp string, limitMinutes int) (int, error) {
+	sinceTime := time.Now().Add(-time.Minute * time.Duration(limitMinutes)).Format(time.RFC3339)
+	count, err := adapter.engine.Where("created_time >= ?", sinceTime).Count(&FormSubmission{ClientIp: clientIp})
+	if err != nil {
+		return -1, err
+	}
+	return int(count), nil
+}
+
+func getFormSubmission(owner string, name string) (*FormSubmission, error) {
+	submission := FormSubmission{Owner: owner, Name: name}
+	existed, err := adapter.engine.Get(&submission)
+	if err != nil {
+		return &submission, err
+	}
+
+	if existed {
+		return &submission, nil
+	} else {
+		return nil, nil
+	}
+}
+
+func GetFormSubmission(id string) (*FormSubmission, error) {
+	owner, name, err := util.GetOwnerAndNameFromIdWithError(id)
+	if err != nil {
+		return nil, err
+	}
+	return getFormSubmission(owner, name)
+}
+
+func DeleteFormSubmission(submission *FormSubmission) (bool, error) {
+	if submission.Store != "" {
+		_, err := DeleteVectorsByFile("admin", submission.Store, submission.getKnowledgeFileName())
+		if err != nil {
+			return false, err
+		}
+	}
+
+	affected, err := adapter.engine.ID(core.PK{submission.Owner, submission.Name}).Delete(&FormSubmission{})
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+func (submission *FormSubmission) GetId() string {
+	return fmt.Sprintf("%s/%s", submission.Owner, submission.Name)
+}
+
+func (submission *FormSubmission) getKnowledgeFileName() string {
+	return fmt.Sprintf("form_submission_%s", submission.Name)
+}
+
+func GetFormSubmissionCount(owner string, form string, field, value string) (int64, error) {
+	session := GetDbSession(owner, -1, -1, field, value, "", "")
+	return session.Count(&FormSubmission{Form: form})
+}
+
+func GetPaginationFormSubmissions(owner string, form string, offset, limit int, field, value, sortField, sortOrder string) ([]*FormSubmission, error) {
+	submissions := []*FormSubmission{}
+	session := GetDbSession(owner, offset, limit, field, value, sortField, sortOrder)
+	err := session.Find(&submissions, &FormSubmission{Form: form})
+	if err != nil {
+		return submissions, err
+	}
+
+	return submissions, nil
+}
+
+func formatFormValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []string:
+		return strings.Join(v, ", ")
+	case []interface{}:
+		values := []string{}
+		for _, item := range v {
+			values = append(values, fmt.Sprintf("%v", item))
+		}
+		return strings.Join(values, ", ")
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// getKnowledgeText returns the submission as the text of a knowledge vector, one "label: value" line per field.
+func (submission *FormSubmission) getKnowledgeText(form *Form) string {
+	lines := []string{}
+	if form.DisplayName != "" {
+		lines = append(lines, form.DisplayName)
+	}
+	for _, item := range form.FormItems {
+		value, ok := submission.Values[item.Name]
+		if !ok {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", item.getLabel(), formatFormValue(value)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func addFormSubmissionKnowledge(form *Form, submission *FormSubmission, lang string) error {
+	store, err := getStore("admin", form.Store)
+	if err != nil {
+		return err
+	}
+	if store == nil {
+		return fmt.Errorf(i18n.Translate(lang, "account:The store: %s is not found"), form.Store)
+	}
+
+	modelProvider, err := store.GetModelProvider()
+	if err != nil {
+		return err
+	}
+	if modelProvider == nil {
+		return fmt.Errorf(i18n.Translate(lang, "object:The model provider for store: %s is not found"), store.GetId())
+	}
+
+	embeddingProvider, err := store.GetEmbeddingProvider()
+	if err != nil {
+		return err
+	}
+	if embeddingProvider == nil {
+		return fmt.Errorf(i18n.Translate(lang, "object:The embedding provider for store: %s is not found"), store.GetId())
+	}
+
+	embeddingProviderObj, err := embeddingProvider.GetEmbeddingProvider(lang)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = addEmbeddedVector(embeddingProviderObj, submission.getKnowledgeText(form), store.Name, submission.getKnowledgeFileName(), 0, embeddingProvider.Name, modelProvider.SubType, lang)
+	if err != nil {
+		return err
+	}
+
+	submission.Store = store.Name
+	return nil
+}
+
+func startFormSubmissionWorkflow(form *Form, submission *FormSubmission, lang string) error {
+	workflow, err := getWorkflow(form.Owner, form.Workflow)
+	if err != nil {
+		return err
+	}
+	if workflow == nil {
+		return fmt.Errorf(i18n.Translate(lang, "object:The workflow: %s is not found"), util.GetId(form.Owner, form.Workflow))
+	}
+	if !CanStartWorkflow(workflow, submission.User, false) {
+		return fmt.Errorf(i18n.Translate(lang, "object:You are not allowed to start the workflow: %s"), workflow.GetId())
+	}
+
+	variables := map[string]interface{}{}
+	for key, value := range submission.Values {
+		variables[key] = value
+	}
+	variables["form"] = form.Name
+	variables["formSubmission"] = submission.Name
+
+	instance, err := StartWorkflowInstance(workflow, submission.User, variables, lang)
+	if err != nil {
+		return err
+	}
+
+	submission.WorkflowInstance = instance.Name
+	return nil
+}
+
+// SubmitForm validates the values against the fields of the form, uploads the files of the file
+// fields and stores the submission. The submission is then added as knowledge to the store of the
+// form and starts an instance of the workflow of the form, if any. A failure of these follow-ups
+// does not reject the submission but is kept in its error text. The anonymous submissions are
+// throttled by client IP address before anything is uploaded.
+func SubmitForm(form *Form, user string, clientIp string, values map[string]interface{}, lang string) (*FormSubmission, error) {
+	if form.Category != FormCategoryForm {
+		return nil, fmt.Errorf(i18n.Translate(lang, "object:The form: %s does not accept submissions"), form.GetId())
+	}
+
+	if user == "" {
+		count, err := getNearFormSubmissionCount(clientIp, formSubmissionLimitMinutes)
+		if err != nil {
+			return nil, err
+		}
+		if count >= formSubmissionLimit {
+			return nil, fmt.Errorf(i18n.Translate(lang, "object:You have submitted too many times, please wait for a while"))
+		}
+	}
+
+	res, files, err := ValidateFormValues(form, values, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	submission := &FormSubmission{
+		Owner:       form.Owner,
+		Name:        fmt.Sprintf("%s_%s", form.Name, util.GetRandomName()),
+		CreatedTime: util.GetCurrentTime(),
+		Form:        form.Name,
+		User:        user,
+		ClientIp:    clientIp,
+		Values:      res,
+	}
+
+	for name, file := range files {
+		uploader := user
+		if uploader == "" {
+			uploader = form.Owner
+		}
+		filePath := fmt.Sprintf("casibase/form-submissions/%s/%s/%s", form.Name, submission.Name, file.Name)
+		fileUrl, err := UploadFileToStorageSafe(uploader, "file", "SubmitForm", filePath, file.Data)
+		if err != nil {
+			return nil, err
+		}
+		submission.Values[name] = fileUrl
+	}
+
+	errorTexts := []string{}
+	if form.Store != "" {
+		err = addFormSubmissionKnowledge(form, submission, lang)
+		if err != nil {
+			errorTexts = append(errorTexts, err.Error())
+		}
+	}
+	if form.Workflow != "" {
+		err = startFormSubmissionWorkflow(form, submission, lang)
+		if err != nil {
+			errorTexts = append(errorTexts, err.Error())
+		}
+	}
+	submission.ErrorText = strings.Join(errorTexts, "\n")
+
+	_, err = adapter.engine.Insert(submission)
+	if err != nil {
+		return nil, err
+	}
+	return submission, nil
+}
@@ -0,0 +1,142 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build skipCi
+// +build skipCi
+
+package object
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func newTestForm() *Form {
+	minAge := 18.0
+	maxAge := 99.0
+	return &Form{
+		Owner:    "admin",
+		Name:     "form_1",
+		Category: FormCategoryForm,
+		FormItems: []*FormItem{
+			{Name: "name", Label: "Name", Type: FormItemTypeText, Required: true, MaxLength: 10},
+			{Name: "phone", Label: "Phone", Type: FormItemTypeText, Regex: `^[0-9]{11}$`},
+			{Name: "age", Label: "Age", Type: FormItemTypeNumber, MinValue: &minAge, MaxValue: &maxAge},
+			{Name: "email", Label: "Email", Type: FormItemTypeEmail},
+			{Name: "level", Label: "Level", Type: FormItemTypeSelect, Options: []string{"Junior", "Senior"}},
+			{Name: "topics", Label: "Topics", Type: FormItemTypeMultipleSelect, Options: []string{"AI", "Cloud"}},
+			{Name: "resume", Label: "Resume", Type: FormItemTypeFile, FileTypes: []string{"pdf"}, MaxFileSize: 1},
+		},
+	}
+}
+
+func TestValidateForm(t *testing.T) {
+	form := newTestForm()
+	if err := ValidateForm(form, "en"); err != nil {
+		t.Errorf("unexpected error for a valid form: %v", err)
+	}
+
+	form.FormItems[1].Regex = "[0-9"
+	if err := ValidateForm(form, "en"); err == nil {
+		t.Errorf("expected an error for an invalid regex")
+	}
+
+	form = newTestForm()
+	form.FormItems[4].Options = nil
+	if err := ValidateForm(form, "en"); err == nil {
+		t.Errorf("expected an error for a select field without options")
+	}
+}
+
+func TestValidateFormValues(t *testing.T) {
+	form := newTestForm()
+	fileData := "data:application/pdf;base64," + base64.StdEncoding.EncodeToString([]byte("%PDF-1.4"))
+	values := map[string]interface{}{
+		"name":    " Alice ",
+		"phone":   "13800000000",
+		"age":     "30",
+		"email":   "alice@example.com",
+		"level":   "Senior",
+		"topics":  []interface{}{"AI"},
+		"resume":  map[string]interface{}{"name": "resume.pdf", "data": fileData},
+		"unknown": "dropped",
+	}
+	res, files, err := ValidateFormValues(form, values, "en")
+	if err != nil {
+		t.Fatalf("unexpected error for valid values: %v", err)
+	}
+	if res["name"] != "Alice" || res["age"] != 30.0 || res["unknown"] != nil {
+		t.Errorf("expected the values to be normalized, got: %v", res)
+	}
+	if files["resume"] == nil || string(files["resume"].Data) != "%PDF-1.4" {
+		t.Errorf("expected the file to be decoded, got: %v", files)
+	}
+
+	invalids := []map[string]interface{}{
+		{},
+		{"name": "A name longer than ten"},
+		{"name": "Alice", "phone": "123"},
+		{"name": "Alice", "age": 12.0},
+		{"name": "Alice", "email": "alice"},
+		{"name": "Alice", "level": "Lead"},
+		{"name": "Alice", "topics": []interface{}{"AI", "Mobile"}},
+		{"name": "Alice", "resume": map[string]interface{}{"name": "resume.exe", "data": fileData}},
+	}
+	for _, invalid := range invalids {
+		if _, _, err := ValidateFormValues(form, invalid, "en"); err == nil {
+			t.Errorf("expected an error for the values: %v", invalid)
+		}
+	}
+}
+
+func TestValidateFormValuesDefaultLimits(t *testing.T) {
+	form := &Form{
+		Category: FormCategoryForm,
+		FormItems: []*FormItem{
+			{Name: "comment", Label: "Comment", Type: FormItemTypeTextArea},
+			{Name: "attachment", Label: "Attachment", Type: FormItemTypeFile},
+		},
+	}
+
+	comment := strings.Repeat("a", defaultFormMaxLength)
+	if _, _, err := ValidateFormValues(form, map[string]interface{}{"comment": comment}, "en"); err != nil {
+		t.Errorf("unexpected error for a value at the default limit: %v", err)
+	}
+	if _, _, err := ValidateFormValues(form, map[string]interface{}{"comment": comment + "a"}, "en"); err == nil {
+		t.Errorf("expected an error for a value over the default limit")
+	}
+
+	fileData := "data:text/plain;base64," + base64.StdEncoding.EncodeToString(make([]byte, defaultFormMaxFileSize*1024*1024+1))
+	values := map[string]interface{}{"attachment": map[string]interface{}{"name": "attachment.txt", "data": fileData}}
+	if _, _, err := ValidateFormValues(form, values, "en"); err == nil {
+		t.Errorf("expected an error for a file over the default limit")
+	}
+}
+
+func TestExportFormSubmissionsCsv(t *testing.T) {
+	form := newTestForm()
+	submissions := []*FormSubmission{
+		{Name: "form_1_abc", User: "alice", Values: map[string]interface{}{"name": "Alice", "topics": []interface{}{"AI", "Cloud"}}},
+	}
+	data, err := ExportFormSubmissionsCsv(form, submissions, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "Resume") || !strings.Contains(lines[1], `"AI, Cloud"`) {
+		t.Errorf("unexpected CSV export:\n%s", data)
+	}
+}
@@ -0,0 +1,290 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/mail"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/casibase/casibase/i18n"
+)
+
+// The limits of the fields leaving them unset, as the public forms are open to anonymous submitters.
+const (
+	defaultFormMaxLength   = 10000
+	defaultFormMaxFileSize = 10 // in MB
+)
+
+// FormFile is a file uploaded to a field of type FormItemTypeFile, sent as {"name": ..., "data": "data:...;base64,..."}.
+type FormFile struct {
+	Name string
+	Data []byte
+}
+
+func (item *FormItem) getLabel() string {
+	if item.Label != "" {
+		return item.Label
+	}
+	return item.Name
+}
+
+func (item *FormItem) getMaxLength() int {
+	if item.MaxLength > 0 {
+		return item.MaxLength
+	}
+	return defaultFormMaxLength
+}
+
+func (item *FormItem) getMaxFileSize() int {
+	if item.MaxFileSize > 0 {
+		return item.MaxFileSize
+	}
+	return defaultFormMaxFileSize
+}
+
+func (item *FormItem) isFileTypeAllowed(fileName string) bool {
+	if len(item.FileTypes) == 0 {
+		return true
+	}
+
+	ext := strings.ToLower(filepath.Ext(fileName))
+	for _, fileType := range item.FileTypes {
+		fileType = strings.ToLower(strings.TrimSpace(fileType))
+		if !strings.HasPrefix(fileType, ".") {
+			fileType = "." + fileType
+		}
+		if ext == fileType {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFormOption(options []string, value string) bool {
+	for _, option := range options {
+		if option == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateForm checks the fields of a form of FormCategoryForm: the names are unique, the
+// regexes compile, the ranges are not empty and the select fields have options.
+func ValidateForm(form *Form, lang string) error {
+	if form.Category != FormCategoryForm {
+		return nil
+	}
+
+	names := map[string]bool{}
+	for _, item := range form.FormItems {
+		if item.Name == "" || names[item.Name] {
+			return fmt.Errorf(i18n.Translate(lang, "object:The field name: %s of the form is empty or duplicated"), item.Name)
+		}
+		names[item.Name] = true
+
+		switch item.Type {
+		case FormItemTypeText, FormItemTypeTextArea, FormItemTypeNumber, FormItemTypeEmail, FormItemTypeDate, FormItemTypeCheckbox, FormItemTypeFile:
+		case FormItemTypeSelect, FormItemTypeMultipleSelect:
+			if len(item.Options) == 0 {
+				return fmt.Errorf(i18n.Translate(lang, "object:The field: %s has no options"), item.getLabel())
+			}
+		default:
+			return fmt.Errorf(i18n.Translate(lang, "object:The field type: %s is not supported"), item.Type)
+		}
+
+		if item.Regex != "" {
+			_, err := regexp.Compile(item.Regex)
+			if err != nil {
+				return fmt.Errorf(i18n.Translate(lang, "object:The regex of the field: %s is invalid: %s"), item.getLabel(), err.Error())
+			}
+		}
+		if item.MinValue != nil && item.MaxValue != nil && *item.MinValue > *item.MaxValue {
+			return fmt.Errorf(i18n.Translate(lang, "object:The minimum value of the field: %s is greater than its maximum value"), item.getLabel())
+		}
+	}
+	return nil
+}
+
+func isFormValueEmpty(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []interface{}:
+		return len(v) == 0
+	case bool:
+		return !v
+	case map[string]interface{}:
+		return v["data"] == nil || v["data"] == ""
+	}
+	return false
+}
+
+func getFormFile(item *FormItem, value interface{}, lang string) (*FormFile, error) {
+	fileValue, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf(i18n.Translate(lang, "object:The value of the field: %s is invalid"), item.getLabel())
+	}
+	name, _ := fileValue["name"].(string)
+	data, _ := fileValue["data"].(string)
+	index := strings.Index(data, ",")
+	if name == "" || index == -1 {
+		return nil, fmt.Errorf(i18n.Translate(lang, "object:The value of the field: %s is invalid"), item.getLabel())
+	}
+
+	if !item.isFileTypeAllowed(name) {
+		return nil, fmt.Errorf(i18n.Translate(lang, "object:The file type of: %s is not allowed for the field: %s"), name, item.getLabel())
+	}
+
+	fileBytes, err := base64.StdEncoding.DecodeString(data[index+1:])
+	if err != nil {
+		return nil, fmt.Errorf(i18n.Translate(lang, "object:The value of the field: %s is invalid"), item.getLabel())
+	}
+	maxFileSize := item.getMaxFileSize()
+	if len(fileBytes) > maxFileSize*1024*1024 {
+		return nil, fmt.Errorf(i18n.Translate(lang, "object:The file: %s is larger than %d MB"), name, maxFileSize)
+	}
+
+	return &FormFile{Name: filepath.Base(name), Data: fileBytes}, nil
+}
+
+func getFormNumber(item *FormItem, value interface{}, lang string) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case string:
+		number, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err == nil {
+			return number, nil
+		}
+	}
+	return 0, fmt.Errorf(i18n.Translate(lang, "object:The value of the field: %s should be a number"), item.getLabel())
+}
+
+func validateFormText(item *FormItem, text string, lang string) error {
+	maxLength := item.getMaxLength()
+	if utf8.RuneCountInString(text) > maxLength {
+		return fmt.Errorf(i18n.Translate(lang, "object:The value of the field: %s should not be longer than %d characters"), item.getLabel(), maxLength)
+	}
+	if item.Regex != "" {
+		re, err := regexp.Compile(item.Regex)
+		if err != nil {
+			return err
+		}
+		if !re.MatchString(text) {
+			return fmt.Errorf(i18n.Translate(lang, "object:The value of the field: %s does not match the required format"), item.getLabel())
+		}
+	}
+	return nil
+}
+
+// validateFormValue checks the value of a non-empty field and returns it normalized: trimmed
+// texts, numbers as float64 and the files decoded.
+func validateFormValue(item *FormItem, value interface{}, lang string) (interface{}, error) {
+	switch item.Type {
+	case FormItemTypeNumber:
+		number, err := getFormNumber(item, value, lang)
+		if err != nil {
+			return nil, err
+		}
+		if (item.MinValue != nil && number < *item.MinValue) || (item.MaxValue != nil && number > *item.MaxValue) {
+			return nil, fmt.Errorf(i18n.Translate(lang, "object:The value of the field: %s is out of its range"), item.getLabel())
+		}
+		return number, nil
+	case FormItemTypeCheckbox:
+		checked, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf(i18n.Translate(lang, "object:The value of the field: %s is invalid"), item.getLabel())
+		}
+		return checked, nil
+	case FormItemTypeMultipleSelect:
+		values, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf(i18n.Translate(lang, "object:The value of the field: %s is invalid"), item.getLabel())
+		}
+		res := []string{}
+		for _, v := range values {
+			option, ok := v.(string)
+			if !ok || !containsFormOption(item.Options, option) {
+				return nil, fmt.Errorf(i18n.Translate(lang, "object:The value: %v is not an option of the field: %s"), v, item.getLabel())
+			}
+			res = append(res, option)
+		}
+		return res, nil
+	case FormItemTypeFile:
+		return getFormFile(item, value, lang)
+	}
+
+	text, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf(i18n.Translate(lang, "object:The value of the field: %s is invalid"), item.getLabel())
+	}
+	text = strings.TrimSpace(text)
+
+	switch item.Type {
+	case FormItemTypeEmail:
+		address, err := mail.ParseAddress(text)
+		if err != nil || address.Address != text {
+			return nil, fmt.Errorf(i18n.Translate(lang, "object:The value of the field: %s is not a valid email"), item.getLabel())
+		}
+	case FormItemTypeDate:
+		_, err := time.Parse("2006-01-02", text)
+		if err != nil {
+			return nil, fmt.Errorf(i18n.Translate(lang, "object:The value of the field: %s is not a valid date"), item.getLabel())
+		}
+	case FormItemTypeSelect:
+		if !containsFormOption(item.Options, text) {
+			return nil, fmt.Errorf(i18n.Translate(lang, "object:The value: %v is not an option of the field: %s"), text, item.getLabel())
+		}
+	}
+	return text, validateFormText(item, text, lang)
+}
+
+// ValidateFormValues checks the submitted values against the rules of the fields of the form.
+// It returns the values of the known fields normalized, without the files, and the uploaded
+// files by field name. The values of unknown fields are dropped.
+func ValidateFormValues(form *Form, values map[string]interface{}, lang string) (map[string]interface{}, map[string]*FormFile, error) {
+	res := map[string]interface{}{}
+	files := map[string]*FormFile{}
+	for _, item := range form.FormItems {
+		value := values[item.Name]
+		if isFormValueEmpty(value) {
+			if item.Required {
+				return nil, nil, fmt.Errorf(i18n.Translate(lang, "object:The field: %s is required"), item.getLabel())
+			}
+			continue
+		}
+
+		normalized, err := validateFormValue(item, value, lang)
+		if err != nil {
+			return nil, nil, err
+		}
+		if file, ok := normalized.(*FormFile); ok {
+			files[item.Name] = file
+			continue
+		}
+		res[item.Name] = normalized
+	}
+	return res, files, nil
+}
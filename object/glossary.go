@@ -0,0 +1,291 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/casibase/casibase/i18n"
+	"github.com/casibase/casibase/util"
+	"xorm.io/core"
+)
+
+// GlossaryTerm maps a term of the source text, or any of its other forms, to the term its
+// translation must use. A term whose target is its source is kept as it is.
+type GlossaryTerm struct {
+	Source          string   `json:"source"`
+	Target          string   `json:"target"`
+	Forms           []string `json:"forms,omitempty"`
+	IsCaseSensitive bool     `json:"isCaseSensitive,omitempty"`
+}
+
+// Glossary is a terminology of the organization enforced on the translations, see Translate.
+type Glossary struct {
+	Owner       string `xorm:"varchar(100) notnull pk" json:"owner"`
+	Name        string `xorm:"varchar(100) notnull pk" json:"name"`
+	CreatedTime string `xorm:"varchar(100)" json:"createdTime"`
+	DisplayName string `xorm:"varchar(200)" json:"displayName"`
+
+	Description    string          `xorm:"varchar(500)" json:"description"`
+	Language       string          `xorm:"varchar(100)" json:"language"`
+	Terms          []*GlossaryTerm `xorm:"mediumtext" json:"terms"`
+	DoNotTranslate []string        `xorm:"mediumtext" json:"doNotTranslate"`
+}
+
+func GetGlossaryCount(owner, field, value string) (int64, error) {
+	session := GetDbSession(owner, -1, -1, field, value, "", "")
+	return session.Count(&Glossary{})
+}
+
+func GetGlossaries(owner string) ([]*Glossary, error) {
+	glossaries := []*Glossary{}
+	err := adapter.engine.Desc("created_time").Find(&glossaries, &Glossary{Owner: owner})
+	if err != nil {
+		return glossaries, err
+	}
+	return glossaries, nil
+}
+
+func GetPaginationGlossaries(owner string, offset, limit int, field, value, sortField, sortOrder string) ([]*Glossary, error) {
+	glossaries := []*Glossary{}
+	session := GetDbSession(owner, offset, limit, field, value, sortField, sortOrder)
+	err := session.Find(&glossaries)
+	if err != nil {
+		return glossaries, err
+	}
+
+	return glossaries, nil
+}
+
+func getGlossary(owner string, name string) (*Glossary, error) {
+	if owner == "" || name == "" {
+		return nil, nil
+	}
+
+	glossary := Glossary{Owner: owner, Name: name}
+	existed, err := adapter.engine.Get(&glossary)
+	if err != nil {
+		return &glossary, err
+	}
+
+	if existed {
+		return &glossary, nil
+	} else {
+		return nil, nil
+	}
+}
+
+func GetGlossary(id string) (*Glossary, error) {
+	owner, name, err := util.GetOwnerAndNameFromIdWithError(id)
+	if err != nil {
+		return nil, err
+	}
+	return getGlossary(owner, name)
+}
+
+// ValidateGlossary checks that each term has a source and a target, and that no source
+// is mapped twice.
+func ValidateGlossary(glossary *Glossary, lang string) error {
+	sources := map[string]bool{}
+	for i, term := range glossary.Terms {
+		term.Source = strings.TrimSpace(term.Source)
+		term.Target = strings.TrimSpace(term.Target)
+		if term.Source == "" || term.Target == "" {
+			return fmt.Errorf(i18n.Translate(lang, "object:The source and target of the glossary term: %d should not be empty"), i+1)
+		}
+
+		for _, source := range term.getSources() {
+			key := strings.ToLower(source)
+			if sources[key] {
+				return fmt.Errorf(i18n.Translate(lang, "object:The glossary term: %s is duplicated"), source)
+			}
+			sources[key] = true
+		}
+	}
+	return nil
+}
+
+func UpdateGlossary(id string, glossary *Glossary, lang string) (bool, error) {
+	owner, name, err := util.GetOwnerAndNameFromIdWithError(id)
+	if err != nil {
+		return false, err
+	}
+	if _, err := getGlossary(owner, name); err != nil {
+		return false, err
+	}
+
+	err = ValidateGlossary(glossary, lang)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := adapter.engine.ID(core.PK{owner, name}).AllCols().Update(glossary)
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+func AddGlossary(glossary *Glossary, lang string) (bool, error) {
+	err := ValidateGlossary(glossary, lang)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := adapter.engine.Insert(glossary)
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+func DeleteGlossary(glossary *Glossary) (bool, error) {
+	affected, err := adapter.engine.ID(core.PK{glossary.Owner, glossary.Name}).Delete(&Glossary{})
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+func (glossary *Glossary) GetId() string {
+	return fmt.Sprintf("%s/%s", glossary.Owner, glossary.Name)
+}
+
+// getTerms returns the terms of the glossary for a translation to the language, its mappings
+// only applying to the language of the glossary while the terms not to translate always apply.
+func (glossary *Glossary) getTerms(language string) []*GlossaryTerm {
+	terms := []*GlossaryTerm{}
+	if glossary.Language == "" || strings.EqualFold(glossary.Language, language) {
+		terms = append(terms, glossary.Terms...)
+	}
+	for _, term := range glossary.DoNotTranslate {
+		term = strings.TrimSpace(term)
+		if term != "" {
+			terms = append(terms, &GlossaryTerm{Source: term, Target: term, IsCaseSensitive: true})
+		}
+	}
+	return terms
+}
+
+// getGlossaryTerms returns the terms of the glossaries of the ids for a translation to the language.
+func getGlossaryTerms(ids []string, language string, lang string) ([]*GlossaryTerm, error) {
+	terms := []*GlossaryTerm{}
+	for _, id := range ids {
+		glossary, err := GetGlossary(id)
+		if err != nil {
+			return nil, err
+		}
+		if glossary == nil {
+			return nil, fmt.Errorf(i18n.Translate(lang, "object:The glossary: %s is not found"), id)
+		}
+
+		terms = append(terms, glossary.getTerms(language)...)
+	}
+	return terms, nil
+}
+
+// parseGlossary parses the glossary entries of an article, either "source=target" or a bare term
+// that must not be translated.
+func parseGlossary(entries []string) []*GlossaryTerm {
+	terms := []*GlossaryTerm{}
+	for _, entry := range entries {
+		source, target, found := strings.Cut(entry, "=")
+		source = strings.TrimSpace(source)
+		target = strings.TrimSpace(target)
+		if !found || target == "" {
+			target = source
+		}
+		if source == "" {
+			continue
+		}
+
+		terms = append(terms, &GlossaryTerm{Source: source, Target: target})
+	}
+	return terms
+}
+
+func (term *GlossaryTerm) getSources() []string {
+	sources := []string{term.Source}
+	for _, form := range term.Forms {
+		form = strings.TrimSpace(form)
+		if form != "" {
+			sources = append(sources, form)
+		}
+	}
+	return sources
+}
+
+func (term *GlossaryTerm) isContainedIn(text string, s string) bool {
+	if term.IsCaseSensitive {
+		return strings.Contains(text, s)
+	}
+	return strings.Contains(strings.ToLower(text), strings.ToLower(s))
+}
+
+// isUsedIn returns whether the source or any of its forms is in the text.
+func (term *GlossaryTerm) isUsedIn(text string) bool {
+	for _, source := range term.getSources() {
+		if term.isContainedIn(text, source) {
+			return true
+		}
+	}
+	return false
+}
+
+func getGlossaryInstruction(terms []*GlossaryTerm) string {
+	if len(terms) == 0 {
+		return ""
+	}
+
+	lines := []string{}
+	for _, term := range terms {
+		sources := fmt.Sprintf("\"%s\"", strings.Join(term.getSources(), "\", \""))
+		var line string
+		if term.Source == term.Target {
+			line = fmt.Sprintf("- %s must be kept as it is", sources)
+		} else {
+			line = fmt.Sprintf("- %s must be translated as \"%s\"", sources, term.Target)
+		}
+		if term.IsCaseSensitive {
+			line += " (case-sensitive)"
+		}
+		lines = append(lines, line)
+	}
+	return fmt.Sprintf("Follow this glossary strictly:\n%s\n", strings.Join(lines, "\n"))
+}
+
+// getMissingGlossaryTerms returns the terms used in the text whose target is missing from
+// the translated text.
+func getMissingGlossaryTerms(terms []*GlossaryTerm, text string, translation string) []*GlossaryTerm {
+	res := []*GlossaryTerm{}
+	for _, term := range terms {
+		if term.isUsedIn(text) && !term.isContainedIn(translation, term.Target) {
+			res = append(res, term)
+		}
+	}
+	return res
+}
+
+func getGlossaryTermsText(terms []*GlossaryTerm) string {
+	res := []string{}
+	for _, term := range terms {
+		res = append(res, fmt.Sprintf("%s -> %s", term.Source, term.Target))
+	}
+	return strings.Join(res, ", ")
+}
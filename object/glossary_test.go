@@ -0,0 +1,71 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build skipCi
+// +build skipCi
+
+package object
+
+import "testing"
+
+func TestParseGlossary(t *testing.T) {
+	terms := parseGlossary([]string{"机器学习 = machine learning", "Casibase", " = ignored", ""})
+	if len(terms) != 2 || terms[0].Target != "machine learning" || terms[1].Target != "Casibase" {
+		t.Fatalf("unexpected glossary terms: %v", terms)
+	}
+
+	missingTerms := getMissingGlossaryTerms(terms, "Casibase 使用机器学习", "casibase uses ML")
+	if len(missingTerms) != 1 || missingTerms[0].Source != "机器学习" {
+		t.Errorf("expected the mapping of 机器学习 to be missing, got: %v", missingTerms)
+	}
+
+	missingTerms = getMissingGlossaryTerms(terms, "Casibase 使用机器学习", "Casibase uses Machine Learning")
+	if len(missingTerms) != 0 {
+		t.Errorf("expected no missing terms, got: %v", missingTerms)
+	}
+}
+
+func TestGlossaryTerms(t *testing.T) {
+	glossary := &Glossary{
+		Language: "English",
+		Terms: []*GlossaryTerm{
+			{Source: "知识库", Target: "store", Forms: []string{"知识仓库"}},
+			{Source: "模型", Target: "Model", IsCaseSensitive: true},
+		},
+		DoNotTranslate: []string{"Casibase", " "},
+	}
+	if err := ValidateGlossary(glossary, "en"); err != nil {
+		t.Fatalf("unexpected error for a valid glossary: %v", err)
+	}
+
+	if terms := glossary.getTerms("Chinese"); len(terms) != 1 || terms[0].Source != "Casibase" {
+		t.Errorf("expected only the terms not to translate for another language, got: %v", terms)
+	}
+
+	terms := glossary.getTerms("english")
+	missingTerms := getMissingGlossaryTerms(terms, "知识仓库的模型由 Casibase 管理", "The model of the Store is managed by casibase")
+	if len(missingTerms) != 2 || missingTerms[0].Source != "模型" || missingTerms[1].Source != "Casibase" {
+		t.Errorf("expected the case-sensitive terms to be missing, got: %v", missingTerms)
+	}
+
+	glossary.Terms = append(glossary.Terms, &GlossaryTerm{Source: "知识仓库", Target: "repository"})
+	if err := ValidateGlossary(glossary, "en"); err == nil {
+		t.Errorf("expected an error for a duplicated term")
+	}
+
+	glossary.Terms = []*GlossaryTerm{{Source: "知识库", Target: " "}}
+	if err := ValidateGlossary(glossary, "en"); err == nil {
+		t.Errorf("expected an error for a term without target")
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/casibase/casibase/i18n"
+	"github.com/casibase/casibase/imagegen"
+	"github.com/casibase/casibase/model"
+	"github.com/casibase/casibase/proxy"
+)
+
+const (
+	ImageCommandGenerate = "/image"
+	ImageCommandEdit     = "/edit"
+)
+
+// GetImageCommand parses a chat question like "/image a cat on the moon" into its command and prompt,
+// it returns an empty command when the question is not an image command.
+func GetImageCommand(question string) (string, string) {
+	question = strings.TrimSpace(question)
+	for _, command := range []string{ImageCommandGenerate, ImageCommandEdit} {
+		if question == command || strings.HasPrefix(question, command+" ") || strings.HasPrefix(question, command+"\n") {
+			return command, strings.TrimSpace(strings.TrimPrefix(question, command))
+		}
+	}
+	return "", question
+}
+
+func getImageFromMessagePart(ctx context.Context, part *model.MessagePart) (*imagegen.Image, error) {
+	if part.Data != nil {
+		mimeType := part.MimeType
+		if mimeType == "" {
+			mimeType = http.DetectContentType(part.Data)
+		}
+		return &imagegen.Image{Data: part.Data, MimeType: mimeType}, nil
+	}
+
+	return imagegen.DownloadImage(ctx, proxy.ProxyHttpClient, part.Url)
+}
+
+func getImageExtension(mimeType string) string {
+	switch mimeType {
+	case "image/jpeg":
+		return "jpg"
+	case "image/webp":
+		return "webp"
+	case "image/gif":
+		return "gif"
+	default:
+		return "png"
+	}
+}
+
+// GetImageAnswer runs an image command with the store's image model provider, saves the images
+// through the store's storage provider and returns them as the HTML answer of the message.
+func GetImageAnswer(store *Store, message *Message, command string, question string, origin string, lang string) (string, *model.ModelResult, error) {
+	provider, err := store.GetImageModelProvider()
+	if err != nil {
+		return "", nil, err
+	}
+	if provider == nil {
+		return "", nil, fmt.Errorf(i18n.Translate(lang, "object:The image model provider for store: %s should not be empty"), store.GetId())
+	}
+
+	imageProvider, err := provider.GetImageGenerationProvider(lang)
+	if err != nil {
+		return "", nil, err
+	}
+
+	parts, prompt := model.GetMessageParts(question)
+	if prompt == "" {
+		return "", nil, fmt.Errorf(i18n.Translate(lang, "object:The image prompt should not be empty"))
+	}
+
+	ctx := context.Background()
+	var images []*imagegen.Image
+	var imageResult *imagegen.ImageResult
+	if command == ImageCommandEdit {
+		if len(parts) == 0 {
+			return "", nil, fmt.Errorf(i18n.Translate(lang, "object:The image to edit should be attached to the question"))
+		}
+
+		var image *imagegen.Image
+		image, err = getImageFromMessagePart(ctx, parts[0])
+		if err != nil {
+			return "", nil, err
+		}
+
+		var mask *imagegen.Image
+		if len(parts) > 1 {
+			mask, err = getImageFromMessagePart(ctx, parts[1])
+			if err != nil {
+				return "", nil, err
+			}
+		}
+
+		images, imageResult, err = imageProvider.EditImage(ctx, prompt, image, mask, "", 1, lang)
+	} else {
+		images, imageResult, err = imageProvider.GenerateImages(ctx, prompt, "", 1, lang)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	storageProviderObj, err := store.GetStorageProviderObj(lang)
+	if err != nil {
+		return "", nil, err
+	}
+
+	answer := ""
+	for i, image := range images {
+		key := fmt.Sprintf("%s/%s/%s/%s_%d.%s", message.Organization, message.User, message.Chat, message.Name, i, getImageExtension(image.MimeType))
+
+		var fileUrl string
+		fileUrl, err = storageProviderObj.PutObject(message.User, message.Chat, key, bytes.NewBuffer(image.Data))
+		if err != nil {
+			return "", nil, err
+		}
+
+		var httpUrl string
+		httpUrl, err = getUrlFromPath(fileUrl, origin)
+		if err != nil {
+			return "", nil, err
+		}
+
+		answer += fmt.Sprintf("<img src=\"%s\" width=\"100%%\" height=\"auto\">", httpUrl)
+	}
+
+	modelResult := &model.ModelResult{
+		ImageCount:      imageResult.ImageCount,
+		TotalTokenCount: imageResult.ImageCount,
+		TotalPrice:      imageResult.Price,
+		Currency:        imageResult.Currency,
+	}
+	message.ModelProvider = provider.Name
+	return answer, modelResult, nil
+}
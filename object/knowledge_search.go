@@ -0,0 +1,155 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/casibase/casibase/agent"
+	"github.com/casibase/casibase/agent/builtin_tool/knowledge"
+	"github.com/casibase/casibase/embedding"
+	"github.com/casibase/casibase/i18n"
+)
+
+// KnowledgeSearch runs the searches of the knowledge_search tool for a chat and keeps the
+// vector scores of the retrieved chunks for the answer message.
+type KnowledgeSearch struct {
+	store                *Store
+	embeddingProvider    *Provider
+	embeddingProviderObj embedding.EmbeddingProvider
+	modelProvider        *Provider
+	lang                 string
+
+	mutex        sync.Mutex
+	vectorScores []VectorScore
+}
+
+func NewKnowledgeSearch(store *Store, embeddingProvider *Provider, embeddingProviderObj embedding.EmbeddingProvider, modelProvider *Provider, lang string) *KnowledgeSearch {
+	return &KnowledgeSearch{
+		store:                store,
+		embeddingProvider:    embeddingProvider,
+		embeddingProviderObj: embeddingProviderObj,
+		modelProvider:        modelProvider,
+		lang:                 lang,
+	}
+}
+
+// GetStores returns the current store followed by its vector stores and child stores.
+func (s *KnowledgeSearch) GetStores() []string {
+	res := []string{s.store.Name}
+	stores := map[string]bool{s.store.Name: true}
+	for _, name := range append(append([]string{}, s.store.VectorStores...), s.store.ChildStores...) {
+		if name != "" && !stores[name] {
+			res = append(res, name)
+			stores[name] = true
+		}
+	}
+	return res
+}
+
+// BindTool binds the knowledge_search tool to the chat's stores when the store has selected it.
+func (s *KnowledgeSearch) BindTool(agentClients *agent.AgentClients) {
+	tool, ok := s.store.getSelectedBuiltinTool(agentClients, "knowledge_search")
+	if !ok {
+		return
+	}
+
+	knowledgeTool, ok := tool.(*knowledgetools.KnowledgeSearchTool)
+	if !ok {
+		return
+	}
+	agentClients.BuiltinToolReg.RegisterTool(knowledgeTool.WithSearcher(s.GetStores(), s.Search))
+
+	// offer the schema listing the chat's stores to the model
+	for _, protocolTool := range agentClients.BuiltinToolReg.GetToolsAsProtocolTools() {
+		if protocolTool.Name != tool.GetName() {
+			continue
+		}
+		for i, agentTool := range agentClients.Tools {
+			if agentTool.Name == tool.GetName() {
+				agentClients.Tools[i] = protocolTool
+			}
+		}
+	}
+}
+
+// Search finds the knowledge of the store and its vector stores, with the store's own embedding
+// provider when it differs from the chat's one.
+func (s *KnowledgeSearch) Search(ctx context.Context, storeName string, query string, knowledgeCount int) ([]*knowledgetools.KnowledgeChunk, error) {
+	store := s.store
+	if storeName != s.store.Name {
+		var err error
+		store, err = getStore(s.store.Owner, storeName)
+		if err != nil {
+			return nil, err
+		}
+		if store == nil {
+			return nil, fmt.Errorf(i18n.Translate(s.lang, "account:The store: %s is not found"), storeName)
+		}
+	}
+
+	embeddingProvider, embeddingProviderObj := s.embeddingProvider, s.embeddingProviderObj
+	if store.EmbeddingProvider != "" && store.EmbeddingProvider != embeddingProvider.Name {
+		var err error
+		embeddingProvider, embeddingProviderObj, err = getEmbeddingProviderFromName("admin", store.EmbeddingProvider, s.lang)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	searchProvider, err := GetSearchProvider(store.SearchProvider, "admin")
+	if err != nil {
+		return nil, err
+	}
+
+	relatedStores := append(append([]string{}, store.VectorStores...), store.Name)
+	vectors, _, err := searchProvider.Search(relatedStores, embeddingProvider.Name, embeddingProviderObj, s.modelProvider.Name, query, knowledgeCount, s.lang)
+	if err != nil {
+		if err.Error() == "no knowledge vectors found" {
+			return []*knowledgetools.KnowledgeChunk{}, nil
+		}
+		return nil, err
+	}
+
+	res := []*knowledgetools.KnowledgeChunk{}
+	vectorScores := []VectorScore{}
+	for _, vector := range vectors {
+		res = append(res, &knowledgetools.KnowledgeChunk{
+			Store: vector.Store,
+			File:  vector.File,
+			Text:  vector.Text,
+			Score: vector.Score,
+		})
+		vectorScores = append(vectorScores, VectorScore{
+			Vector: vector.Name,
+			Score:  vector.Score,
+		})
+	}
+
+	s.mutex.Lock()
+	s.vectorScores = append(s.vectorScores, vectorScores...)
+	s.mutex.Unlock()
+
+	return res, nil
+}
+
+// GetVectorScores returns the vector scores of all the searches run so far.
+func (s *KnowledgeSearch) GetVectorScores() []VectorScore {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]VectorScore{}, s.vectorScores...)
+}
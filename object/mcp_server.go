@@ -0,0 +1,501 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/ThinkInAIXYZ/go-mcp/server"
+	"github.com/ThinkInAIXYZ/go-mcp/transport"
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+	"github.com/casibase/casibase/i18n"
+	"github.com/casibase/casibase/model"
+	"github.com/casibase/casibase/util"
+)
+
+const (
+	mcpMaxKnowledgeCount = 20
+	mcpMaxFileCount      = 500
+	mcpMaxRecordCount    = 100
+)
+
+// the tools only administrators can list and call
+var mcpAdminTools = map[string]bool{
+	"query_record": true,
+	"run_scan":     true,
+}
+
+type mcpCallerKey struct{}
+
+type mcpCaller struct {
+	user   *casdoorsdk.User
+	stores []string
+	lang   string
+}
+
+// WithMcpUser returns a context carrying the authenticated user of an MCP request, the tools of
+// the MCP server read it to check the user's permissions. A non-nil stores limits the user to these stores.
+func WithMcpUser(ctx context.Context, user *casdoorsdk.User, stores []string, lang string) context.Context {
+	return context.WithValue(ctx, mcpCallerKey{}, &mcpCaller{user: user, stores: stores, lang: lang})
+}
+
+func getMcpCaller(ctx context.Context) *mcpCaller {
+	caller, ok := ctx.Value(mcpCallerKey{}).(*mcpCaller)
+	if !ok {
+		return &mcpCaller{lang: "en"}
+	}
+	return caller
+}
+
+type SearchStoreKnowledgeRequest struct {
+	Store string `json:"store,omitempty" description:"The name of the store. Defaults to the default store."`
+	Query string `json:"query" description:"The search query, a question or keywords."`
+	Count int    `json:"count,omitempty" description:"The number of chunks to return, at most 20. Defaults to the store's knowledge count."`
+}
+
+type AskStoreRequest struct {
+	Store    string `json:"store,omitempty" description:"The name of the store. Defaults to the default store."`
+	Question string `json:"question" description:"The question to answer with the store's knowledge."`
+}
+
+type ListFilesRequest struct {
+	Store  string `json:"store,omitempty" description:"The name of the store. Defaults to the default store."`
+	Prefix string `json:"prefix,omitempty" description:"Only list the files whose key starts with the prefix."`
+}
+
+type QueryRecordRequest struct {
+	Field string `json:"field,omitempty" description:"The field to filter the records by, like user, action, method or requestUri."`
+	Value string `json:"value,omitempty" description:"The value the field should contain."`
+	Limit int    `json:"limit,omitempty" description:"The number of the most recent records to return, at most 100. Defaults to 20."`
+}
+
+type RunScanRequest struct {
+	Provider string `json:"provider" description:"The name of the scan provider."`
+	Target   string `json:"target,omitempty" description:"The IP address or network range to scan."`
+	Asset    string `json:"asset,omitempty" description:"The name of the asset to scan, used instead of the target."`
+	Command  string `json:"command,omitempty" description:"Optional. The scan command, %s is replaced by the target."`
+}
+
+// NewMcpServer returns an MCP server serving the store knowledge, the files, the records and the scans
+// of Casibase over the transport. The tools check the user set by WithMcpUser on every call.
+func NewMcpServer(t transport.ServerTransport) (*server.Server, error) {
+	srv, err := server.NewServer(t,
+		server.WithServerInfo(protocol.Implementation{
+			Name:    "casibase",
+			Version: "1.0.0",
+		}),
+		server.WithInstructions("Casibase is a knowledge base. Use search_store_knowledge or ask_store to answer questions with the knowledge of its stores."),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	tools := []struct {
+		name        string
+		description string
+		request     interface{}
+		handler     server.ToolHandlerFunc
+	}{
+		{"search_store_knowledge", "Search the knowledge of a store and return the most relevant chunks with their files and scores.", &SearchStoreKnowledgeRequest{}, handleSearchStoreKnowledge},
+		{"ask_store", "Answer a question with the store's model and the knowledge retrieved from the store.", &AskStoreRequest{}, handleAskStore},
+		{"list_files", "List the files of a store.", &ListFilesRequest{}, handleListFiles},
+		{"query_record", "Query the most recent audit records. Only for administrators.", &QueryRecordRequest{}, handleQueryRecord},
+		{"run_scan", "Run a security scan on a target or an asset with a scan provider and return the result. Only for administrators.", &RunScanRequest{}, handleRunScan},
+	}
+
+	for _, tool := range tools {
+		protocolTool, err := protocol.NewTool(tool.name, tool.description, tool.request)
+		if err != nil {
+			return nil, err
+		}
+		srv.RegisterTool(protocolTool, tool.handler)
+	}
+
+	srv.SetToolFilter(func(ctx context.Context, tools []*protocol.Tool) []*protocol.Tool {
+		if util.IsAdmin(getMcpCaller(ctx).user) {
+			return tools
+		}
+
+		res := []*protocol.Tool{}
+		for _, tool := range tools {
+			if !mcpAdminTools[tool.Name] {
+				res = append(res, tool)
+			}
+		}
+		return res
+	})
+
+	return srv, nil
+}
+
+func getMcpTextResult(text string, isError bool) *protocol.CallToolResult {
+	return &protocol.CallToolResult{
+		IsError: isError,
+		Content: []protocol.Content{
+			&protocol.TextContent{
+				Type: "text",
+				Text: text,
+			},
+		},
+	}
+}
+
+func getMcpJsonResult(v interface{}) (*protocol.CallToolResult, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return getMcpTextResult(string(data), false), nil
+}
+
+// getStore returns the store the caller asked for, a user bound to a store by its homepage
+// can only access that store, a caller limited to some stores can only access these stores.
+func (caller *mcpCaller) getStore(name string) (*Store, error) {
+	if caller.stores != nil {
+		if name == "" && len(caller.stores) > 0 {
+			name = caller.stores[0]
+		} else if !util.InSlice(caller.stores, name) {
+			return nil, fmt.Errorf(i18n.Translate(caller.lang, "controllers:You can only access data from your assigned store"))
+		}
+	}
+
+	if caller.user != nil && caller.user.Homepage != "" {
+		if name == "" {
+			name = caller.user.Homepage
+		} else if name != caller.user.Homepage {
+			return nil, fmt.Errorf(i18n.Translate(caller.lang, "controllers:You can only access data from your assigned store"))
+		}
+	}
+
+	var store *Store
+	var err error
+	if name == "" {
+		store, err = GetDefaultStore("admin")
+	} else {
+		store, err = getStore("admin", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if store == nil {
+		return nil, fmt.Errorf(i18n.Translate(caller.lang, "account:The store: %s is not found"), name)
+	}
+	return store, nil
+}
+
+// getKnowledgeSearch returns a knowledge search over the store with its model and embedding providers.
+func (caller *mcpCaller) getKnowledgeSearch(store *Store) (*KnowledgeSearch, error) {
+	modelProvider, _, err := GetModelProviderFromContext("admin", store.ModelProvider, caller.lang)
+	if err != nil {
+		return nil, err
+	}
+
+	embeddingProvider, embeddingProviderObj, err := GetEmbeddingProviderFromContext("admin", store.EmbeddingProvider, caller.lang)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKnowledgeSearch(store, embeddingProvider, embeddingProviderObj, modelProvider, caller.lang), nil
+}
+
+func (caller *mcpCaller) requireAdmin() error {
+	if !util.IsAdmin(caller.user) {
+		return fmt.Errorf(i18n.Translate(caller.lang, "auth:this operation requires admin privilege"))
+	}
+	return nil
+}
+
+func getKnowledgeCount(store *Store, count int) int {
+	if count <= 0 {
+		count = store.KnowledgeCount
+	}
+	if count <= 0 {
+		count = 5
+	}
+	if count > mcpMaxKnowledgeCount {
+		count = mcpMaxKnowledgeCount
+	}
+	return count
+}
+
+func handleSearchStoreKnowledge(ctx context.Context, request *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+	caller := getMcpCaller(ctx)
+
+	req := &SearchStoreKnowledgeRequest{}
+	err := protocol.VerifyAndUnmarshal(request.RawArguments, req)
+	if err != nil {
+		return getMcpTextResult(err.Error(), true), nil
+	}
+
+	store, err := caller.getStore(req.Store)
+	if err != nil {
+		return getMcpTextResult(err.Error(), true), nil
+	}
+
+	knowledgeSearch, err := caller.getKnowledgeSearch(store)
+	if err != nil {
+		return getMcpTextResult(err.Error(), true), nil
+	}
+
+	chunks, err := knowledgeSearch.Search(ctx, store.Name, req.Query, getKnowledgeCount(store, req.Count))
+	if err != nil {
+		return getMcpTextResult(err.Error(), true), nil
+	}
+
+	return getMcpJsonResult(chunks)
+}
+
+func handleAskStore(ctx context.Context, request *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+	caller := getMcpCaller(ctx)
+
+	req := &AskStoreRequest{}
+	err := protocol.VerifyAndUnmarshal(request.RawArguments, req)
+	if err != nil {
+		return getMcpTextResult(err.Error(), true), nil
+	}
+
+	store, err := caller.getStore(req.Store)
+	if err != nil {
+		return getMcpTextResult(err.Error(), true), nil
+	}
+
+	knowledgeSearch, err := caller.getKnowledgeSearch(store)
+	if err != nil {
+		return getMcpTextResult(err.Error(), true), nil
+	}
+
+	knowledge, _, _, err := GetNearestKnowledge(store.Name, store.VectorStores, store.SearchProvider, knowledgeSearch.embeddingProvider, knowledgeSearch.embeddingProviderObj, knowledgeSearch.modelProvider, "admin", req.Question, getKnowledgeCount(store, 0), caller.lang)
+	if err != nil && err.Error() != "no knowledge vectors found" {
+		return getMcpTextResult(err.Error(), true), nil
+	}
+
+	answer, modelResult, err := GetAnswerWithContext(knowledgeSearch.modelProvider.Name, req.Question, nil, knowledge, store.Prompt, caller.lang)
+	if err != nil {
+		return getMcpTextResult(err.Error(), true), nil
+	}
+
+	err = caller.addAnswerMessages(store, knowledgeSearch.modelProvider.Name, req.Question, answer, modelResult)
+	if err != nil {
+		return getMcpTextResult(err.Error(), true), nil
+	}
+
+	return getMcpTextResult(answer, false), nil
+}
+
+// addAnswerMessages records the question and the answer of ask_store in a hidden chat of the caller,
+// so the tokens are counted and charged like the answers in the chats.
+func (caller *mcpCaller) addAnswerMessages(store *Store, modelProviderName string, question string, answer string, modelResult *model.ModelResult) error {
+	userName := "admin"
+	organization := ""
+	if caller.user != nil {
+		userName = caller.user.Name
+		organization = caller.user.Owner
+	}
+
+	chatName := fmt.Sprintf("mcp_%s_%s", userName, store.Name)
+	chat, err := GetChat(util.GetIdFromOwnerAndName("admin", chatName))
+	if err != nil {
+		return err
+	}
+	if chat == nil {
+		chat = &Chat{
+			Owner:         "admin",
+			Name:          chatName,
+			CreatedTime:   util.GetCurrentTime(),
+			UpdatedTime:   util.GetCurrentTime(),
+			Organization:  organization,
+			DisplayName:   "MCP",
+			Store:         store.Name,
+			ModelProvider: modelProviderName,
+			Category:      "MCP",
+			Type:          "AI",
+			User:          userName,
+			Users:         []string{},
+			IsHidden:      true,
+		}
+		_, err = AddChat(chat)
+		if err != nil {
+			return err
+		}
+	}
+
+	questionMessage := &Message{
+		Owner:        chat.Owner,
+		Name:         fmt.Sprintf("message_%s", util.GetRandomName()),
+		CreatedTime:  util.GetCurrentTimeEx(chat.CreatedTime),
+		Organization: chat.Organization,
+		Store:        chat.Store,
+		User:         userName,
+		Chat:         chat.Name,
+		Author:       userName,
+		Text:         question,
+	}
+	_, err = AddMessage(questionMessage)
+	if err != nil {
+		return err
+	}
+
+	message := &Message{
+		Owner:         chat.Owner,
+		Name:          fmt.Sprintf("message_%s", util.GetRandomName()),
+		CreatedTime:   util.GetCurrentTimeEx(questionMessage.CreatedTime),
+		Organization:  chat.Organization,
+		Store:         chat.Store,
+		User:          userName,
+		Chat:          chat.Name,
+		ReplyTo:       questionMessage.Name,
+		Author:        "AI",
+		Text:          answer,
+		ModelProvider: modelProviderName,
+		TokenCount:    modelResult.TotalTokenCount,
+		Price:         model.AddPrices(modelResult.TotalPrice, 0),
+		Currency:      modelResult.Currency,
+	}
+	_, err = AddMessage(message)
+	if err != nil {
+		return err
+	}
+
+	err = AddTransactionForMessage(message)
+	if err != nil {
+		return err
+	}
+
+	_, err = UpdateMessage(message.GetId(), message, false)
+	if err != nil {
+		return err
+	}
+
+	// reload the chat, adding the messages has updated its message count
+	chat, err = GetChat(chat.GetId())
+	if err != nil || chat == nil {
+		return err
+	}
+
+	chat.TokenCount += message.TokenCount
+	chat.Price += message.Price
+	if chat.Currency == "" {
+		chat.Currency = message.Currency
+	}
+	_, err = UpdateChat(chat.GetId(), chat)
+	return err
+}
+
+func handleListFiles(ctx context.Context, request *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+	caller := getMcpCaller(ctx)
+
+	req := &ListFilesRequest{}
+	err := protocol.VerifyAndUnmarshal(request.RawArguments, req)
+	if err != nil {
+		return getMcpTextResult(err.Error(), true), nil
+	}
+
+	store, err := caller.getStore(req.Store)
+	if err != nil {
+		return getMcpTextResult(err.Error(), true), nil
+	}
+
+	storageProviderObj, err := store.GetStorageProviderObj(caller.lang)
+	if err != nil {
+		return getMcpTextResult(err.Error(), true), nil
+	}
+
+	objects, err := storageProviderObj.ListObjects(req.Prefix)
+	if err != nil {
+		return getMcpTextResult(err.Error(), true), nil
+	}
+
+	type file struct {
+		Key          string `json:"key"`
+		Size         int64  `json:"size"`
+		LastModified string `json:"lastModified"`
+	}
+
+	files := []*file{}
+	for _, obj := range objects {
+		if strings.HasSuffix(obj.Key, "/") {
+			continue
+		}
+		if len(files) == mcpMaxFileCount {
+			break
+		}
+		files = append(files, &file{Key: obj.Key, Size: obj.Size, LastModified: obj.LastModified})
+	}
+
+	return getMcpJsonResult(files)
+}
+
+func handleQueryRecord(ctx context.Context, request *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+	caller := getMcpCaller(ctx)
+	err := caller.requireAdmin()
+	if err != nil {
+		return getMcpTextResult(err.Error(), true), nil
+	}
+
+	req := &QueryRecordRequest{}
+	if len(request.RawArguments) != 0 {
+		err = protocol.VerifyAndUnmarshal(request.RawArguments, req)
+		if err != nil {
+			return getMcpTextResult(err.Error(), true), nil
+		}
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > mcpMaxRecordCount {
+		limit = mcpMaxRecordCount
+	}
+
+	records, err := GetPaginationRecords("", 0, limit, req.Field, req.Value, "", "")
+	if err != nil {
+		return getMcpTextResult(err.Error(), true), nil
+	}
+
+	return getMcpJsonResult(records)
+}
+
+func handleRunScan(ctx context.Context, request *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+	caller := getMcpCaller(ctx)
+	err := caller.requireAdmin()
+	if err != nil {
+		return getMcpTextResult(err.Error(), true), nil
+	}
+
+	req := &RunScanRequest{}
+	err = protocol.VerifyAndUnmarshal(request.RawArguments, req)
+	if err != nil {
+		return getMcpTextResult(err.Error(), true), nil
+	}
+
+	targetMode := "Manual Input"
+	if req.Asset != "" {
+		targetMode = "Asset"
+	} else if req.Target == "" {
+		return getMcpTextResult("Missing required parameter: target or asset", true), nil
+	}
+
+	scanResult, err := ScanAsset(util.GetIdFromOwnerAndName("admin", req.Provider), "", targetMode, req.Target, req.Asset, req.Command, false, caller.lang)
+	if err != nil {
+		return getMcpTextResult(err.Error(), true), nil
+	}
+
+	return getMcpJsonResult(scanResult)
+}
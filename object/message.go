@@ -62,14 +62,19 @@ type Message struct {
 	IsAlerted         bool                 `json:"isAlerted"`
 	IsRegenerated     bool                 `json:"isRegenerated"`
 	WebSearchEnabled  bool                 `json:"webSearchEnabled"`
+	IsCached          bool                 `json:"isCached"`
 	ModelProvider     string               `xorm:"varchar(100)" json:"modelProvider"`
 	EmbeddingProvider string               `xorm:"varchar(100)" json:"embeddingProvider"`
+	Experiment        string               `xorm:"varchar(100)" json:"experiment"`
+	Variant           string               `xorm:"varchar(100)" json:"variant"`
+	Latency           int64                `json:"latency"`
 	VectorScores      []VectorScore        `xorm:"mediumtext" json:"vectorScores"`
 	LikeUsers         []string             `json:"likeUsers"`
 	DisLikeUsers      []string             `json:"dislikeUsers"`
 	Suggestions       []Suggestion         `json:"suggestions"`
 	ToolCalls         []model.ToolCall     `xorm:"mediumtext" json:"toolCalls"`
 	SearchResults     []model.SearchResult `xorm:"mediumtext" json:"searchResults"`
+	PendingToolCalls  []*PendingToolCall   `xorm:"mediumtext" json:"pendingToolCalls"`
 
 	TransactionId string `xorm:"varchar(100)" json:"transactionId"`
 }
@@ -275,6 +280,11 @@ func DeleteMessage(message *Message) (bool, error) {
 		return false, err
 	}
 
+	err = deleteTracesByMessage(message.Owner, message.Name)
+	if err != nil {
+		return false, err
+	}
+
 	return affected != 0, nil
 }
 
@@ -307,6 +317,11 @@ func DeleteMessagesByChat(message *Message) (bool, error) {
 		return false, err
 	}
 
+	err = deleteTracesByChat(message.Owner, message.Chat)
+	if err != nil {
+		return false, err
+	}
+
 	return affected != 0, nil
 }
 
@@ -326,6 +341,12 @@ func GetRecentRawMessages(chat string, createdTime string, memoryLimit int) ([]*
 		return nil, err
 	}
 
+	return getRawMessagesFromMessages(messages)
+}
+
+func getRawMessagesFromMessages(messages []*Message) ([]*model.RawMessage, error) {
+	res := []*model.RawMessage{}
+	var err error
 	for _, message := range messages {
 		rawTextTokenCount := message.TextTokenCount
 		if rawTextTokenCount == 0 {
@@ -387,6 +408,18 @@ func GetAnswerWithContext(provider string, question string, history []*model.Raw
 	return res, modelResult, nil
 }
 
+// GetStructuredAnswer asks the provider for an answer conforming to the schema and unmarshals
+// the validated JSON into v, see model.QueryStructured.
+func GetStructuredAnswer(provider string, schema *model.ResponseSchema, v interface{}, question string, lang string) (*model.ModelResult, error) {
+	_, modelProviderObj, err := GetModelProviderFromContext("admin", provider, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	prompt := "You are an expert in your field and you specialize in using your knowledge to answer or solve people's problems."
+	return model.QueryStructured(modelProviderObj, schema, v, question, []*model.RawMessage{}, prompt, []*model.RawMessage{}, lang)
+}
+
 func GetMessageCount(owner string, field string, value string, store string) (int64, error) {
 	session := GetDbSession(owner, -1, -1, field, value, "", "")
 	if store != "" {
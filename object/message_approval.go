@@ -0,0 +1,276 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/casibase/casibase/i18n"
+	"github.com/casibase/casibase/util"
+	"github.com/sashabaranov/go-openai"
+	"xorm.io/core"
+)
+
+const (
+	ToolCallStatePending  = "Pending"
+	ToolCallStateApproved = "Approved"
+	ToolCallStateRejected = "Rejected"
+	ToolCallStateExpired  = "Expired"
+)
+
+const (
+	toolCallApprovalTimeout      = 10 * time.Minute
+	toolCallApprovalPollInterval = 10 * time.Second
+)
+
+// PendingToolCall is a tool call of an answer waiting for the approval of the chat user or an admin.
+// Id is generated for each pending call, the models reusing their tool call IDs across the steps
+// of an answer, ToolCallId is the ID given by the model.
+type PendingToolCall struct {
+	Id          string `json:"id"`
+	ToolCallId  string `json:"toolCallId"`
+	Name        string `json:"name"`
+	Arguments   string `json:"arguments"`
+	State       string `json:"state"`
+	CreatedTime string `json:"createdTime"`
+	DecidedBy   string `json:"decidedBy"`
+	DecidedTime string `json:"decidedTime"`
+}
+
+var (
+	toolCallDecisionMutex    sync.Mutex
+	toolCallDecisionChannels = map[string]chan struct{}{}
+)
+
+// getToolCallDecisionChannel returns a channel closed at the next decision on the tool calls
+// of the message in this process, the other processes being caught up by polling the database.
+func getToolCallDecisionChannel(id string) chan struct{} {
+	toolCallDecisionMutex.Lock()
+	defer toolCallDecisionMutex.Unlock()
+
+	decisionChannel, ok := toolCallDecisionChannels[id]
+	if !ok {
+		decisionChannel = make(chan struct{})
+		toolCallDecisionChannels[id] = decisionChannel
+	}
+	return decisionChannel
+}
+
+func notifyToolCallDecision(id string) {
+	toolCallDecisionMutex.Lock()
+	defer toolCallDecisionMutex.Unlock()
+
+	if decisionChannel, ok := toolCallDecisionChannels[id]; ok {
+		close(decisionChannel)
+		delete(toolCallDecisionChannels, id)
+	}
+}
+
+func releaseToolCallDecisionChannel(id string) {
+	toolCallDecisionMutex.Lock()
+	defer toolCallDecisionMutex.Unlock()
+
+	delete(toolCallDecisionChannels, id)
+}
+
+// updateMessagePendingToolCalls applies update to the pending tool calls of the message as
+// stored in the database, in a transaction locking the message row so that concurrent decisions
+// never overwrite each other. The message gets the updated pending tool calls.
+func updateMessagePendingToolCalls(message *Message, update func(pendingToolCalls []*PendingToolCall) ([]*PendingToolCall, error)) error {
+	session := adapter.engine.NewSession()
+	defer session.Close()
+	err := session.Begin()
+	if err != nil {
+		return err
+	}
+
+	currentMessage := Message{}
+	existed, err := session.ID(core.PK{message.Owner, message.Name}).Cols("pending_tool_calls").ForUpdate().Get(&currentMessage)
+	if err != nil {
+		session.Rollback()
+		return err
+	}
+	if !existed {
+		session.Rollback()
+		return fmt.Errorf("the message: %s is not found", message.GetId())
+	}
+
+	currentMessage.PendingToolCalls, err = update(currentMessage.PendingToolCalls)
+	if err != nil {
+		session.Rollback()
+		return err
+	}
+
+	_, err = session.ID(core.PK{message.Owner, message.Name}).Cols("pending_tool_calls").Update(&currentMessage)
+	if err != nil {
+		session.Rollback()
+		return err
+	}
+
+	err = session.Commit()
+	if err != nil {
+		return err
+	}
+
+	message.PendingToolCalls = currentMessage.PendingToolCalls
+	return nil
+}
+
+func isToolCallDecided(message *Message, ids map[string]bool) bool {
+	for _, pendingToolCall := range message.PendingToolCalls {
+		if ids[pendingToolCall.Id] && pendingToolCall.State == ToolCallStatePending {
+			return false
+		}
+	}
+	return true
+}
+
+// AddPendingToolCalls persists the tool calls on the message as pending approval.
+func AddPendingToolCalls(message *Message, toolCalls []openai.ToolCall) ([]*PendingToolCall, error) {
+	res := []*PendingToolCall{}
+	for _, toolCall := range toolCalls {
+		pendingToolCall := &PendingToolCall{
+			Id:          util.GenerateId(),
+			ToolCallId:  toolCall.ID,
+			Name:        toolCall.Function.Name,
+			Arguments:   toolCall.Function.Arguments,
+			State:       ToolCallStatePending,
+			CreatedTime: util.GetCurrentTime(),
+		}
+		res = append(res, pendingToolCall)
+	}
+
+	err := updateMessagePendingToolCalls(message, func(pendingToolCalls []*PendingToolCall) ([]*PendingToolCall, error) {
+		return append(pendingToolCalls, res...), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// WaitForToolCallDecisions waits until the tool calls are approved or rejected, see DecideToolCall,
+// being notified of the decisions made in this process and polling the message for the others.
+// The calls still pending after the timeout are expired, which rejects them. The decisions are
+// returned by the tool call IDs of the model.
+func WaitForToolCallDecisions(ctx context.Context, message *Message, toolCalls []*PendingToolCall) (map[string]bool, error) {
+	ids := map[string]bool{}
+	for _, toolCall := range toolCalls {
+		ids[toolCall.Id] = true
+	}
+
+	id := message.GetId()
+	defer releaseToolCallDecisionChannel(id)
+
+	timer := time.NewTimer(toolCallApprovalTimeout)
+	defer timer.Stop()
+	ticker := time.NewTicker(toolCallApprovalPollInterval)
+	defer ticker.Stop()
+
+	isTimeout := false
+	for {
+		// got before reading the message so that no decision is missed in between
+		decisionChannel := getToolCallDecisionChannel(id)
+
+		currentMessage, err := GetMessage(id)
+		if err != nil {
+			return nil, err
+		}
+		if currentMessage == nil {
+			return nil, fmt.Errorf("the message: %s is not found", id)
+		}
+
+		message.PendingToolCalls = currentMessage.PendingToolCalls
+		if isTimeout || isToolCallDecided(message, ids) {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timer.C:
+			isTimeout = true
+		case <-decisionChannel:
+		case <-ticker.C:
+		}
+	}
+
+	if isToolCallDecided(message, ids) {
+		return getToolCallDecisions(message.PendingToolCalls, ids), nil
+	}
+
+	// the expiry is written in the same transaction as the decisions, so a decision made
+	// meanwhile is kept instead of being expired
+	var res map[string]bool
+	err := updateMessagePendingToolCalls(message, func(pendingToolCalls []*PendingToolCall) ([]*PendingToolCall, error) {
+		for _, pendingToolCall := range pendingToolCalls {
+			if ids[pendingToolCall.Id] && pendingToolCall.State == ToolCallStatePending {
+				pendingToolCall.State = ToolCallStateExpired
+				pendingToolCall.DecidedTime = util.GetCurrentTime()
+			}
+		}
+		res = getToolCallDecisions(pendingToolCalls, ids)
+		return pendingToolCalls, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func getToolCallDecisions(pendingToolCalls []*PendingToolCall, ids map[string]bool) map[string]bool {
+	res := map[string]bool{}
+	for _, pendingToolCall := range pendingToolCalls {
+		if ids[pendingToolCall.Id] {
+			res[pendingToolCall.ToolCallId] = pendingToolCall.State == ToolCallStateApproved
+		}
+	}
+	return res
+}
+
+// DecideToolCall approves or rejects a pending tool call of the message, the answer being
+// generated picks up the decision in WaitForToolCallDecisions.
+func DecideToolCall(message *Message, toolCallId string, isApproved bool, user string, lang string) (bool, error) {
+	err := updateMessagePendingToolCalls(message, func(pendingToolCalls []*PendingToolCall) ([]*PendingToolCall, error) {
+		for _, pendingToolCall := range pendingToolCalls {
+			if pendingToolCall.Id != toolCallId {
+				continue
+			}
+
+			if pendingToolCall.State != ToolCallStatePending {
+				return nil, fmt.Errorf(i18n.Translate(lang, "object:The tool call: %s is already %s"), toolCallId, pendingToolCall.State)
+			}
+
+			pendingToolCall.State = ToolCallStateRejected
+			if isApproved {
+				pendingToolCall.State = ToolCallStateApproved
+			}
+			pendingToolCall.DecidedBy = user
+			pendingToolCall.DecidedTime = util.GetCurrentTime()
+			return pendingToolCalls, nil
+		}
+
+		return nil, fmt.Errorf(i18n.Translate(lang, "object:The tool call: %s is not found"), toolCallId)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	notifyToolCallDecision(message.GetId())
+	return true, nil
+}
@@ -0,0 +1,39 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build skipCi
+// +build skipCi
+
+package object
+
+import "testing"
+
+func TestGetToolCallDecisions(t *testing.T) {
+	// the model reused the tool call ID "call_0" in the second step of the answer
+	pendingToolCalls := []*PendingToolCall{
+		{Id: "1", ToolCallId: "call_0", State: ToolCallStateRejected},
+		{Id: "2", ToolCallId: "call_0", State: ToolCallStateApproved},
+		{Id: "3", ToolCallId: "call_1", State: ToolCallStateExpired},
+	}
+
+	decisions := getToolCallDecisions(pendingToolCalls, map[string]bool{"2": true, "3": true})
+	if len(decisions) != 2 || !decisions["call_0"] || decisions["call_1"] {
+		t.Errorf("unexpected decisions: %v", decisions)
+	}
+
+	message := &Message{PendingToolCalls: pendingToolCalls}
+	if !isToolCallDecided(message, map[string]bool{"2": true}) {
+		t.Errorf("the tool call: 2 should be decided")
+	}
+}
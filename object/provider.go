@@ -22,6 +22,7 @@ import (
 	"github.com/casibase/casibase/agent"
 	"github.com/casibase/casibase/embedding"
 	"github.com/casibase/casibase/i18n"
+	"github.com/casibase/casibase/imagegen"
 	"github.com/casibase/casibase/model"
 	"github.com/casibase/casibase/scan"
 	"github.com/casibase/casibase/storage"
@@ -228,6 +229,13 @@ func UpdateProvider(id string, provider *Provider) (bool, error) {
 
 	provider.processProviderParams(providerDb)
 
+	if provider.Category == "Agent" && provider.Type == "MCP" {
+		provider.Text, err = agent.GetConfigWithRotatedTokens(provider.GetId(), provider.Text)
+		if err != nil {
+			return false, err
+		}
+	}
+
 	if providerAdapter != nil && provider.IsRemote {
 		_, err = providerAdapter.engine.ID(core.PK{owner, name}).AllCols().Update(provider)
 		if err != nil {
@@ -345,7 +353,7 @@ func (p *Provider) GetEmbeddingProvider(lang string) (embedding.EmbeddingProvide
 }
 
 func (p *Provider) GetAgentProvider(lang string) (agent.AgentProvider, error) {
-	pProvider, err := agent.GetAgentProvider(p.Type, p.SubType, p.Text, p.McpTools, lang)
+	pProvider, err := agent.GetAgentProvider(p.Type, p.SubType, p.GetId(), p.Text, p.McpTools, lang)
 	if err != nil {
 		return nil, err
 	}
@@ -383,6 +391,20 @@ func (p *Provider) GetSpeechToTextProvider(lang string) (stt.SpeechToTextProvide
 	return pProvider, nil
 }
 
+func (p *Provider) GetImageGenerationProvider(lang string) (imagegen.ImageGenerationProvider, error) {
+	// for the self-hosted types, the input price is the price per image
+	pProvider, err := imagegen.GetImageGenerationProvider(p.Type, p.SubType, p.ClientSecret, p.ProviderUrl, p.InputPricePerThousandTokens, p.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	if pProvider == nil {
+		return nil, fmt.Errorf(i18n.Translate(lang, "object:the image generation provider type: %s is not supported"), p.Type)
+	}
+
+	return pProvider, nil
+}
+
 func (p *Provider) GetScanProvider(lang string) (scan.ScanProvider, error) {
 	pProvider, err := scan.GetScanProvider(p.Type, p.ClientId, lang)
 	if err != nil {
@@ -520,6 +542,9 @@ func collectProviderNames(store *Store) []string {
 	if store.AgentProvider != "" {
 		providerNames = append(providerNames, store.AgentProvider)
 	}
+	if store.ImageModelProvider != "" {
+		providerNames = append(providerNames, store.ImageModelProvider)
+	}
 	if store.ChildModelProviders != nil {
 		providerNames = append(providerNames, store.ChildModelProviders...)
 	}
@@ -621,12 +646,77 @@ func GetPaginationProviders(owner, storeName string, offset, limit int, field, v
 	return providers, nil
 }
 
+func init() {
+	agent.SaveRotatedTokens = saveMcpRotatedTokens
+}
+
+// saveMcpRotatedTokens saves the refresh tokens rotated by the MCP servers into the provider's config.
+func saveMcpRotatedTokens(providerId string) error {
+	owner, name, err := util.GetOwnerAndNameFromIdWithError(providerId)
+	if err != nil {
+		return err
+	}
+	provider, err := getProvider(owner, name)
+	if err != nil {
+		return err
+	}
+	if provider == nil {
+		return fmt.Errorf("the provider: %s is not found", providerId)
+	}
+
+	text, err := agent.GetConfigWithRotatedTokens(providerId, provider.Text)
+	if err != nil || text == provider.Text {
+		return err
+	}
+
+	provider.Text = text
+	engine := adapter.engine
+	if providerAdapter != nil && provider.IsRemote {
+		engine = providerAdapter.engine
+	}
+	_, err = engine.ID(core.PK{owner, name}).Cols("text").Update(provider)
+	return err
+}
+
 func RefreshMcpTools(provider *Provider) error {
-	tools, err := agent.GetToolsList(provider.Text)
+	tools, err := agent.GetToolsList(provider.GetId(), provider.Text)
+	if err != nil {
+		return err
+	}
+
+	// the listing may have rotated the refresh tokens of the servers
+	provider.Text, err = agent.GetConfigWithRotatedTokens(provider.GetId(), provider.Text)
 	if err != nil {
 		return err
 	}
 
+	serverConfigs, err := agent.GetServerConfigs(provider.Text)
+	if err != nil {
+		return err
+	}
+
+	// keep the tool policies set on the servers that are still configured
+	oldTools := map[string]*agent.McpTools{}
+	for _, tool := range provider.McpTools {
+		oldTools[tool.ServerName] = tool
+	}
+	for _, tool := range tools {
+		if oldTool, ok := oldTools[tool.ServerName]; ok {
+			tool.ToolPolicies = oldTool.ToolPolicies
+			delete(oldTools, tool.ServerName)
+		}
+	}
+
+	// keep the cached tools of the configured servers that were unreachable
+	for _, oldTool := range provider.McpTools {
+		if _, ok := oldTools[oldTool.ServerName]; !ok {
+			continue
+		}
+		if _, ok := serverConfigs[oldTool.ServerName]; ok {
+			tools = append(tools, oldTool)
+		}
+	}
+
 	provider.McpTools = tools
 	return nil
 }
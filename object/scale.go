@@ -27,15 +27,45 @@ const (
 	ScaleStateHidden = "Hidden"
 )
 
+// ScaleBand describes the performance expected for the scores of an item between MinScore and MaxScore.
+type ScaleBand struct {
+	Name       string  `json:"name"`
+	MinScore   float64 `json:"minScore"`
+	MaxScore   float64 `json:"maxScore"`
+	Descriptor string  `json:"descriptor"`
+}
+
+// ScaleItem is a second-level item of a scale, scored between MinScore and MaxScore, 0 and 100 when
+// both are zero. The weight is relative to the other items of its category, 1 when zero.
+type ScaleItem struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Weight      float64      `json:"weight"`
+	MinScore    float64      `json:"minScore"`
+	MaxScore    float64      `json:"maxScore"`
+	Bands       []*ScaleBand `json:"bands"`
+}
+
+// ScaleCategory is a first-level item of a scale, weighted relative to the other categories, 1 when zero.
+type ScaleCategory struct {
+	Name   string       `json:"name"`
+	Weight float64      `json:"weight"`
+	Items  []*ScaleItem `json:"items"`
+}
+
 // Scale is a reusable rubric / evaluation scale (量表), referenced by tasks via Task.Scale (owner/name id).
+// A scale is either structured by its categories or, for older scales, given by its free text only.
 type Scale struct {
 	Owner       string `xorm:"varchar(100) notnull pk" json:"owner"`
 	Name        string `xorm:"varchar(100) notnull pk" json:"name"`
 	CreatedTime string `xorm:"varchar(100)" json:"createdTime"`
 
-	DisplayName string `xorm:"varchar(100)" json:"displayName"`
-	Text        string `xorm:"mediumtext" json:"text"`
-	State       string `xorm:"varchar(50)" json:"state"`
+	DisplayName string           `xorm:"varchar(100)" json:"displayName"`
+	Text        string           `xorm:"mediumtext" json:"text"`
+	Prompt      string           `xorm:"mediumtext" json:"prompt"`
+	Categories  []*ScaleCategory `xorm:"mediumtext" json:"categories"`
+	Version     int              `json:"version"`
+	State       string           `xorm:"varchar(50)" json:"state"`
 }
 
 func GetMaskedScale(scale *Scale, isMaskEnabled bool) *Scale {
@@ -111,18 +141,41 @@ func GetScale(id string) (*Scale, error) {
 	return getScale(owner, name)
 }
 
-func UpdateScale(id string, scale *Scale) (bool, error) {
+// UpdateScale saves the scale as a new version when its rubric changed, the tasks graded against
+// the previous versions keep referencing them.
+func UpdateScale(id string, scale *Scale, lang string) (bool, error) {
 	owner, name, err := util.GetOwnerAndNameFromIdWithError(id)
 	if err != nil {
 		return false, err
 	}
-	_, err = getScale(owner, name)
+	existing, err := getScale(owner, name)
 	if err != nil {
 		return false, err
 	}
 	if scale == nil {
 		return false, nil
 	}
+
+	err = ValidateScale(scale, lang)
+	if err != nil {
+		return false, err
+	}
+
+	scale.Version = 0
+	if existing != nil {
+		scale.Version = existing.Version
+		if existing.isRubricChanged(scale) || existing.Owner != scale.Owner || existing.Name != scale.Name {
+			scale.Version, err = getNextScaleVersion(scale.Owner, scale.Name)
+			if err != nil {
+				return false, err
+			}
+			err = addScaleVersion(scale)
+			if err != nil {
+				return false, err
+			}
+		}
+	}
+
 	_, err = adapter.engine.ID(core.PK{owner, name}).AllCols().Update(scale)
 	if err != nil {
 		return false, err
@@ -130,7 +183,22 @@ func UpdateScale(id string, scale *Scale) (bool, error) {
 	return true, nil
 }
 
-func AddScale(scale *Scale) (bool, error) {
+func AddScale(scale *Scale, lang string) (bool, error) {
+	err := ValidateScale(scale, lang)
+	if err != nil {
+		return false, err
+	}
+
+	// the versions of a deleted scale of the same name are kept for the tasks graded against them
+	scale.Version, err = getNextScaleVersion(scale.Owner, scale.Name)
+	if err != nil {
+		return false, err
+	}
+	err = addScaleVersion(scale)
+	if err != nil {
+		return false, err
+	}
+
 	affected, err := adapter.engine.Insert(scale)
 	if err != nil {
 		return false, err
@@ -0,0 +1,292 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/casibase/casibase/i18n"
+	"github.com/casibase/casibase/util"
+	"github.com/tealeg/xlsx"
+)
+
+// The columns of a scale sheet, one row per band of an item, or per item when it has no bands.
+// The category and item cells left empty are those of the row above.
+const (
+	scaleColumnCategory = iota
+	scaleColumnCategoryWeight
+	scaleColumnItem
+	scaleColumnDescription
+	scaleColumnItemWeight
+	scaleColumnMinScore
+	scaleColumnMaxScore
+	scaleColumnBand
+	scaleColumnBandMinScore
+	scaleColumnBandMaxScore
+	scaleColumnDescriptor
+	scaleColumnCount
+)
+
+func getScaleCell(row []string, column int) string {
+	if column >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[column])
+}
+
+func getScaleCellFloat(row []string, column int, lang string) (float64, error) {
+	value := getScaleCell(row, column)
+	if value == "" {
+		return 0, nil
+	}
+
+	res, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf(i18n.Translate(lang, "object:The value: %s is not a number"), value)
+	}
+	return res, nil
+}
+
+// getScaleCategoriesFromRows parses the rows of a scale sheet, the first of which is the header.
+func getScaleCategoriesFromRows(rows [][]string, lang string) ([]*ScaleCategory, error) {
+	categories := []*ScaleCategory{}
+	var category *ScaleCategory
+	var item *ScaleItem
+	for i, row := range rows {
+		if i == 0 {
+			continue
+		}
+
+		numbers := map[int]float64{}
+		for _, column := range []int{scaleColumnCategoryWeight, scaleColumnItemWeight, scaleColumnMinScore, scaleColumnMaxScore, scaleColumnBandMinScore, scaleColumnBandMaxScore} {
+			number, err := getScaleCellFloat(row, column, lang)
+			if err != nil {
+				return nil, err
+			}
+			numbers[column] = number
+		}
+
+		if name := getScaleCell(row, scaleColumnCategory); name != "" && (category == nil || category.Name != name) {
+			category = &ScaleCategory{Name: name, Weight: numbers[scaleColumnCategoryWeight], Items: []*ScaleItem{}}
+			categories = append(categories, category)
+			item = nil
+		}
+
+		if name := getScaleCell(row, scaleColumnItem); name != "" && (item == nil || item.Name != name) {
+			if category == nil {
+				return nil, fmt.Errorf(i18n.Translate(lang, "object:The item: %s has no category"), name)
+			}
+			item = &ScaleItem{
+				Name:        name,
+				Description: getScaleCell(row, scaleColumnDescription),
+				Weight:      numbers[scaleColumnItemWeight],
+				MinScore:    numbers[scaleColumnMinScore],
+				MaxScore:    numbers[scaleColumnMaxScore],
+			}
+			category.Items = append(category.Items, item)
+		}
+
+		if name := getScaleCell(row, scaleColumnBand); name != "" && item != nil {
+			item.Bands = append(item.Bands, &ScaleBand{
+				Name:       name,
+				MinScore:   numbers[scaleColumnBandMinScore],
+				MaxScore:   numbers[scaleColumnBandMaxScore],
+				Descriptor: getScaleCell(row, scaleColumnDescriptor),
+			})
+		}
+	}
+	return categories, nil
+}
+
+// ParseScale reads a scale from an XLSX sheet of categories, items and bands, or from the JSON of
+// a scale, as exported by ExportScaleXlsx and ExportScaleJson. Only the rubric fields are taken from the JSON.
+func ParseScale(fileName string, data []byte, lang string) (*Scale, error) {
+	scale := &Scale{}
+	ext := strings.ToLower(filepath.Ext(fileName))
+	switch ext {
+	case ".xlsx":
+		file, err := xlsx.OpenBinary(data)
+		if err != nil {
+			return nil, err
+		}
+		if len(file.Sheets) == 0 {
+			return nil, fmt.Errorf(i18n.Translate(lang, "object:The scale file is empty"))
+		}
+
+		rows := [][]string{}
+		for _, row := range file.Sheets[0].Rows {
+			line := []string{}
+			for _, cell := range row.Cells {
+				line = append(line, cell.String())
+			}
+			rows = append(rows, line)
+		}
+		scale.Categories, err = getScaleCategoriesFromRows(rows, lang)
+		if err != nil {
+			return nil, err
+		}
+	case ".json":
+		var parsed Scale
+		err := json.Unmarshal(data, &parsed)
+		if err != nil {
+			return nil, err
+		}
+		scale.DisplayName = parsed.DisplayName
+		scale.Text = parsed.Text
+		scale.Prompt = parsed.Prompt
+		scale.Categories = parsed.Categories
+	default:
+		return nil, fmt.Errorf(i18n.Translate(lang, "object:The scale file type: %s is not supported, please use XLSX or JSON"), ext)
+	}
+
+	if len(scale.Categories) == 0 {
+		return nil, fmt.Errorf(i18n.Translate(lang, "object:The scale file is empty"))
+	}
+	return scale, nil
+}
+
+// ImportScale replaces the rubric of the scale of the owner and the name with the one of the file,
+// as a new version, or adds the scale when it does not exist.
+func ImportScale(owner string, name string, fileName string, data []byte, lang string) (*Scale, error) {
+	imported, err := ParseScale(fileName, data, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	scale, err := getScale(owner, name)
+	if err != nil {
+		return nil, err
+	}
+	if scale == nil {
+		scale = &Scale{
+			Owner:       owner,
+			Name:        name,
+			CreatedTime: util.GetCurrentTime(),
+			DisplayName: name,
+			State:       ScaleStatePublic,
+		}
+		if imported.DisplayName != "" {
+			scale.DisplayName = imported.DisplayName
+		}
+		scale.Text = imported.Text
+		scale.Prompt = imported.Prompt
+		scale.Categories = imported.Categories
+		_, err = AddScale(scale, lang)
+		if err != nil {
+			return nil, err
+		}
+		return scale, nil
+	}
+
+	scale.Categories = imported.Categories
+	if imported.Text != "" {
+		scale.Text = imported.Text
+	}
+	if imported.Prompt != "" {
+		scale.Prompt = imported.Prompt
+	}
+	_, err = UpdateScale(scale.GetId(), scale, lang)
+	if err != nil {
+		return nil, err
+	}
+	return scale, nil
+}
+
+func addScaleExportRow(sheet *xlsx.Sheet, values []string) {
+	row := sheet.AddRow()
+	for _, value := range values {
+		row.AddCell().SetString(value)
+	}
+}
+
+func formatScaleNumber(value float64) string {
+	if value == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// ExportScaleXlsx writes the categories of the scale to the sheet read back by ParseScale.
+func ExportScaleXlsx(scale *Scale, lang string) ([]byte, error) {
+	file := xlsx.NewFile()
+	sheet, err := file.AddSheet(i18n.Translate(lang, "object:Scale"))
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]string, scaleColumnCount)
+	header[scaleColumnCategory] = i18n.Translate(lang, "object:Category")
+	header[scaleColumnCategoryWeight] = i18n.Translate(lang, "object:Category weight")
+	header[scaleColumnItem] = i18n.Translate(lang, "object:Item")
+	header[scaleColumnDescription] = i18n.Translate(lang, "object:Description")
+	header[scaleColumnItemWeight] = i18n.Translate(lang, "object:Item weight")
+	header[scaleColumnMinScore] = i18n.Translate(lang, "object:Min score")
+	header[scaleColumnMaxScore] = i18n.Translate(lang, "object:Max score")
+	header[scaleColumnBand] = i18n.Translate(lang, "object:Band")
+	header[scaleColumnBandMinScore] = i18n.Translate(lang, "object:Band min score")
+	header[scaleColumnBandMaxScore] = i18n.Translate(lang, "object:Band max score")
+	header[scaleColumnDescriptor] = i18n.Translate(lang, "object:Descriptor")
+	addScaleExportRow(sheet, header)
+
+	for _, category := range scale.Categories {
+		for _, item := range category.Items {
+			row := make([]string, scaleColumnCount)
+			row[scaleColumnCategory] = category.Name
+			row[scaleColumnCategoryWeight] = formatScaleNumber(category.Weight)
+			row[scaleColumnItem] = item.Name
+			row[scaleColumnDescription] = item.Description
+			row[scaleColumnItemWeight] = formatScaleNumber(item.Weight)
+			row[scaleColumnMinScore] = formatScaleNumber(item.MinScore)
+			row[scaleColumnMaxScore] = formatScaleNumber(item.MaxScore)
+			if len(item.Bands) == 0 {
+				addScaleExportRow(sheet, row)
+				continue
+			}
+
+			for _, band := range item.Bands {
+				bandRow := append([]string{}, row...)
+				bandRow[scaleColumnBand] = band.Name
+				bandRow[scaleColumnBandMinScore] = fmt.Sprintf("%v", band.MinScore)
+				bandRow[scaleColumnBandMaxScore] = fmt.Sprintf("%v", band.MaxScore)
+				bandRow[scaleColumnDescriptor] = band.Descriptor
+				addScaleExportRow(sheet, bandRow)
+			}
+		}
+	}
+
+	var buffer bytes.Buffer
+	err = file.Write(&buffer)
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// ExportScaleJson writes the rubric fields of the scale as the JSON read back by ParseScale.
+func ExportScaleJson(scale *Scale) ([]byte, error) {
+	exported := &Scale{
+		DisplayName: scale.DisplayName,
+		Text:        scale.Text,
+		Prompt:      scale.Prompt,
+		Categories:  scale.Categories,
+		Version:     scale.Version,
+	}
+	return json.MarshalIndent(exported, "", "  ")
+}
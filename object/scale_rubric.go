@@ -0,0 +1,217 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/casibase/casibase/i18n"
+)
+
+func (item *ScaleItem) getScoreRange() (float64, float64) {
+	if item.MinScore == 0 && item.MaxScore == 0 {
+		return 0, 100
+	}
+	return item.MinScore, item.MaxScore
+}
+
+func getScaleWeight(weight float64) float64 {
+	if weight == 0 {
+		return 1
+	}
+	return weight
+}
+
+func (scale *Scale) isStructured() bool {
+	return scale != nil && len(scale.Categories) > 0
+}
+
+func (scale *Scale) getItem(categoryName string, itemName string) *ScaleItem {
+	for _, category := range scale.Categories {
+		if category.Name != categoryName {
+			continue
+		}
+		for _, item := range category.Items {
+			if item.Name == itemName {
+				return item
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateScale checks that the categories and the items of the scale are named uniquely, that the
+// weights are not negative and that the score ranges of the items contain the ranges of their bands.
+func ValidateScale(scale *Scale, lang string) error {
+	categoryNames := map[string]bool{}
+	for _, category := range scale.Categories {
+		if category.Name == "" || categoryNames[category.Name] {
+			return fmt.Errorf(i18n.Translate(lang, "object:The category name: %s of the scale is empty or duplicated"), category.Name)
+		}
+		categoryNames[category.Name] = true
+
+		if category.Weight < 0 {
+			return fmt.Errorf(i18n.Translate(lang, "object:The weight of: %s should not be negative"), category.Name)
+		}
+		if len(category.Items) == 0 {
+			return fmt.Errorf(i18n.Translate(lang, "object:The category: %s of the scale has no items"), category.Name)
+		}
+
+		itemNames := map[string]bool{}
+		for _, item := range category.Items {
+			if item.Name == "" || itemNames[item.Name] {
+				return fmt.Errorf(i18n.Translate(lang, "object:The item name: %s of the category: %s is empty or duplicated"), item.Name, category.Name)
+			}
+			itemNames[item.Name] = true
+
+			if item.Weight < 0 {
+				return fmt.Errorf(i18n.Translate(lang, "object:The weight of: %s should not be negative"), item.Name)
+			}
+			minScore, maxScore := item.getScoreRange()
+			if minScore >= maxScore {
+				return fmt.Errorf(i18n.Translate(lang, "object:The score range: [%v, %v] of the item: %s is empty"), minScore, maxScore, item.Name)
+			}
+			for _, band := range item.Bands {
+				if band.MinScore > band.MaxScore || band.MinScore < minScore || band.MaxScore > maxScore {
+					return fmt.Errorf(i18n.Translate(lang, "object:The band: %s of the item: %s is out of the score range of the item"), band.Name, item.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// GetRubricText returns the rubric given to the model: the free text of the scale followed by its
+// categories with their weights, the items with their score ranges and the descriptors of their bands.
+func (scale *Scale) GetRubricText(lang string) string {
+	if !scale.isStructured() {
+		return scale.Text
+	}
+
+	var builder strings.Builder
+	if scale.Text != "" {
+		builder.WriteString(scale.Text)
+		builder.WriteString("\n\n")
+	}
+
+	for i, category := range scale.Categories {
+		builder.WriteString(fmt.Sprintf("%d. %s (%s: %v)\n", i+1, category.Name, i18n.Translate(lang, "object:Weight"), getScaleWeight(category.Weight)))
+		for j, item := range category.Items {
+			minScore, maxScore := item.getScoreRange()
+			builder.WriteString(fmt.Sprintf("  %d.%d %s (%s: %v, %s: %v-%v)", i+1, j+1, item.Name, i18n.Translate(lang, "object:Weight"), getScaleWeight(item.Weight), i18n.Translate(lang, "object:Score range"), minScore, maxScore))
+			if item.Description != "" {
+				builder.WriteString(": " + item.Description)
+			}
+			builder.WriteString("\n")
+
+			for _, band := range item.Bands {
+				builder.WriteString(fmt.Sprintf("    - %s (%v-%v): %s\n", band.Name, band.MinScore, band.MaxScore, band.Descriptor))
+			}
+		}
+	}
+	return strings.TrimRight(builder.String(), "\n")
+}
+
+// validateTaskResultWithScale checks that the result scores every item of the structured scale
+// within the score range of the item.
+func validateTaskResultWithScale(result *TaskResult, scale *Scale, lang string) error {
+	scores := map[[2]string]float64{}
+	for _, category := range result.Categories {
+		for _, item := range category.Items {
+			scores[[2]string{category.Name, item.Name}] = item.Score
+		}
+	}
+
+	for _, category := range scale.Categories {
+		for _, item := range category.Items {
+			score, ok := scores[[2]string{category.Name, item.Name}]
+			if !ok {
+				return fmt.Errorf(i18n.Translate(lang, "object:The item: %s of the category: %s is missing from the analysis result"), item.Name, category.Name)
+			}
+
+			minScore, maxScore := item.getScoreRange()
+			if score < minScore || score > maxScore {
+				return fmt.Errorf(i18n.Translate(lang, "object:The score: %v of the item: %s is out of its range [%v, %v]"), score, item.Name, minScore, maxScore)
+			}
+		}
+	}
+	return nil
+}
+
+// getTaskResultScores computes the score of each category of the result and its overall score from
+// the item scores. For a structured scale, the item scores are brought to 0-100 from their ranges,
+// a category scores the weighted average of its items and the overall score is the weighted average
+// of the categories. Otherwise a category scores the average of its items and the overall score is
+// the average of all the items.
+func getTaskResultScores(result *TaskResult, scale *Scale) (map[string]float64, float64) {
+	categoryScores := map[string]float64{}
+	if !scale.isStructured() {
+		for _, category := range result.Categories {
+			total := 0.0
+			for _, item := range category.Items {
+				total += item.Score
+			}
+			if len(category.Items) > 0 {
+				categoryScores[category.Name] = total / float64(len(category.Items))
+			}
+		}
+		return categoryScores, getTaskResultItemAverage(result)
+	}
+
+	total := 0.0
+	totalWeight := 0.0
+	for _, category := range result.Categories {
+		categoryTotal := 0.0
+		categoryWeight := 0.0
+		for _, item := range category.Items {
+			scaleItem := scale.getItem(category.Name, item.Name)
+			if scaleItem == nil {
+				continue
+			}
+
+			minScore, maxScore := scaleItem.getScoreRange()
+			weight := getScaleWeight(scaleItem.Weight)
+			categoryTotal += weight * (item.Score - minScore) / (maxScore - minScore) * 100
+			categoryWeight += weight
+		}
+		if categoryWeight == 0 {
+			continue
+		}
+		categoryScores[category.Name] = categoryTotal / categoryWeight
+	}
+
+	for _, category := range scale.Categories {
+		score, ok := categoryScores[category.Name]
+		if !ok {
+			continue
+		}
+		weight := getScaleWeight(category.Weight)
+		total += weight * score
+		totalWeight += weight
+	}
+	if totalWeight == 0 {
+		return categoryScores, 0
+	}
+	return categoryScores, total / totalWeight
+}
+
+func setTaskResultScores(result *TaskResult, scale *Scale) {
+	categoryScores, score := getTaskResultScores(result, scale)
+	for _, category := range result.Categories {
+		category.Score = roundTaskScore(categoryScores[category.Name], 2)
+	}
+	result.Score = roundTaskScore(score, 1)
+}
@@ -0,0 +1,137 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build skipCi
+// +build skipCi
+
+package object
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newTestScale() *Scale {
+	return &Scale{
+		Owner: "admin",
+		Name:  "scale_1",
+		Categories: []*ScaleCategory{
+			{
+				Name:   "Objectives",
+				Weight: 3,
+				Items: []*ScaleItem{
+					{
+						Name:     "Standards",
+						Weight:   2,
+						MinScore: 0,
+						MaxScore: 10,
+						Bands: []*ScaleBand{
+							{Name: "Good", MinScore: 8, MaxScore: 10, Descriptor: "Aligned with the standards"},
+							{Name: "Poor", MinScore: 0, MaxScore: 7, Descriptor: "Not aligned"},
+						},
+					},
+					{Name: "Clarity", Description: "Clear and measurable"},
+				},
+			},
+			{
+				Name:  "Activities",
+				Items: []*ScaleItem{{Name: "Engagement"}},
+			},
+		},
+	}
+}
+
+func TestValidateScale(t *testing.T) {
+	scale := newTestScale()
+	if err := ValidateScale(scale, "en"); err != nil {
+		t.Errorf("unexpected error for a valid scale: %v", err)
+	}
+
+	scale.Categories[0].Items[0].Bands[0].MaxScore = 12
+	if err := ValidateScale(scale, "en"); err == nil {
+		t.Errorf("expected an error for a band out of the range of its item")
+	}
+
+	scale = newTestScale()
+	scale.Categories[1].Items = append(scale.Categories[1].Items, &ScaleItem{Name: "Engagement"})
+	if err := ValidateScale(scale, "en"); err == nil {
+		t.Errorf("expected an error for a duplicated item")
+	}
+}
+
+func TestGetRubricText(t *testing.T) {
+	text := newTestScale().GetRubricText("en")
+	for _, expected := range []string{"1. Objectives (Weight: 3)", "1.1 Standards (Weight: 2, Score range: 0-10)", "- Good (8-10): Aligned with the standards", "1.2 Clarity (Weight: 1, Score range: 0-100): Clear and measurable"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("expected the rubric to contain %q, got:\n%s", expected, text)
+		}
+	}
+}
+
+func TestWeightedTaskResultScores(t *testing.T) {
+	scale := newTestScale()
+	result := &TaskResult{
+		Score: 99,
+		Categories: []*TaskResultCategory{
+			{Name: "Objectives", Items: []*TaskResultItem{{Name: "Standards", Score: 8}, {Name: "Clarity", Score: 50}}},
+			{Name: "Activities", Items: []*TaskResultItem{{Name: "Engagement", Score: 90}}},
+		},
+	}
+	if err := ValidateTaskResult(result, scale, "en"); err != nil {
+		t.Fatalf("unexpected error for a valid result: %v", err)
+	}
+
+	calibrated := CalibrateTaskResults([]*TaskResult{result}, scale)
+	// objectives: (2 * 80 + 1 * 50) / 3 = 70, overall: (3 * 70 + 1 * 90) / 4 = 75
+	if calibrated.Categories[0].Score != 70 || calibrated.Categories[1].Score != 90 || calibrated.Score != 75 {
+		t.Errorf("expected the weighted scores 70, 90 and 75, got: %v, %v and %v", calibrated.Categories[0].Score, calibrated.Categories[1].Score, calibrated.Score)
+	}
+
+	result.Categories[0].Items[0].Score = 11
+	if err := ValidateTaskResult(result, scale, "en"); err == nil {
+		t.Errorf("expected an error for a score out of the range of its item")
+	}
+	result.Categories[0].Items = result.Categories[0].Items[1:]
+	if err := ValidateTaskResult(result, scale, "en"); err == nil {
+		t.Errorf("expected an error for a missing item")
+	}
+}
+
+func TestExportAndParseScale(t *testing.T) {
+	scale := newTestScale()
+	data, err := ExportScaleXlsx(scale, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseScale("scale.xlsx", data, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed.Categories, scale.Categories) {
+		t.Errorf("expected the XLSX export to be parsed back to the same categories")
+	}
+
+	data, err = ExportScaleJson(scale)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err = ParseScale("scale.json", data, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed.Categories, scale.Categories) {
+		t.Errorf("expected the JSON export to be parsed back to the same categories")
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"encoding/json"
+
+	"github.com/casibase/casibase/util"
+	"xorm.io/core"
+)
+
+// ScaleVersion is a snapshot of the rubric of a scale, taken each time the rubric changes.
+// Tasks keep the version they were graded against in Task.ScaleVersion.
+type ScaleVersion struct {
+	Owner       string `xorm:"varchar(100) notnull pk" json:"owner"`
+	Name        string `xorm:"varchar(100) notnull pk" json:"name"`
+	Version     int    `xorm:"notnull pk" json:"version"`
+	CreatedTime string `xorm:"varchar(100)" json:"createdTime"`
+
+	DisplayName string           `xorm:"varchar(100)" json:"displayName"`
+	Text        string           `xorm:"mediumtext" json:"text"`
+	Prompt      string           `xorm:"mediumtext" json:"prompt"`
+	Categories  []*ScaleCategory `xorm:"mediumtext" json:"categories"`
+}
+
+func (scale *Scale) isRubricChanged(other *Scale) bool {
+	if scale.Text != other.Text || scale.Prompt != other.Prompt {
+		return true
+	}
+
+	categories, _ := json.Marshal(scale.Categories)
+	otherCategories, _ := json.Marshal(other.Categories)
+	return string(categories) != string(otherCategories)
+}
+
+func getNextScaleVersion(owner string, name string) (int, error) {
+	scaleVersion := ScaleVersion{}
+	existed, err := adapter.engine.Where("owner = ? and name = ?", owner, name).Desc("version").Get(&scaleVersion)
+	if err != nil {
+		return 0, err
+	}
+	if !existed {
+		return 1, nil
+	}
+	return scaleVersion.Version + 1, nil
+}
+
+func addScaleVersion(scale *Scale) error {
+	scaleVersion := &ScaleVersion{
+		Owner:       scale.Owner,
+		Name:        scale.Name,
+		Version:     scale.Version,
+		CreatedTime: util.GetCurrentTime(),
+		DisplayName: scale.DisplayName,
+		Text:        scale.Text,
+		Prompt:      scale.Prompt,
+		Categories:  scale.Categories,
+	}
+	_, err := adapter.engine.Insert(scaleVersion)
+	return err
+}
+
+// ensureScaleVersion gives a version to a scale created before scales were versioned, so that the
+// tasks graded against it can reference it.
+func ensureScaleVersion(scale *Scale) error {
+	if scale.Version != 0 {
+		return nil
+	}
+
+	version, err := getNextScaleVersion(scale.Owner, scale.Name)
+	if err != nil {
+		return err
+	}
+	scale.Version = version
+	err = addScaleVersion(scale)
+	if err != nil {
+		return err
+	}
+
+	_, err = adapter.engine.ID(core.PK{scale.Owner, scale.Name}).Cols("version").Update(scale)
+	return err
+}
+
+func GetScaleVersions(owner string, name string) ([]*ScaleVersion, error) {
+	scaleVersions := []*ScaleVersion{}
+	err := adapter.engine.Where("owner = ? and name = ?", owner, name).Desc("version").Find(&scaleVersions)
+	if err != nil {
+		return scaleVersions, err
+	}
+
+	return scaleVersions, nil
+}
+
+// GetScaleOfVersion returns the scale of the id as it was at the version.
+func GetScaleOfVersion(id string, version int) (*Scale, error) {
+	owner, name, err := util.GetOwnerAndNameFromIdWithError(id)
+	if err != nil {
+		return nil, err
+	}
+
+	scaleVersion := ScaleVersion{Owner: owner, Name: name, Version: version}
+	existed, err := adapter.engine.Get(&scaleVersion)
+	if err != nil {
+		return nil, err
+	}
+	if !existed {
+		return nil, nil
+	}
+
+	scale := &Scale{
+		Owner:       scaleVersion.Owner,
+		Name:        scaleVersion.Name,
+		CreatedTime: scaleVersion.CreatedTime,
+		DisplayName: scaleVersion.DisplayName,
+		Text:        scaleVersion.Text,
+		Prompt:      scaleVersion.Prompt,
+		Categories:  scaleVersion.Categories,
+		Version:     scaleVersion.Version,
+	}
+	return scale, nil
+}
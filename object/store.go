@@ -19,6 +19,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/casibase/casibase/agent"
+	"github.com/casibase/casibase/agent/builtin_tool"
 	"github.com/casibase/casibase/i18n"
 	"github.com/casibase/casibase/storage"
 	"github.com/casibase/casibase/util"
@@ -71,6 +73,7 @@ type Store struct {
 	EnableTtsStreaming   bool     `xorm:"bool" json:"enableTtsStreaming"`
 	SpeechToTextProvider string   `xorm:"varchar(100)" json:"speechToTextProvider"`
 	AgentProvider        string   `xorm:"varchar(100)" json:"agentProvider"`
+	ImageModelProvider   string   `xorm:"varchar(100)" json:"imageModelProvider"`
 	VectorStoreId        string   `xorm:"varchar(100)" json:"vectorStoreId"`
 	BuiltinTools         []string `xorm:"varchar(500)" json:"builtinTools"`
 
@@ -102,6 +105,27 @@ type Store struct {
 	IsDefault           bool              `json:"isDefault"`
 	State               string            `xorm:"varchar(100)" json:"state"`
 
+	EnableSemanticCache    bool    `json:"enableSemanticCache"`
+	SemanticCacheThreshold float32 `json:"semanticCacheThreshold"`
+	SemanticCacheMinutes   int     `json:"semanticCacheMinutes"`
+
+	EnableSummaryMemory bool `json:"enableSummaryMemory"`
+	SummaryThreshold    int  `json:"summaryThreshold"`
+
+	AgentMaxSteps       int  `json:"agentMaxSteps"`
+	AgentTimeoutSeconds int  `json:"agentTimeoutSeconds"`
+	ToolTimeoutSeconds  int  `json:"toolTimeoutSeconds"`
+	EnableDelegation    bool `json:"enableDelegation"`
+
+	WebAllowedDomains  []string `xorm:"text" json:"webAllowedDomains"`
+	WebDeniedDomains   []string `xorm:"text" json:"webDeniedDomains"`
+	WebMaxResponseSize int      `json:"webMaxResponseSize"`
+
+	McpResources []string `xorm:"text" json:"mcpResources"`
+	McpPrompt    string   `xorm:"varchar(500)" json:"mcpPrompt"`
+
+	Experiment *StoreExperiment `xorm:"mediumtext" json:"experiment"`
+
 	ChatCount    int `xorm:"-" json:"chatCount"`
 	MessageCount int `xorm:"-" json:"messageCount"`
 
@@ -181,7 +205,7 @@ func UpdateStore(id string, store *Store) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	_, err = getStore(owner, name)
+	oldStore, err := getStore(owner, name)
 	if err != nil {
 		return false, err
 	}
@@ -194,6 +218,13 @@ func UpdateStore(id string, store *Store) (bool, error) {
 		return false, err
 	}
 
+	if oldStore != nil && (oldStore.Prompt != store.Prompt || oldStore.EmbeddingProvider != store.EmbeddingProvider) {
+		_, err = DeleteAnswerCachesByStore(owner, name)
+		if err != nil {
+			return false, err
+		}
+	}
+
 	// return affected != 0
 	return true, nil
 }
@@ -284,6 +315,29 @@ func (store *Store) GetSpeechToTextProvider() (*Provider, error) {
 	return GetProvider(providerId)
 }
 
+func (store *Store) GetImageModelProvider() (*Provider, error) {
+	if store.ImageModelProvider == "" {
+		return nil, nil
+	}
+
+	providerId := util.GetIdFromOwnerAndName(store.Owner, store.ImageModelProvider)
+	return GetProvider(providerId)
+}
+
+// getSelectedBuiltinTool returns the builtin tool of the agent clients when the store has selected it.
+func (store *Store) getSelectedBuiltinTool(agentClients *agent.AgentClients, name string) (builtin_tool.BuiltinTool, bool) {
+	if agentClients == nil || agentClients.BuiltinToolReg == nil {
+		return nil, false
+	}
+
+	for _, toolName := range store.BuiltinTools {
+		if toolName == name {
+			return agentClients.BuiltinToolReg.GetTool(name)
+		}
+	}
+	return nil, false
+}
+
 func (store *Store) GetEmbeddingProvider() (*Provider, error) {
 	if store.EmbeddingProvider == "" {
 		return GetDefaultEmbeddingProvider()
@@ -421,6 +475,17 @@ func GetStoresByFields(owner string, fields ...string) ([]*Store, error) {
 	return stores, nil
 }
 
+// GetStoresByProvider returns the stores using the provider as their storage, model or embedding provider.
+func GetStoresByProvider(owner string, providerName string) ([]*Store, error) {
+	stores := []*Store{}
+	err := adapter.engine.Desc("created_time").Where("storage_provider = ? or model_provider = ? or embedding_provider = ?", providerName, providerName, providerName).Find(&stores, &Store{Owner: owner})
+	if err != nil {
+		return stores, err
+	}
+
+	return stores, nil
+}
+
 func GetStoreCount(name, field, value string) (int64, error) {
 	session := GetDbSession("", -1, -1, field, value, "", "")
 	return session.Count(&Store{Name: name})
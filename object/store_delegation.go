@@ -0,0 +1,272 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/casibase/casibase/agent"
+	"github.com/casibase/casibase/agent/builtin_tool"
+	"github.com/casibase/casibase/agent/builtin_tool/store"
+	"github.com/casibase/casibase/i18n"
+	"github.com/casibase/casibase/model"
+)
+
+// maxDelegationDepth is the number of stores in a chain of delegations, the router store included.
+const maxDelegationDepth = 3
+
+const defaultDelegationPrompt = "You are an expert in your field and you specialize in using your knowledge to answer or solve people's problems."
+
+// StoreDelegation lets a router store delegate questions to its child stores: each child store is
+// offered to the model as a tool answering with the child's own knowledge, prompt, model and tools.
+// The children may delegate to their own child stores, the tool calls and the costs of the whole
+// tree are gathered for the answer message of the router store.
+type StoreDelegation struct {
+	store *Store
+	path  []string
+	lang  string
+
+	mutex       sync.Mutex
+	childStores map[string]*Store
+	modelResult *model.ModelResult
+	toolCalls   []model.ToolCall
+}
+
+func NewStoreDelegation(store *Store, lang string) *StoreDelegation {
+	return newStoreDelegation(store, nil, lang)
+}
+
+func newStoreDelegation(store *Store, parentPath []string, lang string) *StoreDelegation {
+	return &StoreDelegation{
+		store:       store,
+		path:        append(append([]string{}, parentPath...), store.Name),
+		lang:        lang,
+		childStores: map[string]*Store{},
+	}
+}
+
+// getChildStoreDescription describes the child store to the model from its display name, title
+// and welcome texts.
+func getChildStoreDescription(store *Store) string {
+	texts := []string{}
+	for _, text := range []string{store.DisplayName, store.Title, store.Welcome, store.WelcomeTitle, store.WelcomeText} {
+		text = strings.TrimSpace(text)
+		if text == "" || text == store.Name {
+			continue
+		}
+
+		isDuplicated := false
+		for _, t := range texts {
+			if t == text {
+				isDuplicated = true
+				break
+			}
+		}
+		if !isDuplicated {
+			texts = append(texts, text)
+		}
+	}
+	return strings.Join(texts, ". ")
+}
+
+// BindTools offers the child stores to the model when the store has enabled delegation, and
+// returns the agent clients, created if the store had no tools. The stores already in the chain
+// of delegations are not offered again, and the chain stops at maxDelegationDepth.
+func (d *StoreDelegation) BindTools(agentClients *agent.AgentClients) *agent.AgentClients {
+	if !d.store.EnableDelegation || len(d.store.ChildStores) == 0 || len(d.path) >= maxDelegationDepth {
+		return agentClients
+	}
+
+	tools := []builtin_tool.BuiltinTool{}
+	for _, name := range d.store.ChildStores {
+		if name == "" || d.isInPath(name) || d.childStores[name] != nil {
+			continue
+		}
+
+		childStore, err := getStore(d.store.Owner, name)
+		if err != nil {
+			fmt.Printf("StoreDelegation.BindTools() error, failed to get the child store: %s, %s\n", name, err.Error())
+			continue
+		}
+		if childStore == nil {
+			fmt.Printf("StoreDelegation.BindTools() error, the child store: %s is not found\n", name)
+			continue
+		}
+
+		d.childStores[name] = childStore
+		tools = append(tools, &storetools.AskStoreTool{
+			Name:        storetools.GetToolName(name),
+			Store:       name,
+			Description: getChildStoreDescription(childStore),
+			Ask:         d.Ask,
+		})
+	}
+
+	if len(tools) == 0 {
+		return agentClients
+	}
+
+	if agentClients == nil {
+		agentClients = &agent.AgentClients{}
+	}
+	if agentClients.BuiltinToolReg == nil {
+		agentClients.BuiltinToolReg = builtin_tool.NewToolRegistry()
+	}
+
+	toolNames := map[string]bool{}
+	for _, tool := range tools {
+		agentClients.BuiltinToolReg.RegisterTool(tool)
+		toolNames[tool.GetName()] = true
+	}
+	for _, protocolTool := range agentClients.BuiltinToolReg.GetToolsAsProtocolTools() {
+		if toolNames[protocolTool.Name] {
+			agentClients.Tools = append(agentClients.Tools, protocolTool)
+		}
+	}
+	return agentClients
+}
+
+func (d *StoreDelegation) isInPath(name string) bool {
+	for _, storeName := range d.path {
+		if storeName == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Ask answers the question with the child store like in its own chat, without history.
+func (d *StoreDelegation) Ask(ctx context.Context, storeName string, question string) (string, error) {
+	store := d.childStores[storeName]
+	if store == nil {
+		return "", fmt.Errorf(i18n.Translate(d.lang, "account:The store: %s is not found"), storeName)
+	}
+
+	modelProvider, modelProviderObj, err := GetModelProviderFromContext("admin", store.ModelProvider, d.lang)
+	if err != nil {
+		return "", err
+	}
+
+	embeddingProvider, embeddingProviderObj, err := GetEmbeddingProviderFromContext("admin", store.EmbeddingProvider, d.lang)
+	if err != nil {
+		return "", err
+	}
+
+	_, agentProviderObj, err := GetAgentProviderFromContext("admin", store.AgentProvider, d.lang)
+	if err != nil {
+		return "", err
+	}
+
+	agentClients, err := GetAgentClients(agentProviderObj)
+	if err != nil {
+		return "", err
+	}
+	defer agentClients.Release()
+
+	agentClients = agent.MergeBuiltinAndWebSearchTools(agentClients, store.BuiltinTools, false)
+	knowledgeSearch := NewKnowledgeSearch(store, embeddingProvider, embeddingProviderObj, modelProvider, d.lang)
+	knowledgeSearch.BindTool(agentClients)
+	store.BindWebTools(agentClients, d.lang)
+	childDelegation := newStoreDelegation(store, d.path, d.lang)
+	agentClients = childDelegation.BindTools(agentClients)
+
+	knowledgeCount := store.KnowledgeCount
+	if knowledgeCount <= 0 {
+		knowledgeCount = 10
+	}
+
+	knowledge, _, embeddingResult, err := GetNearestKnowledge(store.Name, store.VectorStores, store.SearchProvider, embeddingProvider, embeddingProviderObj, modelProvider, "admin", question, knowledgeCount, d.lang)
+	if err != nil && err.Error() != "no knowledge vectors found" {
+		return "", err
+	}
+	knowledge = append(knowledge, store.GetMcpKnowledge(ctx, agentClients)...)
+
+	prompt := store.GetPrompt(ctx, agentClients)
+	if prompt == "" {
+		prompt = defaultDelegationPrompt
+	}
+
+	writer := &model.AnswerWriter{}
+	var modelResult *model.ModelResult
+	if agentClients != nil {
+		agentInfo := &model.AgentInfo{
+			AgentClients:       agentClients,
+			AgentMessages:      &model.AgentMessages{Messages: []*model.RawMessage{}},
+			Context:            ctx,
+			MaxSteps:           store.AgentMaxSteps,
+			TimeoutSeconds:     store.AgentTimeoutSeconds,
+			ToolTimeoutSeconds: store.ToolTimeoutSeconds,
+		}
+		modelResult, err = model.QueryTextWithTools(modelProviderObj, question, writer, []*model.RawMessage{}, prompt, knowledge, agentInfo, d.lang)
+	} else {
+		modelResult, err = modelProviderObj.QueryText(question, writer, []*model.RawMessage{}, prompt, knowledge, nil, d.lang)
+	}
+
+	// the costs of the child store are charged even when it fails to answer
+	if embeddingResult != nil {
+		d.addModelResult(&model.ModelResult{
+			TotalTokenCount: embeddingResult.TokenCount,
+			TotalPrice:      embeddingResult.Price,
+			Currency:        embeddingResult.Currency,
+		}, nil)
+	}
+	d.addModelResult(childDelegation.GetModelResult(), childDelegation.GetToolCalls())
+	if err != nil {
+		return "", err
+	}
+
+	toolCalls := model.GetToolCallsFromWriter(writer.ToolString())
+	for i := range toolCalls {
+		toolCalls[i].Store = store.Name
+	}
+	d.addModelResult(modelResult, toolCalls)
+
+	return strings.TrimSpace(writer.String()), nil
+}
+
+func (d *StoreDelegation) addModelResult(modelResult *model.ModelResult, toolCalls []model.ToolCall) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if modelResult != nil {
+		if d.modelResult == nil {
+			d.modelResult = &model.ModelResult{}
+		}
+		model.AddModelResults(d.modelResult, modelResult)
+	}
+	d.toolCalls = append(d.toolCalls, toolCalls...)
+}
+
+// GetModelResult returns the costs of all the delegated questions so far, or nil if there was none.
+func (d *StoreDelegation) GetModelResult() *model.ModelResult {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.modelResult == nil {
+		return nil
+	}
+	modelResult := *d.modelResult
+	return &modelResult
+}
+
+// GetToolCalls returns the tool calls made by the child stores so far, with the store making each call.
+func (d *StoreDelegation) GetToolCalls() []model.ToolCall {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return append([]model.ToolCall{}, d.toolCalls...)
+}
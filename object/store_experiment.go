@@ -0,0 +1,310 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"hash/fnv"
+	"math"
+)
+
+// StoreVariant is an arm of a store experiment, its empty fields keep the store's settings.
+type StoreVariant struct {
+	Name           string `json:"name"`
+	Weight         int    `json:"weight"`
+	Prompt         string `json:"prompt"`
+	ModelProvider  string `json:"modelProvider"`
+	KnowledgeCount int    `json:"knowledgeCount"`
+	SearchProvider string `json:"searchProvider"`
+}
+
+// StoreExperiment splits the chats of a store between variants of its settings. The first variant
+// is the control the others are compared to in the report.
+type StoreExperiment struct {
+	Name      string          `json:"name"`
+	IsEnabled bool            `json:"isEnabled"`
+	Variants  []*StoreVariant `json:"variants"`
+}
+
+func getVariantWeight(variant *StoreVariant) int {
+	if variant.Weight <= 0 {
+		return 1
+	}
+	return variant.Weight
+}
+
+// getVariantByUser picks the variant of the user from the hash of the experiment name and the
+// user, so that a user keeps the same variant across chats and a new experiment reshuffles users.
+func getVariantByUser(experiment *StoreExperiment, user string) *StoreVariant {
+	totalWeight := 0
+	for _, variant := range experiment.Variants {
+		totalWeight += getVariantWeight(variant)
+	}
+	if totalWeight == 0 {
+		return nil
+	}
+
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(experiment.Name + "/" + user))
+	bucket := int(hash.Sum32() % uint32(totalWeight))
+	for _, variant := range experiment.Variants {
+		bucket -= getVariantWeight(variant)
+		if bucket < 0 {
+			return variant
+		}
+	}
+	return nil
+}
+
+// GetExperimentVariant returns the variant of the running experiment of the store for the chat,
+// nil when the store has no running experiment. A chat already assigned to a variant of the
+// experiment keeps it even if the weights have changed since.
+func (store *Store) GetExperimentVariant(chat *Chat) *StoreVariant {
+	experiment := store.Experiment
+	if experiment == nil || !experiment.IsEnabled || len(experiment.Variants) == 0 {
+		return nil
+	}
+
+	if chat.Experiment == experiment.Name {
+		for _, variant := range experiment.Variants {
+			if variant.Name == chat.Variant {
+				return variant
+			}
+		}
+	}
+
+	user := chat.User
+	if user == "" {
+		user = chat.Name
+	}
+	return getVariantByUser(experiment, user)
+}
+
+// WithVariant returns a copy of the store with the settings of the variant.
+func (store *Store) WithVariant(variant *StoreVariant) *Store {
+	if variant == nil {
+		return store
+	}
+
+	res := *store
+	if variant.Prompt != "" {
+		res.Prompt = variant.Prompt
+	}
+	if variant.ModelProvider != "" {
+		res.ModelProvider = variant.ModelProvider
+	}
+	if variant.KnowledgeCount > 0 {
+		res.KnowledgeCount = variant.KnowledgeCount
+	}
+	if variant.SearchProvider != "" {
+		res.SearchProvider = variant.SearchProvider
+	}
+	return &res
+}
+
+// VariantReport sums up the answers of a variant. The like and dislike rates are over the rated
+// answers only, the feedback coverage is the share of the answers rated at all. The p-values
+// compare the variant to the control variant: a two-proportion z-test for the rates and a Welch
+// z-test for the means, they are 1 for the control itself and when either side has too few
+// answers to tell.
+type VariantReport struct {
+	Variant      string `json:"variant"`
+	ChatCount    int    `json:"chatCount"`
+	MessageCount int    `json:"messageCount"`
+	RatedCount   int    `json:"ratedCount"`
+	LikeCount    int    `json:"likeCount"`
+	DislikeCount int    `json:"dislikeCount"`
+
+	FeedbackCoverage  float64 `json:"feedbackCoverage"`
+	LikeRate          float64 `json:"likeRate"`
+	DislikeRate       float64 `json:"dislikeRate"`
+	AverageTokenCount float64 `json:"averageTokenCount"`
+	AveragePrice      float64 `json:"averagePrice"`
+	Currency          string  `json:"currency"`
+	AverageLatency    float64 `json:"averageLatency"`
+
+	LikeRatePValue    float64 `json:"likeRatePValue"`
+	DislikeRatePValue float64 `json:"dislikeRatePValue"`
+	TokenCountPValue  float64 `json:"tokenCountPValue"`
+	LatencyPValue     float64 `json:"latencyPValue"`
+
+	tokenCounts []float64
+	latencies   []float64
+}
+
+type ExperimentReport struct {
+	Store             string           `json:"store"`
+	Experiment        string           `json:"experiment"`
+	Control           string           `json:"control"`
+	SignificanceLevel float64          `json:"significanceLevel"`
+	Variants          []*VariantReport `json:"variants"`
+}
+
+const experimentSignificanceLevel = 0.05
+
+// getProportionPValue is the two-sided p-value of the difference between the proportions
+// successes1 / count1 and successes2 / count2.
+func getProportionPValue(successes1 int, count1 int, successes2 int, count2 int) float64 {
+	if count1 == 0 || count2 == 0 {
+		return 1
+	}
+
+	pooled := float64(successes1+successes2) / float64(count1+count2)
+	standardError := math.Sqrt(pooled * (1 - pooled) * (1/float64(count1) + 1/float64(count2)))
+	if standardError == 0 {
+		return 1
+	}
+
+	z := (float64(successes1)/float64(count1) - float64(successes2)/float64(count2)) / standardError
+	return math.Erfc(math.Abs(z) / math.Sqrt2)
+}
+
+func getMeanAndVariance(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	mean := 0.0
+	for _, value := range values {
+		mean += value
+	}
+	mean /= float64(len(values))
+	if len(values) == 1 {
+		return mean, 0
+	}
+
+	variance := 0.0
+	for _, value := range values {
+		variance += (value - mean) * (value - mean)
+	}
+	return mean, variance / float64(len(values)-1)
+}
+
+// getMeanPValue is the two-sided p-value of the difference between the means, with the normal
+// approximation of the Welch statistic which holds for the sample sizes of live traffic.
+func getMeanPValue(values1 []float64, values2 []float64) float64 {
+	if len(values1) < 2 || len(values2) < 2 {
+		return 1
+	}
+
+	mean1, variance1 := getMeanAndVariance(values1)
+	mean2, variance2 := getMeanAndVariance(values2)
+	standardError := math.Sqrt(variance1/float64(len(values1)) + variance2/float64(len(values2)))
+	if standardError == 0 {
+		return 1
+	}
+
+	z := (mean1 - mean2) / standardError
+	return math.Erfc(math.Abs(z) / math.Sqrt2)
+}
+
+func getExperimentReport(store *Store, messages []*Message) *ExperimentReport {
+	report := &ExperimentReport{
+		Store:             store.Name,
+		Experiment:        store.Experiment.Name,
+		SignificanceLevel: experimentSignificanceLevel,
+		Variants:          []*VariantReport{},
+	}
+
+	variantReports := map[string]*VariantReport{}
+	for _, variant := range store.Experiment.Variants {
+		variantReport := &VariantReport{Variant: variant.Name, tokenCounts: []float64{}, latencies: []float64{}}
+		variantReports[variant.Name] = variantReport
+		report.Variants = append(report.Variants, variantReport)
+	}
+	if len(report.Variants) == 0 {
+		return report
+	}
+	report.Control = report.Variants[0].Variant
+
+	chats := map[string]map[string]bool{}
+	prices := map[string]float64{}
+	for _, message := range messages {
+		variantReport, ok := variantReports[message.Variant]
+		if !ok {
+			continue
+		}
+
+		if chats[message.Variant] == nil {
+			chats[message.Variant] = map[string]bool{}
+		}
+		chats[message.Variant][message.Chat] = true
+
+		variantReport.MessageCount++
+		if len(message.LikeUsers) > 0 || len(message.DisLikeUsers) > 0 {
+			variantReport.RatedCount++
+		}
+		if len(message.LikeUsers) > 0 {
+			variantReport.LikeCount++
+		}
+		if len(message.DisLikeUsers) > 0 {
+			variantReport.DislikeCount++
+		}
+		variantReport.tokenCounts = append(variantReport.tokenCounts, float64(message.TokenCount))
+		if message.Latency > 0 {
+			variantReport.latencies = append(variantReport.latencies, float64(message.Latency))
+		}
+
+		// the prices in other currencies than the first one of the variant cannot be averaged
+		if variantReport.Currency == "" {
+			variantReport.Currency = message.Currency
+		}
+		if message.Currency == variantReport.Currency {
+			prices[message.Variant] += message.Price
+		}
+	}
+
+	control := report.Variants[0]
+	for _, variantReport := range report.Variants {
+		variantReport.ChatCount = len(chats[variantReport.Variant])
+		if variantReport.MessageCount > 0 {
+			variantReport.FeedbackCoverage = float64(variantReport.RatedCount) / float64(variantReport.MessageCount)
+			variantReport.AveragePrice = prices[variantReport.Variant] / float64(variantReport.MessageCount)
+		}
+		if variantReport.RatedCount > 0 {
+			variantReport.LikeRate = float64(variantReport.LikeCount) / float64(variantReport.RatedCount)
+			variantReport.DislikeRate = float64(variantReport.DislikeCount) / float64(variantReport.RatedCount)
+		}
+		variantReport.AverageTokenCount, _ = getMeanAndVariance(variantReport.tokenCounts)
+		variantReport.AverageLatency, _ = getMeanAndVariance(variantReport.latencies)
+
+		variantReport.LikeRatePValue = 1
+		variantReport.DislikeRatePValue = 1
+		variantReport.TokenCountPValue = 1
+		variantReport.LatencyPValue = 1
+		if variantReport != control {
+			variantReport.LikeRatePValue = getProportionPValue(variantReport.LikeCount, variantReport.RatedCount, control.LikeCount, control.RatedCount)
+			variantReport.DislikeRatePValue = getProportionPValue(variantReport.DislikeCount, variantReport.RatedCount, control.DislikeCount, control.RatedCount)
+			variantReport.TokenCountPValue = getMeanPValue(variantReport.tokenCounts, control.tokenCounts)
+			variantReport.LatencyPValue = getMeanPValue(variantReport.latencies, control.latencies)
+		}
+	}
+	return report
+}
+
+// GetExperimentReport reports the answers of the store's experiment per variant.
+func GetExperimentReport(store *Store) (*ExperimentReport, error) {
+	if store.Experiment == nil {
+		return &ExperimentReport{Store: store.Name, SignificanceLevel: experimentSignificanceLevel, Variants: []*VariantReport{}}, nil
+	}
+
+	messages := []*Message{}
+	err := adapter.engine.Cols("chat", "variant", "like_users", "dis_like_users", "token_count", "price", "currency", "latency").
+		Where("store = ? and experiment = ? and author = ?", store.Name, store.Experiment.Name, "AI").Find(&messages)
+	if err != nil {
+		return nil, err
+	}
+
+	return getExperimentReport(store, messages), nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build skipCi
+// +build skipCi
+
+package object
+
+import (
+	"fmt"
+	"testing"
+)
+
+func getTestExperimentStore() *Store {
+	return &Store{
+		Name:          "store-built-in",
+		ModelProvider: "gpt-4o",
+		Prompt:        "You are a helpful assistant.",
+		Experiment: &StoreExperiment{
+			Name:      "prompt-test",
+			IsEnabled: true,
+			Variants: []*StoreVariant{
+				{Name: "control", Weight: 1},
+				{Name: "concise", Weight: 3, Prompt: "Answer in one sentence.", KnowledgeCount: 3},
+			},
+		},
+	}
+}
+
+func TestGetExperimentVariant(t *testing.T) {
+	store := getTestExperimentStore()
+
+	counts := map[string]int{}
+	for i := 0; i < 4000; i++ {
+		chat := &Chat{Name: fmt.Sprintf("chat_%d", i), User: fmt.Sprintf("user_%d", i)}
+		variant := store.GetExperimentVariant(chat)
+		if variant != store.GetExperimentVariant(&Chat{Name: "another_chat", User: chat.User}) {
+			t.Fatalf("the user: %s got different variants across chats", chat.User)
+		}
+		counts[variant.Name]++
+	}
+	if counts["concise"] < 2800 || counts["concise"] > 3200 {
+		t.Errorf("got %d of 4000 users in the variant of weight 3 of 4, want about 3000", counts["concise"])
+	}
+
+	chat := &Chat{User: "user_1", Experiment: "prompt-test", Variant: "control"}
+	if variant := store.GetExperimentVariant(chat); variant.Name != "control" {
+		t.Errorf("got variant %s for a chat already assigned to control", variant.Name)
+	}
+
+	variantStore := store.WithVariant(store.Experiment.Variants[1])
+	if variantStore.Prompt != "Answer in one sentence." || variantStore.KnowledgeCount != 3 || variantStore.ModelProvider != "gpt-4o" {
+		t.Errorf("WithVariant() got prompt %q, knowledge count %d and model %s", variantStore.Prompt, variantStore.KnowledgeCount, variantStore.ModelProvider)
+	}
+	if store.Prompt != "You are a helpful assistant." {
+		t.Errorf("WithVariant() changed the store itself")
+	}
+
+	store.Experiment.IsEnabled = false
+	if store.GetExperimentVariant(chat) != nil {
+		t.Errorf("got a variant for a stopped experiment")
+	}
+}
+
+func TestGetExperimentReport(t *testing.T) {
+	store := getTestExperimentStore()
+
+	messages := []*Message{}
+	for i := 0; i < 200; i++ {
+		control := &Message{Chat: fmt.Sprintf("chat_%d", i%50), Variant: "control", TokenCount: 100 + i%10, Latency: 1000, Currency: "USD", Price: 0.01}
+		concise := &Message{Chat: fmt.Sprintf("chat_%d", 50+i%50), Variant: "concise", TokenCount: 50 + i%10, Latency: 800 + int64(i%5), Currency: "USD", Price: 0.005}
+		if i%10 < 3 {
+			control.LikeUsers = []string{"alice"}
+		} else if i%10 < 6 {
+			control.DisLikeUsers = []string{"alice"}
+		}
+		if i%10 < 7 {
+			concise.LikeUsers = []string{"bob"}
+		} else if i%10 < 8 {
+			concise.DisLikeUsers = []string{"bob"}
+		}
+		messages = append(messages, control, concise)
+	}
+	messages = append(messages, &Message{Variant: "removed"})
+
+	report := getExperimentReport(store, messages)
+	if report.Control != "control" || len(report.Variants) != 2 {
+		t.Fatalf("got control %s and %d variants", report.Control, len(report.Variants))
+	}
+
+	control, concise := report.Variants[0], report.Variants[1]
+	if control.ChatCount != 50 || control.MessageCount != 200 || control.RatedCount != 120 || control.LikeCount != 60 {
+		t.Errorf("got %d chats, %d messages, %d rated and %d likes for control", control.ChatCount, control.MessageCount, control.RatedCount, control.LikeCount)
+	}
+	if !isAlmostEqual(control.LikeRate, 0.5) || !isAlmostEqual(control.FeedbackCoverage, 0.6) {
+		t.Errorf("got like rate %v and feedback coverage %v for control, want the rates over the rated answers", control.LikeRate, control.FeedbackCoverage)
+	}
+	if !isAlmostEqual(concise.LikeRate, 0.875) || !isAlmostEqual(concise.FeedbackCoverage, 0.8) || !isAlmostEqual(concise.AveragePrice, 0.005) {
+		t.Errorf("got like rate %v, feedback coverage %v and price %v for concise", concise.LikeRate, concise.FeedbackCoverage, concise.AveragePrice)
+	}
+	if control.LikeRatePValue != 1 || concise.LikeRatePValue >= report.SignificanceLevel || concise.TokenCountPValue >= report.SignificanceLevel {
+		t.Errorf("got p-values %v and %v, want the like rate and token count differences significant", concise.LikeRatePValue, concise.TokenCountPValue)
+	}
+	if concise.DislikeRatePValue >= report.SignificanceLevel {
+		t.Errorf("got dislike p-value %v, want the dislike rate difference significant", concise.DislikeRatePValue)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/casibase/casibase/agent"
+	"github.com/casibase/casibase/model"
+)
+
+// GetMcpKnowledge reads the MCP resources attached to the store as knowledge. The resources of
+// unreachable servers are skipped, like the servers themselves.
+func (store *Store) GetMcpKnowledge(ctx context.Context, agentClients *agent.AgentClients) []*model.RawMessage {
+	knowledge := []*model.RawMessage{}
+	for _, id := range store.McpResources {
+		text, err := agentClients.ReadResource(ctx, id)
+		if err != nil {
+			fmt.Printf("GetMcpKnowledge() error, failed to read the MCP resource: %s, %s\n", id, err.Error())
+			continue
+		}
+		if text == "" {
+			continue
+		}
+
+		tokenCount, err := model.GetTokenSize("", text)
+		if err != nil {
+			tokenCount = 0
+		}
+
+		knowledge = append(knowledge, &model.RawMessage{
+			Text:           text,
+			Author:         "System",
+			TextTokenCount: tokenCount,
+		})
+	}
+	return knowledge
+}
+
+// GetPrompt returns the text of the store's MCP prompt template when it has selected one, or
+// else, or when the template can't be got, its own prompt.
+func (store *Store) GetPrompt(ctx context.Context, agentClients *agent.AgentClients) string {
+	if store.McpPrompt == "" {
+		return store.Prompt
+	}
+
+	prompt, err := agentClients.GetPrompt(ctx, store.McpPrompt)
+	if err != nil {
+		fmt.Printf("GetPrompt() error, failed to get the MCP prompt: %s, %s\n", store.McpPrompt, err.Error())
+		return store.Prompt
+	}
+	if prompt == "" {
+		return store.Prompt
+	}
+	return prompt
+}
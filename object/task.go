@@ -48,6 +48,10 @@ type TaskResult struct {
 	Textbook      string                `json:"textbook"`
 	Score         float64               `json:"score"`
 	Categories    []*TaskResultCategory `json:"categories"`
+
+	SampleScores   []float64 `json:"sampleScores,omitempty"`
+	ScoreDeviation float64   `json:"scoreDeviation,omitempty"`
+	ScaleVersion   int       `json:"scaleVersion,omitempty"`
 }
 
 type Task struct {
@@ -65,8 +69,9 @@ type Task struct {
 	Activity string  `xorm:"varchar(100)" json:"activity"`
 	Grade    string  `xorm:"varchar(100)" json:"grade"`
 
-	Path  string `xorm:"varchar(100)" json:"path"`
-	Scale string `xorm:"varchar(100)" json:"scale"`
+	Path         string `xorm:"varchar(100)" json:"path"`
+	Scale        string `xorm:"varchar(100)" json:"scale"`
+	ScaleVersion int    `json:"scaleVersion"`
 
 	Example string   `xorm:"varchar(200)" json:"example"`
 	Labels  []string `xorm:"mediumtext" json:"labels"`
@@ -151,22 +156,31 @@ func GetTask(id string) (*Task, error) {
 	return getTask(owner, name)
 }
 
-// GetTaskEffectiveScale returns rubric text: from referenced Scale.Text when Task.Scale is set.
-func GetTaskEffectiveScale(task *Task) (string, error) {
-	if task == nil {
-		return "", fmt.Errorf("task is nil")
+// GetTaskEffectiveScale returns the rubric text of the scale referenced by Task.Scale.
+func GetTaskEffectiveScale(task *Task, lang string) (string, error) {
+	s, err := getTaskScale(task)
+	if err != nil || s == nil {
+		return "", err
 	}
-	if task.Scale == "" {
-		return "", nil
+	return s.GetRubricText(lang), nil
+}
+
+// GetTaskGradedScale returns the scale the task was graded against, at the version it had then.
+func GetTaskGradedScale(task *Task) (*Scale, error) {
+	if task.Scale == "" || task.ScaleVersion == 0 {
+		return getTaskScale(task)
 	}
-	s, err := GetScale(task.Scale)
-	if err != nil {
-		return "", err
+	return GetScaleOfVersion(task.Scale, task.ScaleVersion)
+}
+
+func getTaskScale(task *Task) (*Scale, error) {
+	if task == nil {
+		return nil, fmt.Errorf("task is nil")
 	}
-	if s == nil {
-		return "", nil
+	if task.Scale == "" {
+		return nil, nil
 	}
-	return s.Text, nil
+	return GetScale(task.Scale)
 }
 
 func UpdateTask(id string, task *Task) (bool, error) {
@@ -17,22 +17,67 @@ package object
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"strings"
 	"time"
 	"unicode/utf8"
 
 	"github.com/beego/beego/logs"
+	"github.com/casibase/casibase/i18n"
+	"github.com/casibase/casibase/model"
 )
 
-const analyzeTaskPrompt = `请对以下教学设计文本进行深度分析，根据提供的评价量表对每个二级评价项进行评分和详细分析。
+// defaultTaskPrompts are the rubric prompt templates by language, used when the scale of the task
+// has no prompt of its own. ${rubric} and ${document} are replaced by the scale text and the document.
+var defaultTaskPrompts = map[string]string{
+	"en": `Analyze the following teaching design in depth, scoring and analyzing each second-level item of the given rubric.
 
-评分要求：每个二级评价项的得分必须在 60-100 分之间（整数）。请根据达成程度在该区间内给分：表现较好给高分（80-100），一般给中分（70-79），有待改进给较低分（60-69），以更好区分不同水平的教学设计。
+Scoring requirements: the score of each second-level item must be an integer, within the score range of the item when the rubric gives one, or else between 60 and 100. Score within the range by the degree of achievement and the descriptors of the rubric: high scores for good performance, medium scores for average performance and lower scores for what needs improvement (80-100, 70-79 and 60-69 for the range 60-100), to better distinguish teaching designs of different levels.
+
+Rubric:
+${rubric}
+
+Teaching design:
+${document}
+
+Return the analysis strictly in the following JSON format, without anything else, only valid JSON:
+{
+  "title": "The topic or unit name extracted from the document",
+  "designer": "The name of the designer or the team extracted from the document",
+  "stage": "The school stage extracted from the document (like primary school, middle school, high school)",
+  "participants": "The description of the participants extracted from the document",
+  "grade": "The grade extracted from the document",
+  "instructor": "The name of the instructor extracted from the document",
+  "subject": "The subject extracted from the document",
+  "school": "The school name extracted from the document",
+  "otherSubjects": "Other related fields or subjects extracted from the document (comma separated)",
+  "textbook": "The main textbook extracted from the document",
+  "score": the average score of all second-level items (a number with one decimal, not a string),
+  "categories": [
+    {
+      "name": "The name of the first-level item",
+      "score": the average score of the second-level items of this category (a number with two decimals),
+      "items": [
+        {
+          "name": "The name of the second-level item",
+          "score": the score of this item (an integer within the score range of the item),
+          "advantage": "The strengths of the teaching design on this item, in detail",
+          "disadvantage": "The problems and shortcomings of the teaching design on this item, in detail",
+          "suggestion": "Concrete and actionable suggestions for improvement"
+        }
+      ]
+    }
+  ]
+}`,
+	"zh": `请对以下教学设计文本进行深度分析，根据提供的评价量表对每个二级评价项进行评分和详细分析。
+
+评分要求：每个二级评价项的得分必须为整数，评价量表给出了该项分值范围的须在该范围内，否则须在 60-100 分之间。请根据达成程度和评价量表的等级描述在该区间内给分：表现较好给高分，一般给中分，有待改进给较低分（60-100 分区间分别为 80-100、70-79、60-69），以更好区分不同水平的教学设计。
 
 评价量表：
-%s
+${rubric}
 
 教学设计文本：
-%s
+${document}
 
 请严格按照以下JSON格式返回分析结果，不要包含任何其他内容，只返回合法的JSON：
 {
@@ -54,7 +99,7 @@ const analyzeTaskPrompt = `请对以下教学设计文本进行深度分析，
       "items": [
         {
           "name": "二级评价项名称",
-          "score": 该项得分（0-100的整数）,
+          "score": 该项得分（该项分值范围内的整数）,
           "advantage": "优点分析（详细说明教学设计在该项的优势和亮点）",
           "disadvantage": "不足分析（详细说明教学设计在该项存在的问题和不足）",
           "suggestion": "改进建议（提供具体可操作的改进措施和建议）"
@@ -62,68 +107,318 @@ const analyzeTaskPrompt = `请对以下教学设计文本进行深度分析，
       ]
     }
   ]
-}`
+}`,
+}
 
-func AnalyzeTask(task *Task, lang string) (*TaskResult, error) {
-	taskID := task.GetId()
-	logs.Info("[analyze-task] start task=%s provider=%s lang=%s", taskID, task.Provider, lang)
+// getTaskResultSchema returns the schema of the analysis results. For a structured scale, the names
+// are restricted to the categories and the items of the scale and the scores to the ranges of its items.
+func getTaskResultSchema(scale *Scale) *model.ResponseSchema {
+	categoryName := model.JsonSchema{"type": "string"}
+	itemName := model.JsonSchema{"type": "string"}
+	itemScore := model.JsonSchema{"type": "integer", "minimum": 0, "maximum": 100}
+	if scale.isStructured() {
+		categoryNames := []interface{}{}
+		itemNames := []interface{}{}
+		minScore, maxScore := scale.Categories[0].Items[0].getScoreRange()
+		for _, category := range scale.Categories {
+			categoryNames = append(categoryNames, category.Name)
+			for _, item := range category.Items {
+				itemNames = append(itemNames, item.Name)
+				itemMinScore, itemMaxScore := item.getScoreRange()
+				minScore = math.Min(minScore, itemMinScore)
+				maxScore = math.Max(maxScore, itemMaxScore)
+			}
+		}
+		categoryName["enum"] = categoryNames
+		itemName["enum"] = itemNames
+		itemScore = model.JsonSchema{"type": "number", "minimum": minScore, "maximum": maxScore}
+	}
 
-	effectiveScale, err := GetTaskEffectiveScale(task)
-	if err != nil {
-		logs.Error("[analyze-task] GetTaskEffectiveScale failed task=%s: %v", taskID, err)
-		return nil, err
+	return &model.ResponseSchema{
+		Name:        "task_result",
+		Description: "The analysis result of a teaching design evaluated against a rubric",
+		Schema: model.JsonSchema{
+			"type": "object",
+			"properties": model.JsonSchema{
+				"title":         model.JsonSchema{"type": "string"},
+				"designer":      model.JsonSchema{"type": "string"},
+				"stage":         model.JsonSchema{"type": "string"},
+				"participants":  model.JsonSchema{"type": "string"},
+				"grade":         model.JsonSchema{"type": "string"},
+				"instructor":    model.JsonSchema{"type": "string"},
+				"subject":       model.JsonSchema{"type": "string"},
+				"school":        model.JsonSchema{"type": "string"},
+				"otherSubjects": model.JsonSchema{"type": "string"},
+				"textbook":      model.JsonSchema{"type": "string"},
+				"score":         model.JsonSchema{"type": "number", "minimum": 0, "maximum": 100},
+				"categories": model.JsonSchema{
+					"type":     "array",
+					"minItems": 1,
+					"items": model.JsonSchema{
+						"type": "object",
+						"properties": model.JsonSchema{
+							"name":  categoryName,
+							"score": model.JsonSchema{"type": "number", "minimum": 0, "maximum": 100},
+							"items": model.JsonSchema{
+								"type":     "array",
+								"minItems": 1,
+								"items": model.JsonSchema{
+									"type": "object",
+									"properties": model.JsonSchema{
+										"name":         itemName,
+										"score":        itemScore,
+										"advantage":    model.JsonSchema{"type": "string"},
+										"disadvantage": model.JsonSchema{"type": "string"},
+										"suggestion":   model.JsonSchema{"type": "string"},
+									},
+									"required": []string{"name", "score", "advantage", "disadvantage", "suggestion"},
+								},
+							},
+						},
+						"required": []string{"name", "score", "items"},
+					},
+				},
+			},
+			"required": []string{"title", "score", "categories"},
+		},
 	}
-	if effectiveScale == "" {
-		return nil, fmt.Errorf("任务量表不能为空")
+}
+
+func getTaskPrompt(scale *Scale, lang string) string {
+	if strings.TrimSpace(scale.Prompt) != "" {
+		return scale.Prompt
+	}
+	if prompt, ok := defaultTaskPrompts[lang]; ok {
+		return prompt
 	}
-	scaleRunes := utf8.RuneCountInString(effectiveScale)
-	logs.Info("[analyze-task] rubric loaded task=%s scaleRef=%s rubricLen=%d runes", taskID, task.Scale, scaleRunes)
+	return defaultTaskPrompts["en"]
+}
 
+// getTaskQuestion fills the rubric prompt template of the task's scale with the rubric of the scale
+// and the document, and returns the scale the task is graded against.
+func getTaskQuestion(task *Task, lang string) (string, *Scale, error) {
+	scale, err := getTaskScale(task)
+	if err != nil {
+		return "", nil, err
+	}
+	if scale == nil || (scale.Text == "" && !scale.isStructured()) {
+		return "", nil, fmt.Errorf(i18n.Translate(lang, "object:The scale of the task should not be empty"))
+	}
 	if task.DocumentText == "" {
-		return nil, fmt.Errorf("任务文档不能为空，请先上传文档")
+		return "", nil, fmt.Errorf(i18n.Translate(lang, "object:The document of the task should not be empty, please upload the document first"))
 	}
-	docRunes := utf8.RuneCountInString(task.DocumentText)
-	logs.Info("[analyze-task] document ready task=%s documentLen=%d runes", taskID, docRunes)
 
-	question := fmt.Sprintf(analyzeTaskPrompt, effectiveScale, task.DocumentText)
-	promptRunes := utf8.RuneCountInString(question)
-	logs.Info("[analyze-task] prompt built task=%s fullPromptLen=%d runes (rubric+template+document)", taskID, promptRunes)
+	prompt := getTaskPrompt(scale, lang)
+	if !strings.Contains(prompt, "${document}") {
+		return "", nil, fmt.Errorf(i18n.Translate(lang, "object:The prompt of the scale: %s should contain ${document}"), scale.Name)
+	}
+
+	err = ensureScaleVersion(scale)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// a single pass, so that a "${document}" inside the rubric is left as it is
+	replacer := strings.NewReplacer("${rubric}", scale.GetRubricText(lang), "${document}", task.DocumentText)
+	return replacer.Replace(prompt), scale, nil
+}
+
+// ValidateTaskResult checks an analysis result against the rubric constraints the JSON schema
+// cannot express well: every category has items and every item has a name and a score in the range
+// of the item for a structured scale, in [0, 100] otherwise.
+func ValidateTaskResult(result *TaskResult, scale *Scale, lang string) error {
+	if len(result.Categories) == 0 {
+		return fmt.Errorf(i18n.Translate(lang, "object:The analysis result has no categories"))
+	}
+	for _, category := range result.Categories {
+		if category.Name == "" || len(category.Items) == 0 {
+			return fmt.Errorf(i18n.Translate(lang, "object:The category: %s of the analysis result has no items"), category.Name)
+		}
+		if scale.isStructured() {
+			continue
+		}
+		for _, item := range category.Items {
+			if item.Name == "" || item.Score < 0 || item.Score > 100 {
+				return fmt.Errorf(i18n.Translate(lang, "object:The score: %v of the item: %s is out of range [0, 100]"), item.Score, item.Name)
+			}
+		}
+	}
+
+	if scale.isStructured() {
+		return validateTaskResultWithScale(result, scale, lang)
+	}
+	return nil
+}
 
-	var answer string
+func analyzeTaskSample(task *Task, scale *Scale, provider string, question string, lang string) (*TaskResult, error) {
+	schema := getTaskResultSchema(scale)
+	var result TaskResult
 	aiStart := time.Now()
 	if strings.Contains(strings.ToLower(task.Name), "demo") {
-		logs.Info("[analyze-task] using GetAnswerFake (task name contains \"demo\") task=%s", taskID)
-		answer, _, err = GetAnswerFake(task.Provider, question, lang)
+		logs.Info("[analyze-task] using GetAnswerFake (task name contains \"demo\") task=%s", task.GetId())
+		answer, _, err := GetAnswerFake(provider, question, lang)
+		if err != nil {
+			return nil, err
+		}
+
+		// the fake answer does not go through structured output, so it is validated here
+		var value interface{}
+		err = json.Unmarshal([]byte(answer), &value)
+		if err != nil {
+			return nil, err
+		}
+		if problems := model.ValidateJsonSchema(schema.Schema, value); len(problems) > 0 {
+			return nil, fmt.Errorf(i18n.Translate(lang, "model:the model output does not conform to the JSON schema: %s"), strings.Join(problems, "; "))
+		}
+		err = json.Unmarshal([]byte(answer), &result)
+		if err != nil {
+			return nil, err
+		}
 	} else {
-		logs.Info("[analyze-task] calling AI model task=%s provider=%s (this may take several minutes)...", taskID, task.Provider)
-		answer, _, err = GetAnswer(task.Provider, question, lang)
+		logs.Info("[analyze-task] calling AI model task=%s provider=%s (this may take several minutes)...", task.GetId(), provider)
+		_, err := GetStructuredAnswer(provider, schema, &result, question, lang)
+		if err != nil {
+			logs.Error("[analyze-task] AI call failed task=%s provider=%s after %v: %v", task.GetId(), provider, time.Since(aiStart), err)
+			return nil, fmt.Errorf(i18n.Translate(lang, "object:Failed to get the analysis from the model: %s"), err.Error())
+		}
 	}
-	aiElapsed := time.Since(aiStart)
+	logs.Info("[analyze-task] AI returned task=%s provider=%s elapsed=%v", task.GetId(), provider, time.Since(aiStart))
+
+	err := ValidateTaskResult(&result, scale, lang)
 	if err != nil {
-		logs.Error("[analyze-task] AI call failed task=%s after %v: %v", taskID, aiElapsed, err)
-		return nil, fmt.Errorf("从AI模型获取分析失败: %v", err)
+		return nil, err
 	}
-	logs.Info("[analyze-task] AI returned task=%s elapsed=%v answerLen=%d bytes", taskID, aiElapsed, len(answer))
+	return &result, nil
+}
+
+func roundTaskScore(score float64, decimals int) float64 {
+	scale := math.Pow(10, float64(decimals))
+	return math.Round(score*scale) / scale
+}
 
-	answer = strings.TrimSpace(answer)
-	// Strip markdown code block if present
-	if strings.HasPrefix(answer, "```json") {
-		answer = strings.TrimPrefix(answer, "```json")
-		answer = strings.TrimSuffix(answer, "```")
-		answer = strings.TrimSpace(answer)
-	} else if strings.HasPrefix(answer, "```") {
-		answer = strings.TrimPrefix(answer, "```")
-		answer = strings.TrimSuffix(answer, "```")
-		answer = strings.TrimSpace(answer)
+func getTaskResultItemAverage(result *TaskResult) float64 {
+	total := 0.0
+	count := 0
+	for _, category := range result.Categories {
+		for _, item := range category.Items {
+			total += item.Score
+			count++
+		}
 	}
+	if count == 0 {
+		return 0
+	}
+	return total / float64(count)
+}
 
-	logs.Info("[analyze-task] parsing JSON task=%s bodyLen=%d bytes", taskID, len(answer))
-	var result TaskResult
-	if err = json.Unmarshal([]byte(answer), &result); err != nil {
-		logs.Error("[analyze-task] JSON unmarshal failed task=%s: %v", taskID, err)
-		return nil, fmt.Errorf("解析AI分析结果为JSON失败: %v\n原始回复: %s", err, answer)
+// CalibrateTaskResults merges the results of several samples of the same task, from repeated runs
+// or from different models, into one: the score of each item is its average over the samples that
+// scored it, with the texts of the sample closest to that average, and the category and overall
+// scores are computed from the item scores and the weights of the scale instead of trusting the
+// arithmetic of the models.
+func CalibrateTaskResults(results []*TaskResult, scale *Scale) *TaskResult {
+	if len(results) == 0 {
+		return nil
 	}
 
-	logs.Info("[analyze-task] done task=%s score=%.2f categories=%d", taskID, result.Score, len(result.Categories))
-	return &result, nil
+	calibrated := *results[0]
+	calibrated.Categories = []*TaskResultCategory{}
+	calibrated.SampleScores = nil
+	calibrated.ScoreDeviation = 0
+
+	categoryMap := map[string]*TaskResultCategory{}
+	itemSamples := map[[2]string][]*TaskResultItem{}
+	for _, result := range results {
+		for _, category := range result.Categories {
+			calibratedCategory, ok := categoryMap[category.Name]
+			if !ok {
+				calibratedCategory = &TaskResultCategory{Name: category.Name, Items: []*TaskResultItem{}}
+				categoryMap[category.Name] = calibratedCategory
+				calibrated.Categories = append(calibrated.Categories, calibratedCategory)
+			}
+
+			for _, item := range category.Items {
+				key := [2]string{category.Name, item.Name}
+				if _, ok = itemSamples[key]; !ok {
+					calibratedCategory.Items = append(calibratedCategory.Items, &TaskResultItem{Name: item.Name})
+				}
+				itemSamples[key] = append(itemSamples[key], item)
+			}
+		}
+	}
+
+	for _, category := range calibrated.Categories {
+		for _, item := range category.Items {
+			samples := itemSamples[[2]string{category.Name, item.Name}]
+			total := 0.0
+			for _, sample := range samples {
+				total += sample.Score
+			}
+			average := total / float64(len(samples))
+
+			closest := samples[0]
+			for _, sample := range samples[1:] {
+				if math.Abs(sample.Score-average) < math.Abs(closest.Score-average) {
+					closest = sample
+				}
+			}
+
+			item.Score = roundTaskScore(average, 2)
+			item.Advantage = closest.Advantage
+			item.Disadvantage = closest.Disadvantage
+			item.Suggestion = closest.Suggestion
+		}
+	}
+	setTaskResultScores(&calibrated, scale)
+
+	if len(results) > 1 {
+		variance := 0.0
+		for _, result := range results {
+			_, score := getTaskResultScores(result, scale)
+			calibrated.SampleScores = append(calibrated.SampleScores, roundTaskScore(score, 1))
+			variance += (score - calibrated.Score) * (score - calibrated.Score)
+		}
+		calibrated.ScoreDeviation = roundTaskScore(math.Sqrt(variance/float64(len(results))), 2)
+	}
+
+	return &calibrated
+}
+
+// AnalyzeTaskWithSamples analyzes the task sampleCount times with each of the providers, the provider
+// of the task when none is given, and calibrates the scores across all the samples.
+func AnalyzeTaskWithSamples(task *Task, providers []string, sampleCount int, lang string) (*TaskResult, error) {
+	taskID := task.GetId()
+	if len(providers) == 0 {
+		providers = []string{task.Provider}
+	}
+	if sampleCount < 1 {
+		sampleCount = 1
+	}
+	logs.Info("[analyze-task] start task=%s providers=%v samples=%d lang=%s", taskID, providers, sampleCount, lang)
+
+	question, scale, err := getTaskQuestion(task, lang)
+	if err != nil {
+		return nil, err
+	}
+	logs.Info("[analyze-task] prompt built task=%s scaleRef=%s fullPromptLen=%d runes", taskID, task.Scale, utf8.RuneCountInString(question))
+
+	results := []*TaskResult{}
+	for _, provider := range providers {
+		for i := 0; i < sampleCount; i++ {
+			result, err := analyzeTaskSample(task, scale, provider, question, lang)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, result)
+		}
+	}
+
+	result := CalibrateTaskResults(results, scale)
+	result.ScaleVersion = scale.Version
+	logs.Info("[analyze-task] done task=%s score=%.2f deviation=%.2f categories=%d", taskID, result.Score, result.ScoreDeviation, len(result.Categories))
+	return result, nil
+}
+
+func AnalyzeTask(task *Task, lang string) (*TaskResult, error) {
+	return AnalyzeTaskWithSamples(task, nil, 1, lang)
 }
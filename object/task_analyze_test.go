@@ -0,0 +1,115 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build skipCi
+// +build skipCi
+
+package object
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/tealeg/xlsx"
+)
+
+func newTestTaskResult(scores ...float64) *TaskResult {
+	return &TaskResult{
+		Title: "Fractions",
+		Score: 99,
+		Categories: []*TaskResultCategory{
+			{
+				Name: "Objectives",
+				Items: []*TaskResultItem{
+					{Name: "Standards", Score: scores[0], Advantage: "advantage"},
+					{Name: "Clarity", Score: scores[1]},
+				},
+			},
+			{
+				Name:  "Activities",
+				Items: []*TaskResultItem{{Name: "Engagement", Score: scores[2]}},
+			},
+		},
+	}
+}
+
+func TestValidateTaskResult(t *testing.T) {
+	if err := ValidateTaskResult(newTestTaskResult(80, 70, 90), nil, "en"); err != nil {
+		t.Errorf("unexpected error for a valid result: %v", err)
+	}
+	if err := ValidateTaskResult(newTestTaskResult(80, 170, 90), nil, "en"); err == nil {
+		t.Errorf("expected an error for a score out of range")
+	}
+	if err := ValidateTaskResult(&TaskResult{Title: "Fractions"}, nil, "en"); err == nil {
+		t.Errorf("expected an error for a result without categories")
+	}
+}
+
+func TestCalibrateTaskResults(t *testing.T) {
+	single := CalibrateTaskResults([]*TaskResult{newTestTaskResult(80, 70, 90)}, nil)
+	if single.Score != 80 || single.Categories[0].Score != 75 || single.SampleScores != nil {
+		t.Errorf("expected the scores of a single sample to be recomputed from its items, got: %+v", single)
+	}
+
+	first := newTestTaskResult(80, 70, 90)
+	second := newTestTaskResult(90, 70, 60)
+	second.Categories[0].Items[0].Advantage = "closest"
+	third := newTestTaskResult(94, 73, 60)
+	result := CalibrateTaskResults([]*TaskResult{first, second, third}, nil)
+
+	standards := result.Categories[0].Items[0]
+	if standards.Score != 88 || standards.Advantage != "closest" {
+		t.Errorf("expected the average score with the texts of the closest sample, got: %+v", standards)
+	}
+	if result.Categories[1].Items[0].Score != 70 || result.Score != 76.3 {
+		t.Errorf("expected an overall score of 76.3, got: %v", result.Score)
+	}
+	if len(result.SampleScores) != 3 || result.ScoreDeviation == 0 {
+		t.Errorf("expected the sample scores and their deviation, got: %v, %v", result.SampleScores, result.ScoreDeviation)
+	}
+	if first.Categories[0].Items[0].Score != 80 {
+		t.Errorf("expected the samples to be left unchanged")
+	}
+}
+
+func TestExportTaskResults(t *testing.T) {
+	resultBytes, err := json.Marshal(newTestTaskResult(80, 70, 90))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tasks := []*Task{
+		{Owner: "admin", Name: "task_1", Result: string(resultBytes)},
+		{Owner: "admin", Name: "task_2"},
+	}
+
+	data, err := ExportTaskResults(tasks, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := xlsx.OpenBinary(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(file.Sheets) != 2 {
+		t.Fatalf("expected a summary and an item sheet, got %d sheets", len(file.Sheets))
+	}
+
+	summary := file.Sheets[0]
+	if len(summary.Rows) != 3 || summary.Rows[0].Cells[10].String() != "Objectives" || summary.Rows[1].Cells[0].String() != "task_1" {
+		t.Errorf("expected a header and a row per task with the category columns")
+	}
+	if items := file.Sheets[1]; len(items.Rows) != 4 {
+		t.Errorf("expected a header and a row per item, got %d rows", len(items.Rows))
+	}
+}
@@ -0,0 +1,369 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/beego/beego/logs"
+	"github.com/casibase/casibase/i18n"
+	"github.com/casibase/casibase/util"
+	"github.com/robfig/cron/v3"
+	"xorm.io/core"
+)
+
+const (
+	TaskBatchStatePending  = "Pending"
+	TaskBatchStateRunning  = "Running"
+	TaskBatchStateFinished = "Finished"
+	TaskBatchStateFailed   = "Failed"
+)
+
+const taskBatchRetryDelay = 5 * time.Second
+
+type TaskBatchItem struct {
+	Task      string  `json:"task"`
+	State     string  `json:"state"`
+	Attempts  int     `json:"attempts"`
+	Score     float64 `json:"score"`
+	ErrorText string  `json:"errorText"`
+}
+
+// TaskBatch is a background job analyzing many tasks of an owner, each of them sampled SampleCount
+// times with each of the Providers and retried up to MaxRetries times when the analysis fails.
+type TaskBatch struct {
+	Owner       string `xorm:"varchar(100) notnull pk" json:"owner"`
+	Name        string `xorm:"varchar(100) notnull pk" json:"name"`
+	CreatedTime string `xorm:"varchar(100)" json:"createdTime"`
+	UpdatedTime string `xorm:"varchar(100)" json:"updatedTime"`
+
+	DisplayName string   `xorm:"varchar(100)" json:"displayName"`
+	Providers   []string `xorm:"mediumtext" json:"providers"`
+	SampleCount int      `json:"sampleCount"`
+	MaxRetries  int      `json:"maxRetries"`
+	Language    string   `xorm:"varchar(100)" json:"language"`
+
+	State         string           `xorm:"varchar(100)" json:"state"`
+	Runner        string           `xorm:"varchar(100)" json:"runner"`
+	Items         []*TaskBatchItem `xorm:"mediumtext" json:"items"`
+	FinishedCount int              `json:"finishedCount"`
+	FailedCount   int              `json:"failedCount"`
+	ErrorText     string           `xorm:"mediumtext" json:"errorText"`
+}
+
+func GetTaskBatchCount(owner, field, value string) (int64, error) {
+	session := GetDbSession(owner, -1, -1, field, value, "", "")
+	return session.Count(&TaskBatch{})
+}
+
+func GetTaskBatches(owner string) ([]*TaskBatch, error) {
+	taskBatches := []*TaskBatch{}
+	session := adapter.engine.Desc("created_time")
+	if owner != "" {
+		session = session.Where("owner = ?", owner)
+	}
+	err := session.Find(&taskBatches)
+	if err != nil {
+		return taskBatches, err
+	}
+
+	return taskBatches, nil
+}
+
+func GetPaginationTaskBatches(owner string, offset, limit int, field, value, sortField, sortOrder string) ([]*TaskBatch, error) {
+	taskBatches := []*TaskBatch{}
+	session := GetDbSession(owner, offset, limit, field, value, sortField, sortOrder)
+	err := session.Find(&taskBatches)
+	if err != nil {
+		return taskBatches, err
+	}
+
+	return taskBatches, nil
+}
+
+func getTaskBatch(owner string, name string) (*TaskBatch, error) {
+	taskBatch := TaskBatch{Owner: owner, Name: name}
+	existed, err := adapter.engine.Get(&taskBatch)
+	if err != nil {
+		return &taskBatch, err
+	}
+
+	if existed {
+		return &taskBatch, nil
+	} else {
+		return nil, nil
+	}
+}
+
+func GetTaskBatch(id string) (*TaskBatch, error) {
+	owner, name, err := util.GetOwnerAndNameFromIdWithError(id)
+	if err != nil {
+		return nil, err
+	}
+	return getTaskBatch(owner, name)
+}
+
+func updateTaskBatch(taskBatch *TaskBatch) error {
+	taskBatch.UpdatedTime = util.GetCurrentTime()
+	_, err := adapter.engine.ID(core.PK{taskBatch.Owner, taskBatch.Name}).AllCols().Update(taskBatch)
+	return err
+}
+
+// AddTaskBatch checks the tasks of the batch and queues it for the task batch processor.
+func AddTaskBatch(taskBatch *TaskBatch, lang string) (bool, error) {
+	if len(taskBatch.Items) == 0 {
+		return false, fmt.Errorf(i18n.Translate(lang, "object:The task batch should contain at least one task"))
+	}
+	for _, item := range taskBatch.Items {
+		task, err := getTask(taskBatch.Owner, item.Task)
+		if err != nil {
+			return false, err
+		}
+		if task == nil {
+			return false, fmt.Errorf(i18n.Translate(lang, "object:The task: %s does not exist"), item.Task)
+		}
+
+		item.State = TaskBatchStatePending
+		item.Attempts = 0
+		item.Score = 0
+		item.ErrorText = ""
+	}
+
+	if taskBatch.SampleCount < 1 {
+		taskBatch.SampleCount = 1
+	}
+	if taskBatch.MaxRetries < 0 {
+		taskBatch.MaxRetries = 0
+	}
+	if taskBatch.Language == "" {
+		taskBatch.Language = lang
+	}
+	taskBatch.State = TaskBatchStatePending
+	taskBatch.Runner = ""
+	taskBatch.FinishedCount = 0
+	taskBatch.FailedCount = 0
+	taskBatch.ErrorText = ""
+	taskBatch.UpdatedTime = util.GetCurrentTime()
+
+	affected, err := adapter.engine.Insert(taskBatch)
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+func DeleteTaskBatch(taskBatch *TaskBatch) (bool, error) {
+	affected, err := adapter.engine.ID(core.PK{taskBatch.Owner, taskBatch.Name}).Delete(&TaskBatch{})
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+// RetryTaskBatch queues the failed tasks of a finished batch again, with their attempts reset.
+func RetryTaskBatch(taskBatch *TaskBatch, lang string) error {
+	if taskBatch.State == TaskBatchStatePending || taskBatch.State == TaskBatchStateRunning {
+		return fmt.Errorf(i18n.Translate(lang, "object:The task batch: %s is still running"), taskBatch.Name)
+	}
+
+	for _, item := range taskBatch.Items {
+		if item.State == TaskBatchStateFailed {
+			item.State = TaskBatchStatePending
+			item.Attempts = 0
+			item.ErrorText = ""
+		}
+	}
+	taskBatch.State = TaskBatchStatePending
+	taskBatch.Runner = ""
+	taskBatch.ErrorText = ""
+	taskBatch.updateCounts()
+	return updateTaskBatch(taskBatch)
+}
+
+func (taskBatch *TaskBatch) GetId() string {
+	return fmt.Sprintf("%s/%s", taskBatch.Owner, taskBatch.Name)
+}
+
+func (taskBatch *TaskBatch) updateCounts() {
+	taskBatch.FinishedCount = 0
+	taskBatch.FailedCount = 0
+	for _, item := range taskBatch.Items {
+		if item.State == TaskBatchStateFinished {
+			taskBatch.FinishedCount++
+		} else if item.State == TaskBatchStateFailed {
+			taskBatch.FailedCount++
+		}
+	}
+}
+
+var taskBatchCron *cron.Cron
+
+// InitTaskBatchProcessor requeues the batches this instance was running when it stopped and starts
+// the cron job picking up the pending batches.
+func InitTaskBatchProcessor() {
+	hostname, err := os.Hostname()
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = adapter.engine.Table(&TaskBatch{}).
+		Where("state = ? AND runner = ?", TaskBatchStateRunning, hostname).
+		Update(map[string]interface{}{"state": TaskBatchStatePending, "runner": ""})
+	if err != nil {
+		panic(err)
+	}
+
+	taskBatchCron = cron.New()
+	_, err = taskBatchCron.AddFunc("@every 1s", processPendingTaskBatches)
+	if err != nil {
+		panic(err)
+	}
+	taskBatchCron.Start()
+}
+
+func processPendingTaskBatches() {
+	taskBatches := []*TaskBatch{}
+	err := adapter.engine.Where("state = ?", TaskBatchStatePending).Asc("created_time").Find(&taskBatches)
+	if err != nil {
+		logs.Error("processPendingTaskBatches() error getting pending task batches: %v", err)
+		return
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		logs.Error("processPendingTaskBatches() error getting hostname: %v", err)
+		return
+	}
+
+	for _, taskBatch := range taskBatches {
+		// only one instance succeeds in moving the batch from "Pending" to "Running"
+		affected, err := adapter.engine.Table(&TaskBatch{}).
+			Where("owner = ? AND name = ? AND state = ?", taskBatch.Owner, taskBatch.Name, TaskBatchStatePending).
+			Update(map[string]interface{}{
+				"state":        TaskBatchStateRunning,
+				"runner":       hostname,
+				"updated_time": util.GetCurrentTime(),
+			})
+		if err != nil {
+			logs.Error("processPendingTaskBatches() error claiming task batch %s: %v", taskBatch.GetId(), err)
+			continue
+		}
+		if affected == 0 {
+			continue
+		}
+
+		taskBatch.State = TaskBatchStateRunning
+		taskBatch.Runner = hostname
+		go executeTaskBatch(taskBatch)
+	}
+}
+
+// executeTaskBatch analyzes the pending tasks of the batch one by one, saving the progress after each task.
+func executeTaskBatch(taskBatch *TaskBatch) {
+	defer func() {
+		if r := recover(); r != nil {
+			logs.Error("executeTaskBatch() recovered from panic in task batch %s: %v", taskBatch.GetId(), r)
+			taskBatch.State = TaskBatchStateFailed
+			taskBatch.ErrorText = fmt.Sprintf("%v", r)
+			err := updateTaskBatch(taskBatch)
+			if err != nil {
+				logs.Error("executeTaskBatch() error updating task batch after panic %s: %v", taskBatch.GetId(), err)
+			}
+		}
+	}()
+
+	for _, item := range taskBatch.Items {
+		// a running item was interrupted by a restart of the instance
+		if item.State != TaskBatchStatePending && item.State != TaskBatchStateRunning {
+			continue
+		}
+
+		item.State = TaskBatchStateRunning
+		err := updateTaskBatch(taskBatch)
+		if err != nil {
+			logs.Error("executeTaskBatch() error updating task batch %s: %v", taskBatch.GetId(), err)
+		}
+
+		executeTaskBatchItem(taskBatch, item)
+
+		taskBatch.updateCounts()
+		err = updateTaskBatch(taskBatch)
+		if err != nil {
+			logs.Error("executeTaskBatch() error updating task batch %s: %v", taskBatch.GetId(), err)
+		}
+	}
+
+	taskBatch.State = TaskBatchStateFinished
+	err := updateTaskBatch(taskBatch)
+	if err != nil {
+		logs.Error("executeTaskBatch() error updating task batch %s: %v", taskBatch.GetId(), err)
+	}
+}
+
+func executeTaskBatchItem(taskBatch *TaskBatch, item *TaskBatchItem) {
+	for item.Attempts <= taskBatch.MaxRetries {
+		if item.Attempts > 0 {
+			time.Sleep(time.Duration(item.Attempts) * taskBatchRetryDelay)
+		}
+		item.Attempts++
+
+		err := analyzeTaskBatchItem(taskBatch, item)
+		if err == nil {
+			item.State = TaskBatchStateFinished
+			item.ErrorText = ""
+			return
+		}
+
+		logs.Warn("executeTaskBatch() attempt %d of task %s in task batch %s failed: %v", item.Attempts, item.Task, taskBatch.GetId(), err)
+		item.ErrorText = err.Error()
+	}
+
+	item.State = TaskBatchStateFailed
+}
+
+func analyzeTaskBatchItem(taskBatch *TaskBatch, item *TaskBatchItem) error {
+	task, err := getTask(taskBatch.Owner, item.Task)
+	if err != nil {
+		return err
+	}
+	if task == nil {
+		return fmt.Errorf(i18n.Translate(taskBatch.Language, "object:The task: %s does not exist"), item.Task)
+	}
+
+	result, err := AnalyzeTaskWithSamples(task, taskBatch.Providers, taskBatch.SampleCount, taskBatch.Language)
+	if err != nil {
+		return err
+	}
+
+	resultBytes, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	task.Result = string(resultBytes)
+	task.Score = result.Score
+	task.ScaleVersion = result.ScaleVersion
+	_, err = UpdateTask(task.GetId(), task)
+	if err != nil {
+		return err
+	}
+
+	item.Score = result.Score
+	return nil
+}
@@ -0,0 +1,152 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/casibase/casibase/i18n"
+	"github.com/tealeg/xlsx"
+)
+
+func addTaskExportRow(sheet *xlsx.Sheet, values ...interface{}) {
+	row := sheet.AddRow()
+	for _, value := range values {
+		cell := row.AddCell()
+		switch v := value.(type) {
+		case float64:
+			cell.SetFloat(v)
+		case int:
+			cell.SetInt(v)
+		case string:
+			cell.SetString(v)
+		}
+	}
+}
+
+func getTaskExportResult(task *Task) *TaskResult {
+	if task.Result == "" {
+		return nil
+	}
+
+	var result TaskResult
+	if json.Unmarshal([]byte(task.Result), &result) != nil {
+		return nil
+	}
+	return &result
+}
+
+// GetTaskBatchTasks returns the tasks of the batch that still exist, in the order of the batch.
+func GetTaskBatchTasks(taskBatch *TaskBatch) ([]*Task, error) {
+	tasks := []*Task{}
+	for _, item := range taskBatch.Items {
+		task, err := getTask(taskBatch.Owner, item.Task)
+		if err != nil {
+			return nil, err
+		}
+		if task != nil {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+// ExportTaskResults writes the analysis results of the tasks to an XLSX workbook with a summary
+// sheet, one row per task with a column per rubric category, and an item sheet with the score
+// and the analysis of every item of every task.
+func ExportTaskResults(tasks []*Task, lang string) ([]byte, error) {
+	file := xlsx.NewFile()
+	summarySheet, err := file.AddSheet(i18n.Translate(lang, "object:Summary"))
+	if err != nil {
+		return nil, err
+	}
+	itemSheet, err := file.AddSheet(i18n.Translate(lang, "object:Items"))
+	if err != nil {
+		return nil, err
+	}
+
+	results := []*TaskResult{}
+	categoryNames := []string{}
+	categoryIndexes := map[string]int{}
+	for _, task := range tasks {
+		result := getTaskExportResult(task)
+		results = append(results, result)
+		if result == nil {
+			continue
+		}
+		for _, category := range result.Categories {
+			if _, ok := categoryIndexes[category.Name]; !ok {
+				categoryIndexes[category.Name] = len(categoryNames)
+				categoryNames = append(categoryNames, category.Name)
+			}
+		}
+	}
+
+	header := []interface{}{
+		i18n.Translate(lang, "object:Task"),
+		i18n.Translate(lang, "object:Display name"),
+		i18n.Translate(lang, "object:Title"),
+		i18n.Translate(lang, "object:Designer"),
+		i18n.Translate(lang, "object:School"),
+		i18n.Translate(lang, "object:Subject"),
+		i18n.Translate(lang, "object:Stage"),
+		i18n.Translate(lang, "object:Grade"),
+		i18n.Translate(lang, "object:Score"),
+		i18n.Translate(lang, "object:Score deviation"),
+	}
+	for _, name := range categoryNames {
+		header = append(header, name)
+	}
+	addTaskExportRow(summarySheet, header...)
+
+	addTaskExportRow(itemSheet,
+		i18n.Translate(lang, "object:Task"),
+		i18n.Translate(lang, "object:Category"),
+		i18n.Translate(lang, "object:Item"),
+		i18n.Translate(lang, "object:Score"),
+		i18n.Translate(lang, "object:Advantage"),
+		i18n.Translate(lang, "object:Disadvantage"),
+		i18n.Translate(lang, "object:Suggestion"),
+	)
+
+	for i, task := range tasks {
+		result := results[i]
+		if result == nil {
+			addTaskExportRow(summarySheet, task.Name, task.DisplayName)
+			continue
+		}
+
+		row := []interface{}{task.Name, task.DisplayName, result.Title, result.Designer, result.School, result.Subject, result.Stage, result.Grade, result.Score, result.ScoreDeviation}
+		categoryScores := make([]interface{}, len(categoryNames))
+		for j := range categoryScores {
+			categoryScores[j] = ""
+		}
+		for _, category := range result.Categories {
+			categoryScores[categoryIndexes[category.Name]] = category.Score
+			for _, item := range category.Items {
+				addTaskExportRow(itemSheet, task.Name, category.Name, item.Name, item.Score, item.Advantage, item.Disadvantage, item.Suggestion)
+			}
+		}
+		addTaskExportRow(summarySheet, append(row, categoryScores...)...)
+	}
+
+	var buffer bytes.Buffer
+	err = file.Write(&buffer)
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,259 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/casibase/casibase/agent"
+	"github.com/casibase/casibase/model"
+	"github.com/casibase/casibase/util"
+	"xorm.io/core"
+)
+
+// TraceMessage is a message of the history or a piece of knowledge sent to the model.
+type TraceMessage struct {
+	Author string `json:"author"`
+	Text   string `json:"text"`
+}
+
+// Trace is the execution trace of an answer message: its model turns and tool calls with their
+// timing, together with the inputs of the model so that the answer can be replayed. The trace of
+// an answer is named after its message, the replays of it have ReplayOf set to its name.
+type Trace struct {
+	Owner       string `xorm:"varchar(100) notnull pk" json:"owner"`
+	Name        string `xorm:"varchar(100) notnull pk" json:"name"`
+	CreatedTime string `xorm:"varchar(100)" json:"createdTime"`
+
+	User          string `xorm:"varchar(100) index" json:"user"`
+	Store         string `xorm:"varchar(100)" json:"store"`
+	Chat          string `xorm:"varchar(100) index" json:"chat"`
+	Message       string `xorm:"varchar(100) index" json:"message"`
+	ReplayOf      string `xorm:"varchar(100)" json:"replayOf"`
+	ModelProvider string `xorm:"varchar(100)" json:"modelProvider"`
+
+	Question  string                  `xorm:"mediumtext" json:"question"`
+	Prompt    string                  `xorm:"mediumtext" json:"prompt"`
+	History   []*TraceMessage         `xorm:"mediumtext" json:"history"`
+	Knowledge []*TraceMessage         `xorm:"mediumtext" json:"knowledge"`
+	Tools     []*protocol.Tool        `xorm:"mediumtext" json:"tools"`
+	Steps     []*model.AgentTraceStep `xorm:"mediumtext" json:"steps"`
+
+	Answer     string  `xorm:"mediumtext" json:"answer"`
+	ErrorText  string  `xorm:"mediumtext" json:"errorText"`
+	TokenCount int     `json:"tokenCount"`
+	Price      float64 `json:"price"`
+	Currency   string  `xorm:"varchar(100)" json:"currency"`
+	Duration   int64   `json:"duration"`
+}
+
+func GetTraces(owner string, message string) ([]*Trace, error) {
+	traces := []*Trace{}
+	err := adapter.engine.Asc("created_time").Find(&traces, &Trace{Owner: owner, Message: message})
+	if err != nil {
+		return traces, err
+	}
+
+	return traces, nil
+}
+
+func getTrace(owner string, name string) (*Trace, error) {
+	trace := Trace{Owner: owner, Name: name}
+	existed, err := adapter.engine.Get(&trace)
+	if err != nil {
+		return &trace, err
+	}
+
+	if existed {
+		return &trace, nil
+	} else {
+		return nil, nil
+	}
+}
+
+func GetTrace(id string) (*Trace, error) {
+	owner, name, err := util.GetOwnerAndNameFromIdWithError(id)
+	if err != nil {
+		return nil, err
+	}
+	return getTrace(owner, name)
+}
+
+func AddTrace(trace *Trace) (bool, error) {
+	affected, err := adapter.engine.Insert(trace)
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+func DeleteTrace(trace *Trace) (bool, error) {
+	affected, err := adapter.engine.ID(core.PK{trace.Owner, trace.Name}).Delete(&Trace{})
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+func deleteTracesByMessage(owner string, message string) error {
+	_, err := adapter.engine.Delete(&Trace{Owner: owner, Message: message})
+	return err
+}
+
+func deleteTracesByChat(owner string, chat string) error {
+	_, err := adapter.engine.Delete(&Trace{Owner: owner, Chat: chat})
+	return err
+}
+
+func (trace *Trace) GetId() string {
+	return fmt.Sprintf("%s/%s", trace.Owner, trace.Name)
+}
+
+func getTraceMessages(messages []*model.RawMessage) []*TraceMessage {
+	res := []*TraceMessage{}
+	for _, message := range messages {
+		res = append(res, &TraceMessage{Author: message.Author, Text: message.Text})
+	}
+	return res
+}
+
+func getRawMessages(messages []*TraceMessage) []*model.RawMessage {
+	res := []*model.RawMessage{}
+	for _, message := range messages {
+		tokenCount, err := model.GetTokenSize("", message.Text)
+		if err != nil {
+			tokenCount = 0
+		}
+		res = append(res, &model.RawMessage{Author: message.Author, Text: message.Text, TextTokenCount: tokenCount})
+	}
+	return res
+}
+
+// AddTraceForMessage saves the trace of the answer message with the inputs of the model. The
+// failed answers are traced with their error, the successful ones without any recorded step,
+// like the cached ones, have no trace.
+func AddTraceForMessage(message *Message, modelProvider string, question string, prompt string, history []*model.RawMessage, knowledge []*model.RawMessage, agentClients *agent.AgentClients, agentTrace *model.AgentTrace, startTime time.Time) error {
+	steps := agentTrace.GetSteps()
+	if len(steps) == 0 && message.ErrorText == "" {
+		return nil
+	}
+
+	tools := []*protocol.Tool{}
+	if agentClients != nil {
+		tools = agentClients.Tools
+	}
+
+	trace := &Trace{
+		Owner:         message.Owner,
+		Name:          message.Name,
+		CreatedTime:   util.GetCurrentTime(),
+		User:          message.User,
+		Store:         message.Store,
+		Chat:          message.Chat,
+		Message:       message.Name,
+		ModelProvider: modelProvider,
+		Question:      question,
+		Prompt:        prompt,
+		History:       getTraceMessages(history),
+		Knowledge:     getTraceMessages(knowledge),
+		Tools:         tools,
+		Steps:         steps,
+		Answer:        message.Text,
+		ErrorText:     message.ErrorText,
+		TokenCount:    message.TokenCount,
+		Price:         message.Price,
+		Currency:      message.Currency,
+		Duration:      time.Since(startTime).Milliseconds(),
+	}
+
+	// a regenerated answer replaces the trace of the previous one
+	_, err := DeleteTrace(trace)
+	if err != nil {
+		return err
+	}
+
+	_, err = AddTrace(trace)
+	return err
+}
+
+// ReplayTrace re-runs the traced answer with the model provider, answering the tool calls with the
+// recorded results instead of calling the tools, and saves the replay as a trace of the same
+// message for the regression comparison. A failed replay is saved with its error as well.
+func ReplayTrace(ctx context.Context, trace *Trace, modelProviderName string, lang string) (*Trace, error) {
+	modelProvider, modelProviderObj, err := GetModelProviderFromContext("admin", modelProviderName, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	agentTrace := model.NewAgentTrace()
+	writer := &model.AnswerWriter{}
+	history := getRawMessages(trace.History)
+	knowledge := getRawMessages(trace.Knowledge)
+	startTime := time.Now()
+
+	var modelResult *model.ModelResult
+	if len(trace.Tools) > 0 {
+		agentInfo := &model.AgentInfo{
+			AgentClients:  &agent.AgentClients{Tools: trace.Tools},
+			AgentMessages: &model.AgentMessages{Messages: []*model.RawMessage{}},
+			Context:       ctx,
+			Trace:         agentTrace,
+			Replay:        model.NewAgentReplay(trace.Steps),
+		}
+		modelResult, err = model.QueryTextWithTools(modelProviderObj, trace.Question, writer, history, trace.Prompt, knowledge, agentInfo, lang)
+	} else {
+		modelResult, err = agentTrace.QueryText(modelProviderObj, trace.Question, writer, history, trace.Prompt, knowledge, nil, lang)
+	}
+
+	replay := &Trace{
+		Owner:         trace.Owner,
+		Name:          fmt.Sprintf("%s_replay_%s", trace.Message, util.GetRandomName()),
+		CreatedTime:   util.GetCurrentTime(),
+		User:          trace.User,
+		Store:         trace.Store,
+		Chat:          trace.Chat,
+		Message:       trace.Message,
+		ReplayOf:      trace.Name,
+		ModelProvider: modelProvider.Name,
+		Question:      trace.Question,
+		Prompt:        trace.Prompt,
+		History:       trace.History,
+		Knowledge:     trace.Knowledge,
+		Tools:         trace.Tools,
+		Steps:         agentTrace.GetSteps(),
+		Answer:        strings.TrimSpace(writer.String()),
+		Duration:      time.Since(startTime).Milliseconds(),
+	}
+	if err != nil {
+		replay.ErrorText = err.Error()
+	}
+	if modelResult != nil {
+		replay.TokenCount = modelResult.TotalTokenCount
+		replay.Price = model.AddPrices(modelResult.TotalPrice, 0)
+		replay.Currency = modelResult.Currency
+	}
+
+	_, err = AddTrace(replay)
+	if err != nil {
+		return nil, err
+	}
+	return replay, nil
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/beego/beego/logs"
 	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
 	"github.com/casibase/casibase/conf"
+	"github.com/casibase/casibase/model"
 	"github.com/casibase/casibase/util"
 	"github.com/robfig/cron/v3"
 )
@@ -103,6 +104,32 @@ func AddTransactionForMessage(message *Message) error {
 	return nil
 }
 
+// AddTransactionForChatSummary creates a transaction in Casdoor for the summarisation of a chat's
+// older turns, billed to the chat's user like its answers.
+func AddTransactionForChatSummary(chat *Chat, modelProviderName string, modelResult *model.ModelResult) error {
+	if modelResult.TotalPrice <= 0 {
+		return nil
+	}
+
+	message := &Message{
+		Owner:         chat.Owner,
+		Name:          fmt.Sprintf("summary_%s", util.GetRandomName()),
+		CreatedTime:   util.GetCurrentTime(),
+		User:          chat.User,
+		Chat:          chat.Name,
+		ModelProvider: modelProviderName,
+		Price:         modelResult.TotalPrice,
+		Currency:      modelResult.Currency,
+	}
+	transaction := createTransactionFromMessage(message)
+
+	_, _, err := casdoorsdk.AddTransaction(transaction)
+	if err != nil {
+		return fmt.Errorf("failed to add transaction: %s", err.Error())
+	}
+	return nil
+}
+
 func retryFailedTransaction() error {
 	messages, err := GetGlobalFailMessages()
 	if err != nil {
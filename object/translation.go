@@ -0,0 +1,196 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/beego/beego/logs"
+	"github.com/casibase/casibase/model"
+)
+
+const defaultTranslationLanguage = "English"
+
+type TranslationRequest struct {
+	Text           string   `json:"text"`
+	TargetLanguage string   `json:"targetLanguage"`
+	Provider       string   `json:"provider"`
+	Glossaries     []string `json:"glossaries"`
+}
+
+type TranslationResult struct {
+	Text         string          `json:"text"`
+	MissingTerms []*GlossaryTerm `json:"missingTerms"`
+	SegmentCount int             `json:"segmentCount"`
+	CachedCount  int             `json:"cachedCount"`
+}
+
+// translator translates texts segment by segment with a model provider, following the glossary
+// terms and caching the segments whose translation follows them.
+type translator struct {
+	provider       *Provider
+	providerObj    model.ModelProvider
+	targetLanguage string
+	terms          []*GlossaryTerm
+	fingerprint    string
+	lang           string
+}
+
+func newTranslator(providerName string, targetLanguage string, terms []*GlossaryTerm, lang string) (*translator, error) {
+	provider, providerObj, err := GetModelProviderFromContext("admin", providerName, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	if targetLanguage == "" {
+		targetLanguage = defaultTranslationLanguage
+	}
+
+	termsData, err := json.Marshal(terms)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &translator{
+		provider:       provider,
+		providerObj:    providerObj,
+		targetLanguage: targetLanguage,
+		terms:          terms,
+		fingerprint:    fmt.Sprintf("%s\x00%s\x00%s", provider.Name, targetLanguage, termsData),
+		lang:           lang,
+	}
+	return t, nil
+}
+
+func (t *translator) query(question string) (string, error) {
+	var writer MyWriter
+	_, err := t.providerObj.QueryText(question, &writer, []*model.RawMessage{}, "", []*model.RawMessage{}, nil, t.lang)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(writer.String()), nil
+}
+
+// getCacheName identifies the translation of the segment by everything it depends on.
+func (t *translator) getCacheName(segment string) string {
+	hash := sha256.Sum256([]byte(t.fingerprint + "\x00" + segment))
+	return hex.EncodeToString(hash[:])
+}
+
+// translateSegment translates the segment following the glossary, asking once more when the
+// translation misses some of the glossary terms, which are returned if they are still missing.
+func (t *translator) translateSegment(segment string) (string, []*GlossaryTerm, bool, error) {
+	cacheName := t.getCacheName(segment)
+	cache, err := getTranslationCache("admin", cacheName)
+	if err != nil {
+		return "", nil, false, err
+	}
+	if cache != nil {
+		err = hitTranslationCache(cache)
+		if err != nil {
+			logs.Error("translateSegment() error, failed to update translation cache: %s, %s", cache.Name, err.Error())
+		}
+		return cache.Target, nil, true, nil
+	}
+
+	question := fmt.Sprintf("Translate the following text to %s.\n%sOnly respond with the translated text:\n%s", t.targetLanguage, getGlossaryInstruction(t.terms), segment)
+	translation, err := t.query(question)
+	if err != nil {
+		return "", nil, false, err
+	}
+
+	missingTerms := getMissingGlossaryTerms(t.terms, segment, translation)
+	if len(missingTerms) != 0 {
+		question = fmt.Sprintf("%s\nYour previous translation was:\n%s\nIt did not follow these glossary terms: %s. Translate it again following the glossary.", question, translation, getGlossaryTermsText(missingTerms))
+		translation, err = t.query(question)
+		if err != nil {
+			return "", nil, false, err
+		}
+
+		missingTerms = getMissingGlossaryTerms(t.terms, segment, translation)
+	}
+
+	// the translations missing glossary terms are not cached to be tried again next time
+	if len(missingTerms) == 0 {
+		err = addTranslationCache(cacheName, t.provider.Name, t.targetLanguage, segment, translation)
+		if err != nil {
+			logs.Error("translateSegment() error, failed to add translation cache: %s, %s", cacheName, err.Error())
+		}
+	}
+
+	return translation, missingTerms, false, nil
+}
+
+// translate translates the text line by line so that the unchanged lines of an edited text
+// are found in the cache, the empty lines being kept as they are.
+func (t *translator) translate(text string) (*TranslationResult, error) {
+	result := &TranslationResult{MissingTerms: []*GlossaryTerm{}}
+	missingSources := map[string]bool{}
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		segment := strings.TrimSpace(line)
+		if segment == "" {
+			lines[i] = ""
+			continue
+		}
+
+		translation, missingTerms, isCached, err := t.translateSegment(segment)
+		if err != nil {
+			return nil, err
+		}
+
+		lines[i] = translation
+		result.SegmentCount += 1
+		if isCached {
+			result.CachedCount += 1
+		}
+		for _, term := range missingTerms {
+			if !missingSources[term.Source] {
+				missingSources[term.Source] = true
+				result.MissingTerms = append(result.MissingTerms, term)
+			}
+		}
+	}
+
+	result.Text = strings.TrimSpace(strings.Join(lines, "\n"))
+	return result, nil
+}
+
+// Translate translates the text of the request with its model provider, the one of the default
+// store if empty, following the terms of its glossaries.
+func Translate(request *TranslationRequest, lang string) (*TranslationResult, error) {
+	targetLanguage := request.TargetLanguage
+	if targetLanguage == "" {
+		targetLanguage = defaultTranslationLanguage
+	}
+
+	terms, err := getGlossaryTerms(request.Glossaries, targetLanguage, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := newTranslator(request.Provider, targetLanguage, terms, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.translate(request.Text)
+}
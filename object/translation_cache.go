@@ -0,0 +1,86 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"fmt"
+
+	"github.com/casibase/casibase/util"
+	"xorm.io/core"
+)
+
+// TranslationCache is a translated segment, named by the hash of the segment, the model provider,
+// the target language and the glossary terms of its translation, see translator.getCacheName.
+type TranslationCache struct {
+	Owner       string `xorm:"varchar(100) notnull pk" json:"owner"`
+	Name        string `xorm:"varchar(100) notnull pk" json:"name"`
+	CreatedTime string `xorm:"varchar(100)" json:"createdTime"`
+
+	Provider       string `xorm:"varchar(100)" json:"provider"`
+	TargetLanguage string `xorm:"varchar(100)" json:"targetLanguage"`
+	Source         string `xorm:"mediumtext" json:"source"`
+	Target         string `xorm:"mediumtext" json:"target"`
+	HitCount       int    `json:"hitCount"`
+	LastHitTime    string `xorm:"varchar(100)" json:"lastHitTime"`
+}
+
+func getTranslationCache(owner string, name string) (*TranslationCache, error) {
+	cache := TranslationCache{Owner: owner, Name: name}
+	existed, err := adapter.engine.Get(&cache)
+	if err != nil {
+		return &cache, err
+	}
+
+	if existed {
+		return &cache, nil
+	} else {
+		return nil, nil
+	}
+}
+
+func addTranslationCache(name string, provider string, targetLanguage string, source string, target string) error {
+	cache := &TranslationCache{
+		Owner:          "admin",
+		Name:           name,
+		CreatedTime:    util.GetCurrentTime(),
+		Provider:       provider,
+		TargetLanguage: targetLanguage,
+		Source:         source,
+		Target:         target,
+	}
+	_, err := adapter.engine.Insert(cache)
+	return err
+}
+
+func hitTranslationCache(cache *TranslationCache) error {
+	cache.HitCount += 1
+	cache.LastHitTime = util.GetCurrentTime()
+	_, err := adapter.engine.ID(core.PK{cache.Owner, cache.Name}).Cols("hit_count", "last_hit_time").Update(cache)
+	return err
+}
+
+// DeleteTranslationCaches clears the cached translations of the model provider, or all of them
+// if empty, e.g. after the provider's model has changed.
+func DeleteTranslationCaches(provider string) (int64, error) {
+	session := adapter.engine.Where("owner = ?", "admin")
+	if provider != "" {
+		session = session.And("provider = ?", provider)
+	}
+	return session.Delete(&TranslationCache{})
+}
+
+func (cache *TranslationCache) GetId() string {
+	return fmt.Sprintf("%s/%s", cache.Owner, cache.Name)
+}
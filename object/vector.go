@@ -169,6 +169,11 @@ func DeleteVectorsByStore(owner string, storeName string) (bool, error) {
 		return false, err
 	}
 
+	_, err = DeleteAnswerCachesByStore(owner, storeName)
+	if err != nil {
+		return false, err
+	}
+
 	return affected != 0, nil
 }
 
@@ -178,6 +183,11 @@ func DeleteVectorsByFile(owner string, storeName string, fileKey string) (bool,
 		return false, err
 	}
 
+	_, err = DeleteAnswerCachesByStore(owner, storeName)
+	if err != nil {
+		return false, err
+	}
+
 	return affected != 0, nil
 }
 
@@ -31,6 +31,7 @@ type Label struct {
 	StartTime float64 `json:"startTime"`
 	EndTime   float64 `json:"endTime"`
 	Text      string  `xorm:"varchar(100)" json:"text"`
+	TextEn    string  `xorm:"varchar(100)" json:"textEn"`
 	Speaker   string  `xorm:"varchar(100)" json:"speaker"`
 	Tag1      string  `xorm:"varchar(100)" json:"tag1"`
 	Tag2      string  `xorm:"varchar(100)" json:"tag2"`
@@ -87,6 +88,8 @@ type Video struct {
 	Keywords []string `xorm:"varchar(200)" json:"keywords"`
 	Template string   `xorm:"varchar(200)" json:"template"`
 
+	Glossaries []string `xorm:"varchar(500)" json:"glossaries"`
+
 	Task1 string `xorm:"varchar(100)" json:"task1"`
 	Task2 string `xorm:"varchar(100)" json:"task2"`
 	Task3 string `xorm:"varchar(100)" json:"task3"`
@@ -0,0 +1,73 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"fmt"
+
+	"github.com/casibase/casibase/i18n"
+	"github.com/casibase/casibase/util"
+)
+
+// TranslateVideoLabels translates the text of the labels of the video to English following its
+// glossaries, the result summing up the translations of all the labels.
+func TranslateVideoLabels(id string, provider string, lang string) (*Video, *TranslationResult, error) {
+	owner, name, err := util.GetOwnerAndNameFromIdWithError(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	v, err := getVideo(owner, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if v == nil {
+		return nil, nil, fmt.Errorf(i18n.Translate(lang, "object:The video: %s is not found"), id)
+	}
+
+	terms, err := getGlossaryTerms(v.Glossaries, defaultTranslationLanguage, lang)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	t, err := newTranslator(provider, defaultTranslationLanguage, terms, lang)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res := &TranslationResult{MissingTerms: []*GlossaryTerm{}}
+	for _, label := range v.Labels {
+		if label.Text == "" {
+			continue
+		}
+
+		result, err := t.translate(label.Text)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		label.TextEn = result.Text
+		res.SegmentCount += result.SegmentCount
+		res.CachedCount += result.CachedCount
+		res.MissingTerms = append(res.MissingTerms, result.MissingTerms...)
+	}
+
+	_, err = UpdateVideo(id, v)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return v, res, nil
+}
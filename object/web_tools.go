@@ -0,0 +1,48 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"github.com/casibase/casibase/agent"
+	"github.com/casibase/casibase/agent/builtin_tool/web"
+)
+
+// getWebPolicy returns the domains and the response size, in KB, the web tools of the store are limited to.
+func (store *Store) getWebPolicy() *webtools.WebPolicy {
+	return &webtools.WebPolicy{
+		AllowedDomains:   store.WebAllowedDomains,
+		DeniedDomains:    store.WebDeniedDomains,
+		MaxResponseBytes: store.WebMaxResponseSize * 1024,
+	}
+}
+
+// BindWebTools binds the web_fetch and http_request tools selected by the store to its web policy.
+func (store *Store) BindWebTools(agentClients *agent.AgentClients, lang string) {
+	policy := store.getWebPolicy()
+
+	tool, ok := store.getSelectedBuiltinTool(agentClients, "web_fetch")
+	if ok {
+		if webFetchTool, ok := tool.(*webtools.WebFetchTool); ok {
+			agentClients.BuiltinToolReg.RegisterTool(webFetchTool.WithPolicy(policy, lang))
+		}
+	}
+
+	tool, ok = store.getSelectedBuiltinTool(agentClients, "http_request")
+	if ok {
+		if httpRequestTool, ok := tool.(*webtools.HttpRequestTool); ok {
+			agentClients.BuiltinToolReg.RegisterTool(httpRequestTool.WithPolicy(policy))
+		}
+	}
+}
@@ -28,10 +28,27 @@ type Workflow struct {
 	CreatedTime string `xorm:"varchar(100)" json:"createdTime"`
 	DisplayName string `xorm:"varchar(100)" json:"displayName"`
 
-	Text             string `xorm:"mediumtext" json:"text"`
-	Text2            string `xorm:"mediumtext" json:"text2"`
-	Message          string `xorm:"mediumtext" json:"message"`
-	QuestionTemplate string `xorm:"mediumtext" json:"questionTemplate"`
+	Text             string   `xorm:"mediumtext" json:"text"`
+	Text2            string   `xorm:"mediumtext" json:"text2"`
+	Message          string   `xorm:"mediumtext" json:"message"`
+	QuestionTemplate string   `xorm:"mediumtext" json:"questionTemplate"`
+	Starters         []string `xorm:"mediumtext" json:"starters"`
+}
+
+// CanStartWorkflow reports whether the user can start an instance of the workflow. The admins can
+// start any workflow, the other users only the workflows listing them as starters. "*" lets anyone
+// start the workflow, including the anonymous submitters of the public forms.
+func CanStartWorkflow(workflow *Workflow, user string, isAdmin bool) bool {
+	if isAdmin {
+		return true
+	}
+
+	for _, starter := range workflow.Starters {
+		if starter == "*" || (user != "" && starter == user) {
+			return true
+		}
+	}
+	return false
 }
 
 func GetMaskedWorkflow(workflow *Workflow, isMaskEnabled bool) *Workflow {
@@ -153,6 +170,16 @@ func DeleteWorkflow(workflow *Workflow) (bool, error) {
 	return affected != 0, nil
 }
 
+// ValidateWorkflow checks the process of the workflow for the structural problems.
+func ValidateWorkflow(workflow *Workflow, lang string) ([]*bpmn.ValidationIssue, error) {
+	model, err := bpmn.ParseModel(workflow.Text, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	return bpmn.ValidateModel(model, lang), nil
+}
+
 func (workflow *Workflow) GetId() string {
 	return fmt.Sprintf("%s/%s", workflow.Owner, workflow.Name)
 }
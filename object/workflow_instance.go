@@ -0,0 +1,541 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/beego/beego/logs"
+	"github.com/casibase/casibase/agent"
+	"github.com/casibase/casibase/bpmn"
+	"github.com/casibase/casibase/i18n"
+	"github.com/casibase/casibase/util"
+	"github.com/robfig/cron/v3"
+	"xorm.io/core"
+)
+
+const (
+	WorkflowInstanceStateRunning   = "Running"
+	WorkflowInstanceStateCompleted = "Completed"
+	WorkflowInstanceStateFailed    = "Failed"
+	WorkflowInstanceStateCancelled = "Cancelled"
+)
+
+const workflowServiceTaskTimeout = 2 * time.Minute
+
+// WorkflowInstance is an execution of the process of a Workflow. Its revision is bumped on every
+// save, so that a user completing a task and the timer job firing a timer at the same time do not
+// overwrite each other's changes. WorkflowText is the BPMN of the workflow when the instance
+// started, the instance runs on it even if the workflow is edited meanwhile.
+type WorkflowInstance struct {
+	Owner       string `xorm:"varchar(100) notnull pk" json:"owner"`
+	Name        string `xorm:"varchar(100) notnull pk" json:"name"`
+	CreatedTime string `xorm:"varchar(100)" json:"createdTime"`
+	UpdatedTime string `xorm:"varchar(100)" json:"updatedTime"`
+
+	Workflow     string `xorm:"varchar(100) index" json:"workflow"`
+	WorkflowText string `xorm:"mediumtext" json:"workflowText"`
+	User         string `xorm:"varchar(100)" json:"user"`
+	State        string `xorm:"varchar(100)" json:"state"`
+	NextDueTime  string `xorm:"varchar(100) index" json:"nextDueTime"`
+	ErrorText    string `xorm:"mediumtext" json:"errorText"`
+	Revision     int    `json:"revision"`
+
+	Variables  map[string]interface{} `xorm:"mediumtext" json:"variables"`
+	Tokens     []*bpmn.Token          `xorm:"mediumtext" json:"tokens"`
+	Joins      map[string][]string    `xorm:"mediumtext" json:"joins"`
+	TokenCount int                    `json:"tokenCount"`
+	History    []*bpmn.HistoryEvent   `xorm:"mediumtext" json:"history"`
+}
+
+// WorkflowTask is a user task waiting to be completed.
+type WorkflowTask struct {
+	Instance       string   `json:"instance"`
+	Workflow       string   `json:"workflow"`
+	Token          string   `json:"token"`
+	NodeId         string   `json:"nodeId"`
+	NodeName       string   `json:"nodeName"`
+	Assignee       string   `json:"assignee"`
+	CandidateUsers []string `json:"candidateUsers"`
+	CreatedTime    string   `json:"createdTime"`
+}
+
+func GetWorkflowInstances(owner string, workflow string) ([]*WorkflowInstance, error) {
+	instances := []*WorkflowInstance{}
+	session := adapter.engine.Desc("created_time").Omit("history", "workflow_text")
+	if workflow != "" {
+		session = session.Where("workflow = ?", workflow)
+	}
+	err := session.Find(&instances, &WorkflowInstance{Owner: owner})
+	if err != nil {
+		return instances, err
+	}
+
+	return instances, nil
+}
+
+func getWorkflowInstance(owner string, name string) (*WorkflowInstance, error) {
+	instance := WorkflowInstance{Owner: owner, Name: name}
+	existed, err := adapter.engine.Get(&instance)
+	if err != nil {
+		return &instance, err
+	}
+
+	if existed {
+		return &instance, nil
+	} else {
+		return nil, nil
+	}
+}
+
+func GetWorkflowInstance(id string) (*WorkflowInstance, error) {
+	owner, name, err := util.GetOwnerAndNameFromIdWithError(id)
+	if err != nil {
+		return nil, err
+	}
+	return getWorkflowInstance(owner, name)
+}
+
+func DeleteWorkflowInstance(instance *WorkflowInstance) (bool, error) {
+	affected, err := adapter.engine.ID(core.PK{instance.Owner, instance.Name}).Delete(&WorkflowInstance{})
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+func (instance *WorkflowInstance) GetId() string {
+	return fmt.Sprintf("%s/%s", instance.Owner, instance.Name)
+}
+
+func (instance *WorkflowInstance) getState() *bpmn.InstanceState {
+	state := bpmn.NewInstanceState(instance.Variables)
+	if instance.Tokens != nil {
+		state.Tokens = instance.Tokens
+	}
+	if instance.Joins != nil {
+		state.Joins = instance.Joins
+	}
+	if instance.History != nil {
+		state.History = instance.History
+	}
+	state.TokenCount = instance.TokenCount
+	state.IsEnded = instance.State == WorkflowInstanceStateCompleted
+	return state
+}
+
+func (instance *WorkflowInstance) setState(state *bpmn.InstanceState, err error) {
+	instance.Variables = state.Variables
+	instance.Tokens = state.Tokens
+	instance.Joins = state.Joins
+	instance.History = state.History
+	instance.TokenCount = state.TokenCount
+	instance.NextDueTime = bpmn.GetNextDueTime(state)
+	if err != nil {
+		instance.State = WorkflowInstanceStateFailed
+		instance.ErrorText = err.Error()
+		instance.NextDueTime = ""
+	} else if state.IsEnded {
+		instance.State = WorkflowInstanceStateCompleted
+	}
+}
+
+// saveWorkflowInstance saves the instance if nobody else has saved it since it was read.
+func saveWorkflowInstance(instance *WorkflowInstance, lang string) error {
+	revision := instance.Revision
+	instance.Revision++
+	instance.UpdatedTime = util.GetCurrentTime()
+	affected, err := adapter.engine.ID(core.PK{instance.Owner, instance.Name}).Where("revision = ?", revision).AllCols().Update(instance)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf(i18n.Translate(lang, "object:The workflow instance: %s has been changed by someone else, please retry"), instance.GetId())
+	}
+	return nil
+}
+
+func getWorkflowEngine(text string, lang string) (*bpmn.Engine, error) {
+	model, err := bpmn.ParseModel(text, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	return bpmn.NewEngine(model, func(node *bpmn.Node, variables map[string]interface{}) (map[string]interface{}, error) {
+		return callWorkflowServiceTask(node, variables, lang)
+	}, lang), nil
+}
+
+// StartWorkflowInstance starts an instance of the process of the workflow with the variables and
+// runs it up to its first user tasks or timers.
+func StartWorkflowInstance(workflow *Workflow, user string, variables map[string]interface{}, lang string) (*WorkflowInstance, error) {
+	engine, err := getWorkflowEngine(workflow.Text, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	// the structural errors like a deadlocking join would leave the instance stuck forever
+	for _, issue := range bpmn.ValidateModel(engine.Model, lang) {
+		if issue.Severity == bpmn.ValidationSeverityError {
+			return nil, fmt.Errorf("%s", issue.Message)
+		}
+	}
+
+	currentTime := util.GetCurrentTime()
+	instance := &WorkflowInstance{
+		Owner:        workflow.Owner,
+		Name:         fmt.Sprintf("%s_%s", workflow.Name, util.GetRandomName()),
+		CreatedTime:  currentTime,
+		UpdatedTime:  currentTime,
+		Workflow:     workflow.Name,
+		WorkflowText: workflow.Text,
+		User:         user,
+		State:        WorkflowInstanceStateRunning,
+	}
+
+	state := bpmn.NewInstanceState(variables)
+	err = engine.Start(state, user)
+	instance.setState(state, err)
+
+	_, err = adapter.engine.Insert(instance)
+	if err != nil {
+		return nil, err
+	}
+	return instance, nil
+}
+
+// getWorkflowTextOfInstance returns the BPMN the instance started with. The instances started
+// before the BPMN was kept on them fall back to the current BPMN of their workflow.
+func getWorkflowTextOfInstance(instance *WorkflowInstance, lang string) (string, error) {
+	if instance.WorkflowText != "" {
+		return instance.WorkflowText, nil
+	}
+
+	workflow, err := getWorkflow(instance.Owner, instance.Workflow)
+	if err != nil {
+		return "", err
+	}
+	if workflow == nil {
+		return "", fmt.Errorf(i18n.Translate(lang, "object:The workflow: %s is not found"), instance.Workflow)
+	}
+	return workflow.Text, nil
+}
+
+func canCompleteWorkflowTask(instance *WorkflowInstance, token *bpmn.Token, user string) bool {
+	if token.Assignee == "" && len(token.CandidateUsers) == 0 {
+		return user == instance.User
+	}
+	return token.CanCompleteTask(user)
+}
+
+// CompleteWorkflowTask completes the user task the token of the instance waits at. The user must
+// be the assignee or a candidate user of the task, the starter of the instance for a task without
+// assignment, or an admin.
+func CompleteWorkflowTask(instance *WorkflowInstance, tokenId string, user string, isAdmin bool, variables map[string]interface{}, lang string) error {
+	if instance.State != WorkflowInstanceStateRunning {
+		return fmt.Errorf(i18n.Translate(lang, "object:The workflow instance: %s is not running"), instance.GetId())
+	}
+
+	text, err := getWorkflowTextOfInstance(instance, lang)
+	if err != nil {
+		return err
+	}
+	engine, err := getWorkflowEngine(text, lang)
+	if err != nil {
+		return err
+	}
+
+	state := instance.getState()
+	token, err := engine.GetUserTask(state, tokenId)
+	if err != nil {
+		return err
+	}
+	if !isAdmin && !canCompleteWorkflowTask(instance, token, user) {
+		return fmt.Errorf(i18n.Translate(lang, "object:The task: %s is not assigned to you"), tokenId)
+	}
+
+	// the errors of running the instance on from the task fail the instance
+	err = engine.CompleteUserTask(state, tokenId, user, variables)
+	instance.setState(state, err)
+	return saveWorkflowInstance(instance, lang)
+}
+
+func CancelWorkflowInstance(instance *WorkflowInstance, lang string) error {
+	if instance.State != WorkflowInstanceStateRunning {
+		return fmt.Errorf(i18n.Translate(lang, "object:The workflow instance: %s is not running"), instance.GetId())
+	}
+
+	instance.State = WorkflowInstanceStateCancelled
+	instance.NextDueTime = ""
+	return saveWorkflowInstance(instance, lang)
+}
+
+// GetWorkflowTasks returns the user tasks waiting for the user in the running instances, all of
+// them for an admin.
+func GetWorkflowTasks(owner string, user string, isAdmin bool) ([]*WorkflowTask, error) {
+	instances := []*WorkflowInstance{}
+	err := adapter.engine.Desc("created_time").Cols("owner", "name", "workflow", "workflow_text", "user", "tokens").
+		Find(&instances, &WorkflowInstance{Owner: owner, State: WorkflowInstanceStateRunning})
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := []*WorkflowTask{}
+	for _, instance := range instances {
+		var workflowModel *bpmn.Model
+		for _, token := range instance.Tokens {
+			if token.State != bpmn.TokenStateWaiting || token.DueTime != "" {
+				continue
+			}
+
+			if !isAdmin && !canCompleteWorkflowTask(instance, token, user) {
+				continue
+			}
+
+			nodeName := token.NodeId
+			if workflowModel == nil {
+				text, err := getWorkflowTextOfInstance(instance, "en")
+				if err == nil {
+					workflowModel, _ = bpmn.ParseModel(text, "en")
+				}
+			}
+			if workflowModel != nil && workflowModel.Nodes[token.NodeId] != nil && workflowModel.Nodes[token.NodeId].Name != "" {
+				nodeName = workflowModel.Nodes[token.NodeId].Name
+			}
+
+			tasks = append(tasks, &WorkflowTask{
+				Instance:       instance.GetId(),
+				Workflow:       instance.Workflow,
+				Token:          token.Id,
+				NodeId:         token.NodeId,
+				NodeName:       nodeName,
+				Assignee:       token.Assignee,
+				CandidateUsers: token.CandidateUsers,
+				CreatedTime:    token.CreatedTime,
+			})
+		}
+	}
+	return tasks, nil
+}
+
+// callWorkflowServiceTask executes a service task with the store given by its store attribute,
+// the default store if none: it calls the MCP tool given by its tool attribute (as
+// server__tool) with its arguments attribute as the JSON arguments, or else asks the store's
+// model its prompt attribute. The ${name} placeholders of the arguments and the prompt are
+// replaced with the variables, and the result is saved in the variable named by the
+// resultVariable attribute, the node id if none.
+func callWorkflowServiceTask(node *bpmn.Node, variables map[string]interface{}, lang string) (map[string]interface{}, error) {
+	var store *Store
+	var err error
+	if storeName := node.Attributes["store"]; storeName != "" {
+		store, err = getStore("admin", storeName)
+		if err == nil && store == nil {
+			err = fmt.Errorf(i18n.Translate(lang, "account:The store: %s is not found"), storeName)
+		}
+	} else {
+		store, err = GetDefaultStore("admin")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if store == nil {
+		return nil, fmt.Errorf(i18n.Translate(lang, "account:The default store is not found"))
+	}
+
+	resultVariable := node.Attributes["resultVariable"]
+	if resultVariable == "" {
+		resultVariable = node.Id
+	}
+
+	var result string
+	if tool := node.Attributes["tool"]; tool != "" {
+		result, err = callWorkflowTool(store, tool, replaceWorkflowArguments(node.Attributes["arguments"], variables), lang)
+	} else if prompt := node.Attributes["prompt"]; prompt != "" {
+		result, _, err = GetAnswerWithContext(store.ModelProvider, bpmn.ReplaceVariables(prompt, variables), nil, nil, store.Prompt, lang)
+	} else {
+		err = fmt.Errorf(i18n.Translate(lang, "object:The service task: %s has neither a tool nor a prompt"), node.Id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{resultVariable: strings.TrimSpace(result)}, nil
+}
+
+// replaceWorkflowArguments replaces the ${name} placeholders of the JSON arguments, escaping the
+// values for the JSON strings they are in.
+func replaceWorkflowArguments(arguments string, variables map[string]interface{}) string {
+	escaped := map[string]interface{}{}
+	for key, value := range variables {
+		if text, ok := value.(string); ok {
+			bytes, err := json.Marshal(text)
+			if err == nil {
+				value = string(bytes[1 : len(bytes)-1])
+			}
+		}
+		escaped[key] = value
+	}
+	return bpmn.ReplaceVariables(arguments, escaped)
+}
+
+func callWorkflowTool(store *Store, tool string, arguments string, lang string) (string, error) {
+	_, agentProviderObj, err := GetAgentProviderFromContext("admin", store.AgentProvider, lang)
+	if err != nil {
+		return "", err
+	}
+	agentClients, err := GetAgentClients(agentProviderObj)
+	if err != nil {
+		return "", err
+	}
+	defer agentClients.Release()
+	if agentClients == nil {
+		return "", fmt.Errorf(i18n.Translate(lang, "object:The store: %s has no agent provider"), store.Name)
+	}
+
+	// the same checks as the tool calls of the model, the service tasks can't bypass the tool policies
+	switch agentClients.GetToolPolicy(tool) {
+	case agent.ToolPolicyDeny:
+		return "", fmt.Errorf(i18n.Translate(lang, "model:the tool: %s is denied by its policy"), tool)
+	case agent.ToolPolicyApproval:
+		return "", fmt.Errorf(i18n.Translate(lang, "object:the tool: %s requires an approval and can't be called by a workflow"), tool)
+	}
+	if !isWorkflowToolEnabled(agentClients, tool) {
+		return "", fmt.Errorf(i18n.Translate(lang, "model:the tool: %s is not available"), tool)
+	}
+
+	argumentMap := map[string]interface{}{}
+	if strings.TrimSpace(arguments) != "" {
+		err = json.Unmarshal([]byte(arguments), &argumentMap)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), workflowServiceTaskTimeout)
+	defer cancel()
+	serverName, toolName := agent.GetServerNameAndToolNameFromId(tool)
+	result, err := agentClients.CallTool(ctx, serverName, toolName, argumentMap)
+	if err != nil {
+		return "", err
+	}
+
+	texts := []string{}
+	for _, content := range result.Content {
+		if textContent, ok := content.(*protocol.TextContent); ok {
+			texts = append(texts, textContent.Text)
+		}
+	}
+	text := strings.Join(texts, "\n")
+	if result.IsError {
+		return "", fmt.Errorf("%s", text)
+	}
+	return text, nil
+}
+
+// isWorkflowToolEnabled reports whether the tool is an enabled MCP tool of a connected server.
+func isWorkflowToolEnabled(agentClients *agent.AgentClients, tool string) bool {
+	if strings.Count(tool, "__") != 1 {
+		return false
+	}
+	serverName, _ := agent.GetServerNameAndToolNameFromId(tool)
+	if _, ok := agentClients.Clients[serverName]; !ok {
+		return false
+	}
+	for _, t := range agentClients.Tools {
+		if t.Name == tool {
+			return true
+		}
+	}
+	return false
+}
+
+// InitWorkflowTimerProcessor fires the due timers of the running workflow instances.
+func InitWorkflowTimerProcessor() {
+	cronJob := cron.New()
+	_, err := cronJob.AddFunc("@every 10s", processDueWorkflowTimers)
+	if err != nil {
+		panic(err)
+	}
+	cronJob.Start()
+}
+
+func processDueWorkflowTimers() {
+	instances := []*WorkflowInstance{}
+	err := adapter.engine.Where("state = ? and next_due_time != ? and next_due_time <= ?", WorkflowInstanceStateRunning, "", time.Now().Format(time.RFC3339)).Find(&instances)
+	if err != nil {
+		logs.Error("processDueWorkflowTimers() error: %s", err.Error())
+		return
+	}
+
+	for _, instance := range instances {
+		err = fireWorkflowTimers(instance)
+		if err != nil {
+			logs.Error("processDueWorkflowTimers() error, instance: %s, %s", instance.GetId(), err.Error())
+		}
+	}
+}
+
+func fireWorkflowTimers(instance *WorkflowInstance) error {
+	text, err := getWorkflowTextOfInstance(instance, "en")
+	if err != nil {
+		return err
+	}
+	engine, err := getWorkflowEngine(text, "en")
+	if err != nil {
+		return err
+	}
+
+	state := instance.getState()
+	isFired, err := engine.FireTimers(state)
+	if !isFired {
+		return nil
+	}
+	instance.setState(state, err)
+	return saveWorkflowInstance(instance, "en")
+}
+
+// CanViewWorkflowInstance returns whether the user started the instance, handles one of its
+// waiting tasks or has completed one of its tasks.
+func CanViewWorkflowInstance(instance *WorkflowInstance, user string) bool {
+	if user == instance.User {
+		return true
+	}
+	for _, token := range instance.Tokens {
+		if token.Assignee == user || containsWorkflowUser(token.CandidateUsers, user) {
+			return true
+		}
+	}
+	for _, event := range instance.History {
+		if event.Type == bpmn.HistoryTypeTaskCompleted && event.User == user {
+			return true
+		}
+	}
+	return false
+}
+
+func containsWorkflowUser(users []string, user string) bool {
+	for _, u := range users {
+		if u == user {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/casibase/casibase/bpmn"
+	"github.com/casibase/casibase/i18n"
+	"github.com/casibase/casibase/util"
+)
+
+func getRecordCaseId(record *Record, caseField string) string {
+	switch caseField {
+	case "", "user":
+		return record.User
+	case "organization":
+		return record.Organization
+	case "unit":
+		return record.Unit
+	case "section":
+		return record.Section
+	case "clientIp":
+		return record.ClientIp
+	}
+
+	// a field of the JSON object of the record, like object.patient
+	key := strings.TrimPrefix(caseField, "object.")
+	fields := map[string]interface{}{}
+	if json.Unmarshal([]byte(record.Object), &fields) != nil || fields[key] == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", fields[key])
+}
+
+// GetRecordEventLog builds an event log from the records of the owner: the activities are their
+// actions, and the cases are given by the case field, which is user, organization, unit, section,
+// clientIp, or object.<key> for a field of the JSON object of the records.
+func GetRecordEventLog(owner string, caseField string) (*bpmn.EventLog, error) {
+	records, err := GetRecords(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	// the records come newest first, the rows of equal timestamps must stay in their order
+	rows := []*bpmn.EventLogRow{}
+	for i := len(records) - 1; i >= 0; i-- {
+		record := records[i]
+		timestamp, _ := bpmn.ParseEventTime(record.CreatedTime)
+		rows = append(rows, &bpmn.EventLogRow{
+			CaseId:    getRecordCaseId(record, caseField),
+			Activity:  record.Action,
+			Timestamp: timestamp,
+			Resource:  record.User,
+		})
+	}
+	return bpmn.NewEventLog(rows), nil
+}
+
+// GetCaaseEventLog builds an event log from the cases of the owner, taking the cases of a patient
+// as the history of the patient: each case is an activity named by its type, by its diagnosis if
+// it has no type, done at its diagnosis date.
+func GetCaaseEventLog(owner string) (*bpmn.EventLog, error) {
+	caases, err := GetCaases(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []*bpmn.EventLogRow{}
+	for i := len(caases) - 1; i >= 0; i-- {
+		caase := caases[i]
+		activity := caase.Type
+		if activity == "" {
+			activity = caase.Diagnosis
+		}
+		timestamp, ok := bpmn.ParseEventTime(caase.DiagnosisDate)
+		if !ok {
+			timestamp, _ = bpmn.ParseEventTime(caase.CreatedTime)
+		}
+		rows = append(rows, &bpmn.EventLogRow{
+			CaseId:    caase.PatientName,
+			Activity:  activity,
+			Timestamp: timestamp,
+			Resource:  caase.DoctorName,
+		})
+	}
+	return bpmn.NewEventLog(rows), nil
+}
+
+// DiscoverWorkflow discovers the process of the event log and adds it as a new workflow.
+func DiscoverWorkflow(owner string, name string, log *bpmn.EventLog, options *bpmn.DiscoveryOptions, lang string) (*Workflow, error) {
+	existing, err := getWorkflow(owner, name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf(i18n.Translate(lang, "object:The workflow: %s already exists"), name)
+	}
+
+	text, err := bpmn.DiscoverBpmn(log, name, options, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	workflow := &Workflow{
+		Owner:       owner,
+		Name:        name,
+		CreatedTime: util.GetCurrentTime(),
+		DisplayName: name,
+		Text:        text,
+	}
+	_, err = AddWorkflow(workflow, lang)
+	if err != nil {
+		return nil, err
+	}
+	return workflow, nil
+}
+
+// CheckWorkflowConformance replays the cases of the event log on the process of the workflow,
+// which is the standard the cases are checked against.
+func CheckWorkflowConformance(workflow *Workflow, log *bpmn.EventLog, lang string) (*bpmn.ConformanceReport, error) {
+	model, err := bpmn.ParseModel(workflow.Text, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	return bpmn.CheckConformance(model, log, lang)
+}
@@ -0,0 +1,41 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build skipCi
+// +build skipCi
+
+package object
+
+import "testing"
+
+func TestCanStartWorkflow(t *testing.T) {
+	workflow := &Workflow{Starters: []string{"built-in/alice"}}
+	if !CanStartWorkflow(workflow, "built-in/bob", true) {
+		t.Errorf("the admin should be able to start the workflow")
+	}
+	if !CanStartWorkflow(workflow, "built-in/alice", false) {
+		t.Errorf("the starter should be able to start the workflow")
+	}
+	if CanStartWorkflow(workflow, "built-in/bob", false) {
+		t.Errorf("the other users should not be able to start the workflow")
+	}
+	if CanStartWorkflow(workflow, "", false) {
+		t.Errorf("the anonymous users should not be able to start the workflow")
+	}
+
+	workflow.Starters = []string{"*"}
+	if !CanStartWorkflow(workflow, "", false) {
+		t.Errorf("anyone should be able to start the workflow")
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+var privateNetworks = getNetworks(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.0.0.0/24",
+	"192.168.0.0/16",
+	"198.18.0.0/15",
+	"224.0.0.0/4",
+	"240.0.0.0/4",
+	"::/128",
+	"::1/128",
+	"64:ff9b::/96",
+	"fc00::/7",
+	"fe80::/10",
+	"ff00::/8",
+)
+
+func getNetworks(cidrs ...string) []*net.IPNet {
+	res := []*net.IPNet{}
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		res = append(res, network)
+	}
+	return res
+}
+
+// IsPublicIp returns false for the loopback, private, link-local, multicast and reserved
+// addresses, which the requests made on behalf of users must not reach.
+func IsPublicIp(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	for _, network := range privateNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func getPublicIps(ctx context.Context, host string) ([]net.IP, error) {
+	ips, err := net.DefaultResolver.LookupIP(ctx, "ip", host)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ip := range ips {
+		if !IsPublicIp(ip) {
+			return nil, fmt.Errorf("the host: %s resolves to the non-public address: %s", host, ip.String())
+		}
+	}
+	return ips, nil
+}
+
+type dialFunc func(ctx context.Context, network string, address string) (net.Conn, error)
+
+// getSafeDial checks the addresses of the host before dialing. Without a proxy, it dials the
+// checked address itself so that the host can't resolve to another address in between.
+func getSafeDial(dial dialFunc, isProxy bool) dialFunc {
+	return func(ctx context.Context, network string, address string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+
+		ips, err := getPublicIps(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		if isProxy {
+			return dial(ctx, network, address)
+		}
+
+		var conn net.Conn
+		for _, ip := range ips {
+			conn, err = dial(ctx, network, net.JoinHostPort(ip.String(), port))
+			if err == nil {
+				return conn, nil
+			}
+		}
+		return nil, err
+	}
+}
+
+// GetSafeHttpClient returns the client of GetHttpClient for the URL, refusing to connect to
+// non-public addresses, including after redirects.
+func GetSafeHttpClient(url string, timeout time.Duration) *http.Client {
+	var transport *http.Transport
+	isProxy := false
+	if client := GetHttpClient(url); client == nil {
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	} else if t, ok := client.Transport.(*http.Transport); ok && t != nil {
+		// the SOCKS5 proxy dials the target address, which is checked before
+		transport = t.Clone()
+		isProxy = transport.Dial != nil || transport.DialContext != nil
+	} else {
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	}
+	transport.Proxy = nil
+
+	var dial dialFunc
+	if transport.DialContext != nil {
+		dial = transport.DialContext
+	} else if transport.Dial != nil {
+		legacyDial := transport.Dial
+		dial = func(ctx context.Context, network string, address string) (net.Conn, error) {
+			return legacyDial(network, address)
+		}
+	} else {
+		dial = (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}).DialContext
+	}
+	transport.Dial = nil
+	transport.DialContext = getSafeDial(dial, isProxy)
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+	}
+}
@@ -45,6 +45,10 @@ func AutoSigninFilter(ctx *context.Context) {
 	if accessToken != "" {
 		userId, err := getUsernameByAccessToken(accessToken)
 		if err != nil {
+			// The MCP endpoints also accept provider keys and Casdoor access tokens as Bearer tokens
+			if strings.HasPrefix(urlPath, "/api/mcp") {
+				return
+			}
 			responseError(ctx, err.Error())
 			return
 		}
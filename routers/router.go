@@ -59,6 +59,7 @@ func initAPI() {
 	beego.Router("/api/add-store", &controllers.ApiController{}, "POST:AddStore")
 	beego.Router("/api/delete-store", &controllers.ApiController{}, "POST:DeleteStore")
 	beego.Router("/api/refresh-store-vectors", &controllers.ApiController{}, "POST:RefreshStoreVectors")
+	beego.Router("/api/get-store-experiment-report", &controllers.ApiController{}, "GET:GetStoreExperimentReport")
 	beego.Router("/api/get-storage-providers", &controllers.ApiController{}, "GET:GetStorageProviders")
 	beego.Router("/api/get-store-names", &controllers.ApiController{}, "GET:GetStoreNames")
 
@@ -101,12 +102,18 @@ func initAPI() {
 	beego.Router("/api/get-messages", &controllers.ApiController{}, "GET:GetMessages")
 	beego.Router("/api/get-message", &controllers.ApiController{}, "GET:GetMessage")
 	beego.Router("/api/get-message-answer", &controllers.ApiController{}, "GET:GetMessageAnswer")
+	beego.Router("/api/approve-tool-call", &controllers.ApiController{}, "POST:ApproveToolCall")
+	beego.Router("/api/reject-tool-call", &controllers.ApiController{}, "POST:RejectToolCall")
 	beego.Router("/api/get-answer", &controllers.ApiController{}, "GET:GetAnswer")
 	beego.Router("/api/update-message", &controllers.ApiController{}, "POST:UpdateMessage")
 	beego.Router("/api/add-message", &controllers.ApiController{}, "POST:AddMessage")
 	beego.Router("/api/delete-message", &controllers.ApiController{}, "POST:DeleteMessage")
 	beego.Router("/api/delete-welcome-message", &controllers.ApiController{}, "POST:DeleteWelcomeMessage")
 
+	beego.Router("/api/get-traces", &controllers.ApiController{}, "GET:GetTraces")
+	beego.Router("/api/get-trace", &controllers.ApiController{}, "GET:GetTrace")
+	beego.Router("/api/replay-trace", &controllers.ApiController{}, "POST:ReplayTrace")
+
 	beego.Router("/api/get-global-graphs", &controllers.ApiController{}, "GET:GetGlobalGraphs")
 	beego.Router("/api/get-graphs", &controllers.ApiController{}, "GET:GetGraphs")
 	beego.Router("/api/get-graph", &controllers.ApiController{}, "GET:GetGraph")
@@ -144,6 +151,17 @@ func initAPI() {
 	beego.Router("/api/update-workflow", &controllers.ApiController{}, "POST:UpdateWorkflow")
 	beego.Router("/api/add-workflow", &controllers.ApiController{}, "POST:AddWorkflow")
 	beego.Router("/api/delete-workflow", &controllers.ApiController{}, "POST:DeleteWorkflow")
+	beego.Router("/api/validate-workflow", &controllers.ApiController{}, "POST:ValidateWorkflow")
+	beego.Router("/api/discover-workflow", &controllers.ApiController{}, "POST:DiscoverWorkflow")
+	beego.Router("/api/check-workflow-conformance", &controllers.ApiController{}, "POST:CheckWorkflowConformance")
+	beego.Router("/api/get-workflow-instances", &controllers.ApiController{}, "GET:GetWorkflowInstances")
+	beego.Router("/api/get-workflow-instance", &controllers.ApiController{}, "GET:GetWorkflowInstance")
+	beego.Router("/api/get-workflow-instance-history", &controllers.ApiController{}, "GET:GetWorkflowInstanceHistory")
+	beego.Router("/api/start-workflow-instance", &controllers.ApiController{}, "POST:StartWorkflowInstance")
+	beego.Router("/api/complete-workflow-task", &controllers.ApiController{}, "POST:CompleteWorkflowTask")
+	beego.Router("/api/get-workflow-tasks", &controllers.ApiController{}, "GET:GetWorkflowTasks")
+	beego.Router("/api/cancel-workflow-instance", &controllers.ApiController{}, "POST:CancelWorkflowInstance")
+	beego.Router("/api/delete-workflow-instance", &controllers.ApiController{}, "POST:DeleteWorkflowInstance")
 
 	beego.Router("/api/get-global-tasks", &controllers.ApiController{}, "GET:GetGlobalTasks")
 	beego.Router("/api/get-tasks", &controllers.ApiController{}, "GET:GetTasks")
@@ -153,6 +171,12 @@ func initAPI() {
 	beego.Router("/api/delete-task", &controllers.ApiController{}, "POST:DeleteTask")
 	beego.Router("/api/upload-task-document", &controllers.ApiController{}, "POST:UploadTaskDocument")
 	beego.Router("/api/analyze-task", &controllers.ApiController{}, "POST:AnalyzeTask")
+	beego.Router("/api/get-task-batches", &controllers.ApiController{}, "GET:GetTaskBatches")
+	beego.Router("/api/get-task-batch", &controllers.ApiController{}, "GET:GetTaskBatch")
+	beego.Router("/api/add-task-batch", &controllers.ApiController{}, "POST:AddTaskBatch")
+	beego.Router("/api/delete-task-batch", &controllers.ApiController{}, "POST:DeleteTaskBatch")
+	beego.Router("/api/retry-task-batch", &controllers.ApiController{}, "POST:RetryTaskBatch")
+	beego.Router("/api/export-task-results", &controllers.ApiController{}, "GET:ExportTaskResults")
 
 	beego.Router("/api/get-global-scales", &controllers.ApiController{}, "GET:GetGlobalScales")
 	beego.Router("/api/get-scales", &controllers.ApiController{}, "GET:GetScales")
@@ -161,6 +185,10 @@ func initAPI() {
 	beego.Router("/api/update-scale", &controllers.ApiController{}, "POST:UpdateScale")
 	beego.Router("/api/add-scale", &controllers.ApiController{}, "POST:AddScale")
 	beego.Router("/api/delete-scale", &controllers.ApiController{}, "POST:DeleteScale")
+	beego.Router("/api/get-scale-versions", &controllers.ApiController{}, "GET:GetScaleVersions")
+	beego.Router("/api/get-scale-version", &controllers.ApiController{}, "GET:GetScaleVersion")
+	beego.Router("/api/import-scale", &controllers.ApiController{}, "POST:ImportScale")
+	beego.Router("/api/export-scale", &controllers.ApiController{}, "GET:ExportScale")
 
 	beego.Router("/api/get-global-forms", &controllers.ApiController{}, "GET:GetGlobalForms")
 	beego.Router("/api/get-forms", &controllers.ApiController{}, "GET:GetForms")
@@ -170,6 +198,12 @@ func initAPI() {
 	beego.Router("/api/delete-form", &controllers.ApiController{}, "POST:DeleteForm")
 
 	beego.Router("/api/get-form-data", &controllers.ApiController{}, "GET:GetFormData")
+	beego.Router("/api/get-public-form", &controllers.ApiController{}, "GET:GetPublicForm")
+	beego.Router("/api/submit-form", &controllers.ApiController{}, "POST:SubmitForm")
+	beego.Router("/api/get-form-submissions", &controllers.ApiController{}, "GET:GetFormSubmissions")
+	beego.Router("/api/get-form-submission", &controllers.ApiController{}, "GET:GetFormSubmission")
+	beego.Router("/api/delete-form-submission", &controllers.ApiController{}, "POST:DeleteFormSubmission")
+	beego.Router("/api/export-form-submissions", &controllers.ApiController{}, "GET:ExportFormSubmissions")
 
 	beego.Router("/api/get-global-articles", &controllers.ApiController{}, "GET:GetGlobalArticles")
 	beego.Router("/api/get-articles", &controllers.ApiController{}, "GET:GetArticles")
@@ -177,6 +211,20 @@ func initAPI() {
 	beego.Router("/api/update-article", &controllers.ApiController{}, "POST:UpdateArticle")
 	beego.Router("/api/add-article", &controllers.ApiController{}, "POST:AddArticle")
 	beego.Router("/api/delete-article", &controllers.ApiController{}, "POST:DeleteArticle")
+	beego.Router("/api/generate-article", &controllers.ApiController{}, "POST:GenerateArticle")
+	beego.Router("/api/generate-article-block", &controllers.ApiController{}, "POST:GenerateArticleBlock")
+	beego.Router("/api/translate-article-block", &controllers.ApiController{}, "POST:TranslateArticleBlock")
+	beego.Router("/api/update-article-block-state", &controllers.ApiController{}, "POST:UpdateArticleBlockState")
+	beego.Router("/api/export-article", &controllers.ApiController{}, "GET:ExportArticle")
+
+	beego.Router("/api/get-glossaries", &controllers.ApiController{}, "GET:GetGlossaries")
+	beego.Router("/api/get-glossary", &controllers.ApiController{}, "GET:GetGlossary")
+	beego.Router("/api/update-glossary", &controllers.ApiController{}, "POST:UpdateGlossary")
+	beego.Router("/api/add-glossary", &controllers.ApiController{}, "POST:AddGlossary")
+	beego.Router("/api/delete-glossary", &controllers.ApiController{}, "POST:DeleteGlossary")
+	beego.Router("/api/translate-text", &controllers.ApiController{}, "POST:TranslateText")
+	beego.Router("/api/translate-video-labels", &controllers.ApiController{}, "POST:TranslateVideoLabels")
+	beego.Router("/api/delete-translation-caches", &controllers.ApiController{}, "POST:DeleteTranslationCaches")
 
 	beego.Router("/api/update-tree-file", &controllers.ApiController{}, "POST:UpdateTreeFile")
 	beego.Router("/api/add-tree-file", &controllers.ApiController{}, "POST:AddTreeFile")
@@ -218,6 +266,21 @@ func initAPI() {
 	beego.Router("/api/add-scan", &controllers.ApiController{}, "POST:AddScan")
 	beego.Router("/api/delete-scan", &controllers.ApiController{}, "POST:DeleteScan")
 
+	beego.Router("/api/get-datasets", &controllers.ApiController{}, "GET:GetDatasets")
+	beego.Router("/api/get-dataset", &controllers.ApiController{}, "GET:GetDataset")
+	beego.Router("/api/update-dataset", &controllers.ApiController{}, "POST:UpdateDataset")
+	beego.Router("/api/add-dataset", &controllers.ApiController{}, "POST:AddDataset")
+	beego.Router("/api/delete-dataset", &controllers.ApiController{}, "POST:DeleteDataset")
+	beego.Router("/api/upload-dataset", &controllers.ApiController{}, "POST:UploadDataset")
+
+	beego.Router("/api/get-evaluations", &controllers.ApiController{}, "GET:GetEvaluations")
+	beego.Router("/api/get-evaluation", &controllers.ApiController{}, "GET:GetEvaluation")
+	beego.Router("/api/update-evaluation", &controllers.ApiController{}, "POST:UpdateEvaluation")
+	beego.Router("/api/add-evaluation", &controllers.ApiController{}, "POST:AddEvaluation")
+	beego.Router("/api/delete-evaluation", &controllers.ApiController{}, "POST:DeleteEvaluation")
+	beego.Router("/api/run-evaluation", &controllers.ApiController{}, "POST:RunEvaluation")
+	beego.Router("/api/get-evaluation-diff", &controllers.ApiController{}, "GET:GetEvaluationDiff")
+
 	beego.Router("/api/install-patch", &controllers.ApiController{}, "POST:InstallPatch")
 
 	beego.Router("/api/get-images", &controllers.ApiController{}, "GET:GetImages")
@@ -306,5 +369,9 @@ func initAPI() {
 
 	beego.Router("/api/chat/completions", &controllers.ApiController{}, "POST:ChatCompletions")
 
+	beego.Router("/api/mcp", &controllers.ApiController{}, "GET,POST,DELETE:HandleMcp")
+	beego.Router("/api/mcp/sse", &controllers.ApiController{}, "GET:HandleMcpSse")
+	beego.Router("/api/mcp/message", &controllers.ApiController{}, "POST:HandleMcpMessage")
+
 	beego.Router("/api/wecom-bot/callback/:botId", &controllers.ApiController{}, "GET:WecomBotVerifyUrl;POST:WecomBotHandleMessage")
 }
@@ -0,0 +1,223 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package txt
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+var (
+	spaceRegex     = regexp.MustCompile(`[ \t\r\n]+`)
+	blankLineRegex = regexp.MustCompile(`\n{3,}`)
+)
+
+var skippedHtmlTags = map[string]bool{
+	"script": true, "style": true, "noscript": true, "template": true, "svg": true, "iframe": true,
+	"nav": true, "footer": true, "aside": true, "form": true, "button": true, "select": true,
+	"head": true,
+}
+
+var blockHtmlTags = map[string]bool{
+	"p": true, "div": true, "section": true, "article": true, "main": true, "header": true,
+	"table": true, "blockquote": true, "figure": true, "ul": true, "ol": true, "dl": true,
+}
+
+type markdownWriter struct {
+	sb        strings.Builder
+	listDepth int
+	newLines  int
+}
+
+func (w *markdownWriter) writeString(text string) {
+	if text == "" {
+		return
+	}
+	w.sb.WriteString(text)
+
+	trimmed := strings.TrimRight(text, "\n")
+	if trimmed == "" {
+		w.newLines += len(text)
+	} else {
+		w.newLines = len(text) - len(trimmed)
+	}
+}
+
+// newLine ends the current line and adds blank lines up to count line breaks.
+func (w *markdownWriter) newLine(count int) {
+	for w.sb.Len() > 0 && w.newLines < count {
+		w.writeString("\n")
+	}
+}
+
+func getHtmlAttr(node *html.Node, key string) string {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+func getHtmlText(node *html.Node) string {
+	var sb strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(node)
+	return sb.String()
+}
+
+func (w *markdownWriter) writeChildren(node *html.Node) {
+	for c := node.FirstChild; c != nil; c = c.NextSibling {
+		w.write(c)
+	}
+}
+
+func (w *markdownWriter) write(node *html.Node) {
+	switch node.Type {
+	case html.TextNode:
+		text := spaceRegex.ReplaceAllString(node.Data, " ")
+		if w.newLines > 0 || w.sb.Len() == 0 {
+			text = strings.TrimLeft(text, " ")
+		}
+		w.writeString(text)
+		return
+	case html.DocumentNode:
+		w.writeChildren(node)
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	tag := node.Data
+	if skippedHtmlTags[tag] || getHtmlAttr(node, "hidden") != "" || getHtmlAttr(node, "aria-hidden") == "true" {
+		return
+	}
+
+	switch tag {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		w.newLine(2)
+		w.writeString(strings.Repeat("#", int(tag[1]-'0')) + " ")
+		w.writeString(strings.TrimSpace(spaceRegex.ReplaceAllString(getHtmlText(node), " ")))
+		w.newLine(2)
+	case "br":
+		w.newLine(1)
+	case "hr":
+		w.newLine(2)
+		w.writeString("---")
+		w.newLine(2)
+	case "pre":
+		w.newLine(2)
+		w.writeString("```\n" + strings.Trim(getHtmlText(node), "\n") + "\n```")
+		w.newLine(2)
+	case "code":
+		w.writeString("`" + getHtmlText(node) + "`")
+	case "strong", "b":
+		w.writeString("**")
+		w.writeChildren(node)
+		w.writeString("**")
+	case "em", "i":
+		w.writeString("_")
+		w.writeChildren(node)
+		w.writeString("_")
+	case "a":
+		href := getHtmlAttr(node, "href")
+		text := strings.TrimSpace(spaceRegex.ReplaceAllString(getHtmlText(node), " "))
+		if href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(href, "javascript:") || text == "" {
+			w.writeString(text)
+		} else {
+			w.writeString(fmt.Sprintf("[%s](%s)", text, href))
+		}
+	case "img":
+		alt := getHtmlAttr(node, "alt")
+		if alt != "" {
+			w.writeString(fmt.Sprintf("![%s](%s)", alt, getHtmlAttr(node, "src")))
+		}
+	case "li":
+		w.newLine(1)
+		w.writeString(strings.Repeat("  ", w.listDepth-1) + "- ")
+		w.writeChildren(node)
+		w.newLine(1)
+	case "ul", "ol":
+		// nested lists continue the item of the outer list
+		gap := 2
+		if w.listDepth > 0 {
+			gap = 1
+		}
+		w.newLine(gap)
+		w.listDepth++
+		w.writeChildren(node)
+		w.listDepth--
+		w.newLine(gap)
+	case "tr":
+		w.newLine(1)
+		w.writeChildren(node)
+		w.writeString("|")
+		w.newLine(1)
+	case "td", "th":
+		w.writeString("| ")
+		w.writeChildren(node)
+		w.writeString(" ")
+	default:
+		isBlock := blockHtmlTags[tag]
+		if isBlock {
+			w.newLine(2)
+		}
+		w.writeChildren(node)
+		if isBlock {
+			w.newLine(2)
+		}
+	}
+}
+
+// GetMarkdownFromHtml converts an HTML page into Markdown, keeping its headings, paragraphs, lists,
+// tables, links and code, and dropping scripts, styles, navigation and forms.
+func GetMarkdownFromHtml(data string) (string, error) {
+	doc, err := html.Parse(strings.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+
+	w := &markdownWriter{}
+	w.write(doc)
+
+	lines := strings.Split(w.sb.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	res := blankLineRegex.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+	return strings.TrimSpace(res), nil
+}
+
+func getTextFromHtml(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return GetMarkdownFromHtml(string(data))
+}
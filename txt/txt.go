@@ -50,6 +50,8 @@ func GetParsedTextFromUrl(url string, ext string, lang string) (string, error) {
 		res, err = getTextFromPlain(path)
 	} else if ext == ".csv" {
 		res, err = getTextFromCsv(path)
+	} else if ext == ".html" || ext == ".htm" {
+		res, err = getTextFromHtml(path)
 	} else if ext == ".pdf" {
 		res, err = getTextFromPdf(path)
 	} else if ext == ".docx" {
@@ -17,6 +17,7 @@ package util
 import (
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"strings"
@@ -130,3 +131,78 @@ func GetIPFromRequest(req *http.Request) string {
 
 	return GetIPInfo(clientIP)
 }
+
+// GetClientIPFromRequest returns the IP address of the client of the request. The X-Forwarded-For
+// and X-Real-IP headers are only trusted when the request comes from one of the trusted proxies,
+// given as comma-separated IP addresses or CIDRs, otherwise any client could choose its address.
+// X-Forwarded-For is walked from the right and the first address that is not a trusted proxy is
+// the client, as the addresses on its left are set by the client itself.
+func GetClientIPFromRequest(req *http.Request, trustedProxies string) string {
+	remoteIP := getRemoteIPFromRequest(req)
+	proxies := getTrustedProxies(trustedProxies)
+	if !isTrustedProxy(remoteIP, proxies) {
+		return remoteIP
+	}
+
+	forwardedIPs := strings.Split(req.Header.Get("X-Forwarded-For"), ",")
+	for i := len(forwardedIPs) - 1; i >= 0; i-- {
+		ip := strings.TrimSpace(forwardedIPs[i])
+		if net.ParseIP(ip) == nil {
+			break
+		}
+		if !isTrustedProxy(ip, proxies) {
+			return ip
+		}
+	}
+
+	realIP := strings.TrimSpace(req.Header.Get("X-Real-IP"))
+	if net.ParseIP(realIP) != nil {
+		return realIP
+	}
+	return remoteIP
+}
+
+func getRemoteIPFromRequest(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = strings.Trim(req.RemoteAddr, "[]")
+	}
+	return host
+}
+
+func getTrustedProxies(trustedProxies string) []*net.IPNet {
+	res := []*net.IPNet{}
+	for _, proxy := range strings.Split(trustedProxies, ",") {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+
+		if !strings.Contains(proxy, "/") {
+			if ip := net.ParseIP(proxy); ip != nil && ip.To4() != nil {
+				proxy += "/32"
+			} else {
+				proxy += "/128"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err == nil {
+			res = append(res, ipNet)
+		}
+	}
+	return res
+}
+
+func isTrustedProxy(ip string, proxies []*net.IPNet) bool {
+	parsedIP := net.ParseIP(ip)
+	if parsedIP == nil {
+		return false
+	}
+
+	for _, proxy := range proxies {
+		if proxy.Contains(parsedIP) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build skipCi
+// +build skipCi
+
+package util
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestGetClientIPFromRequest(t *testing.T) {
+	scenarios := []struct {
+		remoteAddr     string
+		forwardedFor   string
+		realIP         string
+		trustedProxies string
+		expected       string
+	}{
+		{"203.0.113.7:1234", "198.51.100.1", "", "", "203.0.113.7"},
+		{"10.0.0.2:1234", "198.51.100.1", "", "", "10.0.0.2"},
+		{"10.0.0.2:1234", "198.51.100.1", "", "10.0.0.0/8", "198.51.100.1"},
+		{"10.0.0.2:1234", "1.1.1.1, 198.51.100.1, 10.0.0.3", "", "10.0.0.0/8", "198.51.100.1"},
+		{"10.0.0.2:1234", "", "198.51.100.2", "10.0.0.2", "198.51.100.2"},
+		{"10.0.0.2:1234", "", "", "10.0.0.2", "10.0.0.2"},
+		{"[::1]:1234", "198.51.100.1", "", "::1", "198.51.100.1"},
+	}
+
+	for _, scenario := range scenarios {
+		req := &http.Request{RemoteAddr: scenario.remoteAddr, Header: http.Header{}}
+		if scenario.forwardedFor != "" {
+			req.Header.Set("X-Forwarded-For", scenario.forwardedFor)
+		}
+		if scenario.realIP != "" {
+			req.Header.Set("X-Real-IP", scenario.realIP)
+		}
+
+		ip := GetClientIPFromRequest(req, scenario.trustedProxies)
+		if ip != scenario.expected {
+			t.Errorf("GetClientIPFromRequest() with %+v = %s, expected: %s", scenario, ip, scenario.expected)
+		}
+	}
+}
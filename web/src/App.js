@@ -75,9 +75,13 @@ import ScaleEditPage from "./ScaleEditPage";
 import FormListPage from "./FormListPage";
 import FormEditPage from "./FormEditPage";
 import FormDataPage from "./FormDataPage";
+import FormSubmitPage from "./FormSubmitPage";
+import FormSubmissionListPage from "./FormSubmissionListPage";
 import * as FormBackend from "./backend/FormBackend";
 import ArticleListPage from "./ArticleListPage";
 import ArticleEditPage from "./ArticleEditPage";
+import GlossaryListPage from "./GlossaryListPage";
+import GlossaryEditPage from "./GlossaryEditPage";
 import ChatPage from "./ChatPage";
 import CustomGithubCorner from "./CustomGithubCorner";
 import ShortcutsPage from "./basic/ShortcutsPage";
@@ -264,6 +268,8 @@ class App extends Component {
       this.setState({selectedMenuKey: "/forms"});
     } else if (uri.includes("/articles")) {
       this.setState({selectedMenuKey: "/articles"});
+    } else if (uri.includes("/glossaries")) {
+      this.setState({selectedMenuKey: "/glossaries"});
     } else if (uri.includes("/hospitals")) {
       this.setState({selectedMenuKey: "/hospitals"});
     } else if (uri.includes("/doctors")) {
@@ -732,6 +738,7 @@ class App extends Component {
         Setting.getItem(<Link to="/yolov8mi">{i18next.t("med:Medical Image Analysis")}</Link>, "/yolov8mi"),
         Setting.getItem(<Link to="/sr">{i18next.t("med:Super Resolution")}</Link>, "/sr"),
         Setting.getItem(<Link to="/articles">{i18next.t("general:Articles")}</Link>, "/articles"),
+        Setting.getItem(<Link to="/glossaries">{i18next.t("general:Glossaries")}</Link>, "/glossaries"),
         Setting.getItem(<Link to="/graphs">{i18next.t("general:Graphs")}</Link>, "/graphs"),
         Setting.getItem(<Link to="/scans">{i18next.t("general:Scans")}</Link>, "/scans"),
       ]));
@@ -883,8 +890,12 @@ class App extends Component {
         <Route exact path="/forms" render={(props) => this.renderSigninIfNotSignedIn(<FormListPage account={this.state.account} {...props} />)} />
         <Route exact path="/forms/:formName" render={(props) => this.renderSigninIfNotSignedIn(<FormEditPage account={this.state.account} {...props} />)} />
         <Route exact path="/forms/:formName/data" render={(props) => this.renderSigninIfNotSignedIn(<FormDataPage key={props.match.params.formName} account={this.state.account} {...props} />)} />
+        <Route exact path="/forms/:formName/submissions" render={(props) => this.renderSigninIfNotSignedIn(<FormSubmissionListPage key={props.match.params.formName} account={this.state.account} {...props} />)} />
+        <Route exact path="/public-forms/:owner/:formName" render={(props) => <FormSubmitPage account={this.state.account} {...props} />} />
         <Route exact path="/articles" render={(props) => this.renderSigninIfNotSignedIn(<ArticleListPage account={this.state.account} {...props} />)} />
         <Route exact path="/articles/:articleName" render={(props) => this.renderSigninIfNotSignedIn(<ArticleEditPage account={this.state.account} {...props} />)} />
+        <Route exact path="/glossaries" render={(props) => this.renderSigninIfNotSignedIn(<GlossaryListPage account={this.state.account} {...props} />)} />
+        <Route exact path="/glossaries/:glossaryName" render={(props) => this.renderSigninIfNotSignedIn(<GlossaryEditPage account={this.state.account} {...props} />)} />
         <Route exact path="/hospitals" render={(props) => this.renderSigninIfNotSignedIn(<HospitalListPage account={this.state.account} {...props} />)} />
         <Route exact path="/hospitals/:hospitalName" render={(props) => this.renderSigninIfNotSignedIn(<HospitalEditPage account={this.state.account} {...props} />)} />
         <Route exact path="/doctors" render={(props) => this.renderSigninIfNotSignedIn(<DoctorListPage account={this.state.account} {...props} />)} />
@@ -917,7 +928,7 @@ class App extends Component {
     if (uri === undefined) {
       uri = this.state.uri;
     }
-    const hiddenPaths = ["/workbench", "/access"];
+    const hiddenPaths = ["/workbench", "/access", "/public-forms"];
     for (const path of hiddenPaths) {
       if (uri.startsWith(path)) {
         return true;
@@ -18,6 +18,8 @@ import * as ArticleBackend from "./backend/ArticleBackend";
 import * as Setting from "./Setting";
 import i18next from "i18next";
 import * as WorkflowBackend from "./backend/WorkflowBackend";
+import * as StoreBackend from "./backend/StoreBackend";
+import * as GlossaryBackend from "./backend/GlossaryBackend";
 import ArticleTable from "./table/ArticleTable";
 import ArticleMenu from "./ArticleMenu";
 
@@ -31,7 +33,11 @@ class ArticleEditPage extends React.Component {
       classes: props,
       articleName: props.match.params.articleName,
       workflows: [],
+      stores: [],
+      glossaries: [],
       article: null,
+      generating: false,
+      exportLanguage: "en",
       chatPageObj: null,
       loading: false,
       isNewArticle: props.location?.state?.isNewArticle || false,
@@ -43,6 +49,8 @@ class ArticleEditPage extends React.Component {
   UNSAFE_componentWillMount() {
     this.getArticle();
     this.getWorkflows();
+    this.getStores();
+    this.getGlossaries();
   }
 
   componentDidMount() {
@@ -87,6 +95,99 @@ class ArticleEditPage extends React.Component {
       });
   }
 
+  getStores() {
+    StoreBackend.getStoreNames("admin")
+      .then((res) => {
+        if (res.status === "ok") {
+          this.setState({
+            stores: res.data,
+          });
+        }
+      });
+  }
+
+  getGlossaries() {
+    GlossaryBackend.getGlossaries(this.props.account.owner)
+      .then((res) => {
+        if (res.status === "ok") {
+          this.setState({
+            glossaries: res.data,
+          });
+        }
+      });
+  }
+
+  // saves the edits first since the generation works on the saved article, then shows the article it returns
+  runArticleRequest(request) {
+    const article = Setting.deepCopy(this.state.article);
+    return ArticleBackend.updateArticle(article.owner, this.state.articleName, article)
+      .then((res) => {
+        if (res.status !== "ok") {
+          return res;
+        }
+
+        this.setState({
+          articleName: article.name,
+          isNewArticle: false,
+        });
+        return request(article);
+      })
+      .then((res) => {
+        if (res.status === "ok") {
+          this.setState({
+            article: res.data,
+          });
+        } else {
+          Setting.showMessage("error", `${i18next.t("general:Failed to generate")}: ${res.msg}`);
+        }
+        return res;
+      })
+      .catch(error => {
+        Setting.showMessage("error", `${i18next.t("general:Failed to generate")}: ${error}`);
+        return {status: "error", msg: `${error}`};
+      });
+  }
+
+  generateArticle() {
+    this.setState({generating: true});
+    this.runArticleRequest((article) => ArticleBackend.generateArticle(article.owner, article.name))
+      .then((res) => {
+        this.setState({generating: false});
+        if (res.status === "ok") {
+          Setting.showMessage("success", i18next.t("general:Successfully generated"));
+        }
+      });
+  }
+
+  generateBlock(index) {
+    return this.runArticleRequest((article) => ArticleBackend.generateArticleBlock(article.owner, article.name, index));
+  }
+
+  translateBlock(index) {
+    return this.runArticleRequest((article) => ArticleBackend.translateArticleBlock(article.owner, article.name, index));
+  }
+
+  updateBlockState(index, state) {
+    return this.runArticleRequest((article) => ArticleBackend.updateArticleBlockState(article.owner, article.name, index, state));
+  }
+
+  exportArticle(format) {
+    const article = this.state.article;
+    ArticleBackend.exportArticle(article.owner, article.name, format, this.state.exportLanguage)
+      .then((blob) => {
+        if (blob.type === "application/json") {
+          blob.text().then(text => Setting.showMessage("error", `${i18next.t("general:Failed to get")}: ${JSON.parse(text).msg}`));
+          return;
+        }
+
+        const link = document.createElement("a");
+        link.href = URL.createObjectURL(blob);
+        link.download = `${article.name}.${format}`;
+        link.click();
+        URL.revokeObjectURL(link.href);
+      });
+  }
+
   parseArticleField(key, value) {
     if ([""].includes(key)) {
       value = Setting.myParseInt(value);
@@ -339,6 +440,14 @@ class ArticleEditPage extends React.Component {
           <Button onClick={() => this.submitArticleEdit(false)}>{i18next.t("general:Save")}</Button>
           <Button style={{marginLeft: "20px"}} type="primary" onClick={() => this.submitArticleEdit(true)}>{i18next.t("general:Save & Exit")}</Button>
           {this.state.isNewArticle && <Button style={{marginLeft: "20px"}} onClick={() => this.cancelArticleEdit()}>{i18next.t("general:Cancel")}</Button>}
+          <Button style={{marginLeft: "20px"}} disabled={!this.state.article.store} loading={this.state.generating} onClick={() => this.generateArticle()}>{i18next.t("general:Generate")}</Button>
+          <Select virtual={false} style={{marginLeft: "20px", width: "150px"}} value={this.state.exportLanguage} onChange={(value => {this.setState({exportLanguage: value});})}
+            options={[
+              Setting.getOption(i18next.t("store:English"), "en"),
+              Setting.getOption(i18next.t("article:Original text"), ""),
+            ]} />
+          <Button style={{marginLeft: "10px"}} onClick={() => this.exportArticle("docx")}>{i18next.t("article:Export DOCX")}</Button>
+          <Button style={{marginLeft: "10px"}} onClick={() => this.exportArticle("md")}>{i18next.t("article:Export Markdown")}</Button>
         </div>
       } style={{marginLeft: "5px"}} type="inner">
         <Row style={{marginTop: "10px"}} >
@@ -403,6 +512,27 @@ class ArticleEditPage extends React.Component {
             </Popover>
           </Col>
         </Row>
+        {
+          this.props.account.name !== "admin" ? null : (
+            <Row style={{marginTop: "10px"}} >
+              <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+                {Setting.getLabel(i18next.t("general:Store"), i18next.t("article:Generation store - Tooltip"))} :
+              </Col>
+              <Col span={5} >
+                <Select virtual={false} allowClear style={{width: "100%"}} value={this.state.article.store || undefined} onChange={(value => {this.updateArticleField("store", value || "");})}
+                  options={this.state.stores.map((store) => Setting.getOption(store.displayName, store.name))} />
+              </Col>
+              <Col span={1} />
+              <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+                {Setting.getLabel(i18next.t("general:Glossaries"), i18next.t("glossary:Glossaries - Tooltip"))} :
+              </Col>
+              <Col span={8} >
+                <Select virtual={false} mode="multiple" style={{width: "100%"}} value={this.state.article.glossaries || []} onChange={(value => {this.updateArticleField("glossaries", value);})}
+                  options={this.state.glossaries.map((glossary) => Setting.getOption(glossary.displayName, `${glossary.owner}/${glossary.name}`))} />
+              </Col>
+            </Row>
+          )
+        }
         <Row style={{marginTop: "20px"}} >
           {/* <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>*/}
           {/*  {i18next.t("article:Content")}:*/}
@@ -420,7 +550,8 @@ class ArticleEditPage extends React.Component {
           </Col>
           {/* <Col span={1} />*/}
           <Col span={19} >
-            <ArticleTable ref={this.articleTableRef} article={this.state.article} table={blocks} onUpdateTable={(value) => {this.updateArticleField("content", value);}} onSubmitArticleEdit={() => {this.submitArticleEdit(false);}} />
+            <ArticleTable ref={this.articleTableRef} article={this.state.article} table={blocks} onUpdateTable={(value) => {this.updateArticleField("content", value);}} onSubmitArticleEdit={() => {this.submitArticleEdit(false);}}
+              onGenerateBlock={(index) => this.generateBlock(index)} onTranslateBlock={(index) => this.translateBlock(index)} onUpdateBlockState={(index, state) => this.updateBlockState(index, state)} />
           </Col>
         </Row>
       </Card>
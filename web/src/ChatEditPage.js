@@ -272,6 +272,16 @@ class ChatEditPage extends React.Component {
             }} />
           </Col>
         </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("scan:Summary"), i18next.t("chat:Summary - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Input.TextArea autoSize={{minRows: 1, maxRows: 10}} value={this.state.chat.summary} onChange={e => {
+              this.updateChatField("summary", e.target.value);
+            }} />
+          </Col>
+        </Row>
         <Row style={{marginTop: "20px"}} >
           <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
             {Setting.getLabel(i18next.t("general:Messages"), i18next.t("general:Messages - Tooltip"))} :
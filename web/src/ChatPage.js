@@ -514,6 +514,11 @@ class ChatPage extends BaseListPage {
                 lastMessage2.vectorScores = res.data[res.data.length - 1].vectorScores;
               }
 
+              // Preserve tool call approvals when finalizing the message
+              if (res.data[res.data.length - 1].pendingToolCalls) {
+                lastMessage2.pendingToolCalls = res.data[res.data.length - 1].pendingToolCalls;
+              }
+
               // We're no longer in reasoning phase
               lastMessage2.isReasoningPhase = false;
               // If there are suggestions or title , split them from the text
@@ -548,6 +553,21 @@ class ChatPage extends BaseListPage {
                   this.chatBox.current.toggleMessageReadState(lastMessage2);
                 }
               }
+            }, (data) => {
+              // onApproval callback
+              if (!chat || (this.state.chat.name !== chat.name)) {
+                return;
+              }
+              const pendingToolCalls = JSON.parse(data);
+
+              const currentMessage = res.data[res.data.length - 1];
+              const lastMessage2 = Setting.deepCopy(currentMessage);
+              lastMessage2.pendingToolCalls = (lastMessage2.pendingToolCalls || []).concat(pendingToolCalls);
+              res.data[res.data.length - 1] = lastMessage2;
+
+              this.setState({
+                messages: res.data,
+              });
             });
           } else {
             this.setState({
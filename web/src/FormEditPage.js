@@ -13,12 +13,15 @@
 // limitations under the License.
 
 import React from "react";
-import {Button, Card, Col, Input, Row, Select} from "antd";
+import {Button, Card, Col, Input, Row, Select, Switch} from "antd";
 import {LinkOutlined} from "@ant-design/icons";
 import * as FormBackend from "./backend/FormBackend";
+import * as StoreBackend from "./backend/StoreBackend";
+import * as WorkflowBackend from "./backend/WorkflowBackend";
 import * as Setting from "./Setting";
 import i18next from "i18next";
 import FormItemTable from "./table/FormItemTable";
+import FormFieldTable from "./table/FormFieldTable";
 import RecordListPage from "./RecordListPage";
 import StoreListPage from "./StoreListPage";
 import VectorListPage from "./VectorListPage";
@@ -40,11 +43,37 @@ class FormEditPage extends React.Component {
       isNewForm: props.location?.state?.isNewForm || false,
       form: null,
       formCount: "key",
+      stores: [],
+      workflows: [],
     };
   }
 
   UNSAFE_componentWillMount() {
     this.getForm();
+    this.getStores();
+    this.getWorkflows();
+  }
+
+  getStores() {
+    StoreBackend.getStoreNames("admin")
+      .then((res) => {
+        if (res.status === "ok") {
+          this.setState({
+            stores: res.data,
+          });
+        }
+      });
+  }
+
+  getWorkflows() {
+    WorkflowBackend.getWorkflows(this.props.account.owner)
+      .then((res) => {
+        if (res.status === "ok") {
+          this.setState({
+            workflows: res.data,
+          });
+        }
+      });
   }
 
   getForm() {
@@ -130,6 +159,7 @@ class FormEditPage extends React.Component {
                   {id: "Table", name: i18next.t("form:Table")},
                   {id: "iFrame", name: i18next.t("form:iFrame")},
                   {id: "List Page", name: i18next.t("form:List Page")},
+                  {id: "Form", name: i18next.t("form:Form")},
                 ].map((item, index) => <Option key={index} value={item.id}>{item.name}</Option>)
               }
             </Select>
@@ -219,12 +249,17 @@ class FormEditPage extends React.Component {
             </div>
           )
         }
+        {
+          this.state.form.category === "Form" && this.renderSubmissionForm()
+        }
         <Row style={{marginTop: "20px"}} >
           <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
             {i18next.t("general:Preview")}:
           </Col>
           <Col span={22} >
-            {this.state.form.category === "List Page" ? (this.renderListPagePreview()) :
+            {this.state.form.category === "Form" ? (
+              <iframe key={this.state.formCount} title={"formSubmit"} src={this.getPublicFormUrl()} width="100%" height="700px" frameBorder="no" style={{border: "1px solid #e0e0e0", borderRadius: "8px", boxShadow: "0 2px 8px rgba(0, 0, 0, 0.1)"}} />
+            ) : this.state.form.category === "List Page" ? (this.renderListPagePreview()) :
               <div key={this.state.formCount}>
                 <iframe id="formData" title={"formData"} src={`${location.href}/data`} width="100%" height="700px" frameBorder="no" style={{border: "1px solid #e0e0e0", borderRadius: "8px", boxShadow: "0 2px 8px rgba(0, 0, 0, 0.1)"}} />
               </div>
@@ -235,6 +270,65 @@ class FormEditPage extends React.Component {
     );
   }
 
+  getPublicFormUrl() {
+    return `${window.location.origin}/public-forms/${this.state.form.owner}/${this.state.form.name}`;
+  }
+
+  renderSubmissionForm() {
+    return (
+      <div>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("form:Fields"), i18next.t("form:Fields - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <FormFieldTable
+              title={i18next.t("form:Fields")}
+              table={this.state.form.formItems}
+              onUpdateTable={(value) => {this.updateFormField("formItems", value);}}
+            />
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("video:Is public"), i18next.t("video:Is public - Tooltip"))} :
+          </Col>
+          <Col span={1} >
+            <Switch checked={this.state.form.isPublic} onChange={checked => {
+              this.updateFormField("isPublic", checked);
+            }} />
+          </Col>
+          <Col span={21} >
+            <a target="_blank" rel="noreferrer" href={this.getPublicFormUrl()}>{this.getPublicFormUrl()}</a>
+            <Button style={{marginLeft: "20px"}} size="small" onClick={() => this.props.history.push(`/forms/${this.state.form.name}/submissions`)}>{i18next.t("form:Submissions")}</Button>
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("general:Store"), i18next.t("form:Knowledge store - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Select virtual={false} allowClear style={{width: "100%"}} value={this.state.form.store || undefined} onChange={(value => {
+              this.updateFormField("store", value || "");
+            })}
+            options={this.state.stores.map((store) => Setting.getOption(store.displayName, store.name))} />
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("store:Workflow"), i18next.t("form:Submission workflow - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Select virtual={false} allowClear style={{width: "100%"}} value={this.state.form.workflow || undefined} onChange={(value => {
+              this.updateFormField("workflow", value || "");
+            })}
+            options={this.state.workflows.map((workflow) => Setting.getOption(workflow.displayName, workflow.name))} />
+          </Col>
+        </Row>
+      </div>
+    );
+  }
+
   renderListPageItems() {
 
   }
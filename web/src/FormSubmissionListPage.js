@@ -0,0 +1,230 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import React from "react";
+import {Button, Popconfirm, Table} from "antd";
+import BaseListPage from "./BaseListPage";
+import * as Setting from "./Setting";
+import * as FormBackend from "./backend/FormBackend";
+import i18next from "i18next";
+
+class FormSubmissionListPage extends BaseListPage {
+  constructor(props) {
+    super(props);
+    this.state = {
+      ...this.state,
+      formName: props.match.params.formName,
+      form: null,
+    };
+  }
+
+  getForm() {
+    FormBackend.getForm(this.props.account.owner, this.props.match.params.formName)
+      .then((res) => {
+        if (res.status === "ok") {
+          this.setState({
+            form: res.data,
+          });
+        } else {
+          Setting.showMessage("error", `${i18next.t("general:Failed to get")}: ${res.msg}`);
+        }
+      });
+  }
+
+  deleteSubmission(record) {
+    FormBackend.deleteFormSubmission(record)
+      .then((res) => {
+        if (res.status === "ok") {
+          Setting.showMessage("success", i18next.t("general:Successfully deleted"));
+          this.setState({
+            data: this.state.data.filter((item) => item.name !== record.name),
+            pagination: {
+              ...this.state.pagination,
+              total: this.state.pagination.total - 1,
+            },
+          });
+        } else {
+          Setting.showMessage("error", `${i18next.t("general:Failed to delete")}: ${res.msg}`);
+        }
+      })
+      .catch(error => {
+        Setting.showMessage("error", `${i18next.t("general:Failed to delete")}: ${error}`);
+      });
+  }
+
+  exportSubmissions(format) {
+    FormBackend.exportFormSubmissions(this.props.account.owner, this.state.formName, format)
+      .then((blob) => {
+        if (blob.type === "application/json") {
+          blob.text().then(text => Setting.showMessage("error", `${i18next.t("general:Failed to get")}: ${JSON.parse(text).msg}`));
+          return;
+        }
+
+        const link = document.createElement("a");
+        link.href = URL.createObjectURL(blob);
+        link.download = `${this.state.formName}.${format}`;
+        link.click();
+        URL.revokeObjectURL(link.href);
+      });
+  }
+
+  renderValue(item, value) {
+    if (value === undefined || value === null) {
+      return null;
+    }
+    if (item.type === "File") {
+      return <a target="_blank" rel="noreferrer" href={value}>{i18next.t("general:Download")}</a>;
+    }
+    if (Array.isArray(value)) {
+      return value.join(", ");
+    }
+    return `${value}`;
+  }
+
+  renderTable(submissions) {
+    const formItems = this.state.form?.formItems || [];
+    const columns = [
+      {
+        title: i18next.t("general:Name"),
+        dataIndex: "name",
+        key: "name",
+        width: "200px",
+        sorter: true,
+        ...this.getColumnSearchProps("name"),
+      },
+      {
+        title: i18next.t("general:Created time"),
+        dataIndex: "createdTime",
+        key: "createdTime",
+        width: "160px",
+        sorter: true,
+        render: (text, record, index) => {
+          return Setting.getFormattedDate(text);
+        },
+      },
+      {
+        title: i18next.t("general:User"),
+        dataIndex: "user",
+        key: "user",
+        width: "120px",
+        sorter: true,
+        ...this.getColumnSearchProps("user"),
+      },
+      {
+        title: i18next.t("general:Client IP"),
+        dataIndex: "clientIp",
+        key: "clientIp",
+        width: "120px",
+        sorter: true,
+        ...this.getColumnSearchProps("clientIp"),
+      },
+      ...formItems.map(item => ({
+        title: item.label || item.name,
+        dataIndex: ["values", item.name],
+        key: `values.${item.name}`,
+        render: (text, record, index) => {
+          return this.renderValue(item, text);
+        },
+      })),
+      {
+        title: i18next.t("form:Workflow instance"),
+        dataIndex: "workflowInstance",
+        key: "workflowInstance",
+        width: "160px",
+      },
+      {
+        title: i18next.t("general:Error"),
+        dataIndex: "errorText",
+        key: "errorText",
+        width: "200px",
+      },
+      {
+        title: i18next.t("general:Action"),
+        dataIndex: "action",
+        key: "action",
+        width: "100px",
+        fixed: (Setting.isMobile()) ? "false" : "right",
+        render: (text, record, index) => {
+          return (
+            <Popconfirm
+              title={`${i18next.t("general:Sure to delete")}: ${record.name} ?`}
+              onConfirm={() => this.deleteSubmission(record)}
+              okText={i18next.t("general:OK")}
+              cancelText={i18next.t("general:Cancel")}
+            >
+              <Button style={{marginTop: "10px", marginBottom: "10px"}} type="primary" danger>{i18next.t("general:Delete")}</Button>
+            </Popconfirm>
+          );
+        },
+      },
+    ];
+
+    const paginationProps = {
+      total: this.state.pagination.total,
+      showQuickJumper: true,
+      showSizeChanger: true,
+      pageSizeOptions: ["10", "20", "50", "100", "1000", "10000", "100000"],
+      showTotal: () => i18next.t("general:{total} in total").replace("{total}", this.state.pagination.total),
+    };
+
+    return (
+      <div>
+        <Table scroll={{x: "max-content"}} columns={columns} dataSource={submissions} rowKey="name" size="middle" bordered pagination={paginationProps}
+          title={() => (
+            <div>
+              {i18next.t("form:Submissions")}: {this.state.form?.displayName || this.state.formName}&nbsp;&nbsp;&nbsp;&nbsp;
+              <Button size="small" onClick={() => this.exportSubmissions("csv")}>{i18next.t("form:Export CSV")}</Button>
+              <Button style={{marginLeft: "10px"}} size="small" onClick={() => this.exportSubmissions("xlsx")}>{i18next.t("task:Export XLSX")}</Button>
+            </div>
+          )}
+          loading={this.state.loading}
+          onChange={this.handleTableChange}
+        />
+      </div>
+    );
+  }
+
+  fetch = (params = {}) => {
+    const field = params.searchedColumn, value = params.searchText;
+    const sortField = params.sortField, sortOrder = params.sortOrder;
+    this.setState({loading: true});
+    FormBackend.getFormSubmissions(this.props.account.owner, this.props.match.params.formName, params.pagination.current, params.pagination.pageSize, field, value, sortField, sortOrder)
+      .then((res) => {
+        this.setState({
+          loading: false,
+        });
+        if (res.status === "ok") {
+          this.setState({
+            data: res.data,
+            pagination: {
+              ...params.pagination,
+              total: res.data2,
+            },
+            searchText: params.searchText,
+            searchedColumn: params.searchedColumn,
+          });
+        } else {
+          if (Setting.isResponseDenied(res)) {
+            this.setState({
+              isAuthorized: false,
+            });
+          } else {
+            Setting.showMessage("error", res.msg);
+          }
+        }
+      });
+  };
+}
+
+export default FormSubmissionListPage;
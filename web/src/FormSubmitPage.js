@@ -0,0 +1,172 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import React from "react";
+import {Button, Card, Checkbox, DatePicker, Form, Input, InputNumber, Result, Select, Upload} from "antd";
+import {UploadOutlined} from "@ant-design/icons";
+import * as FormBackend from "./backend/FormBackend";
+import * as Setting from "./Setting";
+import i18next from "i18next";
+
+const {TextArea} = Input;
+
+class FormSubmitPage extends React.Component {
+  constructor(props) {
+    super(props);
+    this.state = {
+      classes: props,
+      owner: props.match.params.owner,
+      formName: props.match.params.formName,
+      form: null,
+      files: {},
+      submitting: false,
+      submitted: false,
+    };
+  }
+
+  UNSAFE_componentWillMount() {
+    this.getForm();
+  }
+
+  getForm() {
+    FormBackend.getPublicForm(this.state.owner, this.state.formName)
+      .then((res) => {
+        if (res.status === "ok") {
+          this.setState({
+            form: res.data,
+          });
+        } else {
+          Setting.showMessage("error", `${i18next.t("general:Failed to get")}: ${res.msg}`);
+        }
+      });
+  }
+
+  readFile(item, file) {
+    const reader = new FileReader();
+    reader.onload = (e) => {
+      this.setState({
+        files: {...this.state.files, [item.name]: {name: file.name, data: e.target.result}},
+      });
+    };
+    reader.readAsDataURL(file);
+    return false;
+  }
+
+  getRules(item) {
+    const rules = [];
+    if (item.required && item.type !== "File") {
+      rules.push({required: true, message: `${item.label || item.name} ${i18next.t("form:is required")}`});
+    }
+    if (item.type === "Email") {
+      rules.push({type: "email"});
+    }
+    if (item.regex) {
+      rules.push({pattern: new RegExp(item.regex)});
+    }
+    if (item.maxLength) {
+      rules.push({max: item.maxLength});
+    }
+    return rules;
+  }
+
+  renderInput(item) {
+    switch (item.type) {
+    case "TextArea":
+      return <TextArea rows={4} />;
+    case "Number":
+      return <InputNumber style={{width: "100%"}} min={item.minValue} max={item.maxValue} />;
+    case "Date":
+      return <DatePicker style={{width: "100%"}} />;
+    case "Select":
+      return <Select virtual={false} options={(item.options || []).map(option => Setting.getOption(option, option))} />;
+    case "Multiple Select":
+      return <Select virtual={false} mode="multiple" options={(item.options || []).map(option => Setting.getOption(option, option))} />;
+    case "File":
+      return (
+        <Upload maxCount={1} accept={(item.fileTypes || []).map(fileType => fileType.startsWith(".") ? fileType : `.${fileType}`).join(",")}
+          beforeUpload={(file) => this.readFile(item, file)}
+          onRemove={() => this.setState({files: {...this.state.files, [item.name]: undefined}})}>
+          <Button icon={<UploadOutlined />}>{i18next.t("general:Upload")}</Button>
+        </Upload>
+      );
+    default:
+      return <Input />;
+    }
+  }
+
+  submitForm(values) {
+    const submittedValues = {...values};
+    this.state.form.formItems.forEach(item => {
+      if (item.type === "Date" && values[item.name]) {
+        submittedValues[item.name] = values[item.name].format("YYYY-MM-DD");
+      } else if (item.type === "File") {
+        submittedValues[item.name] = this.state.files[item.name];
+      }
+    });
+
+    this.setState({submitting: true});
+    FormBackend.submitForm(this.state.owner, this.state.formName, submittedValues)
+      .then((res) => {
+        this.setState({submitting: false});
+        if (res.status === "ok") {
+          this.setState({submitted: true});
+        } else {
+          Setting.showMessage("error", `${i18next.t("form:Failed to submit")}: ${res.msg}`);
+        }
+      })
+      .catch(error => {
+        this.setState({submitting: false});
+        Setting.showMessage("error", `${i18next.t("form:Failed to submit")}: ${error}`);
+      });
+  }
+
+  renderForm() {
+    if (this.state.submitted) {
+      return (
+        <Result status="success" title={i18next.t("form:Successfully submitted")}
+          extra={<Button type="primary" onClick={() => this.setState({submitted: false, files: {}})}>{i18next.t("form:Submit another")}</Button>} />
+      );
+    }
+
+    return (
+      <Form layout="vertical" onFinish={(values) => this.submitForm(values)}>
+        {
+          (this.state.form.formItems || []).map(item => (
+            <Form.Item key={item.name} name={item.name} label={item.type === "Checkbox" ? null : (item.label || item.name)} required={item.required} rules={this.getRules(item)}
+              valuePropName={item.type === "Checkbox" ? "checked" : "value"}>
+              {item.type === "Checkbox" ? <Checkbox>{item.label || item.name}</Checkbox> : this.renderInput(item)}
+            </Form.Item>
+          ))
+        }
+        <Form.Item>
+          <Button type="primary" htmlType="submit" loading={this.state.submitting}>{i18next.t("form:Submit")}</Button>
+        </Form.Item>
+      </Form>
+    );
+  }
+
+  render() {
+    if (this.state.form === null) {
+      return null;
+    }
+
+    return (
+      <Card title={this.state.form.displayName} style={{maxWidth: "800px", margin: "20px auto"}}>
+        {this.renderForm()}
+      </Card>
+    );
+  }
+}
+
+export default FormSubmitPage;
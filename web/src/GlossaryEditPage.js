@@ -0,0 +1,252 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import React from "react";
+import {Button, Card, Col, Input, Row, Select, Tag} from "antd";
+import * as GlossaryBackend from "./backend/GlossaryBackend";
+import * as Setting from "./Setting";
+import i18next from "i18next";
+import GlossaryTermTable from "./table/GlossaryTermTable";
+
+const {TextArea} = Input;
+
+class GlossaryEditPage extends React.Component {
+  constructor(props) {
+    super(props);
+    this.state = {
+      classes: props,
+      glossaryName: props.match.params.glossaryName,
+      isNewGlossary: props.location?.state?.isNewGlossary || false,
+      glossary: null,
+      testText: "",
+      testResult: null,
+      translating: false,
+    };
+  }
+
+  UNSAFE_componentWillMount() {
+    this.getGlossary();
+  }
+
+  getGlossary() {
+    GlossaryBackend.getGlossary(this.props.account.owner, this.state.glossaryName)
+      .then((res) => {
+        if (res.status === "ok") {
+          this.setState({
+            glossary: res.data,
+          });
+        } else {
+          Setting.showMessage("error", `${i18next.t("general:Failed to get")}: ${res.msg}`);
+        }
+      });
+  }
+
+  updateGlossaryField(key, value) {
+    const glossary = this.state.glossary;
+    glossary[key] = value;
+    this.setState({
+      glossary: glossary,
+    });
+  }
+
+  // translates with the saved glossary, so the edits should be saved first
+  testTranslation() {
+    this.setState({translating: true});
+    GlossaryBackend.translateText(this.state.testText, this.state.glossary.language, "", [`${this.state.glossary.owner}/${this.state.glossaryName}`])
+      .then((res) => {
+        this.setState({translating: false});
+        if (res.status === "ok") {
+          this.setState({
+            testResult: res.data,
+          });
+        } else {
+          Setting.showMessage("error", `${i18next.t("general:Failed to get")}: ${res.msg}`);
+        }
+      })
+      .catch(error => {
+        this.setState({translating: false});
+        Setting.showMessage("error", `${i18next.t("general:Failed to get")}: ${error}`);
+      });
+  }
+
+  renderTestResult() {
+    const result = this.state.testResult;
+    if (result === null) {
+      return null;
+    }
+
+    return (
+      <div style={{marginTop: "10px"}}>
+        <TextArea autoSize={{minRows: 2, maxRows: 10}} value={result.text} readOnly />
+        <div style={{marginTop: "10px"}}>
+          {
+            result.missingTerms.map((term, index) => <Tag key={index} color="error">{`${term.source} -> ${term.target}`}</Tag>)
+          }
+          <Tag color="processing">{`${i18next.t("glossary:Cached segments")}: ${result.cachedCount} / ${result.segmentCount}`}</Tag>
+        </div>
+      </div>
+    );
+  }
+
+  renderGlossary() {
+    return (
+      <Card size="small" title={
+        <div>
+          {i18next.t("glossary:Edit Glossary")}&nbsp;&nbsp;&nbsp;&nbsp;
+          <Button onClick={() => this.submitGlossaryEdit(false)}>{i18next.t("general:Save")}</Button>
+          <Button style={{marginLeft: "20px"}} type="primary" onClick={() => this.submitGlossaryEdit(true)}>{i18next.t("general:Save & Exit")}</Button>
+          {this.state.isNewGlossary && <Button style={{marginLeft: "20px"}} onClick={() => this.cancelGlossaryEdit()}>{i18next.t("general:Cancel")}</Button>}
+        </div>
+      } style={{marginLeft: "5px"}} type="inner">
+        <Row style={{marginTop: "10px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("general:Name"), i18next.t("general:Name - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Input value={this.state.glossary.name} onChange={e => {
+              this.updateGlossaryField("name", e.target.value);
+            }} />
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("general:Display name"), i18next.t("general:Display name - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Input value={this.state.glossary.displayName} onChange={e => {
+              this.updateGlossaryField("displayName", e.target.value);
+            }} />
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("general:Description"), i18next.t("general:Description - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Input value={this.state.glossary.description} onChange={e => {
+              this.updateGlossaryField("description", e.target.value);
+            }} />
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("glossary:Target language"), i18next.t("glossary:Target language - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Input value={this.state.glossary.language} onChange={e => {
+              this.updateGlossaryField("language", e.target.value);
+            }} />
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("glossary:Terms"), i18next.t("glossary:Terms - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <GlossaryTermTable
+              title={i18next.t("glossary:Terms")}
+              table={this.state.glossary.terms}
+              onUpdateTable={(value) => {this.updateGlossaryField("terms", value);}}
+            />
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("glossary:Do not translate"), i18next.t("glossary:Do not translate - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Select virtual={false} mode="tags" style={{width: "100%"}} value={this.state.glossary.doNotTranslate || []} onChange={(value => {this.updateGlossaryField("doNotTranslate", value);})} />
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("article:Translate"), i18next.t("glossary:Test translation - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <TextArea autoSize={{minRows: 2, maxRows: 10}} value={this.state.testText} onChange={(e) => {
+              this.setState({testText: e.target.value});
+            }} />
+            <Button style={{marginTop: "10px"}} type="primary" disabled={this.state.testText === ""} loading={this.state.translating} onClick={() => this.testTranslation()}>{i18next.t("article:Translate")}</Button>
+            {this.renderTestResult()}
+          </Col>
+        </Row>
+      </Card>
+    );
+  }
+
+  submitGlossaryEdit(exitAfterSave) {
+    const glossary = Setting.deepCopy(this.state.glossary);
+    GlossaryBackend.updateGlossary(this.state.glossary.owner, this.state.glossaryName, glossary)
+      .then((res) => {
+        if (res.status === "ok") {
+          if (res.data) {
+            Setting.showMessage("success", i18next.t("general:Successfully saved"));
+            this.setState({
+              glossaryName: this.state.glossary.name,
+              isNewGlossary: false,
+            });
+            if (exitAfterSave) {
+              this.props.history.push("/glossaries");
+            } else {
+              this.props.history.push(`/glossaries/${this.state.glossary.name}`);
+            }
+          } else {
+            Setting.showMessage("error", i18next.t("general:Failed to save"));
+            this.updateGlossaryField("name", this.state.glossaryName);
+          }
+        } else {
+          Setting.showMessage("error", `${i18next.t("general:Failed to save")}: ${res.msg}`);
+        }
+      })
+      .catch(error => {
+        Setting.showMessage("error", `${i18next.t("general:Failed to save")}: ${error}`);
+      });
+  }
+
+  cancelGlossaryEdit() {
+    if (this.state.isNewGlossary) {
+      GlossaryBackend.deleteGlossary(this.state.glossary)
+        .then((res) => {
+          if (res.status === "ok") {
+            Setting.showMessage("success", i18next.t("general:Cancelled successfully"));
+            this.props.history.push("/glossaries");
+          } else {
+            Setting.showMessage("error", `${i18next.t("general:Failed to cancel")}: ${res.msg}`);
+          }
+        })
+        .catch(error => {
+          Setting.showMessage("error", `${i18next.t("general:Failed to cancel")}: ${error}`);
+        });
+    } else {
+      this.props.history.push("/glossaries");
+    }
+  }
+
+  render() {
+    return (
+      <div>
+        {
+          this.state.glossary !== null ? this.renderGlossary() : null
+        }
+        <div style={{marginTop: "20px", marginLeft: "40px"}}>
+          <Button size="large" onClick={() => this.submitGlossaryEdit(false)}>{i18next.t("general:Save")}</Button>
+          <Button style={{marginLeft: "20px"}} type="primary" size="large" onClick={() => this.submitGlossaryEdit(true)}>{i18next.t("general:Save & Exit")}</Button>
+          {this.state.isNewGlossary && <Button style={{marginLeft: "20px"}} size="large" onClick={() => this.cancelGlossaryEdit()}>{i18next.t("general:Cancel")}</Button>}
+        </div>
+      </div>
+    );
+  }
+}
+
+export default GlossaryEditPage;
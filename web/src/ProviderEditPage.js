@@ -123,7 +123,7 @@ class ProviderEditPage extends React.Component {
         return Setting.getLabel(i18next.t("general:Access secret"), i18next.t("general:Access secret - Tooltip"));
       }
       return Setting.getLabel(i18next.t("general:Secret key"), i18next.t("general:Secret key - Tooltip"));
-    } else if (provider.category === "Model") {
+    } else if (provider.category === "Model" || provider.category === "Image") {
       return Setting.getLabel(i18next.t("provider:API key"), i18next.t("provider:API key - Tooltip"));
     } else if (provider.category === "Blockchain") {
       if (provider.type === "Ethereum") {
@@ -310,6 +310,9 @@ class ProviderEditPage extends React.Component {
               } else if (value === "Speech-to-Text") {
                 this.updateProviderField("type", "Alibaba Cloud");
                 this.updateProviderField("subType", "paraformer-realtime-v1");
+              } else if (value === "Image") {
+                this.updateProviderField("type", "OpenAI");
+                this.updateProviderField("subType", "gpt-image-1");
               } else if (value === "Private Cloud") {
                 this.updateProviderField("type", "Kubernetes");
               } else if (value === "Bot") {
@@ -332,6 +335,7 @@ class ProviderEditPage extends React.Component {
                   {id: "Video", name: "Video"},
                   {id: "Text-to-Speech", name: "Text-to-Speech"},
                   {id: "Speech-to-Text", name: "Speech-to-Text"},
+                  {id: "Image", name: "Image"},
                   {id: "Bot", name: "Bot"},
                   {id: "Scan", name: "Scan"},
                 ].map((item, index) => <Option key={index} value={item.id}>{item.name}</Option>)
@@ -432,6 +436,14 @@ class ProviderEditPage extends React.Component {
                 if (value === "Alibaba Cloud") {
                   this.updateProviderField("subType", "paraformer-realtime-v1");
                 }
+              } else if (this.state.provider.category === "Image") {
+                if (value === "OpenAI") {
+                  this.updateProviderField("subType", "gpt-image-1");
+                } else if (value === "Alibaba Cloud") {
+                  this.updateProviderField("subType", "wanx2.1-t2i-turbo");
+                } else if (value === "Stable Diffusion" || value === "ComfyUI") {
+                  this.updateProviderField("subType", "sd_xl_base_1.0.safetensors");
+                }
               } else if (this.state.provider.category === "Bot") {
                 if (value === "Tencent") {
                   this.updateProviderField("subType", "WeCom Bot");
@@ -455,13 +467,13 @@ class ProviderEditPage extends React.Component {
           </Col>
         </Row>
         {
-          !["Model", "Embedding", "Agent", "Text-to-Speech", "Speech-to-Text", "Bot"].includes(this.state.provider.category) ? null : (
+          !["Model", "Embedding", "Agent", "Text-to-Speech", "Speech-to-Text", "Image", "Bot"].includes(this.state.provider.category) ? null : (
             <Row style={{marginTop: "20px"}} >
               <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
                 {Setting.getLabel(i18next.t("provider:Sub type"), i18next.t("provider:Sub type - Tooltip"))} :
               </Col>
               <Col span={22} >
-                {this.state.provider.type === "Ollama" ? (
+                {["Ollama", "Stable Diffusion", "ComfyUI"].includes(this.state.provider.type) ? (
                   <AutoComplete
                     style={{width: "100%"}}
                     value={this.state.provider.subType}
@@ -523,7 +535,7 @@ class ProviderEditPage extends React.Component {
               (this.state.provider.category === "Storage" && this.state.provider.type !== "OpenAI File System")) ||
             (this.state.provider.category === "Blockchain" && !["ChainMaker", "Ethereum"].includes(this.state.provider.type)) ||
             ((this.state.provider.category === "Model" || this.state.provider.category === "Embedding") && this.state.provider.type === "Azure") ||
-            (!(["Storage", "Model", "Embedding", "Text-to-Speech", "Speech-to-Text", "Agent", "Blockchain"].includes(this.state.provider.category)))
+            (!(["Storage", "Model", "Embedding", "Text-to-Speech", "Speech-to-Text", "Image", "Agent", "Blockchain"].includes(this.state.provider.category)))
           ) ? (
               <Row style={{marginTop: "20px"}} >
                 <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
@@ -603,7 +615,23 @@ class ProviderEditPage extends React.Component {
           )
         }
         {
-          (this.state.provider.type === "Local" || this.state.provider.type === "Ollama") ? (
+          !(this.state.provider.category === "Image" && ["Stable Diffusion", "ComfyUI"].includes(this.state.provider.type)) ? null : (
+            <>
+              <Row style={{marginTop: "20px"}} >
+                <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+                  {Setting.getLabel(i18next.t("provider:Price / image"), i18next.t("provider:Price / image - Tooltip"))} :
+                </Col>
+                <Col span={22} >
+                  <InputNumber min={0} value={this.state.provider.inputPricePerThousandTokens} onChange={value => {
+                    this.updateProviderField("inputPricePerThousandTokens", value);
+                  }} />
+                </Col>
+              </Row>
+            </>
+          )
+        }
+        {
+          (this.state.provider.type === "Local" || this.state.provider.type === "Ollama" || ["Stable Diffusion", "ComfyUI"].includes(this.state.provider.type)) ? (
             <>
               <Row style={{marginTop: "20px"}} >
                 <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
@@ -661,7 +689,8 @@ class ProviderEditPage extends React.Component {
             (this.state.provider.category === "Blockchain" && this.state.provider.type === "ChainMaker") ||
             this.state.provider.category === "Scan" ||
             this.state.provider.type === "Dummy" ||
-            this.state.provider.type === "Ollama"
+            this.state.provider.type === "Ollama" ||
+            ["Stable Diffusion", "ComfyUI"].includes(this.state.provider.type)
           ) ? null : (
               <Row style={{marginTop: "20px"}} >
                 <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
@@ -752,7 +781,7 @@ class ProviderEditPage extends React.Component {
           )
         }
         {
-          ["Storage", "Model", "Embedding", "Agent", "Text-to-Speech", "Speech-to-Text", "Scan"].includes(this.state.provider.category) || (this.state.provider.category === "Blockchain" && this.state.provider.type === "Ethereum") || (this.state.provider.category === "Private Cloud" && this.state.provider.type === "Kubernetes") ? null : (
+          ["Storage", "Model", "Embedding", "Agent", "Text-to-Speech", "Speech-to-Text", "Image", "Scan"].includes(this.state.provider.category) || (this.state.provider.category === "Blockchain" && this.state.provider.type === "Ethereum") || (this.state.provider.category === "Private Cloud" && this.state.provider.type === "Kubernetes") ? null : (
             <Row style={{marginTop: "20px"}} >
               <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
                 {this.getRegionLabel(this.state.provider)} :
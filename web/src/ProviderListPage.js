@@ -169,6 +169,7 @@ class ProviderListPage extends BaseListPage {
           {text: "Video", value: "Video"},
           {text: "Text-to-Speech", value: "Text-to-Speech"},
           {text: "Speech-to-Text", value: "Speech-to-Text"},
+          {text: "Image", value: "Image"},
           {text: "Bot", value: "Bot"},
           {text: "Scan", value: "Scan"},
         ],
@@ -192,6 +193,7 @@ class ProviderListPage extends BaseListPage {
           {text: "Video", value: "Video", children: Setting.getProviderTypeOptions("Video").map((o) => {return {text: o.id, value: o.name};})},
           {text: "Text-to-Speech", value: "Text-to-Speech", children: Setting.getProviderTypeOptions("Text-to-Speech").map((o) => {return {text: o.id, value: o.name};})},
           {text: "Speech-to-Text", value: "Speech-to-Text", children: Setting.getProviderTypeOptions("Speech-to-Text").map((o) => {return {text: o.id, value: o.name};})},
+          {text: "Image", value: "Image", children: Setting.getProviderTypeOptions("Image").map((o) => {return {text: o.id, value: o.name};})},
           {text: "Bot", value: "Bot", children: Setting.getProviderTypeOptions("Bot").map((o) => {return {text: o.id, value: o.name};})},
           {text: "Scan", value: "Scan", children: Setting.getProviderTypeOptions("Scan").map((o) => {return {text: o.id, value: o.name};})},
         ],
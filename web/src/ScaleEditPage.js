@@ -4,7 +4,7 @@
 // you may not use this file except in compliance with the License.
 
 import React from "react";
-import {Button, Card, Col, Input, Row, Select} from "antd";
+import {Button, Card, Col, Input, Row, Select, Table, Tag, Upload} from "antd";
 import * as ScaleBackend from "./backend/ScaleBackend";
 import * as Setting from "./Setting";
 import i18next from "i18next";
@@ -64,6 +64,67 @@ class ScaleEditPage extends React.Component {
       });
   }
 
+  importScale(file) {
+    ScaleBackend.importScale(this.state.scale.owner, this.state.scale.name, file)
+      .then((res) => {
+        if (res.status === "ok") {
+          Setting.showMessage("success", i18next.t("general:Successfully saved"));
+          this.setState({scale: res.data});
+        } else {
+          Setting.showMessage("error", `${i18next.t("general:Failed to save")}: ${res.msg}`);
+        }
+      });
+    return false;
+  }
+
+  exportScale(format) {
+    ScaleBackend.exportScale(this.state.scale.owner, this.state.scale.name, format)
+      .then((blob) => {
+        if (blob.type === "application/json" && format !== "json") {
+          blob.text().then(text => Setting.showMessage("error", `${i18next.t("general:Failed to get")}: ${JSON.parse(text).msg}`));
+          return;
+        }
+
+        const link = document.createElement("a");
+        link.href = URL.createObjectURL(blob);
+        link.download = `${this.state.scale.name}.${format}`;
+        link.click();
+        URL.revokeObjectURL(link.href);
+      });
+  }
+
+  renderCategories() {
+    const rows = [];
+    (this.state.scale.categories || []).forEach((category, i) => {
+      category.items.forEach((item, j) => {
+        rows.push({
+          key: `${i}-${j}`,
+          category: j === 0 ? category.name : "",
+          categoryWeight: j === 0 ? (category.weight || 1) : "",
+          item: item.name,
+          weight: item.weight || 1,
+          range: (item.minScore || item.maxScore) ? `${item.minScore}-${item.maxScore}` : "0-100",
+          bands: item.bands || [],
+        });
+      });
+    });
+
+    const columns = [
+      {title: i18next.t("general:Category"), dataIndex: "category", key: "category"},
+      {title: i18next.t("store:Weight"), dataIndex: "categoryWeight", key: "categoryWeight"},
+      {title: i18next.t("task:Sub-criteria"), dataIndex: "item", key: "item"},
+      {title: i18next.t("store:Weight"), dataIndex: "weight", key: "weight"},
+      {title: i18next.t("task:Score range"), dataIndex: "range", key: "range"},
+      {
+        title: i18next.t("task:Bands"),
+        dataIndex: "bands",
+        key: "bands",
+        render: (bands) => bands.map((band, i) => <Tag key={i} title={band.descriptor}>{`${band.name} (${band.minScore}-${band.maxScore})`}</Tag>),
+      },
+    ];
+    return <Table size="small" bordered pagination={false} columns={columns} dataSource={rows} />;
+  }
+
   renderScale() {
     const s = this.state.scale;
     if (!s) {
@@ -75,6 +136,7 @@ class ScaleEditPage extends React.Component {
           {i18next.t("task:Edit Scale")}&nbsp;&nbsp;&nbsp;&nbsp;
           <Button onClick={() => this.submitScaleEdit(false)}>{i18next.t("general:Save")}</Button>
           <Button style={{marginLeft: "20px"}} type="primary" onClick={() => this.submitScaleEdit(true)}>{i18next.t("general:Save & Exit")}</Button>
+          {s.version > 0 ? <Tag style={{marginLeft: "20px"}}>{`${i18next.t("general:Version")}: ${s.version}`}</Tag> : null}
         </div>
       } style={{marginLeft: "5px"}} type="inner">
         <Row style={{marginTop: "10px"}} gutter={16}>
@@ -114,6 +176,29 @@ class ScaleEditPage extends React.Component {
             <TextArea rows={12} value={s.text} onChange={(e) => this.updateScaleField("text", e.target.value)} />
           </Col>
         </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("scan:Categories"), i18next.t("task:Categories - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Upload accept=".xlsx,.json" showUploadList={false} beforeUpload={(file) => this.importScale(file)}>
+              <Button>{i18next.t("task:Import scale")}</Button>
+            </Upload>
+            <Button style={{marginLeft: "10px"}} onClick={() => this.exportScale("xlsx")}>{i18next.t("task:Export XLSX")}</Button>
+            <Button style={{marginLeft: "10px"}} onClick={() => this.exportScale("json")}>{i18next.t("task:Export JSON")}</Button>
+            <div style={{marginTop: "10px"}}>
+              {this.renderCategories()}
+            </div>
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("store:Prompt"), i18next.t("task:Scale prompt - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <TextArea rows={8} value={s.prompt} placeholder={i18next.t("task:Scale prompt - Placeholder")} onChange={(e) => this.updateScaleField("prompt", e.target.value)} />
+          </Col>
+        </Row>
       </Card>
     );
   }
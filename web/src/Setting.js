@@ -158,6 +158,8 @@ export function showMessage(type, text) {
     message.success(text);
   } else if (type === "error") {
     message.error(text);
+  } else if (type === "warning") {
+    message.warning(text);
   }
 }
 
@@ -1089,6 +1091,24 @@ export function getOtherProviderInfo() {
         url: "https://www.alibabacloud.com/",
       },
     },
+    "Image": {
+      "OpenAI": {
+        logo: `${StaticBaseUrl}/img/social_openai.svg`,
+        url: "https://platform.openai.com",
+      },
+      "Alibaba Cloud": {
+        logo: `${StaticBaseUrl}/img/social_aliyun.png`,
+        url: "https://www.alibabacloud.com/",
+      },
+      "Stable Diffusion": {
+        logo: `${StaticBaseUrl}/img/social_local.jpg`,
+        url: "https://github.com/AUTOMATIC1111/stable-diffusion-webui",
+      },
+      "ComfyUI": {
+        logo: `${StaticBaseUrl}/img/social_local.jpg`,
+        url: "https://github.com/comfyanonymous/ComfyUI",
+      },
+    },
     "Bot": {
       "Tencent": {
         logo: `${StaticBaseUrl}/img/social_tencent_cloud.jpg`,
@@ -1375,6 +1395,13 @@ export function getProviderTypeOptions(category) {
     return [
       {id: "Alibaba Cloud", name: "Alibaba Cloud"},
     ];
+  } else if (category === "Image") {
+    return [
+      {id: "OpenAI", name: "OpenAI"},
+      {id: "Alibaba Cloud", name: "Alibaba Cloud"},
+      {id: "Stable Diffusion", name: "Stable Diffusion"},
+      {id: "ComfyUI", name: "ComfyUI"},
+    ];
   } else if (category === "Bot") {
     return [
       {id: "Tencent", name: "Tencent"},
@@ -2080,6 +2107,28 @@ export function getProviderSubTypeOptions(category, type) {
     } else {
       return [];
     }
+  } else if (category === "Image") {
+    if (type === "OpenAI") {
+      return [
+        {id: "gpt-image-1", name: "gpt-image-1"},
+        {id: "dall-e-3", name: "dall-e-3"},
+        {id: "dall-e-2", name: "dall-e-2"},
+      ];
+    } else if (type === "Alibaba Cloud") {
+      return [
+        {id: "wanx2.1-t2i-plus", name: "wanx2.1-t2i-plus"},
+        {id: "wanx2.1-t2i-turbo", name: "wanx2.1-t2i-turbo"},
+        {id: "wanx2.0-t2i-turbo", name: "wanx2.0-t2i-turbo"},
+        {id: "wanx-v1", name: "wanx-v1"},
+      ];
+    } else if (type === "Stable Diffusion" || type === "ComfyUI") {
+      return [
+        {id: "sd_xl_base_1.0.safetensors", name: "sd_xl_base_1.0.safetensors"},
+        {id: "v1-5-pruned-emaonly.safetensors", name: "v1-5-pruned-emaonly.safetensors"},
+      ];
+    } else {
+      return [];
+    }
   } else if (category === "Bot") {
     if (type === "Tencent") {
       return [
@@ -2686,6 +2735,23 @@ export function getBuiltinTools() {
         {name: "weekday", description: "Calculate weekday"},
       ],
     },
+    {
+      category: "knowledge",
+      name: "Knowledge Tools",
+      icon: "📚",
+      tools: [
+        {name: "knowledge_search", description: "Search knowledge bases"},
+      ],
+    },
+    {
+      category: "web",
+      name: "Web Tools",
+      icon: "🌐",
+      tools: [
+        {name: "web_fetch", description: "Fetch a web page as Markdown"},
+        {name: "http_request", description: "Send a JSON HTTP request"},
+      ],
+    },
     {
       category: "code",
       name: "Code Tools",
@@ -13,7 +13,7 @@
 // limitations under the License.
 
 import React from "react";
-import {Button, Card, Cascader, Col, Input, InputNumber, Popover, Row, Select, Switch} from "antd";
+import {Button, Card, Cascader, Col, Input, InputNumber, Popover, Row, Select, Switch, Table} from "antd";
 import * as StoreBackend from "./backend/StoreBackend";
 import * as StorageProviderBackend from "./backend/StorageProviderBackend";
 import * as ProviderBackend from "./backend/ProviderBackend";
@@ -22,6 +22,7 @@ import i18next from "i18next";
 import FileTree from "./FileTree";
 import {ThemeDefault} from "./Conf";
 import ExampleQuestionTable from "./table/ExampleQuestionTable";
+import StoreVariantTable from "./table/StoreVariantTable";
 import StoreAvatarUploader from "./AvatarUpload";
 import {LinkOutlined} from "@ant-design/icons";
 import Editor from "./common/Editor";
@@ -46,12 +47,14 @@ class StoreEditPage extends React.Component {
       embeddingProviders: [],
       textToSpeechProviders: [],
       speechToTextProviders: [],
+      imageModelProviders: [],
       agentProviders: [],
       builtinTools: [],
       enableTtsStreaming: false,
       store: null,
       themeColor: ThemeDefault.colorPrimary,
       isNewStore: props.location?.state?.isNewStore || false,
+      experimentReport: null,
     };
   }
 
@@ -126,6 +129,7 @@ class StoreEditPage extends React.Component {
             embeddingProviders: res.data.filter(provider => provider.category === "Embedding"),
             textToSpeechProviders: res.data.filter(provider => provider.category === "Text-to-Speech"),
             speechToTextProviders: res.data.filter(provider => provider.category === "Speech-to-Text"),
+            imageModelProviders: res.data.filter(provider => provider.category === "Image"),
             agentProviders: res.data.filter(provider => provider.category === "Agent"),
           });
         } else {
@@ -183,6 +187,33 @@ class StoreEditPage extends React.Component {
     );
   }
 
+  getMcpOptions(key) {
+    const agentProvider = this.state.agentProviders.find(provider => provider.name === this.state.store.agentProvider);
+    if (!agentProvider || !agentProvider.mcpTools) {
+      return [];
+    }
+
+    const options = [];
+    agentProvider.mcpTools.filter(mcpTools => mcpTools.isEnabled).forEach(mcpTools => {
+      let items = [];
+      try {
+        items = JSON.parse(mcpTools[key] || "[]") || [];
+      } catch (e) {
+        items = [];
+      }
+
+      items.forEach(item => {
+        if (key === "resources") {
+          options.push({value: `${mcpTools.serverName}__${item.uri}`, label: `${mcpTools.serverName}: ${item.name || item.uri}`});
+        } else if (!(item.arguments || []).some(argument => argument.required)) {
+          // the prompts are got without arguments
+          options.push({value: `${mcpTools.serverName}__${item.name}`, label: `${mcpTools.serverName}: ${item.name}`});
+        }
+      });
+    });
+    return options;
+  }
+
   updateStoreField(key, value) {
     value = this.parseStoreField(key, value);
 
@@ -193,6 +224,53 @@ class StoreEditPage extends React.Component {
     });
   }
 
+  updateExperimentField(key, value) {
+    const experiment = {name: "", isEnabled: false, variants: [], ...this.state.store.experiment};
+    experiment[key] = value;
+    this.updateStoreField("experiment", experiment);
+  }
+
+  getExperimentReport() {
+    StoreBackend.getStoreExperimentReport(this.state.owner, this.state.storeName)
+      .then((res) => {
+        if (res.status === "ok") {
+          this.setState({
+            experimentReport: res.data,
+          });
+        } else {
+          Setting.showMessage("error", `${i18next.t("general:Failed to get")}: ${res.msg}`);
+        }
+      });
+  }
+
+  renderExperimentReport() {
+    const report = this.state.experimentReport;
+    if (report === null) {
+      return null;
+    }
+
+    const renderRate = (rate, pValue) => {
+      const text = `${(rate * 100).toFixed(1)}%`;
+      return pValue < report.significanceLevel ? <b>{text} (p={pValue.toFixed(3)})</b> : text;
+    };
+
+    const columns = [
+      {title: i18next.t("store:Variant"), dataIndex: "variant", key: "variant"},
+      {title: i18next.t("general:Chats"), dataIndex: "chatCount", key: "chatCount"},
+      {title: i18next.t("store:Answers"), dataIndex: "messageCount", key: "messageCount"},
+      {title: i18next.t("store:Feedback coverage"), dataIndex: "feedbackCoverage", key: "feedbackCoverage", render: (text, record) => `${(text * 100).toFixed(1)}% (${record.ratedCount})`},
+      {title: i18next.t("store:Like rate"), dataIndex: "likeRate", key: "likeRate", render: (text, record) => renderRate(record.likeRate, record.likeRatePValue)},
+      {title: i18next.t("store:Dislike rate"), dataIndex: "dislikeRate", key: "dislikeRate", render: (text, record) => renderRate(record.dislikeRate, record.dislikeRatePValue)},
+      {title: i18next.t("store:Average tokens"), dataIndex: "averageTokenCount", key: "averageTokenCount", render: (text, record) => record.tokenCountPValue < report.significanceLevel ? <b>{text.toFixed(0)}</b> : text.toFixed(0)},
+      {title: i18next.t("store:Average price"), dataIndex: "averagePrice", key: "averagePrice", render: (text, record) => `${text.toFixed(6)} ${record.currency}`},
+      {title: i18next.t("store:Average latency (ms)"), dataIndex: "averageLatency", key: "averageLatency", render: (text, record) => record.latencyPValue < report.significanceLevel ? <b>{text.toFixed(0)}</b> : text.toFixed(0)},
+    ];
+
+    return (
+      <Table style={{marginTop: "20px"}} rowKey="variant" columns={columns} dataSource={report.variants} size="middle" bordered pagination={false} />
+    );
+  }
+
   isAIStorageProvider(storageProvider) {
     const providerSelected = this.state.storageProviders.concat(this.state.casdoorStorageProviders).find(v => v.name === storageProvider);
     if (providerSelected && providerSelected.type === "OpenAI File System") {
@@ -404,6 +482,24 @@ class StoreEditPage extends React.Component {
             </Select>
           </Col>
         </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("store:MCP resources"), i18next.t("store:MCP resources - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Select virtual={false} mode="multiple" style={{width: "100%"}} value={this.state.store.mcpResources} options={this.getMcpOptions("resources")} onChange={(value => {this.updateStoreField("mcpResources", value);})}>
+            </Select>
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("store:MCP prompt"), i18next.t("store:MCP prompt - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Select virtual={false} allowClear style={{width: "100%"}} value={this.state.store.mcpPrompt || undefined} options={this.getMcpOptions("prompts")} onChange={(value => {this.updateStoreField("mcpPrompt", value || "");})}>
+            </Select>
+          </Col>
+        </Row>
         <Row style={{marginTop: "20px"}} >
           <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
             {Setting.getLabel(i18next.t("store:Builtin tools"), i18next.t("store:Builtin tools - Tooltip"))} :
@@ -412,6 +508,64 @@ class StoreEditPage extends React.Component {
             {this.renderBuiltinTools()}
           </Col>
         </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("store:Agent max steps"), i18next.t("store:Agent max steps - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <InputNumber min={0} value={this.state.store.agentMaxSteps} onChange={value => {
+              this.updateStoreField("agentMaxSteps", value);
+            }} />
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("store:Agent timeout (s)"), i18next.t("store:Agent timeout (s) - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <InputNumber min={0} value={this.state.store.agentTimeoutSeconds} onChange={value => {
+              this.updateStoreField("agentTimeoutSeconds", value);
+            }} />
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("store:Tool timeout (s)"), i18next.t("store:Tool timeout (s) - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <InputNumber min={0} value={this.state.store.toolTimeoutSeconds} onChange={value => {
+              this.updateStoreField("toolTimeoutSeconds", value);
+            }} />
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("store:Web allowed domains"), i18next.t("store:Web allowed domains - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Select virtual={false} mode="tags" style={{width: "100%"}} value={this.state.store.webAllowedDomains} onChange={(value => {this.updateStoreField("webAllowedDomains", value);})}>
+            </Select>
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("store:Web denied domains"), i18next.t("store:Web denied domains - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Select virtual={false} mode="tags" style={{width: "100%"}} value={this.state.store.webDeniedDomains} onChange={(value => {this.updateStoreField("webDeniedDomains", value);})}>
+            </Select>
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("store:Web max response size (KB)"), i18next.t("store:Web max response size (KB) - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <InputNumber min={0} value={this.state.store.webMaxResponseSize} onChange={value => {
+              this.updateStoreField("webMaxResponseSize", value);
+            }} />
+          </Col>
+        </Row>
         <Row style={{marginTop: "20px"}} >
           <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
             {Setting.getLabel(i18next.t("store:Text-to-Speech provider"), i18next.t("store:Text-to-Speech provider - Tooltip"))} :
@@ -451,6 +605,19 @@ class StoreEditPage extends React.Component {
             </Select>
           </Col>
         </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("store:Image model provider"), i18next.t("store:Image model provider - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Select virtual={false} style={{width: "100%"}} value={this.state.store.imageModelProvider} onChange={(value => {this.updateStoreField("imageModelProvider", value);})}>
+              <Option key="Empty" value="">{i18next.t("general:empty")}</Option>
+              {
+                this.state.imageModelProviders.map((provider, index) => this.renderProviderOption(provider, index))
+              }
+            </Select>
+          </Col>
+        </Row>
         <Row style={{marginTop: "20px"}} >
           <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
             {Setting.getLabel(i18next.t("store:Frequency"), i18next.t("store:Frequency - Tooltip"))} :
@@ -471,6 +638,30 @@ class StoreEditPage extends React.Component {
             }} />
           </Col>
         </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("store:Enable summary memory"), i18next.t("store:Enable summary memory - Tooltip"))} :
+          </Col>
+          <Col span={1} >
+            <Switch checked={this.state.store.enableSummaryMemory} onChange={checked => {
+              this.updateStoreField("enableSummaryMemory", checked);
+            }} />
+          </Col>
+        </Row>
+        {
+          !this.state.store.enableSummaryMemory ? null : (
+            <Row style={{marginTop: "20px"}} >
+              <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+                {Setting.getLabel(i18next.t("store:Summary threshold"), i18next.t("store:Summary threshold - Tooltip"))} :
+              </Col>
+              <Col span={22} >
+                <InputNumber min={0} value={this.state.store.summaryThreshold} onChange={value => {
+                  this.updateStoreField("summaryThreshold", value);
+                }} />
+              </Col>
+            </Row>
+          )
+        }
         <Row style={{marginTop: "20px"}} >
           <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
             {Setting.getLabel(i18next.t("store:Limit minutes"), i18next.t("store:Limit minutes - Tooltip"))} :
@@ -555,6 +746,42 @@ class StoreEditPage extends React.Component {
             }} />
           </Col>
         </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("store:Enable semantic cache"), i18next.t("store:Enable semantic cache - Tooltip"))} :
+          </Col>
+          <Col span={1} >
+            <Switch checked={this.state.store.enableSemanticCache} onChange={checked => {
+              this.updateStoreField("enableSemanticCache", checked);
+            }} />
+          </Col>
+        </Row>
+        {
+          !this.state.store.enableSemanticCache ? null : (
+            <React.Fragment>
+              <Row style={{marginTop: "20px"}} >
+                <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+                  {Setting.getLabel(i18next.t("store:Cache threshold"), i18next.t("store:Cache threshold - Tooltip"))} :
+                </Col>
+                <Col span={22} >
+                  <InputNumber min={0} max={1} step={0.01} value={this.state.store.semanticCacheThreshold} onChange={value => {
+                    this.updateStoreField("semanticCacheThreshold", value);
+                  }} />
+                </Col>
+              </Row>
+              <Row style={{marginTop: "20px"}} >
+                <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+                  {Setting.getLabel(i18next.t("store:Cache minutes"), i18next.t("store:Cache minutes - Tooltip"))} :
+                </Col>
+                <Col span={22} >
+                  <InputNumber min={0} value={this.state.store.semanticCacheMinutes} onChange={value => {
+                    this.updateStoreField("semanticCacheMinutes", value);
+                  }} />
+                </Col>
+              </Row>
+            </React.Fragment>
+          )
+        }
         <Row style={{marginTop: "20px"}} >
           <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
             {Setting.getLabel(i18next.t("general:Site setting"), i18next.t("general:Site setting - Tooltip"))} :
@@ -687,6 +914,16 @@ class StoreEditPage extends React.Component {
             </Select>
           </Col>
         </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("store:Enable delegation"), i18next.t("store:Enable delegation - Tooltip"))} :
+          </Col>
+          <Col span={1} >
+            <Switch checked={this.state.store.enableDelegation} onChange={checked => {
+              this.updateStoreField("enableDelegation", checked);
+            }} />
+          </Col>
+        </Row>
         <Row style={{marginTop: "20px"}} >
           <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
             {Setting.getLabel(i18next.t("store:Child model providers"), i18next.t("store:Child model providers - Tooltip"))} :
@@ -701,6 +938,26 @@ class StoreEditPage extends React.Component {
             </Select>
           </Col>
         </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("store:Experiment"), i18next.t("store:Experiment - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Input style={{width: "300px", marginRight: "10px"}} value={this.state.store.experiment?.name} placeholder={i18next.t("general:Name")} onChange={e => {
+              this.updateExperimentField("name", e.target.value);
+            }} />
+            <Switch checked={this.state.store.experiment?.isEnabled} onChange={checked => {
+              this.updateExperimentField("isEnabled", checked);
+            }} />
+            <Button style={{marginLeft: "10px"}} size="small" onClick={() => this.getExperimentReport()}>
+              {i18next.t("store:View report")}
+            </Button>
+            <StoreVariantTable table={this.state.store.experiment?.variants ?? []} modelProviders={this.state.modelProviders} onUpdateTable={(variants) => {
+              this.updateExperimentField("variants", variants);
+            }} />
+            {this.renderExperimentReport()}
+          </Col>
+        </Row>
         <Row style={{marginTop: "20px"}} >
           <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
             {Setting.getLabel(i18next.t("store:Forbidden words"), i18next.t("store:Forbidden words - Tooltip"))} :
@@ -20,6 +20,7 @@ import moment from "moment";
 import BaseListPage from "./BaseListPage";
 import * as Setting from "./Setting";
 import * as TaskBackend from "./backend/TaskBackend";
+import * as TaskBatchBackend from "./backend/TaskBatchBackend";
 import * as ScaleBackend from "./backend/ScaleBackend";
 import * as ProviderBackend from "./backend/ProviderBackend";
 import i18next from "i18next";
@@ -151,6 +152,102 @@ class TaskListPage extends BaseListPage {
       });
   }
 
+  batchAnalyzeTasks() {
+    const taskBatch = {
+      owner: this.props.account.name,
+      name: `task_batch_${Setting.getRandomName()}`,
+      createdTime: moment().format(),
+      displayName: `${i18next.t("task:Batch analyze")} (${this.state.selectedRowKeys.length})`,
+      providers: [],
+      sampleCount: 1,
+      maxRetries: 2,
+      items: this.state.selectedRowKeys.map(name => ({task: name})),
+    };
+    TaskBatchBackend.addTaskBatch(taskBatch)
+      .then((res) => {
+        if (res.status === "ok") {
+          Setting.showMessage("success", i18next.t("general:Successfully added"));
+          this.setState({taskBatch: taskBatch, selectedRowKeys: [], selectedRows: []});
+          this.pollTaskBatch(taskBatch);
+        } else {
+          Setting.showMessage("error", `${i18next.t("general:Failed to add")}: ${res.msg}`);
+        }
+      })
+      .catch(error => {
+        Setting.showMessage("error", `${i18next.t("general:Failed to add")}: ${error}`);
+      });
+  }
+
+  pollTaskBatch(taskBatch) {
+    TaskBatchBackend.getTaskBatch(taskBatch.owner, taskBatch.name)
+      .then((res) => {
+        if (res.status !== "ok") {
+          Setting.showMessage("error", `${i18next.t("general:Failed to get")}: ${res.msg}`);
+          return;
+        }
+
+        this.setState({taskBatch: res.data});
+        if (res.data.state === "Pending" || res.data.state === "Running") {
+          setTimeout(() => this.pollTaskBatch(taskBatch), 3000);
+        } else {
+          this.fetch({pagination: this.state.pagination});
+        }
+      });
+  }
+
+  retryTaskBatch() {
+    const taskBatch = this.state.taskBatch;
+    TaskBatchBackend.retryTaskBatch(taskBatch.owner, taskBatch.name)
+      .then((res) => {
+        if (res.status === "ok") {
+          this.setState({taskBatch: res.data});
+          this.pollTaskBatch(taskBatch);
+        } else {
+          Setting.showMessage("error", `${i18next.t("general:Failed to save")}: ${res.msg}`);
+        }
+      });
+  }
+
+  exportTaskResults() {
+    const taskBatchId = this.state.taskBatch ? `${this.state.taskBatch.owner}/${this.state.taskBatch.name}` : "";
+    TaskBatchBackend.exportTaskResults(taskBatchId)
+      .then((blob) => {
+        if (blob.type === "application/json") {
+          blob.text().then(text => Setting.showMessage("error", `${i18next.t("general:Failed to get")}: ${JSON.parse(text).msg}`));
+          return;
+        }
+
+        const link = document.createElement("a");
+        link.href = URL.createObjectURL(blob);
+        link.download = this.state.taskBatch ? `${this.state.taskBatch.name}.xlsx` : "tasks.xlsx";
+        link.click();
+        URL.revokeObjectURL(link.href);
+      })
+      .catch(error => {
+        Setting.showMessage("error", `${i18next.t("general:Failed to get")}: ${error}`);
+      });
+  }
+
+  renderTaskBatchProgress() {
+    const taskBatch = this.state.taskBatch;
+    if (!taskBatch) {
+      return null;
+    }
+
+    const color = taskBatch.state === "Finished" ? (taskBatch.failedCount > 0 ? "warning" : "success") : "processing";
+    return (
+      <React.Fragment>
+        <Tag color={color} style={{marginLeft: 8}}>
+          {i18next.t("general:Progress")}: {taskBatch.finishedCount + taskBatch.failedCount} / {taskBatch.items.length}
+          {taskBatch.failedCount > 0 ? ` (${i18next.t("application:Failed")}: ${taskBatch.failedCount})` : ""}
+        </Tag>
+        {taskBatch.state === "Finished" && taskBatch.failedCount > 0 && (
+          <Button size="small" onClick={this.retryTaskBatch.bind(this)}>{i18next.t("task:Retry")}</Button>
+        )}
+      </React.Fragment>
+    );
+  }
+
   deleteItem = async(i) => {
     return TaskBackend.deleteTask(this.state.data[i]);
   };
@@ -447,6 +544,11 @@ class TaskListPage extends BaseListPage {
             <div>
               {i18next.t("general:Tasks")}&nbsp;&nbsp;&nbsp;&nbsp;
               <Button type="primary" size="small" onClick={this.addTask.bind(this)}>{i18next.t("general:Add")}</Button>
+              <Button size="small" style={{marginLeft: 8}} onClick={this.exportTaskResults.bind(this)}>{i18next.t("task:Export results")}</Button>
+              {this.state.selectedRowKeys.length > 0 && (
+                <Button size="small" style={{marginLeft: 8}} onClick={this.batchAnalyzeTasks.bind(this)}>{i18next.t("task:Batch analyze")} ({this.state.selectedRowKeys.length})</Button>
+              )}
+              {this.renderTaskBatchProgress()}
               {this.state.selectedRowKeys.length > 0 && (
                 <Popconfirm title={`${i18next.t("general:Sure to delete")}: ${this.state.selectedRowKeys.length} ${i18next.t("general:items")} ?`} onConfirm={() => this.performBulkDelete(this.state.selectedRows, this.state.selectedRowKeys)} okText={i18next.t("general:OK")} cancelText={i18next.t("general:Cancel")}>
                   <Button type="primary" danger size="small" icon={<DeleteOutlined />} style={{marginLeft: 8}}>
@@ -15,6 +15,7 @@
 import React from "react";
 import {Affix, Avatar, Button, Card, Col, Input, Row, Segmented, Select, Switch, Tag, Timeline, Tooltip} from "antd";
 import * as VideoBackend from "./backend/VideoBackend";
+import * as GlossaryBackend from "./backend/GlossaryBackend";
 import * as Setting from "./Setting";
 import i18next from "i18next";
 import {CheckOutlined, DownloadOutlined, EditOutlined, LinkOutlined, SyncOutlined} from "@ant-design/icons";
@@ -48,6 +49,8 @@ class VideoEditPage extends React.Component {
       chatPageObj: null,
       videoData: null,
       segmentEditIndex: -1,
+      glossaries: [],
+      translatingLabels: false,
     };
 
     this.labelTable = React.createRef();
@@ -56,6 +59,48 @@ class VideoEditPage extends React.Component {
   UNSAFE_componentWillMount() {
     this.getVideo();
     this.getTasks();
+    this.getGlossaries();
+  }
+
+  getGlossaries() {
+    GlossaryBackend.getGlossaries(this.props.account.owner)
+      .then((res) => {
+        if (res.status === "ok") {
+          this.setState({
+            glossaries: res.data,
+          });
+        }
+      });
+  }
+
+  // saves the edits first since the translation works on the saved video, then shows the translated labels
+  translateLabels() {
+    this.setState({translatingLabels: true});
+    const video = Setting.deepCopy(this.state.video);
+    VideoBackend.updateVideo(video.owner, this.state.videoName, video)
+      .then((res) => {
+        if (res.status !== "ok") {
+          throw new Error(res.msg);
+        }
+        return GlossaryBackend.translateVideoLabels(video.owner, video.name);
+      })
+      .then((res) => {
+        if (res.status === "ok") {
+          this.updateVideoField("labels", res.data.labels);
+          if (res.data2.missingTerms.length > 0) {
+            Setting.showMessage("warning", `${i18next.t("glossary:Missing terms")}: ${res.data2.missingTerms.map((term) => term.source).join(", ")}`);
+          } else {
+            Setting.showMessage("success", i18next.t("general:Successfully generated"));
+          }
+        } else {
+          Setting.showMessage("error", `${i18next.t("general:Failed to generate")}: ${res.msg}`);
+        }
+        this.setState({translatingLabels: false});
+      })
+      .catch(error => {
+        Setting.showMessage("error", `${i18next.t("general:Failed to generate")}: ${error}`);
+        this.setState({translatingLabels: false});
+      });
   }
 
   getVideo() {
@@ -454,9 +499,33 @@ class VideoEditPage extends React.Component {
     );
   }
 
+  renderLabelTranslation() {
+    if (!Setting.isAdminUser(this.props.account)) {
+      return null;
+    }
+
+    return (
+      <Row style={{marginBottom: "10px"}} >
+        <Col style={{marginTop: "5px"}} span={2}>
+          {Setting.getLabel(i18next.t("general:Glossaries"), i18next.t("glossary:Glossaries - Tooltip"))} :
+        </Col>
+        <Col span={14} >
+          <Select virtual={false} mode="multiple" style={{width: "100%"}} value={this.state.video.glossaries || []} onChange={(value => {this.updateVideoField("glossaries", value);})}
+            options={this.state.glossaries.map((glossary) => Setting.getOption(glossary.displayName, `${glossary.owner}/${glossary.name}`))} />
+        </Col>
+        <Col span={4} >
+          <Button style={{marginLeft: "20px"}} loading={this.state.translatingLabels} onClick={() => this.translateLabels()}>{i18next.t("glossary:Translate labels")}</Button>
+        </Col>
+      </Row>
+    );
+  }
+
   renderLabels() {
     return (
       <div style={{marginTop: "20px"}}>
+        {
+          this.renderLabelTranslation()
+        }
         <LabelTable
           ref={this.labelTable}
           title={i18next.t("task:Labels")}
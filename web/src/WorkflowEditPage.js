@@ -13,7 +13,7 @@
 // limitations under the License.
 
 import React from "react";
-import {Button, Card, Col, Input, Mentions, Popover, Row} from "antd";
+import {Button, Card, Col, Input, Mentions, Popover, Row, Select} from "antd";
 import * as WorkflowBackend from "./backend/WorkflowBackend";
 import * as Setting from "./Setting";
 import i18next from "i18next";
@@ -104,6 +104,7 @@ class WorkflowEditPage extends React.Component {
           <Button onClick={() => this.submitWorkflowEdit(false)}>{i18next.t("general:Save")}</Button>
           <Button style={{marginLeft: "20px"}} type="primary" onClick={() => this.submitWorkflowEdit(true)}>{i18next.t("general:Save & Exit")}</Button>
           {this.state.isNewWorkflow && <Button style={{marginLeft: "20px"}} onClick={() => this.cancelWorkflowEdit()}>{i18next.t("general:Cancel")}</Button>}
+          <Button style={{marginLeft: "20px"}} onClick={() => this.validateWorkflow()}>{i18next.t("workflow:Validate")}</Button>
         </div>
       } style={{marginLeft: "5px"}} type="inner">
         <Row style={{marginTop: "10px"}} >
@@ -126,6 +127,14 @@ class WorkflowEditPage extends React.Component {
             }} />
           </Col>
         </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("workflow:Starters"), i18next.t("workflow:Starters - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Select virtual={false} mode="tags" style={{width: "100%"}} value={this.state.workflow.starters || []} onChange={(value => {this.updateWorkflowField("starters", value);})} />
+          </Col>
+        </Row>
         <Row style={{marginTop: "20px"}} >
           <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
             {Setting.getLabel(i18next.t("general:Text"), i18next.t("general:Text - Tooltip"))} :
@@ -278,6 +287,25 @@ class WorkflowEditPage extends React.Component {
     );
   }
 
+  validateWorkflow() {
+    WorkflowBackend.validateWorkflow(this.state.workflow)
+      .then((res) => {
+        if (res.status === "ok") {
+          if (res.data.length === 0) {
+            Setting.showMessage("success", i18next.t("workflow:No problems found"));
+          }
+          res.data.forEach(issue => {
+            Setting.showMessage(issue.severity === "Error" ? "error" : "warning", issue.message);
+          });
+        } else {
+          Setting.showMessage("error", `${i18next.t("workflow:Failed to validate")}: ${res.msg}`);
+        }
+      })
+      .catch(error => {
+        Setting.showMessage("error", `${i18next.t("workflow:Failed to validate")}: ${error}`);
+      });
+  }
+
   submitWorkflowEdit(exitAfterSave) {
     const workflow = Setting.deepCopy(this.state.workflow);
     WorkflowBackend.updateWorkflow(this.state.workflow.owner, this.state.workflowName, workflow)
@@ -79,3 +79,53 @@ export function deleteArticle(article) {
     body: JSON.stringify(newArticle),
   }).then(res => res.json());
 }
+
+export function generateArticle(owner, name) {
+  return fetch(`${Setting.ServerUrl}/api/generate-article?id=${owner}/${encodeURIComponent(name)}`, {
+    method: "POST",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
+
+export function generateArticleBlock(owner, name, index) {
+  return fetch(`${Setting.ServerUrl}/api/generate-article-block?id=${owner}/${encodeURIComponent(name)}&index=${index}`, {
+    method: "POST",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
+
+export function translateArticleBlock(owner, name, index) {
+  return fetch(`${Setting.ServerUrl}/api/translate-article-block?id=${owner}/${encodeURIComponent(name)}&index=${index}`, {
+    method: "POST",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
+
+export function updateArticleBlockState(owner, name, index, state) {
+  return fetch(`${Setting.ServerUrl}/api/update-article-block-state?id=${owner}/${encodeURIComponent(name)}&index=${index}&state=${state}`, {
+    method: "POST",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
+
+export function exportArticle(owner, name, format, language) {
+  return fetch(`${Setting.ServerUrl}/api/export-article?id=${owner}/${encodeURIComponent(name)}&format=${format}&language=${language}`, {
+    method: "GET",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.blob());
+}
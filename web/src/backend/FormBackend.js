@@ -79,3 +79,56 @@ export function deleteForm(form) {
     body: JSON.stringify(newForm),
   }).then(res => res.json());
 }
+
+export function getPublicForm(owner, name) {
+  return fetch(`${Setting.ServerUrl}/api/get-public-form?id=${owner}/${encodeURIComponent(name)}`, {
+    method: "GET",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
+
+export function submitForm(owner, name, values) {
+  return fetch(`${Setting.ServerUrl}/api/submit-form?id=${owner}/${encodeURIComponent(name)}`, {
+    method: "POST",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+    body: JSON.stringify(values),
+  }).then(res => res.json());
+}
+
+export function getFormSubmissions(owner, form, page = "", pageSize = "", field = "", value = "", sortField = "", sortOrder = "") {
+  return fetch(`${Setting.ServerUrl}/api/get-form-submissions?owner=${owner}&form=${encodeURIComponent(form)}&p=${page}&pageSize=${pageSize}&field=${field}&value=${value}&sortField=${sortField}&sortOrder=${sortOrder}`, {
+    method: "GET",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
+
+export function deleteFormSubmission(submission) {
+  const newSubmission = Setting.deepCopy(submission);
+  return fetch(`${Setting.ServerUrl}/api/delete-form-submission`, {
+    method: "POST",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+    body: JSON.stringify(newSubmission),
+  }).then(res => res.json());
+}
+
+export function exportFormSubmissions(owner, name, format) {
+  return fetch(`${Setting.ServerUrl}/api/export-form-submissions?id=${owner}/${encodeURIComponent(name)}&format=${format}`, {
+    method: "GET",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.blob());
+}
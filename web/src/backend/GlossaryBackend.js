@@ -0,0 +1,92 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import * as Setting from "../Setting";
+
+export function getGlossaries(owner, page = "", pageSize = "", field = "", value = "", sortField = "", sortOrder = "") {
+  return fetch(`${Setting.ServerUrl}/api/get-glossaries?owner=${owner}&p=${page}&pageSize=${pageSize}&field=${field}&value=${value}&sortField=${sortField}&sortOrder=${sortOrder}`, {
+    method: "GET",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
+
+export function getGlossary(owner, name) {
+  return fetch(`${Setting.ServerUrl}/api/get-glossary?id=${owner}/${encodeURIComponent(name)}`, {
+    method: "GET",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
+
+export function updateGlossary(owner, name, glossary) {
+  const newGlossary = Setting.deepCopy(glossary);
+  return fetch(`${Setting.ServerUrl}/api/update-glossary?id=${owner}/${encodeURIComponent(name)}`, {
+    method: "POST",
+    credentials: "include",
+    body: JSON.stringify(newGlossary),
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
+
+export function addGlossary(glossary) {
+  const newGlossary = Setting.deepCopy(glossary);
+  return fetch(`${Setting.ServerUrl}/api/add-glossary`, {
+    method: "POST",
+    credentials: "include",
+    body: JSON.stringify(newGlossary),
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
+
+export function deleteGlossary(glossary) {
+  const newGlossary = Setting.deepCopy(glossary);
+  return fetch(`${Setting.ServerUrl}/api/delete-glossary`, {
+    method: "POST",
+    credentials: "include",
+    body: JSON.stringify(newGlossary),
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
+
+export function translateText(text, targetLanguage, provider, glossaries) {
+  return fetch(`${Setting.ServerUrl}/api/translate-text`, {
+    method: "POST",
+    credentials: "include",
+    body: JSON.stringify({text: text, targetLanguage: targetLanguage, provider: provider, glossaries: glossaries}),
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
+
+export function translateVideoLabels(owner, name, provider = "") {
+  return fetch(`${Setting.ServerUrl}/api/translate-video-labels?id=${owner}/${encodeURIComponent(name)}&provider=${provider}`, {
+    method: "POST",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
@@ -46,7 +46,7 @@ export function getChatMessages(owner, chat) {
 
 const eventSourceMap = new Map();
 
-export function getMessageAnswer(owner, name, onMessage, onReason, onTool, onSearch, onVector, onError, onEnd) {
+export function getMessageAnswer(owner, name, onMessage, onReason, onTool, onSearch, onVector, onError, onEnd, onApproval) {
   if (eventSourceMap.has(`${owner}/${name}`)) {
     return;
   }
@@ -77,6 +77,12 @@ export function getMessageAnswer(owner, name, onMessage, onReason, onTool, onSea
     });
   }
 
+  if (onApproval) {
+    eventSource.addEventListener("approval", (e) => {
+      onApproval(e.data);
+    });
+  }
+
   eventSource.addEventListener("myerror", (e) => {
     onError(e.data);
     eventSource.close();
@@ -132,6 +138,26 @@ export function updateMessage(owner, name, message, isHitOnly = false) {
   }).then(res => res.json());
 }
 
+export function approveToolCall(owner, name, toolCallId) {
+  return fetch(`${Setting.ServerUrl}/api/approve-tool-call?id=${owner}/${encodeURIComponent(name)}&toolCallId=${encodeURIComponent(toolCallId)}`, {
+    method: "POST",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
+
+export function rejectToolCall(owner, name, toolCallId) {
+  return fetch(`${Setting.ServerUrl}/api/reject-tool-call?id=${owner}/${encodeURIComponent(name)}&toolCallId=${encodeURIComponent(toolCallId)}`, {
+    method: "POST",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
+
 export function closeMessageEventSource(owner, name) {
   const key = `${owner}/${name}`;
   if (eventSourceMap.has(key)) {
@@ -80,3 +80,26 @@ export function deleteScale(scale) {
     body: JSON.stringify(newScale),
   }).then(res => res.json());
 }
+
+export function importScale(owner, name, file) {
+  const formData = new FormData();
+  formData.append("file", file);
+  return fetch(`${Setting.ServerUrl}/api/import-scale?owner=${owner}&name=${encodeURIComponent(name)}`, {
+    method: "POST",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+    body: formData,
+  }).then(res => res.json());
+}
+
+export function exportScale(owner, name, format) {
+  return fetch(`${Setting.ServerUrl}/api/export-scale?id=${owner}/${encodeURIComponent(name)}&format=${format}`, {
+    method: "GET",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.blob());
+}
@@ -101,3 +101,13 @@ export function refreshStoreVectors(store) {
     body: JSON.stringify(newStore),
   }).then(res => res.json());
 }
+
+export function getStoreExperimentReport(owner, name) {
+  return fetch(`${Setting.ServerUrl}/api/get-store-experiment-report?id=${owner}/${encodeURIComponent(name)}`, {
+    method: "GET",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
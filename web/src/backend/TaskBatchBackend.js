@@ -0,0 +1,79 @@
+// Copyright 2023 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import * as Setting from "../Setting";
+
+export function getTaskBatches(owner, page = "", pageSize = "", field = "", value = "", sortField = "", sortOrder = "") {
+  return fetch(`${Setting.ServerUrl}/api/get-task-batches?owner=${owner}&p=${page}&pageSize=${pageSize}&field=${field}&value=${value}&sortField=${sortField}&sortOrder=${sortOrder}`, {
+    method: "GET",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
+
+export function getTaskBatch(owner, name) {
+  return fetch(`${Setting.ServerUrl}/api/get-task-batch?id=${owner}/${encodeURIComponent(name)}`, {
+    method: "GET",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
+
+export function addTaskBatch(taskBatch) {
+  const newTaskBatch = Setting.deepCopy(taskBatch);
+  return fetch(`${Setting.ServerUrl}/api/add-task-batch`, {
+    method: "POST",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+    body: JSON.stringify(newTaskBatch),
+  }).then(res => res.json());
+}
+
+export function deleteTaskBatch(taskBatch) {
+  const newTaskBatch = Setting.deepCopy(taskBatch);
+  return fetch(`${Setting.ServerUrl}/api/delete-task-batch`, {
+    method: "POST",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+    body: JSON.stringify(newTaskBatch),
+  }).then(res => res.json());
+}
+
+export function retryTaskBatch(owner, name) {
+  return fetch(`${Setting.ServerUrl}/api/retry-task-batch?id=${owner}/${encodeURIComponent(name)}`, {
+    method: "POST",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
+
+export function exportTaskResults(taskBatchId = "") {
+  return fetch(`${Setting.ServerUrl}/api/export-task-results?taskBatch=${encodeURIComponent(taskBatchId)}`, {
+    method: "GET",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.blob());
+}
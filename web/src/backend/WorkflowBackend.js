@@ -79,3 +79,15 @@ export function deleteWorkflow(workflow) {
     body: JSON.stringify(newWorkflow),
   }).then(res => res.json());
 }
+
+export function validateWorkflow(workflow) {
+  const newWorkflow = Setting.deepCopy(workflow);
+  return fetch(`${Setting.ServerUrl}/api/validate-workflow`, {
+    method: "POST",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+    body: JSON.stringify(newWorkflow),
+  }).then(res => res.json());
+}
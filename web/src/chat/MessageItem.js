@@ -18,6 +18,7 @@ import {Alert, Button, Col, Collapse, Row} from "antd";
 import {FileTextOutlined, GlobalOutlined} from "@ant-design/icons";
 import moment from "moment";
 import * as Setting from "../Setting";
+import * as MessageBackend from "../backend/MessageBackend";
 import i18next from "i18next";
 import {AvatarErrorUrl} from "../Conf";
 import {renderText} from "../ChatMessageRender";
@@ -53,6 +54,7 @@ const MessageItem = ({
   const [reasonExpanded, setReasonExpanded] = useState(["reason"]);
   const [searchDrawerVisible, setSearchDrawerVisible] = useState(false);
   const [knowledgeDrawerVisible, setKnowledgeDrawerVisible] = useState(false);
+  const [toolCallStates, setToolCallStates] = useState({});
   const themeColor = Setting.getThemeColor();
   const toolColor = (message.reasonText && message.toolCalls) ? "#1890ff" : themeColor;
 
@@ -124,6 +126,55 @@ const MessageItem = ({
     setAvatarSrc(AvatarErrorUrl);
   };
 
+  const getPendingToolCalls = () => {
+    if (!message.pendingToolCalls) {
+      return [];
+    }
+    return message.pendingToolCalls.filter(toolCall => (toolCallStates[toolCall.id] || toolCall.state) === "Pending");
+  };
+
+  const decideToolCall = (toolCall, isApproved) => {
+    const decide = isApproved ? MessageBackend.approveToolCall : MessageBackend.rejectToolCall;
+    decide(message.owner, message.name, toolCall.id)
+      .then((res) => {
+        if (res.status === "ok") {
+          setToolCallStates({...toolCallStates, [toolCall.id]: isApproved ? "Approved" : "Rejected"});
+        } else {
+          Setting.showMessage("error", `${i18next.t("general:Failed to save")}: ${res.msg}`);
+        }
+      })
+      .catch(error => {
+        Setting.showMessage("error", `${i18next.t("general:Failed to connect to server")}: ${error}`);
+      });
+  };
+
+  const renderToolCallApprovals = () => {
+    return getPendingToolCalls().map((toolCall) => (
+      <Alert
+        key={toolCall.id}
+        type="warning"
+        showIcon
+        style={{marginBottom: "10px"}}
+        message={`${i18next.t("chat:Tool call approval")}: ${toolCall.name}`}
+        description={
+          <div style={{fontSize: "12px", fontFamily: "monospace", whiteSpace: "pre-wrap", wordBreak: "break-word"}}>
+            {toolCall.arguments}
+          </div>
+        }
+        action={
+          <div>
+            <Button size="small" type="primary" style={{marginRight: "8px"}} onClick={() => decideToolCall(toolCall, true)}>
+              {i18next.t("chat:Approve")}
+            </Button>
+            <Button size="small" danger onClick={() => decideToolCall(toolCall, false)}>
+              {i18next.t("chat:Reject")}
+            </Button>
+          </div>
+        }
+      />
+    ));
+  };
+
   const renderMessageContent = () => {
     if (isEditing && message.author !== "AI") {
       return renderEditForm();
@@ -169,6 +220,19 @@ const MessageItem = ({
       );
     }
 
+    if (message.author === "AI" && getPendingToolCalls().length > 0) {
+      return (
+        <div className="message-content">
+          {renderToolCallApprovals()}
+          {message.text !== "" && (
+            <div className="message-answer">
+              {message.html || renderText(message.text)}
+            </div>
+          )}
+        </div>
+      );
+    }
+
     if (message.text === "" && message.author === "AI" && !message.reasonText) {
       return null;
     }
@@ -238,7 +302,7 @@ const MessageItem = ({
                           color: "#096dd9",
                           marginBottom: "4px",
                         }}>
-                          {toolCall.name}
+                          {toolCall.store ? `${toolCall.store} / ${toolCall.name}` : toolCall.name}
                         </div>
                         {toolCall.arguments && (
                           <div style={{
@@ -424,7 +488,7 @@ const MessageItem = ({
               )}
             </div>
           }
-          loading={message.text === "" && message.author === "AI" && !message.reasonText && !message.errorText}
+          loading={message.text === "" && message.author === "AI" && !message.reasonText && !message.errorText && getPendingToolCalls().length === 0}
           typing={message.author === "AI" && !message.isReasoningPhase ? {
             step: 2,
             interval: 50,
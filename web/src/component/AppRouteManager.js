@@ -480,6 +480,21 @@ routeManager.registerApp("articles", {
   ],
 });
 
+routeManager.registerApp("glossaries", {
+  title: "Glossaries",
+  gradient: "linear-gradient(135deg, rgb(176, 214, 196) 0%, rgb(228 240 234) 100%)",
+  routes: [
+    {
+      path: "/glossaries",
+      component: () => import("../GlossaryListPage"),
+    },
+    {
+      path: "/glossaries/:glossaryName",
+      component: () => import("../GlossaryEditPage"),
+    },
+  ],
+});
+
 routeManager.registerApp("super-resolution", {
   title: "Super Resolution",
   gradient: "linear-gradient(135deg, #303B68 0%, #65C37C 100%)",
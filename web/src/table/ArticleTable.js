@@ -13,8 +13,8 @@
 // limitations under the License.
 
 import React from "react";
-import {Button, Col, Row, Select, Table, Tag} from "antd";
-import {DeleteOutlined, DeploymentUnitOutlined, DownOutlined, FileAddOutlined, OrderedListOutlined, UnorderedListOutlined, UpOutlined} from "@ant-design/icons";
+import {Button, Col, Row, Select, Table, Tag, Tooltip} from "antd";
+import {CheckOutlined, DeleteOutlined, DeploymentUnitOutlined, DownOutlined, FileAddOutlined, OrderedListOutlined, RollbackOutlined, SyncOutlined, TranslationOutlined, UnorderedListOutlined, UpOutlined} from "@ant-design/icons";
 import * as Setting from "../Setting";
 import i18next from "i18next";
 import * as MessageBackend from "../backend/MessageBackend";
@@ -27,6 +27,8 @@ class ArticleTable extends React.Component {
     super(props);
     this.state = {
       classes: props,
+      loadingIndex: -1,
+      loadingAction: "",
     };
   }
 
@@ -197,6 +199,59 @@ class ArticleTable extends React.Component {
     //   });
   }
 
+  runBlockAction(index, action, request) {
+    this.setState({loadingIndex: index, loadingAction: action});
+    request().then(() => {
+      this.setState({loadingIndex: -1, loadingAction: ""});
+    });
+  }
+
+  isBlockLoading(index, action) {
+    return this.state.loadingIndex === index && this.state.loadingAction === action;
+  }
+
+  renderState(record) {
+    const state = record.state || "Draft";
+    let tag;
+    if (state === "Reviewed") {
+      tag = <Tag color="success">{i18next.t("video:Reviewed")}</Tag>;
+    } else if (state === "Generated") {
+      tag = <Tag color="processing">{i18next.t("article:Generated")}</Tag>;
+    } else {
+      tag = <Tag color="default">{i18next.t("video:Draft")}</Tag>;
+    }
+
+    if (!record.errorText) {
+      return tag;
+    }
+    return (
+      <Tooltip title={record.errorText}>
+        {tag}
+        <Tag color="error">{i18next.t("general:Error")}</Tag>
+      </Tooltip>
+    );
+  }
+
+  renderBlockActions(record, index) {
+    const isReviewed = record.state === "Reviewed";
+    return (
+      <div>
+        <Tooltip title={i18next.t("general:Generate")}>
+          <Button style={{marginBottom: "5px", marginRight: "5px"}} disabled={!this.props.article.store || !record.prompt} loading={this.isBlockLoading(index, "generate")} icon={<SyncOutlined />} size="small"
+            onClick={() => this.runBlockAction(index, "generate", () => this.props.onGenerateBlock(index))} />
+        </Tooltip>
+        <Tooltip title={i18next.t("article:Translate")}>
+          <Button style={{marginBottom: "5px", marginRight: "5px"}} disabled={!this.props.article.store || !record.text} loading={this.isBlockLoading(index, "translate")} icon={<TranslationOutlined />} size="small"
+            onClick={() => this.runBlockAction(index, "translate", () => this.props.onTranslateBlock(index))} />
+        </Tooltip>
+        <Tooltip title={isReviewed ? i18next.t("article:Back to draft") : i18next.t("article:Mark reviewed")}>
+          <Button style={{marginBottom: "5px", marginRight: "5px"}} disabled={!isReviewed && record.state !== "Generated"} loading={this.isBlockLoading(index, "state")} icon={isReviewed ? <RollbackOutlined /> : <CheckOutlined />} size="small"
+            onClick={() => this.runBlockAction(index, "state", () => this.props.onUpdateBlockState(index, isReviewed ? "Draft" : "Reviewed"))} />
+        </Tooltip>
+      </div>
+    );
+  }
+
   renderTable(table) {
     let columns = [
       {
@@ -245,6 +300,15 @@ class ArticleTable extends React.Component {
           );
         },
       },
+      {
+        title: i18next.t("general:State"),
+        dataIndex: "state",
+        key: "state",
+        width: "100px",
+        render: (text, record, index) => {
+          return this.renderState(record);
+        },
+      },
       {
         title: i18next.t("general:Text"),
         dataIndex: "text",
@@ -291,6 +355,7 @@ class ArticleTable extends React.Component {
         render: (text, record, index) => {
           return (
             <div>
+              {this.renderBlockActions(record, index)}
               {/* <Button type="primary" style={{marginTop: "10px", marginBottom: "10px", marginRight: "5px"}} disabled={record.text === ""} loading={record.isLoadingEn === true} icon={<TranslationOutlined />} onClick={() => this.translateTableToEn(this.props.article, table, index)} >*/}
               {/*  {i18next.t("article:ZH 🡲 EN")}*/}
               {/* </Button>*/}
@@ -311,8 +376,8 @@ class ArticleTable extends React.Component {
 
     if (this.props.article.displayName.endsWith("-P")) {
       columns = columns.filter(column => column.key !== "textEn");
-    } else {
-      columns = columns.filter(column => column.key !== "prompt");
+    } else if (!this.props.article.store) {
+      columns = columns.filter(column => column.key !== "prompt" && column.key !== "state");
     }
 
     return (
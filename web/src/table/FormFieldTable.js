@@ -0,0 +1,238 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import React from "react";
+import {Button, Col, Input, InputNumber, Row, Select, Switch, Table, Tooltip} from "antd";
+import {DeleteOutlined, DownOutlined, UpOutlined} from "@ant-design/icons";
+import * as Setting from "../Setting";
+import i18next from "i18next";
+
+const {Option} = Select;
+
+export const formFieldTypes = ["Text", "TextArea", "Number", "Email", "Date", "Select", "Multiple Select", "Checkbox", "File"];
+
+class FormFieldTable extends React.Component {
+  constructor(props) {
+    super(props);
+    this.state = {
+      classes: props,
+    };
+  }
+
+  updateTable(table) {
+    this.props.onUpdateTable(table);
+  }
+
+  updateField(table, index, key, value) {
+    table[index][key] = value;
+    this.updateTable(table);
+  }
+
+  addRow(table) {
+    if (table === undefined || table === null) {
+      table = [];
+    }
+    const row = {name: `field${table.length}`, label: `Field ${table.length}`, type: "Text", visible: true, width: "", required: false};
+    table = Setting.addRow(table, row);
+    this.updateTable(table);
+  }
+
+  deleteRow(table, i) {
+    table = Setting.deleteRow(table, i);
+    this.updateTable(table);
+  }
+
+  upRow(table, i) {
+    table = Setting.swapRow(table, i - 1, i);
+    this.updateTable(table);
+  }
+
+  downRow(table, i) {
+    table = Setting.swapRow(table, i, i + 1);
+    this.updateTable(table);
+  }
+
+  renderRules(table, record, index) {
+    if (record.type === "Number") {
+      return (
+        <div>
+          <InputNumber style={{width: "120px"}} placeholder={i18next.t("form:Min value")} value={record.minValue} onChange={value => {
+            this.updateField(table, index, "minValue", value === null ? undefined : value);
+          }} />
+          &nbsp;-&nbsp;
+          <InputNumber style={{width: "120px"}} placeholder={i18next.t("form:Max value")} value={record.maxValue} onChange={value => {
+            this.updateField(table, index, "maxValue", value === null ? undefined : value);
+          }} />
+        </div>
+      );
+    } else if (record.type === "Select" || record.type === "Multiple Select") {
+      return (
+        <Select virtual={false} mode="tags" style={{width: "100%"}} placeholder={i18next.t("general:Options")} value={record.options || []} onChange={value => {
+          this.updateField(table, index, "options", value);
+        }} />
+      );
+    } else if (record.type === "File") {
+      return (
+        <div>
+          <Select virtual={false} mode="tags" style={{width: "200px"}} placeholder={i18next.t("form:File types")} value={record.fileTypes || []} onChange={value => {
+            this.updateField(table, index, "fileTypes", value);
+          }} />
+          &nbsp;
+          <InputNumber style={{width: "140px"}} min={0} addonAfter="MB" placeholder={i18next.t("form:Max file size")} value={record.maxFileSize} onChange={value => {
+            this.updateField(table, index, "maxFileSize", value);
+          }} />
+        </div>
+      );
+    } else if (record.type === "Checkbox" || record.type === "Date") {
+      return null;
+    }
+
+    return (
+      <div>
+        <Input style={{width: "200px"}} placeholder={i18next.t("form:Regex")} value={record.regex} onChange={e => {
+          this.updateField(table, index, "regex", e.target.value);
+        }} />
+        &nbsp;
+        <InputNumber style={{width: "140px"}} min={0} placeholder={i18next.t("form:Max length")} value={record.maxLength} onChange={value => {
+          this.updateField(table, index, "maxLength", value);
+        }} />
+      </div>
+    );
+  }
+
+  renderTable(table) {
+    const columns = [
+      {
+        title: i18next.t("general:No."),
+        dataIndex: "no",
+        key: "no",
+        width: "60px",
+        render: (text, record, index) => {
+          return (index + 1);
+        },
+      },
+      {
+        title: i18next.t("general:Name"),
+        dataIndex: "name",
+        key: "name",
+        width: "150px",
+        render: (text, record, index) => {
+          return (
+            <Input value={text} onChange={e => {
+              this.updateField(table, index, "name", e.target.value);
+            }} />
+          );
+        },
+      },
+      {
+        title: i18next.t("general:Label"),
+        dataIndex: "label",
+        key: "label",
+        width: "150px",
+        render: (text, record, index) => {
+          return (
+            <Input value={text} onChange={e => {
+              this.updateField(table, index, "label", e.target.value);
+            }} />
+          );
+        },
+      },
+      {
+        title: i18next.t("general:Type"),
+        dataIndex: "type",
+        key: "type",
+        width: "150px",
+        render: (text, record, index) => {
+          return (
+            <Select virtual={false} style={{width: "100%"}} value={text} onChange={value => {
+              this.updateField(table, index, "type", value);
+            }}>
+              {
+                formFieldTypes.map((type, i) => <Option key={i} value={type}>{type}</Option>)
+              }
+            </Select>
+          );
+        },
+      },
+      {
+        title: i18next.t("general:Required"),
+        dataIndex: "required",
+        key: "required",
+        width: "90px",
+        render: (text, record, index) => {
+          return (
+            <Switch checked={text} onChange={checked => {
+              this.updateField(table, index, "required", checked);
+            }} />
+          );
+        },
+      },
+      {
+        title: i18next.t("form:Rules"),
+        dataIndex: "rules",
+        key: "rules",
+        render: (text, record, index) => {
+          return this.renderRules(table, record, index);
+        },
+      },
+      {
+        title: i18next.t("general:Action"),
+        key: "action",
+        width: "100px",
+        render: (text, record, index) => {
+          return (
+            <div>
+              <Tooltip placement="bottomLeft" title={i18next.t("general:Up")}>
+                <Button style={{marginRight: "5px"}} disabled={index === 0} icon={<UpOutlined />} size="small" onClick={() => this.upRow(table, index)} />
+              </Tooltip>
+              <Tooltip placement="topLeft" title={i18next.t("general:Down")}>
+                <Button style={{marginRight: "5px"}} disabled={index === table.length - 1} icon={<DownOutlined />} size="small" onClick={() => this.downRow(table, index)} />
+              </Tooltip>
+              <Tooltip placement="topLeft" title={i18next.t("general:Delete")}>
+                <Button icon={<DeleteOutlined />} size="small" onClick={() => this.deleteRow(table, index)} />
+              </Tooltip>
+            </div>
+          );
+        },
+      },
+    ];
+
+    return (
+      <Table scroll={{x: "max-content"}} rowKey={(record, index) => index} columns={columns} dataSource={table} size="middle" bordered pagination={false}
+        title={() => (
+          <div>
+            {this.props.title}&nbsp;&nbsp;&nbsp;&nbsp;
+            <Button style={{marginRight: "5px"}} type="primary" size="small" onClick={() => this.addRow(table)}>{i18next.t("general:Add")}</Button>
+          </div>
+        )}
+      />
+    );
+  }
+
+  render() {
+    return (
+      <div>
+        <Row style={{marginTop: "20px"}} >
+          <Col span={24}>
+            {
+              this.renderTable(this.props.table)
+            }
+          </Col>
+        </Row>
+      </div>
+    );
+  }
+}
+
+export default FormFieldTable;
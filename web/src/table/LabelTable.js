@@ -232,6 +232,18 @@ class LabelTable extends React.Component {
           );
         },
       },
+      {
+        title: i18next.t("store:English"),
+        dataIndex: "textEn",
+        key: "textEn",
+        render: (text, record, index) => {
+          return (
+            <TextArea disabled={this.props.disabled || this.requireSelfOrAdmin(record)} autoSize={{minRows: 1, maxRows: 15}} value={text} onChange={(e) => {
+              this.updateField(table, index, "textEn", e.target.value);
+            }} />
+          );
+        },
+      },
       {
         title: i18next.t("general:Action"),
         key: "action",
@@ -267,8 +279,11 @@ class LabelTable extends React.Component {
 
     const myRowCount = table.filter((row) => row.user === this.props.account.name).length;
 
+    // the English text only shows up once the labels have been translated
+    const isTranslated = table.some((row) => row.textEn);
+
     return (
-      <Table rowKey={"id"} columns={columns} dataSource={table} size="middle" bordered pagination={false}
+      <Table rowKey={"id"} columns={isTranslated ? columns : columns.filter((column) => column.key !== "textEn")} dataSource={table} size="middle" bordered pagination={false}
         title={() => (
           <div>
             {this.props.title}&nbsp;&nbsp;&nbsp;&nbsp;
@@ -13,7 +13,7 @@
 // limitations under the License.
 
 import React from "react";
-import {Col, Input, Row, Switch, Table} from "antd";
+import {Col, Input, Row, Select, Switch, Table, Tag} from "antd";
 import i18next from "i18next";
 import Editor from "../common/Editor";
 
@@ -34,6 +34,34 @@ class McpToolsTable extends React.Component {
     this.updateTable(table);
   }
 
+  updateToolPolicy(table, index, toolName, policy) {
+    const toolPolicies = {...(table[index].toolPolicies || {})};
+    if (policy === "Auto") {
+      delete toolPolicies[toolName];
+    } else {
+      toolPolicies[toolName] = policy;
+    }
+    this.updateField(table, index, "toolPolicies", toolPolicies);
+  }
+
+  getToolNames(record) {
+    try {
+      const tools = JSON.parse(record.tools);
+      return Array.isArray(tools) ? tools.map(tool => tool.name) : [];
+    } catch (e) {
+      return [];
+    }
+  }
+
+  getNames(record, key) {
+    try {
+      const items = JSON.parse(record[key] || "[]");
+      return Array.isArray(items) ? items.map(item => item.name || item.uri) : [];
+    } catch (e) {
+      return [];
+    }
+  }
+
   renderTable(table) {
     const columns = [
       {
@@ -61,6 +89,50 @@ class McpToolsTable extends React.Component {
           );
         },
       },
+      {
+        title: i18next.t("provider:Policy"),
+        dataIndex: "toolPolicies",
+        key: "toolPolicies",
+        width: "320px",
+        render: (text, record, index) => {
+          const toolPolicies = text || {};
+          return this.getToolNames(record).map(toolName => (
+            <Row key={toolName} style={{marginBottom: "8px"}} align="middle">
+              <Col span={14} style={{overflow: "hidden", textOverflow: "ellipsis"}}>
+                {toolName}
+              </Col>
+              <Col span={10}>
+                <Select virtual={false} style={{width: "100%"}} value={toolPolicies[toolName] || "Auto"} onChange={(value) => {
+                  this.updateToolPolicy(table, index, toolName, value);
+                }}
+                options={[
+                  {value: "Auto", label: i18next.t("provider:Auto")},
+                  {value: "Approval", label: i18next.t("provider:Approval")},
+                  {value: "Deny", label: i18next.t("provider:Deny")},
+                ]} />
+              </Col>
+            </Row>
+          ));
+        },
+      },
+      {
+        title: i18next.t("general:Resources"),
+        dataIndex: "resources",
+        key: "resources",
+        width: "200px",
+        render: (text, record, index) => {
+          return this.getNames(record, "resources").map(name => <Tag key={name} style={{marginBottom: "4px"}}>{name}</Tag>);
+        },
+      },
+      {
+        title: i18next.t("provider:Prompts"),
+        dataIndex: "prompts",
+        key: "prompts",
+        width: "200px",
+        render: (text, record, index) => {
+          return this.getNames(record, "prompts").map(name => <Tag key={name} style={{marginBottom: "4px"}}>{name}</Tag>);
+        },
+      },
       {
         title: i18next.t("provider:Tools"),
         dataIndex: "tools",
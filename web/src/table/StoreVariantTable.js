@@ -0,0 +1,196 @@
+// Copyright 2025 The Casibase Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import {Button, Input, InputNumber, Select, Table, Tooltip} from "antd";
+import {DeleteOutlined, DownOutlined, UpOutlined} from "@ant-design/icons";
+import i18next from "i18next";
+import React from "react";
+import * as Setting from "../Setting";
+
+class StoreVariantTable extends React.Component {
+  constructor(props) {
+    super(props);
+  }
+
+  updateTable(table) {
+    this.props.onUpdateTable(table);
+  }
+
+  updateField(table, index, key, value) {
+    table[index][key] = value;
+    this.updateTable(table);
+  }
+
+  addRow(table) {
+    const row = {
+      name: `variant_${table?.length ?? 0}`,
+      weight: 1,
+      prompt: "",
+      modelProvider: "",
+      knowledgeCount: 0,
+      searchProvider: "",
+    };
+    if (table === undefined) {
+      table = [];
+    }
+    table = Setting.addRow(table, row);
+    this.updateTable(table);
+  }
+
+  deleteRow(table, i) {
+    table = Setting.deleteRow(table, i);
+    this.updateTable(table);
+  }
+
+  upRow(table, i) {
+    table = Setting.swapRow(table, i - 1, i);
+    this.updateTable(table);
+  }
+
+  downRow(table, i) {
+    table = Setting.swapRow(table, i, i + 1);
+    this.updateTable(table);
+  }
+
+  render() {
+    if (!this.props.table) {
+      this.props.onUpdateTable([]);
+    }
+
+    const columns = [
+      {
+        title: i18next.t("general:Name"),
+        dataIndex: "name",
+        key: "name",
+        width: "150px",
+        render: (text, record, index) => (
+          <Input value={text} onChange={e => this.updateField(this.props.table, index, "name", e.target.value)} />
+        ),
+      },
+      {
+        title: i18next.t("store:Weight"),
+        dataIndex: "weight",
+        key: "weight",
+        width: "90px",
+        render: (text, record, index) => (
+          <InputNumber min={1} value={text} onChange={value => this.updateField(this.props.table, index, "weight", value)} />
+        ),
+      },
+      {
+        title: i18next.t("provider:Model provider"),
+        dataIndex: "modelProvider",
+        key: "modelProvider",
+        width: "200px",
+        render: (text, record, index) => (
+          <Select virtual={false} allowClear style={{width: "100%"}} value={text || undefined} placeholder={i18next.t("store:Same as the store")}
+            onChange={value => this.updateField(this.props.table, index, "modelProvider", value ?? "")}
+            options={this.props.modelProviders?.map((provider) => Setting.getOption(`${provider.displayName} (${provider.name})`, provider.name))} />
+        ),
+      },
+      {
+        title: i18next.t("store:Search provider"),
+        dataIndex: "searchProvider",
+        key: "searchProvider",
+        width: "140px",
+        render: (text, record, index) => (
+          <Select virtual={false} allowClear style={{width: "100%"}} value={text || undefined} placeholder={i18next.t("store:Same as the store")}
+            onChange={value => this.updateField(this.props.table, index, "searchProvider", value ?? "")}
+            options={[{name: "Default"}, {name: "Hierarchy"}].map((provider) => Setting.getOption(provider.name, provider.name))} />
+        ),
+      },
+      {
+        title: i18next.t("store:Knowledge count"),
+        dataIndex: "knowledgeCount",
+        key: "knowledgeCount",
+        width: "120px",
+        render: (text, record, index) => (
+          <InputNumber min={0} max={100} value={text} onChange={value => this.updateField(this.props.table, index, "knowledgeCount", value)} />
+        ),
+      },
+      {
+        title: i18next.t("store:Prompt"),
+        dataIndex: "prompt",
+        key: "prompt",
+        render: (text, record, index) => (
+          <Input.TextArea autoSize={{minRows: 1, maxRows: 5}} value={text} placeholder={i18next.t("store:Same as the store")}
+            onChange={e => this.updateField(this.props.table, index, "prompt", e.target.value)} />
+        ),
+      },
+      {
+        title: i18next.t("general:Action"),
+        key: "action",
+        width: "100px",
+        render: (text, record, index) => {
+          return (
+            <div>
+              <Tooltip placement="bottomLeft" title={i18next.t("general:Up")}>
+                <Button
+                  style={{marginRight: "5px"}}
+                  disabled={index === 0}
+                  icon={<UpOutlined />}
+                  size="small"
+                  onClick={() => this.upRow(this.props.table, index)}
+                />
+              </Tooltip>
+              <Tooltip placement="topLeft" title={i18next.t("general:Down")}>
+                <Button
+                  style={{marginRight: "5px"}}
+                  disabled={index === this.props.table.length - 1}
+                  icon={<DownOutlined />}
+                  size="small"
+                  onClick={() => this.downRow(this.props.table, index)}
+                />
+              </Tooltip>
+              <Tooltip placement="right" title={i18next.t("general:Delete")}>
+                <Button
+                  icon={<DeleteOutlined />}
+                  size="small"
+                  onClick={() => this.deleteRow(this.props.table, index)}
+                />
+              </Tooltip>
+            </div>
+          );
+        },
+      },
+    ];
+
+    return (
+      <div style={{marginTop: "20px"}}>
+        <Table
+          rowKey="index"
+          columns={columns}
+          dataSource={this.props.table}
+          size="middle"
+          bordered
+          pagination={false}
+          title={() => (
+            <div>
+              {i18next.t("store:Variants")}&nbsp;&nbsp;&nbsp;&nbsp;
+              <Button
+                style={{marginRight: "5px"}}
+                type="primary"
+                size="small"
+                onClick={() => this.addRow(this.props.table)}
+              >
+                {i18next.t("general:Add")}
+              </Button>
+            </div>
+          )}
+        />
+      </div>
+    );
+  }
+}
+
+export default StoreVariantTable;